@@ -4,8 +4,10 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
@@ -17,7 +19,6 @@ import (
 	"cache-crew/cognify/internal/db"
 	"cache-crew/cognify/internal/middleware"
 	"cache-crew/cognify/internal/services"
-	"cache-crew/cognify/internal/utils"
 )
 
 func main() {
@@ -32,10 +33,18 @@ func main() {
 	ctx := context.Background()
 
 	// Initialize services
-	if err := db.InitFirestore(ctx); err != nil {
-		log.Printf("Firestore initialization warning: %v", err)
+	// Firestore is only needed when it is the configured storage backend
+	if backend := config.AppConfig.StorageBackend; backend == "" || backend == "firestore" {
+		if err := db.InitFirestore(ctx); err != nil {
+			log.Printf("Firestore initialization warning: %v", err)
+		}
+		defer db.CloseFirestore()
+	}
+
+	if err := db.InitRepositories(ctx); err != nil {
+		log.Fatalf("Storage initialization failed: %v", err)
 	}
-	defer db.CloseFirestore()
+	defer db.CloseSQL()
 
 	if err := db.InitBigQuery(ctx); err != nil {
 		log.Printf("BigQuery initialization warning: %v", err)
@@ -51,23 +60,34 @@ func main() {
 	services.StartOTPCleanup()
 
 	// Initialize blockchain client based on mode
+	blockchain.SetMockLedgerPath(config.AppConfig.MockLedgerPath)
+	txConfig := blockchain.TxConfig{
+		ChainID:     config.AppConfig.ChainID,
+		GasLimit:    config.AppConfig.GasLimit,
+		MaxGasPrice: config.AppConfig.MaxGasPrice,
+		BumpAfter:   time.Duration(config.AppConfig.TxBumpAfterSeconds) * time.Second,
+	}
 	if config.AppConfig.BlockchainMode == "real" {
 		log.Println("🔗 Initializing REAL blockchain client...")
 
-		// Decrypt private key
-		privateKey, err := utils.DecryptPrivateKey(
-			config.AppConfig.PrivateKeyEncrypted,
-			config.AppConfig.EncryptionPass,
-		)
+		signer, err := blockchain.NewSigner(ctx, blockchain.SignerConfig{
+			Backend:      config.AppConfig.SignerBackend,
+			EncryptedKey: config.AppConfig.PrivateKeyEncrypted,
+			Passphrase:   config.AppConfig.EncryptionPass,
+			KeystorePath: config.AppConfig.KeystorePath,
+			Endpoint:     config.AppConfig.SignerEndpoint,
+			Address:      config.AppConfig.SignerAddress,
+		})
 		if err != nil {
-			log.Printf("⚠️  Failed to decrypt private key: %v", err)
+			log.Printf("⚠️  Failed to open %s signer: %v", config.AppConfig.SignerBackend, err)
 			log.Println("   Falling back to mock blockchain")
 			blockchain.InitMockBlockchain()
 		} else {
 			err = blockchain.InitRealBlockchain(
 				config.AppConfig.BlockchainRPC,
 				config.AppConfig.ContractAddress,
-				privateKey,
+				signer,
+				txConfig,
 			)
 			if err != nil {
 				log.Printf("⚠️  Failed to initialize real blockchain: %v", err)
@@ -77,11 +97,40 @@ func main() {
 				log.Println("✅ Real blockchain client initialized")
 			}
 		}
+	} else if config.AppConfig.BlockchainMode == "simulated" {
+		log.Println("🧪 Initializing SIMULATED blockchain...")
+
+		err := blockchain.InitSimulatedBlockchain(
+			time.Duration(config.AppConfig.SimulatedBlockTime)*time.Second,
+			config.AppConfig.SimulatedRPCAddr,
+			txConfig,
+		)
+		if err != nil {
+			log.Printf("⚠️  Failed to initialize simulated blockchain: %v", err)
+			log.Println("   Falling back to mock blockchain")
+			blockchain.InitMockBlockchain()
+		}
 	} else {
 		log.Println("🔧 Using MOCK blockchain (development mode)")
 		blockchain.InitMockBlockchain()
 		log.Println("✅ Mock blockchain client initialized")
 	}
+	defer blockchain.CloseSimulatedBlockchain()
+	blockchain.SetDeployments(config.AppConfig.Deployments)
+	if err := blockchain.SetRetiredSigners(config.AppConfig.PlatformPreviousAddresses); err != nil {
+		log.Fatalf("❌ Invalid PLATFORM_PREVIOUS_ADDRESSES: %v", err)
+	}
+	if config.AppConfig.SDJWTEnabled && !blockchain.CanSignJWS() {
+		log.Fatalf("❌ The %s signer cannot sign SD-JWTs or Token Status Lists; set SD_JWT_ENABLED=false or use the env or keystore signer", config.AppConfig.SignerBackend)
+	}
+
+	// `cognify backfill` re-syncs registry events and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		if err := runBackfillCommand(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("❌ Backfill failed: %v", err)
+		}
+		return
+	}
 
 	// Create router
 	r := chi.NewRouter()
@@ -111,6 +160,9 @@ func main() {
 		w.Write([]byte(`{"status":"ok","service":"cognify-backend"}`))
 	})
 
+	// Prometheus metrics
+	r.Handle("/metrics", prometheus.Handler(blockchain.Metrics))
+
 	// Public routes
 	// --- WALLET AUTH ROUTES (NEW) ---
 	r.Post("/api/auth/nonce", api.GenerateNonceHandler)
@@ -155,6 +207,22 @@ func main() {
 
 	// Public Verification Route
 	r.Post("/api/certificates/verify", api.VerifyCertificateHandler)
+	r.Get("/api/certificates/pdf", api.GetCertificatePDFHandler)
+
+	// W3C Verifiable Credentials (Public)
+	r.Get("/api/certificates/credential", api.GetCredentialHandler)
+	r.Get("/api/certificates/badge", api.GetBadgeHandler)
+	r.Post("/api/credentials/verify", api.VerifyCredentialHandler)
+	r.Get("/api/credentials/status/{id}", api.GetStatusListHandler)
+
+	// Selective-disclosure certificates, for the wallet they were issued to
+	if config.AppConfig.SDJWTEnabled {
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.WalletAuthMiddleware)
+
+			r.Post("/api/certificates/sd-jwt", api.IssueSDJWTHandler)
+		})
+	}
 
 	// Protected Instructor Routes
 	r.Group(func(r chi.Router) {
@@ -162,14 +230,29 @@ func main() {
 		r.Use(middleware.RoleAuthMiddleware("instructor"))
 		r.Use(middleware.InstructorOnlyMiddleware)
 
+		r.Post("/api/instructor/certificate/generate", api.GenerateCertificateHandler)
 		r.Post("/api/instructor/mint/prepare", api.PrepareMintHandler)
-		// r.Post("/api/instructor/mint/confirm", api.ConfirmMintHandler) // Future: Record on-chain success
+		r.Post("/api/instructor/certificates/revoke", api.RevokeCertificateHandler)
+		r.Post("/api/instructor/mint/confirm", api.ConfirmMintHandler)
+		r.Post("/api/instructor/mint/relay", api.RelayMintHandler)
+		r.Get("/api/instructor/mint/relay", api.ListRelayedMintsHandler)
+		r.Get("/api/instructor/mint/relay/{hash}", api.GetRelayedMintHandler)
+		r.Get("/api/instructor/transactions/{id}", api.GetTransactionHandler)
+	})
+
+	// Issuer applications (instructors not yet authorized on-chain)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.WalletAuthMiddleware)
+		r.Use(middleware.RoleAuthMiddleware("instructor"))
+
+		r.Post("/api/instructor/issuer/apply", api.ApplyForIssuerHandler)
+		r.Get("/api/instructor/issuer/status", api.GetIssuerStatusHandler)
 	})
 	r.Get("/api/courses/recommendations", api.GetRecommendationsHandler)
 	r.Get("/api/battles/questions", api.GetBattleQuestionsHandler)
 	r.Post("/api/battles/complete", api.CompleteBattleHandler)
 	r.Post("/api/seed-battle-data", api.SeedBattleDataHandler)
-	r.Post("/api/notifications/seed", api.SeedNotificationsHandler(db.FirestoreClient))
+	r.Post("/api/notifications/seed", api.SeedNotificationsHandler(db.Repos.Notifications))
 
 	// Lesson Completion (Student Progress)
 	r.Post("/api/course/level/complete", api.CompleteLessonHandler)
@@ -187,6 +270,24 @@ func main() {
 	r.Get("/api/analytics/instructor", api.GetInstructorAnalyticsHandler)
 	r.Post("/api/analytics/instructor/update-reputation", api.UpdateInstructorReputationHandler)
 
+	// Admin routes
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.WalletAuthMiddleware)
+		r.Use(middleware.RoleAuthMiddleware("admin"))
+
+		r.Get("/api/admin/reconciliation", api.GetReconciliationReportHandler)
+		r.Get("/api/admin/transactions/pending", api.ListPendingTransactionsHandler)
+		r.Post("/api/admin/blockchain/backfill", api.StartBackfillHandler)
+		r.Get("/api/admin/blockchain/backfill", api.GetBackfillHandler)
+		r.Get("/api/admin/blockchain/deployments", api.ListDeploymentsHandler)
+		r.Get("/api/admin/blockchain/treasury", api.GetTreasuryHandler)
+		r.Post("/api/admin/certificates/revoke", api.RevokeCertificateHandler)
+		r.Get("/api/admin/issuers", api.ListIssuersHandler)
+		r.Post("/api/admin/issuers/{wallet}/authorize", api.AuthorizeIssuerHandler)
+		r.Post("/api/admin/issuers/{wallet}/revoke", api.RevokeIssuerHandler)
+		r.Post("/api/admin/issuers/{wallet}/reject", api.RejectIssuerHandler)
+	})
+
 	// Protected routes
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware)
@@ -215,16 +316,19 @@ func main() {
 		// Certificate History (Protected)
 		r.Get("/api/certificate/history", api.GetCertificateHistoryHandler)
 
+		// Open Badges from other issuers
+		r.Post("/api/badges/import", api.ImportBadgeHandler)
+		r.Get("/api/badges", api.ListImportedBadgesHandler)
+
 		// Notification routes
-		r.Get("/api/notifications", api.GetNotificationsHandler(db.FirestoreClient))
-		r.Post("/api/notifications/:id/read", api.MarkNotificationReadHandler(db.FirestoreClient))
+		r.Get("/api/notifications", api.GetNotificationsHandler(db.Repos.Notifications))
+		r.Post("/api/notifications/:id/read", api.MarkNotificationReadHandler(db.Repos.Notifications))
 	})
 
 	// Instructor routes
 	r.Route("/api/instructor", func(r chi.Router) {
 		// Some instructor routes may be public for demo, OR secured
 		r.Get("/dashboard", api.InstructorDashboardHandler)
-		r.Post("/certificate/generate", api.GenerateCertificateHandler)
 		r.Post("/certificate/data", api.GetCertificateDataHandler)
 		r.Get("/analytics", api.InstructorAnalyticsHandler)
 		r.Post("/ai/question", api.GenerateQuestionHandler)
@@ -234,11 +338,37 @@ func main() {
 		r.Get("/stats", api.GetInstructorStatsHandler)
 	})
 
+	// Reconcile pending mints against whichever chain is active
+	blockchain.NewReconciler(
+		blockchain.GetClient(),
+		time.Duration(config.AppConfig.ReconcileIntervalMinutes)*time.Minute,
+		time.Duration(config.AppConfig.PendingMintTTLHours)*time.Hour,
+		config.AppConfig.ContractDeployBlock,
+	).Start(context.Background())
+
+	// Watch the platform wallet's balance; the mock ledger has none
+	if chain := blockchain.GetRealClient(); chain != nil {
+		blockchain.NewTreasuryMonitor(
+			chain,
+			time.Duration(config.AppConfig.TreasuryIntervalMinutes)*time.Minute,
+			config.AppConfig.TreasuryLowMints,
+		).Start(context.Background())
+	}
+
+	// Batch pending certificates under Merkle roots instead of minting each one
+	if config.AppConfig.AnchorMode == "merkle" {
+		blockchain.NewAnchorer(
+			blockchain.GetClient(),
+			time.Duration(config.AppConfig.AnchorIntervalMinutes)*time.Minute,
+			int(config.AppConfig.AnchorMaxBatch),
+		).Start(context.Background())
+	}
+
 	// Start blockchain services (Listener + Sync Worker)
 	go func() {
 		if config.AppConfig.BlockchainMode == "real" {
 			log.Println("🎧 Starting Blockchain Event Listener...")
-			listener, err := blockchain.NewEventListener(config.AppConfig.BlockchainRPC, config.AppConfig.ContractAddress)
+			listener, err := blockchain.NewEventListener(config.AppConfig.BlockchainRPC, config.AppConfig.ContractAddress, config.AppConfig.ContractDeployBlock, uint64(config.AppConfig.BlockConfirmations))
 			if err != nil {
 				log.Printf("❌ Failed to start listener: %v", err)
 			} else {
@@ -247,12 +377,24 @@ func main() {
 
 			// Start Background Sync Worker (Every 5 minutes)
 			log.Println("🔄 Starting Background Sync Worker...")
-			worker, err := blockchain.NewSyncWorker(config.AppConfig.BlockchainRPC, config.AppConfig.ContractAddress, 5*time.Minute)
+			worker, err := blockchain.NewSyncWorker(config.AppConfig.BlockchainRPC, config.AppConfig.ContractAddress, 5*time.Minute, config.AppConfig.ContractDeployBlock, uint64(config.AppConfig.BlockConfirmations))
 			if err != nil {
 				log.Printf("❌ Failed to start sync worker: %v", err)
 			} else {
 				worker.Start(context.Background())
 			}
+		} else if chain := blockchain.GetSimulatedChain(); chain != nil {
+			log.Println("🎧 Starting Blockchain Event Listener (simulated chain)...")
+			blockchain.NewEventListenerWithClient(chain.Client(), chain.ContractAddress(), 0, 0).Start(context.Background())
+
+			// Blocks are cheap here, so sync far more often than on a real network
+			log.Println("🔄 Starting Background Sync Worker (simulated chain)...")
+			blockchain.NewSyncWorkerWithClient(chain.Client(), chain.ContractAddress(), 30*time.Second, 0, 0).Start(context.Background())
+		} else {
+			// The mock ledger emits the registry's events too; index them like a chain that never reorgs
+			log.Println("🎧 Starting Blockchain Event Listener (mock ledger)...")
+			mock := blockchain.GetMockClient()
+			blockchain.NewEventListenerWithClient(mock, mock.ContractAddress(), 0, 0).Start(context.Background())
 		}
 	}()
 
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/config"
+	"cache-crew/cognify/internal/db"
+)
+
+// GetReconciliationReportHandler returns the latest pending-mint reconciliation report,
+// including on-chain certificates that have no metadata in storage
+func GetReconciliationReportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := db.Repos.SystemState.GetReconciliationReport(r.Context())
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{
+			"error": "Reconciliation has not run yet",
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load reconciliation report: %v", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to load reconciliation report",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// StartBackfillRequest optionally overrides where a backfill starts
+type StartBackfillRequest struct {
+	FromBlock *uint64 `json:"fromBlock,omitempty"` // Defaults to CONTRACT_DEPLOY_BLOCK
+}
+
+// StartBackfillHandler starts re-syncing registry events from the deployment block
+// (or fromBlock) in the background. Poll GetBackfillHandler for progress.
+func StartBackfillHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req StartBackfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+	fromBlock := config.AppConfig.ContractDeployBlock
+	if req.FromBlock != nil {
+		fromBlock = *req.FromBlock
+	}
+
+	job, err := blockchain.StartBackfill(fromBlock, uint64(config.AppConfig.BlockConfirmations))
+	if errors.Is(err, blockchain.ErrBackfillRunning) {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "A backfill is already running"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to start backfill: %v", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to start backfill"})
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// GetBackfillHandler returns the progress of the latest backfill
+func GetBackfillHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	job, err := db.Repos.SystemState.GetBackfillJob(r.Context())
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "No backfill has run yet"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load backfill job: %v", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load backfill job"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, job)
+}
+
+// ListDeploymentsHandler returns the registry deployments and which one is active.
+// RPC URLs are left out since they often embed provider API keys.
+func ListDeploymentsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	deployments := blockchain.Deployments()
+	for i := range deployments {
+		deployments[i].RPCURL = ""
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"deployments": deployments,
+		"active":      blockchain.ActiveChain(),
+	})
+}
+
+// GetTreasuryHandler returns the latest sample of the platform wallet's balance
+// and how many mints it still covers
+func GetTreasuryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := blockchain.GetTreasuryStatus()
+	if status == nil {
+		respondJSON(w, http.StatusNotFound, map[string]string{
+			"error": "Treasury monitoring needs a real or simulated blockchain and runs shortly after startup",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
@@ -69,16 +69,8 @@ func GetTrustAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	// Check DB availability
-	if db.FirestoreClient == nil {
-		respondJSON(w, http.StatusServiceUnavailable, map[string]string{
-			"error": "Database unavailable",
-		})
-		return
-	}
-
 	// Fetch certificate
-	doc, err := db.FirestoreClient.Collection("certificates").Doc(hash).Get(ctx)
+	cert, err := db.Repos.Certificates.Get(ctx, hash)
 	if err != nil {
 		respondJSON(w, http.StatusNotFound, map[string]string{
 			"error": "Certificate not found",
@@ -86,14 +78,6 @@ func GetTrustAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var cert models.Certificate
-	if err := doc.DataTo(&cert); err != nil {
-		respondJSON(w, http.StatusInternalServerError, map[string]string{
-			"error": "Failed to parse certificate",
-		})
-		return
-	}
-
 	// Initialize services
 	trustEngine := services.NewTrustEngine()
 	analyticsService := services.NewAnalyticsService()
@@ -108,15 +92,13 @@ func GetTrustAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
 	// Fetch issuer reputation
 	var issuerRep *models.IssuerReputation
 	if cert.InstructorWallet != "" {
-		if repDoc, err := db.FirestoreClient.Collection("issuer_reputation").Doc(cert.InstructorWallet).Get(ctx); err == nil {
-			var ir models.IssuerReputation
-			repDoc.DataTo(&ir)
-			issuerRep = &ir
+		if rep, err := db.Repos.Trust.GetIssuerReputation(ctx, cert.InstructorWallet); err == nil {
+			issuerRep = rep
 		}
 	}
 
 	// Calculate advanced score
-	score, breakdown := trustEngine.CalculateAdvancedScore(ctx, &cert, issuerRep, geoCount)
+	score, breakdown := trustEngine.CalculateAdvancedScore(ctx, cert, issuerRep, geoCount)
 
 	// Async: Snapshot history (fire and forget to avoid latency)
 	go func() {
@@ -177,27 +159,14 @@ func GetInstructorAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
 	wallet = strings.ToLower(wallet)
 	ctx := r.Context()
 
-	// Check DB availability
-	if db.FirestoreClient == nil {
-		respondJSON(w, http.StatusServiceUnavailable, map[string]string{
-			"error": "Database unavailable",
-		})
-		return
-	}
-
 	// Fetch instructor reputation
 	var reputation *models.IssuerReputation
-	if repDoc, err := db.FirestoreClient.Collection("issuer_reputation").Doc(wallet).Get(ctx); err == nil {
-		var ir models.IssuerReputation
-		repDoc.DataTo(&ir)
-		reputation = &ir
+	if rep, err := db.Repos.Trust.GetIssuerReputation(ctx, wallet); err == nil {
+		reputation = rep
 	}
 
 	// Fetch all certificates by this instructor
-	docs, err := db.FirestoreClient.Collection("certificates").
-		Where("instructor_wallet", "==", wallet).
-		Documents(ctx).GetAll()
-
+	certs, err := db.Repos.Certificates.ListByInstructor(ctx, wallet)
 	if err != nil {
 		respondJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "Failed to fetch certificates",
@@ -206,16 +175,13 @@ func GetInstructorAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Calculate statistics
-	totalCerts := len(docs)
+	totalCerts := len(certs)
 	activeCerts := 0
 	revokedCount := 0
 	totalTrustScore := 0
 	topCerts := []CertificateSummary{}
 
-	for _, doc := range docs {
-		var cert models.Certificate
-		doc.DataTo(&cert)
-
+	for _, cert := range certs {
 		if cert.Revoked {
 			revokedCount++
 		} else {
@@ -275,14 +241,6 @@ func UpdateInstructorReputationHandler(w http.ResponseWriter, r *http.Request) {
 	wallet = strings.ToLower(wallet)
 	ctx := r.Context()
 
-	// Check DB availability
-	if db.FirestoreClient == nil {
-		respondJSON(w, http.StatusServiceUnavailable, map[string]string{
-			"error": "Database unavailable",
-		})
-		return
-	}
-
 	// Trigger reputation update
 	analyticsService := services.NewAnalyticsService()
 	if err := analyticsService.UpdateIssuerReputation(ctx, wallet); err != nil {
@@ -12,7 +12,6 @@ import (
 	"cache-crew/cognify/internal/models"
 	"cache-crew/cognify/internal/services"
 
-	"cloud.google.com/go/firestore"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -69,42 +68,13 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// MOCK MODE CHECK
-	if db.FirestoreClient == nil {
-		log.Println("[LoginHandler] ⚠️ Firestore not initialized. Using MOCK login flow.")
-		// In mock mode, accept any password and return mock user
-		mockUser := models.User{
-			ID:          req.Email,
-			Email:       req.Email,
-			Name:        "Mock User",
-			Username:    req.Email,
-			Role:        req.Role,
-			XP:          500,
-			Level:       5,
-			AvatarEmoji: "🥷",
-			CreatedAt:   time.Now(),
-		}
-
-		respondJSON(w, http.StatusOK, AuthResponse{
-			Success: true,
-			Message: "Credentials validated (Mock Mode). Proceeding to wallet verification.",
-			User:    &mockUser,
-		})
-		return
-	}
-
 	// Check if user exists and get password
-	var user models.User
-	doc, err := db.FirestoreClient.Collection("users").Doc(req.Email).Get(r.Context())
+	user, err := db.Repos.Users.Get(r.Context(), req.Email)
 	if err != nil {
 		// Assume user not found or error
 		respondJSON(w, http.StatusUnauthorized, AuthResponse{Success: false, Message: "User not found. Please sign up."})
 		return
 	}
-	if err := doc.DataTo(&user); err != nil {
-		respondJSON(w, http.StatusInternalServerError, AuthResponse{Success: false, Message: "Database error"})
-		return
-	}
 
 	// Verify Password
 	if user.Password == "" {
@@ -142,7 +112,7 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, AuthResponse{
 		Success: true,
 		Message: "Credentials validated. OTP sent.",
-		User:    &user, // Optional: return user details if needed for next step
+		User:    user, // Optional: return user details if needed for next step
 	})
 }
 
@@ -180,49 +150,37 @@ func SignupHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create user with hashed password
-	newUser := map[string]interface{}{
-		"id":        req.Email,
-		"email":     req.Email,
-		"role":      req.Role,
-		"password":  string(hashedPwd),
-		"createdAt": time.Now(),
-		"updatedAt": time.Now(),
+	now := time.Now()
+	newUser := &models.User{
+		ID:       req.Email,
+		Email:    req.Email,
+		Role:     req.Role,
+		Password: string(hashedPwd),
 		// Add default fields to prevent nil issues later
-		"xp":          0,
-		"level":       1,
-		"avatarEmoji": "🥷",
-		"name":        userName,
-		"username":    userName,
+		XP:          0,
+		Level:       1,
+		AvatarEmoji: "🥷",
+		Name:        userName,
+		Username:    userName,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
-	if db.FirestoreClient != nil {
-		_, err = db.FirestoreClient.Collection("users").Doc(req.Email).Set(r.Context(), newUser)
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, AuthResponse{Success: false, Message: "Failed to create user"})
-			return
-		}
+	if err := db.Repos.Users.Save(r.Context(), newUser); err != nil {
+		respondJSON(w, http.StatusInternalServerError, AuthResponse{Success: false, Message: "Failed to create user"})
+		return
+	}
 
-		// Initialize user_stats for leaderboard
-		newStats := map[string]interface{}{
-			"userId":           req.Email,
-			"name":             newUser["name"],
-			"avatarEmoji":      newUser["avatarEmoji"],
-			"totalXp":          0,
-			"level":            1,
-			"battlesWon":       0,
-			"battlesPlayed":    0,
-			"coursesCompleted": 0,
-			"coursesEnrolled":  0,
-			"currentStreak":    0,
-			"longestStreak":    0,
-			"globalRank":       0,
-			"forumPosts":       0,
-			"forumComments":    0,
-		}
-		_, err = db.FirestoreClient.Collection("user_stats").Doc(req.Email).Set(r.Context(), newStats)
-		if err != nil {
-			log.Printf("Failed to initialize user stats for %s: %v", req.Email, err)
-		}
+	// Initialize user_stats for leaderboard
+	newStats := &models.UserStats{
+		UserID:      req.Email,
+		Name:        newUser.Name,
+		AvatarEmoji: newUser.AvatarEmoji,
+		TotalXP:     0,
+		Level:       1,
+	}
+	if err := db.Repos.UserStats.Save(r.Context(), newStats); err != nil {
+		log.Printf("Failed to initialize user stats for %s: %v", req.Email, err)
 	}
 
 	// Generate and Send OTP
@@ -368,36 +326,19 @@ func UpdateProfileHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update in Firestore
-	if db.FirestoreClient != nil {
-		updates := map[string]interface{}{
-			"updatedAt": time.Now(),
-		}
-		if req.Name != "" {
-			updates["name"] = req.Name
-		}
-		if req.Username != "" {
-			updates["username"] = req.Username
-		}
-		if req.Bio != "" {
-			updates["bio"] = req.Bio
-		}
-		if req.AvatarEmoji != "" {
-			updates["avatarEmoji"] = req.AvatarEmoji
-		}
-		if req.Institution != "" {
-			updates["institution"] = req.Institution
-		}
-
-		_, err := db.FirestoreClient.Collection("users").Doc(req.ID).Set(r.Context(), updates, firestore.MergeAll)
-
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, AuthResponse{
-				Success: false,
-				Message: "Failed to update profile",
-			})
-			return
-		}
+	err := db.Repos.Users.UpdateProfile(r.Context(), req.ID, db.UserProfileUpdate{
+		Name:        req.Name,
+		Username:    req.Username,
+		Bio:         req.Bio,
+		AvatarEmoji: req.AvatarEmoji,
+		Institution: req.Institution,
+	})
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, AuthResponse{
+			Success: false,
+			Message: "Failed to update profile",
+		})
+		return
 	}
 
 	respondJSON(w, http.StatusOK, AuthResponse{
@@ -407,31 +348,13 @@ func UpdateProfileHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func getOrCreateUser(ctx context.Context, email string) (*models.User, error) {
-	// If Firestore is not initialized, return mock user
-	if db.FirestoreClient == nil {
-		return &models.User{
-			ID:        "mock-user-id",
-			Email:     email,
-			Name:      "Mock User",
-			Role:      "student",
-			XP:        100,
-			Level:     1,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}, nil
-	}
-
 	// Try to get existing user
-	doc, err := db.FirestoreClient.Collection("users").Doc(email).Get(ctx)
+	user, err := db.Repos.Users.Get(ctx, email)
 	if err == nil {
-		var user models.User
-		if err := doc.DataTo(&user); err == nil {
-			return &user, nil
-		}
-		fmt.Printf("Error parsing user data: %v\n", err)
-	} else {
-		// Only log if it's NOT a "not found" error, although in Go Firestore, Not Found is an error (status code).
-		// simpler: fmt.Printf("Get User Error (might be new user): %v\n", err)
+		return user, nil
+	}
+	if err != db.ErrNotFound {
+		fmt.Printf("Error fetching user data: %v\n", err)
 	}
 
 	// Create new user
@@ -449,9 +372,8 @@ func getOrCreateUser(ctx context.Context, email string) (*models.User, error) {
 		UpdatedAt:   time.Now(),
 	}
 
-	_, err = db.FirestoreClient.Collection("users").Doc(email).Set(ctx, newUser)
-	if err != nil {
-		fmt.Printf("Error Creating User in Firestore: %v\n", err)
+	if err := db.Repos.Users.Save(ctx, newUser); err != nil {
+		fmt.Printf("Error Creating User: %v\n", err)
 		return nil, err
 	}
 
@@ -473,19 +395,14 @@ func extractNameFromEmail(email string) string {
 }
 
 func checkUserExists(ctx context.Context, email string) (bool, error) {
-	if db.FirestoreClient == nil {
-		return true, nil // Mock mode: assume user exists for login flow simplicity or handle differently
+	_, err := db.Repos.Users.Get(ctx, email)
+	if err == db.ErrNotFound {
+		return false, nil
 	}
-	doc, err := db.FirestoreClient.Collection("users").Doc(email).Get(ctx)
 	if err != nil {
-		// grpc.Code(err) == codes.NotFound ?
-		// simplistic check: if error contains "not found" or similar
-		// But firestore.Get returns error if not found.
-		// Actually best way is to check doc.Exists() but Get() errors if missing.
-		// Wait, go firestore Get() returns error if doc doesn't exist.
-		return false, nil
+		return false, err
 	}
-	return doc.Exists(), nil
+	return true, nil
 }
 
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -496,21 +413,11 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 
 // updateUsageStreak calculates and updates the user's daily streak
 func updateUsageStreak(ctx context.Context, userID string) error {
-	if db.FirestoreClient == nil {
-		return nil
-	}
-
-	statsRef := db.FirestoreClient.Collection("user_stats").Doc(userID)
-	doc, err := statsRef.Get(ctx)
+	stats, err := db.Repos.UserStats.Get(ctx, userID)
 	if err != nil {
 		return err
 	}
 
-	var stats models.UserStats
-	if err := doc.DataTo(&stats); err != nil {
-		return err
-	}
-
 	now := time.Now()
 	lastLogin := stats.LastLogin
 
@@ -538,11 +445,11 @@ func updateUsageStreak(ctx context.Context, userID string) error {
 	}
 
 	stats.LastLogin = now
-	_, err = statsRef.Set(ctx, stats, firestore.MergeAll)
+	err = db.Repos.UserStats.Save(ctx, stats)
 
 	// Check Achievements Async
 	if err == nil {
-		go CheckAndUnlockAchievements(context.Background(), userID, stats)
+		go CheckAndUnlockAchievements(context.Background(), userID, *stats)
 	}
 
 	return err
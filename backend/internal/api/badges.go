@@ -0,0 +1,486 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/middleware"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/services"
+	"cache-crew/cognify/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Open Badges 3.0 JSON-LD context; later 3.0.x revisions share the prefix
+const openBadgesContext = "https://purl.imsglobal.org/spec/ob/v3p0/context-3.0.3.json"
+
+// Proof format recorded for badges imported as compact JWTs
+const badgeProofJWT = "jwt"
+
+// GetBadgeHandler exports a minted or anchored certificate as an Open Badges 3.0
+// OpenBadgeCredential signed by the platform DID, for badge backpacks and LinkedIn.
+// GET /api/certificates/badge?hash=<certificate hash>
+func GetBadgeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	certHash := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(r.URL.Query().Get("hash")), "0x"))
+	if certHash == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Certificate hash is required"})
+		return
+	}
+
+	ctx := r.Context()
+	cert, err := db.Repos.Certificates.Get(ctx, certHash)
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Certificate not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load certificate"})
+		return
+	}
+	if !cert.IsMinted && cert.AnchorRoot == "" {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate is not on-chain yet"})
+		return
+	}
+	if cert.Revoked {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate has been revoked"})
+		return
+	}
+	if err := ensureStatusListEntry(ctx, cert); err != nil {
+		log.Printf("Failed to assign status list entry to %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to assign certificate status"})
+		return
+	}
+
+	// The course supplies the achievement's description and criteria when it still exists
+	var course *models.Course
+	if cert.CourseID != "" {
+		course, _ = db.Repos.Courses.Get(ctx, cert.CourseID)
+	}
+
+	badge := buildOpenBadge(cert, course)
+	if err := blockchain.SignCredential(badge); err != nil {
+		log.Printf("Failed to sign badge for %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to sign badge"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, badge)
+}
+
+// buildOpenBadge returns cert as an unsigned OpenBadgeCredential whose achievement
+// is the course. Like buildCredential, credentialStatus names the registry entry.
+func buildOpenBadge(cert *models.Certificate, course *models.Course) models.VerifiableCredential {
+	chain := credentialChain(cert)
+
+	name := cert.CourseName
+	if name == "" {
+		name = cert.CourseTitle
+	}
+	achievementID := "urn:cognify:course:" + cert.CourseID
+	if cert.CourseID == "" {
+		achievementID = "urn:cognify:achievement:" + cert.Hash
+	}
+	description := "Completed the Cognify course " + name
+	criteria := description
+	if course != nil {
+		if course.Description != "" {
+			description = course.Description
+		}
+		if course.LearningOutcomes != "" {
+			criteria = course.LearningOutcomes
+		}
+	}
+
+	achievement := map[string]interface{}{
+		"id":              achievementID,
+		"type":            []string{"Achievement"},
+		"achievementType": "Course",
+		"name":            name,
+		"description":     description,
+		"criteria":        map[string]interface{}{"narrative": criteria},
+	}
+	if len(cert.Skills) > 0 {
+		achievement["tag"] = cert.Skills
+	}
+
+	subject := map[string]interface{}{
+		"type":        []string{"AchievementSubject"},
+		"achievement": achievement,
+	}
+	if did := subjectDID(cert, chain); did != "" {
+		subject["id"] = did
+	}
+	if cert.Marks > 0 {
+		marksID := achievementID + ":marks"
+		achievement["resultDescription"] = []map[string]interface{}{{
+			"id":         marksID,
+			"type":       []string{"ResultDescription"},
+			"name":       "Marks",
+			"resultType": "Percent",
+		}}
+		subject["result"] = []map[string]interface{}{{
+			"type":              []string{"Result"},
+			"resultDescription": marksID,
+			"value":             strconv.FormatFloat(cert.Marks, 'f', -1, 64),
+		}}
+	}
+
+	return models.VerifiableCredential{
+		"@context": []string{
+			"https://www.w3.org/ns/credentials/v2",
+			openBadgesContext,
+			"https://w3id.org/security/suites/eip712sig-2021/v1",
+		},
+		"id":   "urn:cognify:badge:" + cert.Hash,
+		"type": []string{"VerifiableCredential", "OpenBadgeCredential"},
+		"issuer": map[string]interface{}{
+			"id":   blockchain.PlatformDID(),
+			"type": []string{"Profile"},
+			"name": "Cognify",
+		},
+		"name":              name,
+		"validFrom":         cert.IssuedAt.UTC().Format(time.RFC3339),
+		"credentialSubject": subject,
+		"credentialStatus":  registryStatus(cert, chain),
+	}
+}
+
+// ImportBadgeRequest carries an Open Badges 3.0 credential issued elsewhere:
+// either a JSON-LD credential with an EthereumEip712Signature2021 proof, or a
+// compact VC-JWT whose header holds the issuer's public key as a jwk
+type ImportBadgeRequest struct {
+	Credential json.RawMessage `json:"credential"`
+}
+
+// ImportBadgeHandler verifies an externally issued Open Badge and adds it to the
+// user's profile, where it is listed alongside their Cognify certificates
+func ImportBadgeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.JWTClaims)
+	if !ok || claims.UserID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req ImportBadgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Credential) == 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "A credential is required"})
+		return
+	}
+
+	// Step 1: Check the proof and recover the credential it covers
+	var (
+		credential models.VerifiableCredential
+		format     string
+		method     string
+		raw        string
+	)
+	var token string
+	if json.Unmarshal(req.Credential, &token) == nil {
+		doc, key, err := verifyBadgeJWT(token)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Badge signature is invalid: " + err.Error()})
+			return
+		}
+		credential, format, method, raw = doc, badgeProofJWT, key, token
+	} else {
+		decoder := json.NewDecoder(strings.NewReader(string(req.Credential)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&credential); err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid credential"})
+			return
+		}
+		proof, _ := credential["proof"].(map[string]interface{})
+		if proof["type"] != blockchain.CredentialProofType {
+			respondJSON(w, http.StatusBadRequest, map[string]string{
+				"error": "Unsupported proof; import the badge as a JWT or with an EthereumEip712Signature2021 proof",
+			})
+			return
+		}
+		if _, err := blockchain.CredentialSigner(credential); err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Badge signature is invalid"})
+			return
+		}
+		method, _ = proof["verificationMethod"].(string)
+		format, raw = blockchain.CredentialProofType, string(req.Credential)
+	}
+
+	// Step 2: Check it is a current Open Badge and read what to display
+	badge, err := parseOpenBadge(credential, time.Now())
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if strings.EqualFold(badge.IssuerID, blockchain.PlatformDID()) {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Cognify badges are already listed with your certificates"})
+		return
+	}
+
+	key := badge.CredentialID
+	if key == "" {
+		key = raw
+	}
+	badge.ID = utils.GenerateHash(claims.UserID + ":" + key)
+	badge.UserID = claims.UserID
+	badge.ProofFormat = format
+	badge.VerificationMethod = method
+	badge.Credential = raw
+	badge.ImportedAt = time.Now()
+
+	if err := db.Repos.ImportedBadges.Save(r.Context(), badge); err != nil {
+		log.Printf("Failed to save imported badge for %s: %v", claims.UserID, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to save badge"})
+		return
+	}
+
+	log.Printf("🏅 Imported badge %q from %s for %s", badge.AchievementName, badge.IssuerID, claims.UserID)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"badge":   badge,
+	})
+}
+
+// ListImportedBadgesHandler returns the badges the user imported, newest first
+func ListImportedBadgesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, ok := r.Context().Value(middleware.UserContextKey).(*services.JWTClaims)
+	if !ok || claims.UserID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	badges, err := db.Repos.ImportedBadges.ListByUser(r.Context(), claims.UserID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to fetch badges"})
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"badges":  badges,
+		"count":   len(badges),
+	})
+}
+
+// parseOpenBadge checks that credential is an Open Badges 3.0 achievement credential
+// valid at now, and returns what is shown for it
+func parseOpenBadge(credential models.VerifiableCredential, now time.Time) (*models.ImportedBadge, error) {
+	if !containsString(credentialStrings(credential["type"]), "OpenBadgeCredential") &&
+		!containsString(credentialStrings(credential["type"]), "AchievementCredential") {
+		return nil, errors.New("credential is not an OpenBadgeCredential")
+	}
+	hasContext := false
+	for _, context := range credentialStrings(credential["@context"]) {
+		hasContext = hasContext || strings.HasPrefix(context, "https://purl.imsglobal.org/spec/ob/v3p0/context")
+	}
+	if !hasContext {
+		return nil, errors.New("credential does not use the Open Badges 3.0 context")
+	}
+
+	issuerID := credentialID(credential["issuer"])
+	if issuerID == "" {
+		return nil, errors.New("badge has no issuer")
+	}
+	issuerName := ""
+	if issuer, ok := credential["issuer"].(map[string]interface{}); ok {
+		issuerName, _ = issuer["name"].(string)
+	}
+
+	subject, _ := credential["credentialSubject"].(map[string]interface{})
+	achievement, _ := subject["achievement"].(map[string]interface{})
+	name, _ := achievement["name"].(string)
+	if name == "" {
+		return nil, errors.New("badge has no achievement name")
+	}
+
+	// Data model 2.0 uses validFrom/validUntil; 1.1 credentials issuanceDate/expirationDate
+	issuedAt, err := credentialTime(credential, "validFrom", "issuanceDate")
+	if err != nil || issuedAt.IsZero() {
+		return nil, errors.New("badge has no valid issue date")
+	}
+	if issuedAt.After(now.Add(5 * time.Minute)) {
+		return nil, errors.New("badge is not valid yet")
+	}
+	expiresAt, err := credentialTime(credential, "validUntil", "expirationDate")
+	if err != nil {
+		return nil, errors.New("badge has an invalid expiry date")
+	}
+	if !expiresAt.IsZero() && expiresAt.Before(now) {
+		return nil, errors.New("badge has expired")
+	}
+
+	badge := &models.ImportedBadge{
+		IssuerID:        issuerID,
+		IssuerName:      issuerName,
+		AchievementName: name,
+		Skills:          credentialStrings(achievement["tag"]),
+		IssuedAt:        issuedAt,
+		ExpiresAt:       expiresAt,
+	}
+	badge.CredentialID, _ = credential["id"].(string)
+	badge.AchievementDescription, _ = achievement["description"].(string)
+	badge.ImageURL = credentialID(achievement["image"])
+	if criteria, ok := achievement["criteria"].(map[string]interface{}); ok {
+		badge.Criteria, _ = criteria["narrative"].(string)
+		if badge.Criteria == "" {
+			badge.Criteria = credentialID(criteria)
+		}
+	}
+	return badge, nil
+}
+
+// verifyBadgeJWT checks a compact VC-JWT against the public key in its jwk header
+// and returns the credential it carries with a description of the key
+func verifyBadgeJWT(token string) (models.VerifiableCredential, string, error) {
+	keyDescription := ""
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		jwk, ok := t.Header["jwk"].(map[string]interface{})
+		if !ok {
+			return nil, errors.New("only JWTs carrying their key in a jwk header can be verified")
+		}
+		key, err := parseJWK(jwk)
+		if err != nil {
+			return nil, err
+		}
+		raw, _ := json.Marshal(jwk)
+		keyDescription = "jwk:" + string(raw)
+		return key, nil
+	}, jwt.WithValidMethods([]string{"ES256", "ES384", "ES512", "EdDSA", "RS256", "RS384", "RS512", "PS256"}), jwt.WithJSONNumber())
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The credential is either the vc claim or, in Open Badges 3.0, the payload itself
+	credential := models.VerifiableCredential(claims)
+	if vc, ok := claims["vc"].(map[string]interface{}); ok {
+		credential = vc
+	}
+	if iss, ok := claims["iss"].(string); ok && iss != credentialID(credential["issuer"]) {
+		return nil, "", errors.New("iss does not match the credential issuer")
+	}
+	return credential, keyDescription, nil
+}
+
+// parseJWK returns the public key of an EC, OKP (Ed25519) or RSA JSON Web Key
+func parseJWK(jwk map[string]interface{}) (interface{}, error) {
+	field := func(name string) ([]byte, error) {
+		value, _ := jwk[name].(string)
+		if value == "" {
+			return nil, fmt.Errorf("jwk has no %s", name)
+		}
+		return base64.RawURLEncoding.DecodeString(value)
+	}
+
+	switch jwk["kty"] {
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk["crv"] {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %v", jwk["crv"])
+		}
+		x, err := field("x")
+		if err != nil {
+			return nil, err
+		}
+		y, err := field("y")
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("jwk point is not on its curve")
+		}
+		return key, nil
+	case "OKP":
+		if jwk["crv"] != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %v", jwk["crv"])
+		}
+		x, err := field("x")
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	case "RSA":
+		n, err := field("n")
+		if err != nil {
+			return nil, err
+		}
+		e, err := field("e")
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %v", jwk["kty"])
+	}
+}
+
+// credentialID returns a JSON-LD reference: the string itself, or an object's id
+func credentialID(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	if obj, ok := value.(map[string]interface{}); ok {
+		id, _ := obj["id"].(string)
+		return id
+	}
+	return ""
+}
+
+// credentialTime parses the first of keys present in credential as an RFC 3339
+// time. It returns the zero time when none is present.
+func credentialTime(credential models.VerifiableCredential, keys ...string) (time.Time, error) {
+	for _, key := range keys {
+		if value, ok := credential[key].(string); ok {
+			return time.Parse(time.RFC3339, value)
+		}
+	}
+	return time.Time{}, nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
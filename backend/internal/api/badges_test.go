@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestVerifyBadgeRejectsTamperedSubject(t *testing.T) {
+	cert := newSDJWTCertificate(t)
+	badge := buildOpenBadge(cert, &models.Course{Description: "Engines", LearningOutcomes: "Program the engine"})
+	if err := blockchain.SignCredential(badge); err != nil {
+		t.Fatal(err)
+	}
+
+	result := verifyCredential(context.Background(), reparseCredential(t, badge))
+	if !result.Verified || !result.SignatureValid {
+		t.Fatalf("issued badge: %+v", result)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(subject map[string]interface{})
+	}{
+		{"achievement renamed", func(subject map[string]interface{}) {
+			subject["achievement"].(map[string]interface{})["name"] = "Quantum Computing"
+		}},
+		{"result raised", func(subject map[string]interface{}) {
+			subject["result"].([]interface{})[0].(map[string]interface{})["value"] = "100"
+		}},
+		{"holder swapped", func(subject map[string]interface{}) {
+			subject["id"] = "did:pkh:eip155:31337:0x2222222222222222222222222222222222222222"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := reparseCredential(t, badge)
+			tt.tamper(tampered["credentialSubject"].(map[string]interface{}))
+			result := verifyCredential(context.Background(), tampered)
+			if result.Verified || result.SignatureValid {
+				t.Errorf("result = %+v, want the signature rejected", result)
+			}
+		})
+	}
+}
+
+// signedBadgeJWT returns an external Open Badge as an ES256 VC-JWT carrying its key in a jwk header
+func signedBadgeJWT(t *testing.T, credential map[string]interface{}) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims(credential))
+	token.Header["jwk"] = map[string]interface{}{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestVerifyBadgeJWTRejectsTamperedSubject(t *testing.T) {
+	issued := signedBadgeJWT(t, map[string]interface{}{
+		"@context":  []string{"https://www.w3.org/ns/credentials/v2", openBadgesContext},
+		"id":        "urn:uuid:5f6a1c1e-0000-4000-8000-000000000001",
+		"type":      []string{"VerifiableCredential", "OpenBadgeCredential"},
+		"issuer":    map[string]interface{}{"id": "https://badges.example.edu/issuer", "type": []string{"Profile"}, "name": "Example University"},
+		"validFrom": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		"credentialSubject": map[string]interface{}{
+			"type":        []string{"AchievementSubject"},
+			"achievement": map[string]interface{}{"id": "urn:example:achievement:1", "type": []string{"Achievement"}, "name": "Intro to Go"},
+		},
+	})
+
+	credential, _, err := verifyBadgeJWT(issued)
+	if err != nil {
+		t.Fatalf("verifyBadgeJWT: %v", err)
+	}
+	badge, err := parseOpenBadge(credential, time.Now())
+	if err != nil || badge.AchievementName != "Intro to Go" || badge.IssuerName != "Example University" {
+		t.Fatalf("parseOpenBadge = %+v, %v", badge, err)
+	}
+
+	// The payload is edited and re-encoded under the original signature
+	parts := strings.Split(issued, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	claims["credentialSubject"].(map[string]interface{})["achievement"].(map[string]interface{})["name"] = "Advanced Go"
+	payload, err = json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+
+	if _, _, err := verifyBadgeJWT(tampered); err == nil {
+		t.Error("verifyBadgeJWT accepted a badge with a tampered credentialSubject")
+	}
+}
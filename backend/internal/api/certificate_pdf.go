@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/services"
+	"cache-crew/cognify/internal/utils"
+)
+
+// maxCertificatePDFSize bounds uploaded certificate PDFs
+const maxCertificatePDFSize = 10 << 20
+
+// maxVerifyRequestSize bounds a verification request body: a maximum-size PDF
+// base64-encoded in JSON (4 bytes per 3), plus room for the other fields or the
+// multipart framing
+const maxVerifyRequestSize = (maxCertificatePDFSize+2)/3*4 + 1<<20
+
+// GetCertificatePDFHandler renders a minted or anchored certificate as a PDF sealed
+// by the platform wallet: the hash, issuer DID and signature are embedded in the
+// XMP metadata and a QR code, and every claim the hash covers is printed.
+// GET /api/certificates/pdf?hash=<certificate hash>
+func GetCertificatePDFHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	certHash := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(r.URL.Query().Get("hash")), "0x"))
+	if certHash == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Certificate hash is required"})
+		return
+	}
+
+	cert, err := db.Repos.Certificates.Get(r.Context(), certHash)
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Certificate not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load certificate"})
+		return
+	}
+	if !cert.IsMinted && cert.AnchorRoot == "" {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate is not on-chain yet"})
+		return
+	}
+	if cert.Revoked {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate has been revoked"})
+		return
+	}
+	if cert.HashVersion == 0 {
+		// A legacy hash covers no claims, so a PDF of it could not be checked
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate predates claim hashing and cannot be sealed"})
+		return
+	}
+
+	issuer, signature, err := blockchain.SignCertificateSeal(cert.Hash)
+	if err != nil {
+		log.Printf("Failed to seal certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to sign certificate"})
+		return
+	}
+	seal := services.CertificateSeal{Hash: cert.Hash, HashVersion: cert.HashVersion, Issuer: issuer, Signature: signature}
+
+	pdf, err := services.GenerateCertificatePDF(cert.Claims(), "", cert.Skills, cert.Message, seal)
+	if err != nil {
+		log.Printf("Failed to render certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to generate certificate PDF"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="certificate-%s.pdf"`, cert.Hash[:12]))
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
+
+// decodeVerifyRequest reads a verification request: JSON, or a multipart form
+// with the certificate PDF in its "pdf" field. It returns the PDF, if any.
+func decodeVerifyRequest(w http.ResponseWriter, r *http.Request, req *VerifyCertificateRequest) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxVerifyRequestSize)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("pdf")
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return io.ReadAll(io.LimitReader(file, maxCertificatePDFSize))
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, err
+	}
+	if req.PDFFile == "" {
+		return nil, nil
+	}
+	encoded := req.PDFFile
+	if i := strings.Index(encoded, ";base64,"); strings.HasPrefix(encoded, "data:") && i >= 0 {
+		encoded = encoded[i+len(";base64,"):]
+	}
+	pdf, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(pdf) > maxCertificatePDFSize {
+		return nil, fmt.Errorf("certificate PDF is larger than %d bytes", maxCertificatePDFSize)
+	}
+	return pdf, nil
+}
+
+// checkCertificatePDF reads the seal and printed claims of an uploaded certificate
+// PDF. The seal must be the platform's signature over the sealed hash, and the
+// printed claims must hash to it. On success the request is pointed at the sealed
+// hash and claims; otherwise the returned response reports the tampering.
+func checkCertificatePDF(ctx context.Context, pdf []byte, req *VerifyCertificateRequest) (*models.VerificationResponse, error) {
+	seal, claims, err := services.ReadCertificatePDF(pdf)
+	if err != nil {
+		return nil, err
+	}
+	req.CertificateHash = seal.Hash
+
+	if err := blockchain.VerifyCertificateSeal(seal.Hash, seal.Issuer, seal.Signature); err != nil {
+		log.Printf("⚠️ Certificate PDF seal for %s rejected: %v", seal.Hash, err)
+		return &models.VerificationResponse{
+			Tampered: true,
+			Message:  "Certificate PDF seal is not a valid platform signature; it has been tampered with",
+		}, nil
+	}
+
+	printedHash, err := utils.GenerateCanonicalHash(claims)
+	if err != nil || printedHash != seal.Hash {
+		response := &models.VerificationResponse{
+			Tampered: true,
+			Message:  "Printed certificate details do not match its sealed hash; the PDF has been tampered with",
+		}
+		if stored, err := db.Repos.Certificates.Get(ctx, seal.Hash); err == nil {
+			response.TamperedFields = claimDifferences(claims, stored.Claims())
+		}
+		return response, nil
+	}
+
+	req.Certificate = &claims
+	return nil, nil
+}
+
+// claimDifferences names the claims, by JSON key, that differ between a and b
+func claimDifferences(a, b models.CertificateClaims) []string {
+	var fields []string
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			fields = append(fields, strings.Split(va.Type().Field(i).Tag.Get("json"), ",")[0])
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecodeVerifyRequestPDFSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{"largest PDF", maxCertificatePDFSize, false},
+		{"too large", maxCertificatePDFSize + 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdf := bytes.Repeat([]byte{'%'}, tt.size)
+			body := `{"certificateHash":"0xabc","pdfFile":"data:application/pdf;base64,` + base64.StdEncoding.EncodeToString(pdf) + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/verify", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			var decoded VerifyCertificateRequest
+			got, err := decodeVerifyRequest(httptest.NewRecorder(), req, &decoded)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeVerifyRequest error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (len(got) != tt.size || decoded.CertificateHash != "0xabc") {
+				t.Errorf("decoded %d bytes and hash %q", len(got), decoded.CertificateHash)
+			}
+		})
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"cache-crew/cognify/internal/models"
 	"cache-crew/cognify/internal/services"
 
-	"cloud.google.com/go/firestore"
 	"github.com/google/generative-ai-go/genai"
 )
 
@@ -64,13 +63,11 @@ func CompleteLessonHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// 1. Fetch course and level
-	courseDoc, err := db.FirestoreClient.Collection("courses").Doc(req.CourseID).Get(ctx)
+	course, err := db.Repos.Courses.Get(ctx, req.CourseID)
 	if err != nil {
 		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Course not found"})
 		return
 	}
-	var course models.Course
-	courseDoc.DataTo(&course)
 
 	// Find level index
 	var currentLevel *models.CourseLevel
@@ -93,12 +90,10 @@ func CompleteLessonHandler(w http.ResponseWriter, r *http.Request) {
 	var answersForAI []map[string]interface{}
 
 	for _, ans := range req.Answers {
-		qDoc, err := db.FirestoreClient.Collection("questions").Doc(ans.QuestionID).Get(ctx)
+		q, err := db.Repos.Questions.Get(ctx, ans.QuestionID)
 		if err != nil {
 			continue
 		}
-		var q models.Question
-		qDoc.DataTo(&q)
 
 		isCorrect := q.CorrectIndex == ans.SelectedIndex
 		if isCorrect {
@@ -132,12 +127,9 @@ func CompleteLessonHandler(w http.ResponseWriter, r *http.Request) {
 	weakPoints, strongPoints, confidenceScore, aiFeedback := analyzeWithAI(ctx, questionsForAI, answersForAI)
 
 	// 5. Update UserStats
-	statsRef := db.FirestoreClient.Collection("user_stats").Doc(req.UserID)
-	statsDoc, err := statsRef.Get(ctx)
-
 	var stats models.UserStats
-	if err == nil {
-		statsDoc.DataTo(&stats)
+	if existing, err := db.Repos.UserStats.Get(ctx, req.UserID); err == nil {
+		stats = *existing
 	} else {
 		// Create new stats if doesn't exist
 		stats = models.UserStats{
@@ -169,7 +161,7 @@ func CompleteLessonHandler(w http.ResponseWriter, r *http.Request) {
 	stats.WeeklyXP[todayStr] += totalXPGained
 
 	// Save stats to user_stats collection
-	_, err = statsRef.Set(ctx, stats)
+	err = db.Repos.UserStats.Save(ctx, &stats)
 	if err != nil {
 		log.Printf("ERROR: Failed to save user_stats: %v", err)
 	} else {
@@ -177,11 +169,7 @@ func CompleteLessonHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Also update the users collection XP (for profile display)
-	userRef := db.FirestoreClient.Collection("users").Doc(req.UserID)
-	_, err = userRef.Update(ctx, []firestore.Update{
-		{Path: "xp", Value: stats.TotalXP},
-		{Path: "level", Value: stats.Level},
-	})
+	err = db.Repos.Users.SetProgress(ctx, req.UserID, stats.TotalXP, stats.Level)
 	if err != nil {
 		log.Printf("Warning: Could not update users XP: %v (user may not exist in users collection)", err)
 	} else {
@@ -190,7 +178,6 @@ func CompleteLessonHandler(w http.ResponseWriter, r *http.Request) {
 
 	// 6. Update Enrollment progress
 	enrollmentID := fmt.Sprintf("%s_%s", req.UserID, req.CourseID)
-	enrollRef := db.FirestoreClient.Collection("enrollments").Doc(enrollmentID)
 
 	progress := float64(levelIndex+1) / float64(len(course.Levels))
 	if progress > 1 {
@@ -202,29 +189,29 @@ func CompleteLessonHandler(w http.ResponseWriter, r *http.Request) {
 	// But for simplicity/MVP, we just set it.
 	// To strictly increment CoursesCompleted only once, we should check if it WAS NOT completed before.
 
-	wasCompleted := false
-	enrollSnap, err := enrollRef.Get(ctx)
-	if err == nil {
-		var prevEnroll models.Enrollment
-		enrollSnap.DataTo(&prevEnroll)
-		wasCompleted = prevEnroll.Completed
+	enrollment := &models.Enrollment{
+		ID:        enrollmentID,
+		UserID:    req.UserID,
+		CourseID:  req.CourseID,
+		StartedAt: time.Now(),
+	}
+	if prevEnroll, err := db.Repos.Enrollments.Get(ctx, enrollmentID); err == nil {
+		enrollment = prevEnroll
 	}
+	wasCompleted := enrollment.Completed
 
 	isNowCompleted := progress >= 1
 
-	enrollRef.Set(ctx, map[string]interface{}{
-		"userId":    req.UserID,
-		"courseId":  req.CourseID,
-		"progress":  progress,
-		"completed": isNowCompleted,
-		"updatedAt": time.Now(),
-	}, firestore.MergeAll)
+	enrollment.Progress = progress
+	enrollment.Completed = isNowCompleted
+	enrollment.UpdatedAt = time.Now()
+	if err := db.Repos.Enrollments.Save(ctx, enrollment); err != nil {
+		log.Printf("Warning: Could not update enrollment %s: %v", enrollmentID, err)
+	}
 
 	// Update CoursesCompleted count if newly completed
 	if isNowCompleted && !wasCompleted {
-		_, err = statsRef.Update(ctx, []firestore.Update{
-			{Path: "coursesCompleted", Value: firestore.Increment(1)},
-		})
+		err = db.Repos.UserStats.Increment(ctx, req.UserID, db.StatCoursesCompleted, 1)
 		if err == nil {
 			// Update local stats struct to reflect change for achievement check
 			stats.CoursesCompleted++
@@ -351,23 +338,16 @@ func mergeStringSlices(existing, new []string, maxLen int) []string {
 
 // updateGlobalRanks recalculates ranks based on XP
 func updateGlobalRanks(ctx context.Context) {
-	if db.FirestoreClient == nil {
-		return
-	}
-
 	// Fetch all user_stats ordered by XP
-	iter := db.FirestoreClient.Collection("user_stats").OrderBy("totalXp", firestore.Desc).Documents(ctx)
-	docs, err := iter.GetAll()
+	stats, err := db.Repos.UserStats.ListByXP(ctx, 0)
 	if err != nil {
 		log.Printf("Failed to fetch stats for ranking: %v", err)
 		return
 	}
 
 	// Update ranks
-	for i, doc := range docs {
-		doc.Ref.Update(ctx, []firestore.Update{
-			{Path: "globalRank", Value: i + 1},
-		})
+	for i, st := range stats {
+		db.Repos.UserStats.SetGlobalRank(ctx, st.UserID, i+1)
 	}
-	log.Printf("Updated global ranks for %d users", len(docs))
+	log.Printf("Updated global ranks for %d users", len(stats))
 }
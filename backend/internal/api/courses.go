@@ -8,11 +8,9 @@ import (
 
 	"cache-crew/cognify/internal/db"
 	"cache-crew/cognify/internal/models"
-
-	"google.golang.org/api/iterator"
 )
 
-// Mock courses for when Firestore is not available
+// Mock courses for when no courses have been created yet
 var mockCourses = []models.Course{
 	{
 		ID:             "1",
@@ -149,14 +147,11 @@ func EnrollCourseHandler(w http.ResponseWriter, r *http.Request) {
 		StartedAt: time.Now(),
 	}
 
-	if db.FirestoreClient != nil {
-		_, err := db.FirestoreClient.Collection("enrollments").Doc(enrollment.ID).Set(r.Context(), enrollment)
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Failed to enroll in course",
-			})
-			return
-		}
+	if err := db.Repos.Enrollments.Save(r.Context(), enrollment); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to enroll in course",
+		})
+		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -181,28 +176,15 @@ func GetUserEnrollmentsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if db.FirestoreClient == nil {
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"success":     true,
-			"enrollments": []map[string]interface{}{},
-		})
-		return
-	}
-
-	ctx := r.Context()
-	iter := db.FirestoreClient.Collection("enrollments").Where("userId", "==", userID).Documents(ctx)
-	docs, err := iter.GetAll()
+	enrollments, err := db.Repos.Enrollments.ListByUser(r.Context(), userID)
 	if err != nil {
 		respondJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "Failed to fetch enrollments",
 		})
 		return
 	}
-
-	var enrollments []map[string]interface{}
-	for _, doc := range docs {
-		data := doc.Data()
-		enrollments = append(enrollments, data)
+	if enrollments == nil {
+		enrollments = []models.Enrollment{}
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -212,27 +194,9 @@ func GetUserEnrollmentsHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func getCourses(ctx context.Context) ([]models.Course, error) {
-	if db.FirestoreClient == nil {
-		return mockCourses, nil
-	}
-
-	iter := db.FirestoreClient.Collection("courses").Documents(ctx)
-	var courses []models.Course
-
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return mockCourses, nil // Fallback to mock on error
-		}
-
-		var course models.Course
-		if err := doc.DataTo(&course); err != nil {
-			continue
-		}
-		courses = append(courses, course)
+	courses, err := db.Repos.Courses.List(ctx)
+	if err != nil {
+		return mockCourses, nil // Fallback to mock on error
 	}
 
 	if len(courses) == 0 {
@@ -244,16 +208,7 @@ func getCourses(ctx context.Context) ([]models.Course, error) {
 
 // getCourseByID returns a specific course by ID (Internal helper)
 func getCourseByID(ctx context.Context, id string) (*models.Course, error) {
-	if db.FirestoreClient == nil {
-		for _, c := range mockCourses {
-			if c.ID == id {
-				return &c, nil
-			}
-		}
-		return nil, nil
-	}
-
-	doc, err := db.FirestoreClient.Collection("courses").Doc(id).Get(ctx)
+	course, err := db.Repos.Courses.Get(ctx, id)
 	if err != nil {
 		// Fallback to mock
 		for _, c := range mockCourses {
@@ -264,12 +219,7 @@ func getCourseByID(ctx context.Context, id string) (*models.Course, error) {
 		return nil, err
 	}
 
-	var course models.Course
-	if err := doc.DataTo(&course); err != nil {
-		return nil, err
-	}
-
-	return &course, nil
+	return course, nil
 }
 
 // CreateCourseHandler creates a new course
@@ -312,14 +262,11 @@ func CreateCourseHandler(w http.ResponseWriter, r *http.Request) {
 		course.Duration = "10h"
 	}
 
-	if db.FirestoreClient != nil {
-		_, err := db.FirestoreClient.Collection("courses").Doc(course.ID).Set(r.Context(), course)
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Failed to create course",
-			})
-			return
-		}
+	if err := db.Repos.Courses.Save(r.Context(), &course); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create course",
+		})
+		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -365,14 +312,11 @@ func UpdateCourseHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if db.FirestoreClient != nil {
-		_, err := db.FirestoreClient.Collection("courses").Doc(course.ID).Set(r.Context(), course)
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Failed to update course",
-			})
-			return
-		}
+	if err := db.Repos.Courses.Save(r.Context(), &course); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update course",
+		})
+		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -411,28 +355,5 @@ func GetInstructorCoursesHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func getInstructorCourses(ctx context.Context, instructorID string) ([]models.Course, error) {
-	if db.FirestoreClient == nil {
-		return mockCourses, nil
-	}
-
-	iter := db.FirestoreClient.Collection("courses").Where("instructorId", "==", instructorID).Documents(ctx)
-	var courses []models.Course
-
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		var course models.Course
-		if err := doc.DataTo(&course); err != nil {
-			continue
-		}
-		courses = append(courses, course)
-	}
-
-	return courses, nil
+	return db.Repos.Courses.ListByInstructor(ctx, instructorID)
 }
@@ -0,0 +1,330 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// credentialStatusType is the credentialStatus type pointing at a registry entry
+const credentialStatusType = "CognifyRegistryStatus"
+
+// GetCredentialHandler exports a minted or anchored certificate as a W3C Verifiable
+// Credential signed by the platform DID.
+// GET /api/certificates/credential?hash=<certificate hash>
+func GetCredentialHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	certHash := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(r.URL.Query().Get("hash")), "0x"))
+	if certHash == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Certificate hash is required"})
+		return
+	}
+
+	cert, err := db.Repos.Certificates.Get(r.Context(), certHash)
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Certificate not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load certificate"})
+		return
+	}
+	if !cert.IsMinted && cert.AnchorRoot == "" {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate is not on-chain yet"})
+		return
+	}
+	if cert.Revoked {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate has been revoked"})
+		return
+	}
+	if err := ensureStatusListEntry(r.Context(), cert); err != nil {
+		log.Printf("Failed to assign status list entry to %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to assign certificate status"})
+		return
+	}
+
+	credential := buildCredential(cert)
+	if err := blockchain.SignCredential(credential); err != nil {
+		log.Printf("Failed to sign credential for %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to sign credential"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, credential)
+}
+
+// buildCredential returns cert as an unsigned verifiable credential. The subject
+// carries the certificate's claims and credentialStatus names the registry entry
+// (or anchored Merkle root) that proves it and the status list bit revoking it.
+func buildCredential(cert *models.Certificate) models.VerifiableCredential {
+	chain := credentialChain(cert)
+	claims := cert.Claims()
+	subject := map[string]interface{}{
+		"studentId":        claims.StudentID,
+		"studentName":      claims.StudentName,
+		"courseId":         claims.CourseID,
+		"courseName":       claims.CourseName,
+		"marks":            strconv.FormatFloat(claims.Marks, 'f', -1, 64), // EIP-712 has no fractional numbers
+		"instructorWallet": claims.InstructorWallet,
+		"instructorName":   claims.InstructorName,
+		"academicDNA":      claims.AcademicDNA,
+		"hashVersion":      claims.Version,
+	}
+	if did := subjectDID(cert, chain); did != "" {
+		subject["id"] = did
+	}
+
+	return models.VerifiableCredential{
+		"@context": []string{
+			"https://www.w3.org/ns/credentials/v2",
+			"https://w3id.org/security/suites/eip712sig-2021/v1",
+		},
+		"id":                "urn:cognify:certificate:" + cert.Hash,
+		"type":              []string{"VerifiableCredential", "CognifyCertificateCredential"},
+		"issuer":            blockchain.PlatformDID(),
+		"validFrom":         cert.IssuedAt.UTC().Format(time.RFC3339),
+		"credentialSubject": subject,
+		"credentialStatus":  registryStatus(cert, chain),
+	}
+}
+
+// credentialChain is the deployment holding cert's registry entry or anchored root
+func credentialChain(cert *models.Certificate) models.ChainRef {
+	if cert.Chain != nil {
+		return *cert.Chain
+	}
+	return blockchain.ActiveChain()
+}
+
+// subjectDID returns the did:pkh DID of the certificate holder's wallet, or "" without one
+func subjectDID(cert *models.Certificate, chain models.ChainRef) string {
+	if !common.IsHexAddress(cert.WalletAddress) {
+		return ""
+	}
+	return fmt.Sprintf("did:pkh:eip155:%d:%s", chain.ChainID, common.HexToAddress(cert.WalletAddress).Hex())
+}
+
+// registryStatus is the credentialStatus naming cert's registry entry, the
+// Merkle root and proof when it was anchored in a batch, and its status list bit
+func registryStatus(cert *models.Certificate, chain models.ChainRef) map[string]interface{} {
+	status := map[string]interface{}{
+		"id":              fmt.Sprintf("eip155:%d:%s#0x%s", chain.ChainID, chain.Contract, cert.Hash),
+		"type":            credentialStatusType,
+		"chainId":         chain.ChainID,
+		"registry":        chain.Contract,
+		"certificateHash": "0x" + cert.Hash,
+	}
+	if cert.AnchorRoot != "" {
+		status["merkleRoot"] = "0x" + strings.TrimPrefix(cert.AnchorRoot, "0x")
+		if len(cert.MerkleProof) > 0 {
+			status["merkleProof"] = cert.MerkleProof
+		}
+	}
+	addStatusListEntry(status, cert)
+	return status
+}
+
+// VerifyCredentialHandler checks a verifiable credential or Open Badge issued by the platform:
+// its signature, its status in the registry named by credentialStatus, and that the
+// claims it carries are the ones anchored there.
+// POST /api/credentials/verify with the credential as the body
+func VerifyCredentialHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var credential models.VerifiableCredential
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&credential); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid credential"})
+		return
+	}
+
+	result := verifyCredential(r.Context(), credential)
+	logVerification(r.Context(), strings.TrimPrefix(result.CertificateHash, "0x"), result.Verified, r.RemoteAddr, r.UserAgent())
+	respondJSON(w, http.StatusOK, result)
+}
+
+// verifyCredential runs the signature, status and claim checks on a credential.
+// Revocation is read from the registry and, for a BitstringStatusListEntry, from
+// the platform status list it names.
+func verifyCredential(ctx context.Context, credential models.VerifiableCredential) models.CredentialVerification {
+	result := models.CredentialVerification{}
+	result.Issuer = credentialID(credential["issuer"])
+
+	// Step 1: Signature by the platform DID
+	if err := blockchain.VerifyCredentialProof(credential); err != nil {
+		log.Printf("Credential proof rejected: %v", err)
+		result.Message = "Credential signature is invalid or not from the platform"
+		return result
+	}
+	result.SignatureValid = true
+
+	// Step 2: Status in the registry deployment the credential names
+	status, _ := credential["credentialStatus"].(map[string]interface{})
+	subject, _ := credential["credentialSubject"].(map[string]interface{})
+	if (status["type"] != credentialStatusType && status["type"] != statusListEntryType) || subject == nil {
+		result.Message = "Credential has no registry status"
+		return result
+	}
+	chainID, _ := strconv.ParseInt(fmt.Sprint(status["chainId"]), 10, 64)
+	registry, _ := status["registry"].(string)
+	certHash, _ := status["certificateHash"].(string)
+	result.CertificateHash = certHash
+	result.Chain = &models.ChainRef{ChainID: chainID, Contract: registry}
+
+	client, err := blockchain.ClientFor(result.Chain)
+	if err != nil {
+		log.Printf("No registry client for credential status %v: %v", status["id"], err)
+		result.Message = "Credential names an unknown registry deployment"
+		return result
+	}
+	record, err := client.VerifyCertificate(certHash)
+	if err != nil {
+		log.Printf("Blockchain verification error: %v", err)
+		result.Message = "Blockchain verification failed"
+		return result
+	}
+
+	result.ProofType = "direct"
+	if !record.Exists {
+		// Not minted on its own; the status may prove it through an anchored root
+		root, _ := status["merkleRoot"].(string)
+		req := VerifyCertificateRequest{MerkleRoot: root, MerkleProof: credentialStrings(status["merkleProof"])}
+		if _, rootRecord := anchoredRoot(client, certHash, req, nil); rootRecord != nil {
+			record = rootRecord
+			result.ProofType = "merkle"
+		}
+	} else {
+		// A certificate's own entry must be for the subject's wallet and Academic DNA
+		wallet := subjectWallet(subject)
+		dna, hasDNA := subject["academicDNA"]
+		if (wallet != common.Address{} && record.Owner != wallet) || (hasDNA && record.AcademicDNA != dna) {
+			result.Message = "Credential subject does not match the on-chain record"
+			return result
+		}
+	}
+	if !record.Exists {
+		result.ProofType = ""
+		result.Message = "Certificate is not anchored in the registry"
+		return result
+	}
+	result.Anchored = true
+	result.Revoked = record.Revoked
+
+	if status["type"] == statusListEntryType {
+		listURL, _ := status["statusListCredential"].(string)
+		index, err := strconv.Atoi(fmt.Sprint(status["statusListIndex"]))
+		listRevoked := false
+		if err == nil {
+			listRevoked, err = statusListRevoked(ctx, listURL, index)
+		}
+		if err != nil {
+			log.Printf("Credential status list check failed: %v", err)
+			result.Message = "Certificate status could not be checked"
+			return result
+		}
+		result.Revoked = result.Revoked || listRevoked
+	}
+
+	// Step 3: The subject's claims must hash to the anchored certificate hash
+	hashVersion, _ := strconv.Atoi(fmt.Sprint(subject["hashVersion"]))
+	if hashVersion > 0 {
+		claims, err := subjectClaims(credential, subject, hashVersion)
+		if err != nil {
+			result.Message = "Credential subject is malformed"
+			return result
+		}
+		claimsHash, err := utils.GenerateCanonicalHash(claims)
+		if err != nil || claimsHash != strings.ToLower(strings.TrimPrefix(certHash, "0x")) {
+			result.Message = "Credential claims do not match the anchored certificate hash"
+			return result
+		}
+		result.ClaimsVerified = true
+	}
+
+	result.Verified = !result.Revoked
+	if result.Revoked {
+		result.Message = "⚠️ CERTIFICATE REVOKED"
+	}
+	return result
+}
+
+// subjectClaims rebuilds the certificate claims a credential subject carries
+func subjectClaims(credential models.VerifiableCredential, subject map[string]interface{}, version int) (models.CertificateClaims, error) {
+	validFrom, err := time.Parse(time.RFC3339, fmt.Sprint(credential["validFrom"]))
+	if err != nil {
+		return models.CertificateClaims{}, err
+	}
+	marks, err := strconv.ParseFloat(fmt.Sprint(subject["marks"]), 64)
+	if err != nil {
+		return models.CertificateClaims{}, err
+	}
+	str := func(key string) string {
+		s, _ := subject[key].(string)
+		return s
+	}
+
+	wallet := ""
+	if address := subjectWallet(subject); address != (common.Address{}) {
+		wallet = strings.ToLower(address.Hex())
+	}
+	return models.CertificateClaims{
+		Version:          version,
+		StudentID:        str("studentId"),
+		StudentName:      str("studentName"),
+		CourseID:         str("courseId"),
+		CourseName:       str("courseName"),
+		Marks:            marks,
+		WalletAddress:    wallet,
+		InstructorWallet: str("instructorWallet"),
+		InstructorName:   str("instructorName"),
+		IssuedAt:         validFrom.Unix(),
+		AcademicDNA:      str("academicDNA"),
+	}, nil
+}
+
+// subjectWallet returns the wallet in a did:pkh subject id, or the zero address
+func subjectWallet(subject map[string]interface{}) common.Address {
+	id, _ := subject["id"].(string)
+	account := id[strings.LastIndex(id, ":")+1:]
+	if !strings.HasPrefix(id, "did:pkh:eip155:") || !common.IsHexAddress(account) {
+		return common.Address{}
+	}
+	return common.HexToAddress(account)
+}
+
+// credentialStrings converts a JSON string or array of strings, as decoded into a credential
+func credentialStrings(value interface{}) []string {
+	if s, ok := value.(string); ok {
+		return []string{s}
+	}
+	items, _ := value.([]interface{})
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/models"
+)
+
+// reparseCredential round-trips a credential through JSON the way VerifyCredentialHandler decodes it
+func reparseCredential(t *testing.T, credential models.VerifiableCredential) models.VerifiableCredential {
+	t.Helper()
+	raw, err := json.Marshal(credential)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed models.VerifiableCredential
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&parsed); err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestVerifyCredentialRejectsTamperedSubject(t *testing.T) {
+	cert := newSDJWTCertificate(t)
+	credential := buildCredential(cert)
+	if err := blockchain.SignCredential(credential); err != nil {
+		t.Fatal(err)
+	}
+
+	result := verifyCredential(context.Background(), reparseCredential(t, credential))
+	if !result.Verified || !result.SignatureValid || !result.ClaimsVerified {
+		t.Fatalf("issued credential: %+v", result)
+	}
+
+	// setSubject returns a copy of the issued credential with one subject field changed
+	setSubject := func(key string, value interface{}) models.VerifiableCredential {
+		tampered := reparseCredential(t, credential)
+		tampered["credentialSubject"].(map[string]interface{})[key] = value
+		return tampered
+	}
+
+	const badSignature = "Credential signature is invalid or not from the platform"
+	tests := []struct {
+		name       string
+		credential func() models.VerifiableCredential
+		message    string
+	}{
+		{"marks raised", func() models.VerifiableCredential { return setSubject("marks", "99") }, badSignature},
+		{"name changed", func() models.VerifiableCredential { return setSubject("studentName", "Charles Babbage") }, badSignature},
+		{"subject wallet swapped", func() models.VerifiableCredential {
+			return setSubject("id", "did:pkh:eip155:31337:0x2222222222222222222222222222222222222222")
+		}, badSignature},
+		// Even with a valid platform proof, the claims must hash to the anchored certificate
+		{"re-signed with other claims", func() models.VerifiableCredential {
+			tampered := setSubject("marks", "99")
+			delete(tampered, "proof")
+			if err := blockchain.SignCredential(tampered); err != nil {
+				t.Fatal(err)
+			}
+			return reparseCredential(t, tampered)
+		}, "Credential claims do not match the anchored certificate hash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := verifyCredential(context.Background(), tt.credential())
+			if result.Verified || result.ClaimsVerified || result.Message != tt.message {
+				t.Errorf("result = %+v, want rejected with %q", result, tt.message)
+			}
+		})
+	}
+}
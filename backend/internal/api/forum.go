@@ -8,12 +8,9 @@ import (
 
 	"cache-crew/cognify/internal/db"
 	"cache-crew/cognify/internal/models"
-
-	"cloud.google.com/go/firestore"
-	"google.golang.org/api/iterator"
 )
 
-// Mock posts for when Firestore is not available
+// Mock posts for when the forum has no posts yet
 var mockPosts = []models.Post{
 	{
 		ID:           "1",
@@ -95,42 +92,11 @@ func GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func getCommentsForPost(ctx context.Context, postID string) ([]models.Comment, error) {
-	if db.FirestoreClient == nil {
-		// Return empty in mock mode
+	comments, err := db.Repos.Comments.ListByPost(ctx, postID)
+	if err != nil || comments == nil {
+		// Log error and return empty
 		return []models.Comment{}, nil
 	}
-
-	// Query without OrderBy to avoid requiring a composite index
-	query := db.FirestoreClient.Collection("comments").Where("postId", "==", postID)
-	iter := query.Documents(ctx)
-	var comments []models.Comment
-
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			// Log error and return empty
-			return []models.Comment{}, nil
-		}
-
-		var comment models.Comment
-		if err := doc.DataTo(&comment); err != nil {
-			continue
-		}
-		comments = append(comments, comment)
-	}
-
-	// Sort by createdAt in memory
-	for i := 0; i < len(comments)-1; i++ {
-		for j := i + 1; j < len(comments); j++ {
-			if comments[j].CreatedAt.Before(comments[i].CreatedAt) {
-				comments[i], comments[j] = comments[j], comments[i]
-			}
-		}
-	}
-
 	return comments, nil
 }
 
@@ -176,39 +142,22 @@ func CreatePostHandler(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:    time.Now(),
 	}
 
-	if db.FirestoreClient != nil {
-		_, err := db.FirestoreClient.Collection("posts").Doc(post.ID).Set(r.Context(), post)
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Failed to create post",
-			})
-			return
-		}
+	if err := db.Repos.Posts.Save(r.Context(), post); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create post",
+		})
+		return
 	}
 
 	// ---------------------------------------------------------
 	// UPDATE USER STATS (ForumPosts)
 	// ---------------------------------------------------------
-	if db.FirestoreClient != nil {
-		statsRef := db.FirestoreClient.Collection("user_stats").Doc(req.AuthorID)
-
-		// 1. Increment ForumPosts
-		_, err := statsRef.Update(r.Context(), []firestore.Update{
-			{Path: "forumPosts", Value: firestore.Increment(1)},
-		})
+	// 1. Increment ForumPosts
+	err := db.Repos.UserStats.Increment(r.Context(), req.AuthorID, db.StatForumPosts, 1)
 
-		// 2. Check Achievements (async)
-		if err == nil {
-			go func() {
-				// We need to fetch fresh stats to check achievements
-				doc, err := statsRef.Get(context.Background())
-				if err == nil {
-					var stats models.UserStats
-					doc.DataTo(&stats)
-					CheckAndUnlockAchievements(context.Background(), req.AuthorID, stats)
-				}
-			}()
-		}
+	// 2. Check Achievements (async)
+	if err == nil {
+		go refreshAchievements(req.AuthorID)
 	}
 
 	respondJSON(w, http.StatusCreated, map[string]interface{}{
@@ -237,17 +186,8 @@ func VotePostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// For mock mode
-	if db.FirestoreClient == nil {
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"success": true,
-			"message": "Vote recorded (mock mode)",
-		})
-		return
-	}
-
 	// Get post
-	doc, err := db.FirestoreClient.Collection("posts").Doc(req.PostID).Get(r.Context())
+	post, err := db.Repos.Posts.Get(r.Context(), req.PostID)
 	if err != nil {
 		respondJSON(w, http.StatusNotFound, map[string]string{
 			"error": "Post not found",
@@ -255,14 +195,6 @@ func VotePostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var post models.Post
-	if err := doc.DataTo(&post); err != nil {
-		respondJSON(w, http.StatusInternalServerError, map[string]string{
-			"error": "Failed to parse post",
-		})
-		return
-	}
-
 	// Update vote
 	if req.VoteType == "up" {
 		// Remove from downvoted if present
@@ -282,8 +214,7 @@ func VotePostHandler(w http.ResponseWriter, r *http.Request) {
 	post.Downvotes = len(post.DownvotedBy)
 
 	// Save
-	_, err = db.FirestoreClient.Collection("posts").Doc(req.PostID).Set(r.Context(), post)
-	if err != nil {
+	if err := db.Repos.Posts.Save(r.Context(), post); err != nil {
 		respondJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "Failed to update vote",
 		})
@@ -331,44 +262,24 @@ func AddCommentHandler(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:   time.Now(),
 	}
 
-	if db.FirestoreClient != nil {
-		_, err := db.FirestoreClient.Collection("comments").Doc(comment.ID).Set(r.Context(), comment)
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Failed to add comment",
-			})
-			return
-		}
-
-		// Increment the post's commentCount
-		postRef := db.FirestoreClient.Collection("posts").Doc(req.PostID)
-		_, err = postRef.Update(r.Context(), []firestore.Update{
-			{Path: "commentCount", Value: firestore.Increment(1)},
+	if err := db.Repos.Comments.Save(r.Context(), comment); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to add comment",
 		})
-		if err != nil {
-			// Log but don't fail - comment was saved
-		}
+		return
+	}
 
-		// ---------------------------------------------------------
-		// UPDATE USER STATS (ForumComments) & CHECK ACHIEVEMENTS
-		// ---------------------------------------------------------
-		statsRef := db.FirestoreClient.Collection("user_stats").Doc(req.AuthorID)
-		_, err = statsRef.Update(r.Context(), []firestore.Update{
-			{Path: "forumComments", Value: firestore.Increment(1)},
-		})
+	// Increment the post's commentCount (don't fail - comment was saved)
+	_ = db.Repos.Posts.IncrementCommentCount(r.Context(), req.PostID)
 
-		// Check Achievements Async
-		if err == nil {
-			go func() {
-				// We need to fetch fresh stats to check achievements
-				doc, err := statsRef.Get(context.Background())
-				if err == nil {
-					var stats models.UserStats
-					doc.DataTo(&stats)
-					CheckAndUnlockAchievements(context.Background(), req.AuthorID, stats)
-				}
-			}()
-		}
+	// ---------------------------------------------------------
+	// UPDATE USER STATS (ForumComments) & CHECK ACHIEVEMENTS
+	// ---------------------------------------------------------
+	err := db.Repos.UserStats.Increment(r.Context(), req.AuthorID, db.StatForumComments, 1)
+
+	// Check Achievements Async
+	if err == nil {
+		go refreshAchievements(req.AuthorID)
 	}
 
 	respondJSON(w, http.StatusCreated, map[string]interface{}{
@@ -395,17 +306,11 @@ func IncrementViewHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if db.FirestoreClient != nil {
-		postRef := db.FirestoreClient.Collection("posts").Doc(req.PostID)
-		_, err := postRef.Update(r.Context(), []firestore.Update{
-			{Path: "viewCount", Value: firestore.Increment(1)},
+	if err := db.Repos.Posts.IncrementViewCount(r.Context(), req.PostID); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to increment view count",
 		})
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Failed to increment view count",
-			})
-			return
-		}
+		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -433,17 +338,8 @@ func VoteCommentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// For mock mode
-	if db.FirestoreClient == nil {
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"success": true,
-			"message": "Vote recorded (mock mode)",
-		})
-		return
-	}
-
 	// Get comment
-	doc, err := db.FirestoreClient.Collection("comments").Doc(req.CommentID).Get(r.Context())
+	comment, err := db.Repos.Comments.Get(r.Context(), req.CommentID)
 	if err != nil {
 		respondJSON(w, http.StatusNotFound, map[string]string{
 			"error": "Comment not found",
@@ -451,14 +347,6 @@ func VoteCommentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var comment models.Comment
-	if err := doc.DataTo(&comment); err != nil {
-		respondJSON(w, http.StatusInternalServerError, map[string]string{
-			"error": "Failed to parse comment",
-		})
-		return
-	}
-
 	// Update vote
 	if req.VoteType == "up" {
 		// Remove from downvoted if present
@@ -478,8 +366,7 @@ func VoteCommentHandler(w http.ResponseWriter, r *http.Request) {
 	comment.Downvotes = len(comment.DownvotedBy)
 
 	// Save
-	_, err = db.FirestoreClient.Collection("comments").Doc(req.CommentID).Set(r.Context(), comment)
-	if err != nil {
+	if err := db.Repos.Comments.Save(r.Context(), comment); err != nil {
 		respondJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "Failed to update vote",
 		})
@@ -494,43 +381,11 @@ func VoteCommentHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func getPosts(ctx context.Context, courseID string) ([]models.Post, error) {
-	if db.FirestoreClient == nil {
-		if courseID != "" {
-			var filtered []models.Post
-			for _, p := range mockPosts {
-				if p.CourseID == courseID {
-					filtered = append(filtered, p)
-				}
-			}
-			return filtered, nil
-		}
+	posts, err := db.Repos.Posts.List(ctx, courseID)
+	if err != nil {
 		return mockPosts, nil
 	}
 
-	query := db.FirestoreClient.Collection("posts").OrderBy("createdAt", 1)
-	if courseID != "" {
-		query = db.FirestoreClient.Collection("posts").Where("courseId", "==", courseID).OrderBy("createdAt", 1)
-	}
-
-	iter := query.Documents(ctx)
-	var posts []models.Post
-
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return mockPosts, nil
-		}
-
-		var post models.Post
-		if err := doc.DataTo(&post); err != nil {
-			continue
-		}
-		posts = append(posts, post)
-	}
-
 	if len(posts) == 0 {
 		return mockPosts, nil
 	}
@@ -538,6 +393,15 @@ func getPosts(ctx context.Context, courseID string) ([]models.Post, error) {
 	return posts, nil
 }
 
+// refreshAchievements re-reads a user's stats and checks for newly unlocked achievements
+func refreshAchievements(userID string) {
+	// We need to fetch fresh stats to check achievements
+	stats, err := db.Repos.UserStats.Get(context.Background(), userID)
+	if err == nil {
+		CheckAndUnlockAchievements(context.Background(), userID, *stats)
+	}
+}
+
 func generateID() string {
 	return time.Now().Format("20060102150405") + "-" + randomString(6)
 }
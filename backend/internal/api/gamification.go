@@ -11,14 +11,9 @@ import (
 	"cache-crew/cognify/internal/models"
 	"cache-crew/cognify/internal/services"
 
-	"cloud.google.com/go/firestore"
 	"golang.org/x/crypto/bcrypt"
-	"google.golang.org/api/iterator"
 )
 
-// dummy use of iterator to prevent compile errors
-var _ = iterator.Done
-
 // Predefined achievements
 var achievements = []models.Achievement{
 	{
@@ -106,26 +101,15 @@ var achievements = []models.Achievement{
 
 // CheckAndUnlockAchievements checks if the user has unlocked any new achievements
 func CheckAndUnlockAchievements(ctx context.Context, userID string, stats models.UserStats) {
-	if db.FirestoreClient == nil {
+	// 1. Get already unlocked achievements
+	unlocked, err := db.Repos.Achievements.ListByUser(ctx, userID)
+	if err != nil {
+		log.Printf("Error unlocking achievements: %v", err)
 		return
 	}
-
-	// 1. Get already unlocked achievements
-	iter := db.FirestoreClient.Collection("user_achievements").Where("userId", "==", userID).Documents(ctx)
 	unlockedIDs := make(map[string]bool)
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			log.Printf("Error unlocking achievements: %v", err)
-			return
-		}
-		var ua models.UserAchievement
-		if err := doc.DataTo(&ua); err == nil {
-			unlockedIDs[ua.AchievementID] = true
-		}
+	for _, ua := range unlocked {
+		unlockedIDs[ua.AchievementID] = true
 	}
 
 	// 2. Check requirements
@@ -177,7 +161,7 @@ func CheckAndUnlockAchievements(ctx context.Context, userID string, stats models
 				AchievementID: ach.ID,
 				UnlockedAt:    time.Now(),
 			}
-			_, err := db.FirestoreClient.Collection("user_achievements").Doc(ua.ID).Set(ctx, ua)
+			err := db.Repos.Achievements.Save(ctx, &ua)
 			if err != nil {
 				log.Printf("Failed to save achievement %s: %v", ach.ID, err)
 				continue
@@ -195,9 +179,7 @@ func CheckAndUnlockAchievements(ctx context.Context, userID string, stats models
 			// Let's assume this function is called AFTER caller saves their specific updates,
 			// AND we only update XP here.
 
-			_, err = db.FirestoreClient.Collection("user_stats").Doc(userID).Update(ctx, []firestore.Update{
-				{Path: "totalXp", Value: firestore.Increment(ach.XPReward)},
-			})
+			err = db.Repos.UserStats.Increment(ctx, userID, db.StatTotalXP, ach.XPReward)
 			if err != nil {
 				log.Printf("Failed to award XP for achievement %s: %v", ach.ID, err)
 			}
@@ -237,19 +219,13 @@ func GetUserStatsHandler(w http.ResponseWriter, r *http.Request) {
 		userID = "default"
 	}
 
-	// Try to get from Firestore
-	if db.FirestoreClient != nil {
-		doc, err := db.FirestoreClient.Collection("user_stats").Doc(userID).Get(r.Context())
-		if err == nil {
-			var stats models.UserStats
-			if err := doc.DataTo(&stats); err == nil {
-				respondJSON(w, http.StatusOK, map[string]interface{}{
-					"success": true,
-					"stats":   stats,
-				})
-				return
-			}
-		}
+	// Try to get from storage
+	if stats, err := db.Repos.UserStats.Get(r.Context(), userID); err == nil {
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"stats":   stats,
+		})
+		return
 	}
 
 	// Return mock stats if not found
@@ -291,24 +267,9 @@ func GetUserAchievementsHandler(w http.ResponseWriter, r *http.Request) {
 		userID = "default"
 	}
 
-	var userAchievements []models.UserAchievement
-
-	if db.FirestoreClient != nil {
-		iter := db.FirestoreClient.Collection("user_achievements").Where("userId", "==", userID).Documents(r.Context())
-		for {
-			doc, err := iter.Next()
-			if err == iterator.Done {
-				break
-			}
-			if err != nil {
-				break
-			}
-			var ua models.UserAchievement
-			if err := doc.DataTo(&ua); err == nil {
-				log.Printf("Found achievement for user %s: %s", userID, ua.AchievementID)
-				userAchievements = append(userAchievements, ua)
-			}
-		}
+	userAchievements, err := db.Repos.Achievements.ListByUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching achievements for user %s: %v", userID, err)
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -326,54 +287,33 @@ func GetLeaderboardHandler(w http.ResponseWriter, r *http.Request) {
 
 	var leaderboard []models.LeaderboardEntry
 
-	if db.FirestoreClient != nil {
-		iter := db.FirestoreClient.Collection("user_stats").
-			OrderBy("totalXp", firestore.Desc).
-			Limit(10).
-			Documents(r.Context())
-
-		rank := 1
-		for {
-			doc, err := iter.Next()
-			if err == iterator.Done {
-				break
-			}
-			if err != nil {
-				log.Printf("Error fetching leaderboard: %v", err)
-				break
-			}
-			var stats models.UserStats
-			if err := doc.DataTo(&stats); err == nil {
-				// Use default name if missing (e.g. legacy data)
-				name := stats.Name
-				if name == "" {
-					name = "User"
-				}
-				avatar := stats.AvatarEmoji
-				if avatar == "" {
-					avatar = "👤"
-				}
-
-				leaderboard = append(leaderboard, models.LeaderboardEntry{
-					Rank:        rank,
-					UserID:      stats.UserID,
-					Name:        name,
-					AvatarEmoji: avatar,
-					TotalXP:     stats.TotalXP,
-					Level:       stats.Level,
-					BattlesWon:  stats.BattlesWon,
-				})
-				rank++
-			}
+	topStats, err := db.Repos.UserStats.ListByXP(r.Context(), 10)
+	if err != nil {
+		log.Printf("Error fetching leaderboard: %v", err)
+	}
+	for i, stats := range topStats {
+		// Use default name if missing (e.g. legacy data)
+		name := stats.Name
+		if name == "" {
+			name = "User"
+		}
+		avatar := stats.AvatarEmoji
+		if avatar == "" {
+			avatar = "👤"
 		}
-	} else {
-		// Fallback to mock if DB not ready (or keep existing mock)
-		// But better to return empty than confusing mock?
-		// User asked to "propagate ... in backend".
-		// Use empty list if DB up but empty.
+
+		leaderboard = append(leaderboard, models.LeaderboardEntry{
+			Rank:        i + 1,
+			UserID:      stats.UserID,
+			Name:        name,
+			AvatarEmoji: avatar,
+			TotalXP:     stats.TotalXP,
+			Level:       stats.Level,
+			BattlesWon:  stats.BattlesWon,
+		})
 	}
 
-	// Just in case no DB or empty, provide empty list or handle gracefully
+	// Provide empty list rather than null when there are no stats yet
 	if leaderboard == nil {
 		leaderboard = []models.LeaderboardEntry{}
 	}
@@ -391,14 +331,6 @@ func SeedDataHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if db.FirestoreClient == nil {
-		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{
-			"success": false,
-			"error":   "Firestore not initialized",
-		})
-		return
-	}
-
 	ctx := r.Context()
 	userID := r.URL.Query().Get("userId")
 	if userID == "" {
@@ -406,11 +338,11 @@ func SeedDataHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Seed user_achievements - unlock "streak_7" achievement
-	_, err := db.FirestoreClient.Collection("user_achievements").Doc("streak7_"+userID).Set(ctx, map[string]interface{}{
-		"id":            "streak7_" + userID,
-		"userId":        userID,
-		"achievementId": "streak_7",
-		"unlockedAt":    time.Now(),
+	err := db.Repos.Achievements.Save(ctx, &models.UserAchievement{
+		ID:            "streak7_" + userID,
+		UserID:        userID,
+		AchievementID: "streak_7",
+		UnlockedAt:    time.Now(),
 	})
 	if err != nil {
 		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{
@@ -423,13 +355,15 @@ func SeedDataHandler(w http.ResponseWriter, r *http.Request) {
 	// Hash default password
 	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 
-	// Update User in Firestore
-	_, err = db.FirestoreClient.Collection("users").Doc(userID).Set(ctx, map[string]interface{}{
-		"xp":        2450,
-		"level":     5,
-		"password":  string(hashedPwd), // Set default password
-		"updatedAt": time.Now(),
-	}, firestore.MergeAll)
+	// Update User record
+	user, err := db.Repos.Users.Get(ctx, userID)
+	if err == nil {
+		user.XP = 2450
+		user.Level = 5
+		user.Password = string(hashedPwd) // Set default password
+		user.UpdatedAt = time.Now()
+		err = db.Repos.Users.Save(ctx, user)
+	}
 	if err != nil {
 		// Log error but don't return, as user might not exist yet, and stats seeding is more critical.
 		// In a real app, you might handle this more robustly.
@@ -448,23 +382,23 @@ func SeedDataHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Seed user_stats for the requested user
-	_, err = db.FirestoreClient.Collection("user_stats").Doc(userID).Set(ctx, map[string]interface{}{
-		"userId":           userID,
-		"name":             "Naruto Uzumaki", // Default name for seeded user
-		"avatarEmoji":      "🦊",              // Default avatar
-		"totalXp":          2450,
-		"level":            5,
-		"battlesWon":       12,
-		"battlesPlayed":    18,
-		"coursesCompleted": 2,
-		"coursesEnrolled":  4,
-		"currentStreak":    7,
-		"longestStreak":    14,
-		"globalRank":       42,
-		"forumPosts":       5,
-		"forumComments":    12,
-		"weeklyXp":         weeklyXP,
-		"categoryStats": map[string]int{
+	err = db.Repos.UserStats.Save(ctx, &models.UserStats{
+		UserID:           userID,
+		Name:             "Naruto Uzumaki", // Default name for seeded user
+		AvatarEmoji:      "🦊",              // Default avatar
+		TotalXP:          2450,
+		Level:            5,
+		BattlesWon:       12,
+		BattlesPlayed:    18,
+		CoursesCompleted: 2,
+		CoursesEnrolled:  4,
+		CurrentStreak:    7,
+		LongestStreak:    14,
+		GlobalRank:       42,
+		ForumPosts:       5,
+		ForumComments:    12,
+		WeeklyXP:         weeklyXP,
+		CategoryStats: map[string]int{
 			"Flutter":  40,
 			"Python":   25,
 			"Data Sci": 20,
@@ -477,16 +411,6 @@ func SeedDataHandler(w http.ResponseWriter, r *http.Request) {
 
 	// NOTE: Bulk seeding removed as mock data is already propagated.
 
-	// Update user's XP in users collection
-	_, err = db.FirestoreClient.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
-		{Path: "xp", Value: 2450},
-		{Path: "level", Value: 5},
-	})
-	if err != nil {
-		// User might not exist, try Set instead
-		// Just log and continue
-	}
-
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"success": true,
 		"message": "Test data seeded successfully for user: " + userID,
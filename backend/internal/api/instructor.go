@@ -2,14 +2,18 @@ package api
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 	"time"
 
+	"cache-crew/cognify/internal/blockchain"
 	"cache-crew/cognify/internal/config"
 	"cache-crew/cognify/internal/db"
 	"cache-crew/cognify/internal/models"
 	"cache-crew/cognify/internal/services"
 	"cache-crew/cognify/internal/utils"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // GenerateCertificateRequest represents a certificate generation request
@@ -25,12 +29,20 @@ type GenerateCertificateRequest struct {
 
 // GenerateCertificateHandler prepares certificate metadata for frontend minting
 // This handler does NOT mint on the blockchain - the frontend does via MetaMask
+// The calling instructor is recorded as the issuer, among the hashed claims.
 func GenerateCertificateHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	// Set by WalletAuthMiddleware
+	instructorWallet, _ := r.Context().Value("wallet").(string)
+	if instructorWallet == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	var req GenerateCertificateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondJSON(w, http.StatusBadRequest, map[string]string{
@@ -55,25 +67,18 @@ func GenerateCertificateHandler(w http.ResponseWriter, r *http.Request) {
 
 	issuedAt := time.Now()
 
-	// Generate certificate hash using utility
-	certHash := utils.GenerateCertificateHash(req.UserID, req.CourseID, issuedAt)
-
 	// Generate Academic DNA for the student
-	platformSecret := config.AppConfig.PlatformSecret
-	if platformSecret == "" {
-		platformSecret = "COGNIFY_PLATFORM_SECRET_V1" // Fallback for dev
-	}
-	academicDNA := utils.GenerateAcademicDNA(req.WalletAddress, req.UserID, issuedAt, platformSecret)
+	academicDNA := utils.GenerateAcademicDNA(req.WalletAddress, req.UserID, issuedAt, config.AppConfig.PlatformSecret)
 
 	// Create a PENDING certificate record (not yet minted)
 	certificate := &models.Certificate{
-		Hash:              certHash,
 		StudentID:         req.UserID,
 		StudentName:       req.UserName,
 		CourseID:          req.CourseID,
 		CourseName:        req.CourseName,
 		Marks:             req.Marks,
 		WalletAddress:     req.WalletAddress,
+		InstructorWallet:  instructorWallet,
 		IssuedAt:          issuedAt,
 		BlockchainTx:      "", // Will be filled when frontend confirms minting
 		TrustScore:        50, // Initial trust score
@@ -82,19 +87,61 @@ func GenerateCertificateHandler(w http.ResponseWriter, r *http.Request) {
 		IsMinted:          false, // Pending state
 	}
 
+	// The hash commits to every claim on the certificate
+	if err := hashCertificate(certificate); err != nil {
+		log.Printf("Failed to hash certificate claims: %v", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to hash certificate",
+		})
+		return
+	}
+	certHash := certificate.Hash
+
+	// Reserve the certificate's bit in the published revocation status lists
+	if err := services.NewStatusListService().Assign(r.Context(), certificate); err != nil {
+		log.Printf("Failed to assign status list entry to %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to assign certificate status",
+		})
+		return
+	}
+
 	// Calculate initial trust score
 	trustEngine := services.NewTrustEngine()
 	certificate.TrustScore = trustEngine.CalculateTrustScore(r.Context(), certificate)
 
-	// Save PENDING certificate to Firestore
-	if db.FirestoreClient != nil {
-		_, err := db.FirestoreClient.Collection("certificates").Doc(certHash).Set(r.Context(), certificate)
+	// Save PENDING certificate
+	if err := db.Repos.Certificates.Save(r.Context(), certificate); err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to save pending certificate",
+		})
+		return
+	}
+
+	message := "Certificate prepared. Use MetaMask to mint on blockchain."
+
+	// Wallet mints need the platform's EIP-712 authorization; anchored batches don't
+	var mintAuth *models.MintAuthorization
+	if config.AppConfig.AnchorMode != "merkle" && common.IsHexAddress(req.WalletAddress) {
+		auth, err := authorizeMint(r.Context(), certificate)
 		if err != nil {
+			log.Printf("Failed to authorize mint of %s: %v", certHash, err)
 			respondJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Failed to save pending certificate",
+				"error": "Failed to sign mint authorization",
 			})
 			return
 		}
+		mintAuth = auth
+	}
+
+	if config.AppConfig.AnchorMode == "merkle" {
+		// The anchorer includes the certificate in the next Merkle batch
+		message = "Certificate prepared. It will be anchored on-chain with the next batch."
+	} else if blockchain.GetSimulatedChain() != nil && req.WalletAddress != "" {
+		// On the simulated chain there is no MetaMask to sign with, so the platform
+		// wallet mints instead. The event listener flips the record to minted.
+		go mintOnSimulatedChain(certHash, req.WalletAddress, academicDNA)
+		message = "Certificate prepared. Minting on the simulated chain."
 	}
 
 	// Return metadata for frontend to use in MetaMask transaction
@@ -105,12 +152,36 @@ func GenerateCertificateHandler(w http.ResponseWriter, r *http.Request) {
 		"academicDNA":     academicDNA,
 		"studentWallet":   req.WalletAddress,
 		"issuedAt":        issuedAt.Unix(),
+		"claims":          certificate.Claims(),
 		"status":          "pending_mint",
-		"message":         "Certificate prepared. Use MetaMask to mint on blockchain.",
+		"authorization":   mintAuth,
+		"message":         message,
 		"data":            certificate,
 	})
 }
 
+// hashCertificate sets the certificate's hash to the canonical hash of its claims
+func hashCertificate(cert *models.Certificate) error {
+	cert.HashVersion = models.CertificateHashVersion
+	hash, err := utils.GenerateCanonicalHash(cert.Claims())
+	if err != nil {
+		return err
+	}
+	cert.Hash = hash
+	return nil
+}
+
+// mintOnSimulatedChain sends a mint from the platform wallet on the simulated chain.
+// The event listener marks the certificate minted once the transaction is mined.
+func mintOnSimulatedChain(certHash, owner, academicDNA string) {
+	txHash, err := blockchain.GetClient().Mint(certHash, owner, academicDNA)
+	if err != nil {
+		log.Printf("⚠️  Simulated mint failed for %s: %v", certHash, err)
+		return
+	}
+	log.Printf("⛓️  Simulated mint submitted for %s (tx %s)", certHash, txHash)
+}
+
 // GetCertificateDataHandler returns certificate data without generating PDF
 func GetCertificateDataHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -166,41 +237,13 @@ func InstructorDashboardHandler(w http.ResponseWriter, r *http.Request) {
 	var stats models.InstructorStats
 
 	// Try to fetch from DB
-	if db.FirestoreClient != nil {
-		docRef := db.FirestoreClient.Collection("instructor_stats").Doc(instructorID)
-		docSnap, err := docRef.Get(r.Context())
-
-		if err != nil {
-			// If not found or error, we assume missing and seed default data
-			// User requested to "populate the database" so we'll create seed data
-
-			// Seed Data (matches user's profile expectations: 156 students, 2 courses)
-			stats = models.InstructorStats{
-				InstructorID:     instructorID,
-				TotalStudents:    156,
-				ActiveCourses:    2,
-				TotalEnrollments: 423,
-				CompletionRate:   78.0,
-				AverageRating:    4.8,
-			}
-
-			// Save seed data to DB
-			_, setErr := docRef.Set(r.Context(), stats)
-			if setErr != nil {
-				// Just log error but continue with seeded stats locally
-				// In a real app we'd log this properly
-			}
-		} else {
-			// Found, unmarshal
-			if err := docSnap.DataTo(&stats); err != nil {
-				respondJSON(w, http.StatusInternalServerError, map[string]string{
-					"error": "Failed to parse instructor stats",
-				})
-				return
-			}
-		}
+	if existing, err := db.Repos.Instructors.GetStats(r.Context(), instructorID); err == nil {
+		stats = *existing
 	} else {
-		// No DB connection, use mock
+		// If not found or error, we assume missing and seed default data
+		// User requested to "populate the database" so we'll create seed data
+
+		// Seed Data (matches user's profile expectations: 156 students, 2 courses)
 		stats = models.InstructorStats{
 			InstructorID:     instructorID,
 			TotalStudents:    156,
@@ -209,42 +252,22 @@ func InstructorDashboardHandler(w http.ResponseWriter, r *http.Request) {
 			CompletionRate:   78.0,
 			AverageRating:    4.8,
 		}
+
+		// Save seed data to DB; on failure continue with seeded stats locally
+		_ = db.Repos.Instructors.SaveStats(r.Context(), &stats)
 	}
 
 	// Load Recent Activity
-	var activities []models.ActivityItem
-
-	if db.FirestoreClient != nil {
-		actDocRef := db.FirestoreClient.Collection("instructor_activities").Doc(instructorID)
-		actSnap, err := actDocRef.Get(r.Context())
-
-		if err != nil {
-			// Seed Data
-			activities = []models.ActivityItem{
-				{ID: "a1", Type: "enrollment", Title: "New student enrolled", Subtitle: "John Doe joined Flutter Mastery", Timestamp: time.Now().Add(-20 * time.Minute)},
-				{ID: "a2", Type: "completion", Title: "Course completed", Subtitle: "Jane completed Dart Basics", Timestamp: time.Now().Add(-2 * time.Hour)},
-				{ID: "a3", Type: "feedback", Title: "New feedback", Subtitle: "5 new reviews on your course", Timestamp: time.Now().Add(-5 * time.Hour)},
-				{ID: "a4", Type: "certificate", Title: "Certificate issued", Subtitle: "Mike earned Flutter Pro badge", Timestamp: time.Now().Add(-24 * time.Hour)},
-			}
-			// Wrapper struct for saving
-			wrapper := map[string]interface{}{
-				"items": activities,
-			}
-			_, _ = actDocRef.Set(r.Context(), wrapper)
-		} else {
-			var wrapper struct {
-				Items []models.ActivityItem `firestore:"items"`
-			}
-			if err := actSnap.DataTo(&wrapper); err == nil {
-				activities = wrapper.Items
-			}
-		}
-	} else {
-		// Mock
+	activities, err := db.Repos.Instructors.GetActivities(r.Context(), instructorID)
+	if err != nil {
+		// Seed Data
 		activities = []models.ActivityItem{
 			{ID: "a1", Type: "enrollment", Title: "New student enrolled", Subtitle: "John Doe joined Flutter Mastery", Timestamp: time.Now().Add(-20 * time.Minute)},
 			{ID: "a2", Type: "completion", Title: "Course completed", Subtitle: "Jane completed Dart Basics", Timestamp: time.Now().Add(-2 * time.Hour)},
+			{ID: "a3", Type: "feedback", Title: "New feedback", Subtitle: "5 new reviews on your course", Timestamp: time.Now().Add(-5 * time.Hour)},
+			{ID: "a4", Type: "certificate", Title: "Certificate issued", Subtitle: "Mike earned Flutter Pro badge", Timestamp: time.Now().Add(-24 * time.Hour)},
 		}
+		_ = db.Repos.Instructors.SaveActivities(r.Context(), instructorID, activities)
 	}
 
 	// Construct final response
@@ -282,77 +305,49 @@ func InstructorAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
 	var activeCount, droppedCount, completedCount int
 
 	// Try to fetch from DB
-	if db.FirestoreClient != nil {
-		docRef := db.FirestoreClient.Collection("instructor_analytics").Doc(instructorID)
-		docSnap, err := docRef.Get(r.Context())
-
-		if err != nil {
-			// Seed Data if missing
-			studs := []models.StudentProgress{
-				{ID: "s1", StudentName: "John Doe", CourseName: "Flutter Mastery", Progress: 85, Status: "Active", LastActive: time.Now()},
-				{ID: "s2", StudentName: "Jane Smith", CourseName: "Dart Basics", Progress: 42, Status: "Dropped", LastActive: time.Now().Add(-72 * time.Hour)},
-				{ID: "s3", StudentName: "Mike Johnson", CourseName: "State Management", Progress: 95, Status: "Completed", LastActive: time.Now()},
-				{ID: "s4", StudentName: "Sarah Wilson", CourseName: "Flutter Mastery", Progress: 28, Status: "Dropped", LastActive: time.Now().Add(-120 * time.Hour)},
-				{ID: "s5", StudentName: "Tom Brown", CourseName: "UI/UX Design", Progress: 67, Status: "Active", LastActive: time.Now()},
-			}
+	if existing, err := db.Repos.Instructors.GetAnalytics(r.Context(), instructorID); err == nil {
+		analytics = *existing
+	} else {
+		// Seed Data if missing
+		studs := []models.StudentProgress{
+			{ID: "s1", StudentName: "John Doe", CourseName: "Flutter Mastery", Progress: 85, Status: "Active", LastActive: time.Now()},
+			{ID: "s2", StudentName: "Jane Smith", CourseName: "Dart Basics", Progress: 42, Status: "Dropped", LastActive: time.Now().Add(-72 * time.Hour)},
+			{ID: "s3", StudentName: "Mike Johnson", CourseName: "State Management", Progress: 95, Status: "Completed", LastActive: time.Now()},
+			{ID: "s4", StudentName: "Sarah Wilson", CourseName: "Flutter Mastery", Progress: 28, Status: "Dropped", LastActive: time.Now().Add(-120 * time.Hour)},
+			{ID: "s5", StudentName: "Tom Brown", CourseName: "UI/UX Design", Progress: 67, Status: "Active", LastActive: time.Now()},
+		}
 
-			// Calc counts
-			for _, s := range studs {
-				if s.Status == "Active" {
-					activeCount++
-				} else if s.Status == "Dropped" {
-					droppedCount++
-				} else if s.Status == "Completed" {
-					completedCount++
-				}
+		// Calc counts
+		for _, s := range studs {
+			if s.Status == "Active" {
+				activeCount++
+			} else if s.Status == "Dropped" {
+				droppedCount++
+			} else if s.Status == "Completed" {
+				completedCount++
 			}
+		}
 
-			// Generate AI Insights
-			dataSummary := "Students are struggling with State Management. Dropout rate is increasing in Dart Basics. Flutter Mastery has high engagement."
-			insights, _ := services.GenerateAnalyticsInsights(r.Context(), dataSummary)
-
-			// Fill model
-			analytics = models.InstructorAnalytics{
-				InstructorID:    instructorID,
-				ActiveCount:     189, // Mocking larger numbers to match UI screenshot approximately
-				DroppedCount:    23,
-				CompletedCount:  45,
-				StudentProgress: studs,
-				Insights: models.AIInsights{
-					Roadblocks:      insights.Roadblocks,
-					Recommendations: insights.Recommendations,
-				},
-				UpdatedAt: time.Now(),
-			}
+		// Generate AI Insights
+		dataSummary := "Students are struggling with State Management. Dropout rate is increasing in Dart Basics. Flutter Mastery has high engagement."
+		insights, _ := services.GenerateAnalyticsInsights(r.Context(), dataSummary)
 
-			// Save to DB
-			_, _ = docRef.Set(r.Context(), analytics)
-		} else {
-			// Found
-			if err := docSnap.DataTo(&analytics); err != nil {
-				respondJSON(w, http.StatusInternalServerError, map[string]string{
-					"error": "Failed to parse analytics",
-				})
-				return
-			}
-		}
-	} else {
-		// Mock mode
+		// Fill model
 		analytics = models.InstructorAnalytics{
-			InstructorID:   instructorID,
-			ActiveCount:    189,
-			DroppedCount:   23,
-			CompletedCount: 45,
-			StudentProgress: []models.StudentProgress{
-				{ID: "s1", StudentName: "John Doe", CourseName: "Flutter Mastery", Progress: 85, Status: "Active"},
-				{ID: "s2", StudentName: "Jane Smith", CourseName: "Dart Basics", Progress: 42, Status: "Dropped"},
-				{ID: "s3", StudentName: "Mike Johnson", CourseName: "State Management", Progress: 95, Status: "Completed"},
-			},
+			InstructorID:    instructorID,
+			ActiveCount:     189, // Mocking larger numbers to match UI screenshot approximately
+			DroppedCount:    23,
+			CompletedCount:  45,
+			StudentProgress: studs,
 			Insights: models.AIInsights{
-				Roadblocks:      []string{"High dropoff in Module 3", "Quiz 1 scores low"},
-				Recommendations: []string{"Add more examples", "Review Module 3 content"},
+				Roadblocks:      insights.Roadblocks,
+				Recommendations: insights.Recommendations,
 			},
+			UpdatedAt: time.Now(),
 		}
+
+		// Save to DB
+		_ = db.Repos.Instructors.SaveAnalytics(r.Context(), &analytics)
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxIssuerStatementLength bounds the free-text part of an issuer application
+const maxIssuerStatementLength = 2000
+
+// IssuerApplicationRequest is an instructor's application to become an issuer
+type IssuerApplicationRequest struct {
+	Institution string `json:"institution"`
+	Statement   string `json:"statement"`
+}
+
+// IssuerReviewRequest carries an optional admin note for authorize, revoke and reject
+type IssuerReviewRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+// IssuerStatus is an application together with the registry's current answer
+type IssuerStatus struct {
+	Wallet      string                    `json:"wallet"`
+	Authorized  bool                      `json:"authorized"`            // authorizedIssuers on-chain
+	Application *models.IssuerApplication `json:"application,omitempty"` // nil if the wallet never applied or was reviewed
+}
+
+// ApplyForIssuerHandler queues the calling instructor's wallet for admin approval.
+// Re-applying after a rejection or revocation starts a new pending application.
+func ApplyForIssuerHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	wallet, _ := r.Context().Value("wallet").(string)
+	if wallet == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req IssuerApplicationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		return
+	}
+	if len(req.Statement) > maxIssuerStatementLength {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Statement is too long"})
+		return
+	}
+
+	ctx := r.Context()
+
+	if authorized, err := blockchain.IsAuthorizedIssuerCached(wallet); err == nil && authorized {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Wallet is already an authorized issuer"})
+		return
+	}
+
+	existing, err := db.Repos.IssuerApplications.Get(ctx, wallet)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		log.Printf("Failed to load issuer application for %s: %v", wallet, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load application"})
+		return
+	}
+	if existing != nil && (existing.Status == models.IssuerApplicationPending || existing.Status == models.IssuerApplicationApproved) {
+		respondJSON(w, http.StatusOK, existing)
+		return
+	}
+
+	app := &models.IssuerApplication{
+		Wallet:      common.HexToAddress(wallet).Hex(),
+		Institution: strings.TrimSpace(req.Institution),
+		Statement:   strings.TrimSpace(req.Statement),
+		Status:      models.IssuerApplicationPending,
+		SubmittedAt: time.Now(),
+	}
+	if user, err := db.Repos.Users.GetByWallet(ctx, wallet); err == nil {
+		app.UserID = user.ID
+		app.Name = user.Name
+		if app.Institution == "" {
+			app.Institution = user.Institution
+		}
+	}
+
+	if err := db.Repos.IssuerApplications.Save(ctx, app); err != nil {
+		log.Printf("Failed to save issuer application for %s: %v", wallet, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to save application"})
+		return
+	}
+
+	log.Printf("📝 Issuer application submitted by %s", app.Wallet)
+	respondJSON(w, http.StatusCreated, app)
+}
+
+// GetIssuerStatusHandler returns the calling instructor's application and on-chain authorization
+func GetIssuerStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	wallet, _ := r.Context().Value("wallet").(string)
+	if wallet == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	status, err := issuerStatus(r, wallet)
+	if err != nil {
+		log.Printf("Failed to load issuer status for %s: %v", wallet, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load issuer status"})
+		return
+	}
+	respondJSON(w, http.StatusOK, status)
+}
+
+// ListIssuersHandler returns issuer applications (filter with ?status=pending|approved|rejected|revoked),
+// each with its current on-chain authorization
+func ListIssuersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	apps, err := db.Repos.IssuerApplications.List(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Failed to list issuer applications: %v", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to list issuers"})
+		return
+	}
+
+	issuers := make([]IssuerStatus, len(apps))
+	for i := range apps {
+		authorized, err := blockchain.IsAuthorizedIssuerCached(apps[i].Wallet)
+		if err != nil {
+			log.Printf("Failed to check issuer %s on-chain: %v", apps[i].Wallet, err)
+		}
+		issuers[i] = IssuerStatus{Wallet: apps[i].Wallet, Authorized: authorized, Application: &apps[i]}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"issuers": issuers,
+		"count":   len(issuers),
+	})
+}
+
+// AuthorizeIssuerHandler submits authorizeIssuer for a wallet from the platform wallet
+// and approves its application (creating one if the wallet never applied)
+func AuthorizeIssuerHandler(w http.ResponseWriter, r *http.Request) {
+	reviewIssuer(w, r, models.IssuerApplicationApproved)
+}
+
+// RevokeIssuerHandler submits revokeIssuer for a wallet from the platform wallet
+func RevokeIssuerHandler(w http.ResponseWriter, r *http.Request) {
+	reviewIssuer(w, r, models.IssuerApplicationRevoked)
+}
+
+// RejectIssuerHandler declines a pending application without touching the chain
+func RejectIssuerHandler(w http.ResponseWriter, r *http.Request) {
+	reviewIssuer(w, r, models.IssuerApplicationRejected)
+}
+
+// reviewIssuer applies an admin decision: approved and revoked send a transaction,
+// rejected only updates the application
+func reviewIssuer(w http.ResponseWriter, r *http.Request, decision string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	admin, _ := r.Context().Value("wallet").(string)
+	issuer := chi.URLParam(r, "wallet")
+	if !common.IsHexAddress(issuer) {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid wallet address"})
+		return
+	}
+	issuer = common.HexToAddress(issuer).Hex()
+
+	var req IssuerReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		return
+	}
+
+	ctx := r.Context()
+
+	app, err := db.Repos.IssuerApplications.Get(ctx, issuer)
+	if errors.Is(err, db.ErrNotFound) {
+		if decision == models.IssuerApplicationRejected {
+			respondJSON(w, http.StatusNotFound, map[string]string{"error": "Application not found"})
+			return
+		}
+		app = &models.IssuerApplication{Wallet: issuer, SubmittedAt: time.Now()}
+	} else if err != nil {
+		log.Printf("Failed to load issuer application for %s: %v", issuer, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load application"})
+		return
+	}
+	if decision == models.IssuerApplicationRejected && app.Status != models.IssuerApplicationPending {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Only pending applications can be rejected"})
+		return
+	}
+
+	if decision != models.IssuerApplicationRejected {
+		client := blockchain.GetClient()
+		authorized, err := client.IsAuthorizedIssuer(issuer)
+		if err != nil {
+			log.Printf("Failed to check issuer %s on-chain: %v", issuer, err)
+			respondJSON(w, http.StatusBadGateway, map[string]string{"error": "Failed to check issuer on-chain"})
+			return
+		}
+		if authorized == (decision == models.IssuerApplicationApproved) {
+			respondJSON(w, http.StatusConflict, map[string]string{"error": "Issuer is already in that state on-chain"})
+			return
+		}
+
+		send := client.AuthorizeIssuer
+		if decision == models.IssuerApplicationRevoked {
+			send = client.RevokeIssuer
+		}
+		txHash, err := send(issuer)
+		if err != nil {
+			log.Printf("Failed to update issuer %s on-chain: %v", issuer, err)
+			respondJSON(w, http.StatusBadGateway, map[string]string{"error": "Blockchain transaction failed: " + err.Error()})
+			return
+		}
+		app.TxHash = txHash
+		blockchain.ForgetIssuer(issuer)
+	}
+
+	app.Status = decision
+	app.ReviewedBy = admin
+	app.ReviewNote = strings.TrimSpace(req.Note)
+	app.ReviewedAt = time.Now()
+	if err := db.Repos.IssuerApplications.Save(ctx, app); err != nil {
+		// The transaction is already out; the indexer still applies its event
+		log.Printf("Failed to save issuer application for %s: %v", issuer, err)
+	}
+
+	log.Printf("🛡️ Issuer %s %s by %s", issuer, decision, admin)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"txHash":      app.TxHash,
+		"application": app,
+	})
+}
+
+func issuerStatus(r *http.Request, wallet string) (*IssuerStatus, error) {
+	status := &IssuerStatus{Wallet: common.HexToAddress(wallet).Hex()}
+
+	app, err := db.Repos.IssuerApplications.Get(r.Context(), wallet)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
+	status.Application = app
+
+	authorized, err := blockchain.IsAuthorizedIssuerCached(wallet)
+	if err != nil {
+		return nil, err
+	}
+	status.Authorized = authorized
+	return status, nil
+}
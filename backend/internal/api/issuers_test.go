@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestReviewIssuerForgetsCachedAuthorization(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	review := func(handler http.HandlerFunc) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/issuers/"+issuer, nil)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("wallet", issuer)
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+		req = req.WithContext(context.WithValue(ctx, "wallet", "0x9999999999999999999999999999999999999999"))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body, http.StatusOK)
+		}
+	}
+	cached := func() bool {
+		t.Helper()
+		authorized, err := blockchain.IsAuthorizedIssuerCached(issuer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return authorized
+	}
+
+	// Each decision is seen on the next check rather than after the cache TTL
+	if cached() {
+		t.Fatal("new wallet already authorized")
+	}
+	review(AuthorizeIssuerHandler)
+	if !cached() {
+		t.Error("still unauthorized after the admin approved the issuer")
+	}
+	review(RevokeIssuerHandler)
+	if cached() {
+		t.Error("still authorized after the admin revoked the issuer")
+	}
+}
@@ -1,31 +1,51 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/config"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/services"
 	"cache-crew/cognify/internal/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
-// PrepareMintRequest data needed to start minting
+// PrepareMintRequest data needed to start minting. Either name an existing pending
+// certificate by hash, or give the student and course to create one.
 type PrepareMintRequest struct {
-	StudentWallet string  `json:"studentWallet"`
-	StudentName   string  `json:"studentName"`
-	CourseName    string  `json:"courseName"`
-	Marks         float64 `json:"marks"`
+	CertificateHash string  `json:"certificateHash,omitempty"`
+	StudentWallet   string  `json:"studentWallet"`
+	StudentName     string  `json:"studentName"`
+	CourseName      string  `json:"courseName"`
+	Marks           float64 `json:"marks"`
 }
 
 // PrepareMintResponse returns data for the frontend to submit to blockchain
 type PrepareMintResponse struct {
-	AcademicDNA   string `json:"academicDNA"`
-	CertificateID string `json:"certificateID"` // UUID or Hash pre-calculation
-	IssuedAt      int64  `json:"issuedAt"`
-	Signature     string `json:"signature"` // Backend authorization signature
+	AcademicDNA   string                    `json:"academicDNA"`
+	CertificateID string                    `json:"certificateID"` // Certificate hash to pass to mintCertificate
+	Claims        models.CertificateClaims  `json:"claims"`        // What the hash commits to; present them to verify the certificate's contents
+	IssuedAt      int64                     `json:"issuedAt"`
+	ExpiresAt     int64                     `json:"expiresAt"` // The mint must be mined before this
+	Signature     string                    `json:"signature"` // Platform EIP-712 signature over TypedData
+	Authorization *models.MintAuthorization `json:"authorization"`
+	TypedData     apitypes.TypedData        `json:"typedData"`
+	RelayRequest  *apitypes.TypedData       `json:"relayRequest,omitempty"` // MintRequest to sign for a gasless mint via /mint/relay
 }
 
-// PrepareMintHandler handles the pre-mint validation and DNA generation
+// PrepareMintHandler validates a mint and returns the platform's EIP-712 authorization for it.
+// The authorization is stored on the pending certificate; ConfirmMintHandler rejects
+// the mint unless it matches and was mined before it expired.
 func PrepareMintHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -33,7 +53,7 @@ func PrepareMintHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 1. Get Instructor from Context (set by InstructorOnlyMiddleware)
-	instructorWallet := r.Context().Value("wallet").(string)
+	instructorWallet, _ := r.Context().Value("wallet").(string)
 	if instructorWallet == "" {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
@@ -46,51 +66,267 @@ func PrepareMintHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 3. Generate Academic DNA
-	// Uses: StudentWallet + StudentName (as ID) + Time + Secret
-	// In production, fetch StudentID from DB if possible
-	enrollmentTime := time.Now()                   // Or fetch real enrollment time
-	platformSecret := "COGNIFY_PLATFORM_SECRET_V1" // Should be env var
+	ctx := r.Context()
 
-	academicDNA := utils.GenerateAcademicDNA(req.StudentWallet, req.StudentName, enrollmentTime, platformSecret)
+	// 3. Load the pending certificate, or create one with a fresh Academic DNA
+	var cert *models.Certificate
+	if req.CertificateHash != "" {
+		certHash := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.CertificateHash), "0x"))
+		existing, err := db.Repos.Certificates.Get(ctx, certHash)
+		if errors.Is(err, db.ErrNotFound) {
+			respondJSON(w, http.StatusNotFound, map[string]string{"error": "Certificate not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to load certificate %s: %v", certHash, err)
+			respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load certificate"})
+			return
+		}
+		if existing.IsMinted || existing.Revoked {
+			respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate is not pending"})
+			return
+		}
+		cert = existing
+	} else {
+		if !common.IsHexAddress(req.StudentWallet) || req.StudentName == "" || req.CourseName == "" {
+			respondJSON(w, http.StatusBadRequest, map[string]string{
+				"error": "A valid studentWallet, studentName and courseName are required",
+			})
+			return
+		}
 
-	// 4. Sign the data for "Authorized Minting"
-	// We sign: keccak256(studentWallet + academicDNA + issuedAt)
+		// Uses: StudentWallet + StudentName (as ID) + Time + Secret
+		issuedAt := time.Now()
+		academicDNA := utils.GenerateAcademicDNA(req.StudentWallet, req.StudentName, issuedAt, config.AppConfig.PlatformSecret)
+		cert = &models.Certificate{
+			StudentName:      req.StudentName,
+			CourseName:       req.CourseName,
+			Marks:            req.Marks,
+			WalletAddress:    req.StudentWallet,
+			IssuedAt:         issuedAt,
+			AcademicDNA:      academicDNA,
+			InstructorWallet: instructorWallet,
+		}
+		if err := hashCertificate(cert); err != nil {
+			log.Printf("Failed to hash certificate claims: %v", err)
+			respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to hash certificate"})
+			return
+		}
+		if _, err := db.Repos.Certificates.Get(ctx, cert.Hash); err == nil {
+			respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate already prepared"})
+			return
+		}
+		cert.TrustScore = services.NewTrustEngine().CalculateTrustScore(ctx, cert)
+	}
+	if err := services.NewStatusListService().Assign(ctx, cert); err != nil {
+		log.Printf("Failed to assign status list entry to %s: %v", cert.Hash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to assign certificate status"})
+		return
+	}
 
-	// Get Admin Private Key from config
-	// Accessing AppConfig directly (assuming package is accessible or handling circular dep via interface in real app)
-	// For this fix, we'll assume we can use the utils to decrypt a known config value (passed or global)
+	// 4. Sign the EIP-712 mint authorization and store it with the certificate
+	auth, err := authorizeMint(ctx, cert)
+	if err != nil {
+		log.Printf("Failed to authorize mint of %s: %v", cert.Hash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to sign mint authorization"})
+		return
+	}
+
+	resp := PrepareMintResponse{
+		AcademicDNA:   cert.AcademicDNA,
+		CertificateID: cert.Hash,
+		Claims:        cert.Claims(),
+		IssuedAt:      cert.IssuedAt.Unix(),
+		ExpiresAt:     auth.Expiry,
+		Signature:     auth.Signature,
+		Authorization: auth,
+		TypedData:     blockchain.MintAuthorizationTypedData(auth),
+	}
+	if config.AppConfig.RelayDailyQuota > 0 {
+		relayRequest := blockchain.MintRequestTypedData(instructorWallet, auth, auth.Expiry)
+		resp.RelayRequest = &relayRequest
+	}
 
-	// Mocking the config access to avoid circular dependency "api -> config -> api" if it exists.
-	// In production, inject config or use a dedicated Signer service.
-	encryptedKey := "MOCK_ENCRYPTED_KEY_FOR_AUDIT"
-	passphrase := "MOCK_PASSPHRASE"
+	respondJSON(w, http.StatusOK, resp)
+}
 
-	// Decrypt key
-	privKeyHex, err := utils.DecryptPrivateKey(encryptedKey, passphrase)
+// authorizeMint signs a mint authorization for a pending certificate and saves both
+func authorizeMint(ctx context.Context, cert *models.Certificate) (*models.MintAuthorization, error) {
+	ttl := time.Duration(config.AppConfig.MintAuthorizationTTLMinutes) * time.Minute
+	auth, err := blockchain.SignMintAuthorization(cert.WalletAddress, cert.Hash, cert.AcademicDNA, time.Now(), ttl)
 	if err != nil {
-		// Log error and define a fallback for the audit to proceed (so we don't crash)
-		// In production: http.Error(w, "Internal Signing Error", http.StatusInternalServerError)
-		privKeyHex = "0000000000000000000000000000000000000000000000000000000000000001" // Mock Key
+		return nil, err
 	}
 
-	// In a real implementation:
-	// privateKey, _ := crypto.HexToECDSA(privKeyHex)
-	// data := []byte(academicDNA + req.StudentWallet)
-	// hash := crypto.Keccak256Hash(data)
-	// signatureBytes, _ := crypto.Sign(hash.Bytes(), privateKey)
-	// backendSignature := fmt.Sprintf("0x%x", signatureBytes)
+	cert.MintAuthorization = auth
+	if err := db.Repos.Certificates.Save(ctx, cert); err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
 
-	// For Audit Fix "Production Ready Pattern":
-	// We demonstrate the usage of the variable to fix the lint error.
-	backendSignature := fmt.Sprintf("0x_signed_%s_%s", privKeyHex[:4], academicDNA[:8])
+// ConfirmMintRequest identifies the transaction that minted a pending certificate
+type ConfirmMintRequest struct {
+	CertificateHash string `json:"certificateHash"`
+	TxHash          string `json:"txHash"`
+}
 
-	resp := PrepareMintResponse{
-		AcademicDNA:   academicDNA,
-		CertificateID: utils.GenerateHash(req.StudentWallet + academicDNA), // Use Hash as ID
-		IssuedAt:      enrollmentTime.Unix(),
-		Signature:     backendSignature,
+// ConfirmMintHandler marks a pending certificate as minted once its transaction is mined.
+// The receipt must contain a CertificateMinted event for the expected hash and owner
+// wallet, issued by an authorized issuer under a valid, unexpired platform authorization.
+func ConfirmMintHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	respondJSON(w, http.StatusOK, resp)
+	var req ConfirmMintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		return
+	}
+
+	certHash := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.CertificateHash), "0x"))
+	txHash := strings.TrimSpace(req.TxHash)
+	if certHash == "" || txHash == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "Certificate hash and transaction hash are required",
+		})
+		return
+	}
+
+	ctx := r.Context()
+
+	cert, err := db.Repos.Certificates.Get(ctx, certHash)
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Certificate not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load certificate"})
+		return
+	}
+
+	if cert.IsMinted {
+		if sameTransaction(ctx, cert.BlockchainTx, txHash) {
+			respondJSON(w, http.StatusOK, map[string]interface{}{
+				"success": true,
+				"status":  "minted",
+				"message": "Mint already confirmed",
+				"data":    cert,
+			})
+			return
+		}
+		respondJSON(w, http.StatusConflict, map[string]string{
+			"error": "Certificate already minted in another transaction",
+		})
+		return
+	}
+
+	client := blockchain.GetClient()
+	events, err := client.MintEvents(txHash)
+	switch {
+	case errors.Is(err, blockchain.ErrTxPending):
+		respondJSON(w, http.StatusAccepted, map[string]string{
+			"status":  "pending_mint",
+			"message": "Transaction not mined yet, try again shortly",
+		})
+		return
+	case errors.Is(err, blockchain.ErrTxNotFound):
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Transaction not found"})
+		return
+	case errors.Is(err, blockchain.ErrTxReverted):
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Transaction reverted"})
+		return
+	case err != nil:
+		log.Printf("Failed to fetch mint receipt %s: %v", txHash, err)
+		respondJSON(w, http.StatusBadGateway, map[string]string{"error": "Failed to fetch transaction receipt"})
+		return
+	}
+
+	// Find the mint for this certificate in the receipt
+	var event *blockchain.MintEvent
+	for i := range events {
+		if events[i].Hash == certHash {
+			event = &events[i]
+			break
+		}
+	}
+	if event == nil {
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{
+			"error": "Transaction did not mint this certificate",
+		})
+		return
+	}
+
+	if cert.WalletAddress != "" && !strings.EqualFold(event.Owner.Hex(), cert.WalletAddress) {
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{
+			"error": "Certificate was minted to a different wallet",
+		})
+		return
+	}
+
+	authorized, err := client.IsAuthorizedIssuer(event.Issuer.Hex())
+	if err != nil {
+		log.Printf("Failed to check issuer %s: %v", event.Issuer.Hex(), err)
+		respondJSON(w, http.StatusBadGateway, map[string]string{"error": "Failed to check issuer authorization"})
+		return
+	}
+	if !authorized {
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{
+			"error": "Certificate was minted by an unauthorized issuer",
+		})
+		return
+	}
+
+	// The mint must match the platform's authorization and be mined before it expired
+	err = blockchain.ConfirmMint(ctx, client, *event)
+	switch {
+	case errors.Is(err, blockchain.ErrMintUnauthorized):
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Certificate has no platform mint authorization"})
+		return
+	case errors.Is(err, blockchain.ErrMintAuthorizationExpired):
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Certificate was minted after its authorization expired"})
+		return
+	case errors.Is(err, blockchain.ErrMintAuthorizationInvalid):
+		log.Printf("Rejected mint authorization for %s: %v", certHash, err)
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Mint authorization is not valid"})
+		return
+	case errors.Is(err, blockchain.ErrMintMismatch):
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Mint does not match its authorization"})
+		return
+	case err != nil:
+		log.Printf("Failed to mark certificate %s minted: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to record mint"})
+		return
+	}
+
+	log.Printf("✅ Mint confirmed for %s (tx %s, block %d)", certHash, event.TxHash, event.BlockNumber)
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"status":      "minted",
+		"txHash":      event.TxHash,
+		"blockNumber": event.BlockNumber,
+		"issuer":      event.Issuer.Hex(),
+	})
+}
+
+// sameTransaction reports whether minedTx is txHash or, for a platform wallet
+// transaction identified by txHash, the fee-bumped replacement of it that was mined
+func sameTransaction(ctx context.Context, minedTx, txHash string) bool {
+	if strings.EqualFold(minedTx, txHash) {
+		return true
+	}
+	tx, err := db.Repos.Transactions.Get(ctx, strings.ToLower(txHash))
+	if err != nil {
+		return false
+	}
+	for _, hash := range tx.Hashes {
+		if strings.EqualFold(hash, minedTx) {
+			return true
+		}
+	}
+	return false
 }
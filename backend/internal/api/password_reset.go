@@ -5,10 +5,8 @@ import (
 	"net/http"
 
 	"cache-crew/cognify/internal/db"
-	"cache-crew/cognify/internal/models"
 	"cache-crew/cognify/internal/services"
 
-	"cloud.google.com/go/firestore"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -48,8 +46,7 @@ func ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if user exists
-	doc, err := db.FirestoreClient.Collection("users").Doc(req.Email).Get(r.Context())
-	if err != nil || !doc.Exists() {
+	if _, err := db.Repos.Users.Get(r.Context(), req.Email); err != nil {
 		respondJSON(w, http.StatusNotFound, map[string]interface{}{
 			"success":      false,
 			"message":      "Email not registered. Please sign up first.",
@@ -159,8 +156,7 @@ func ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if user exists
-	doc, err := db.FirestoreClient.Collection("users").Doc(req.Email).Get(r.Context())
-	if err != nil || !doc.Exists() {
+	if _, err := db.Repos.Users.Get(r.Context(), req.Email); err != nil {
 		respondJSON(w, http.StatusNotFound, AuthResponse{
 			Success: false,
 			Message: "User not found",
@@ -168,16 +164,6 @@ func ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get existing user data
-	var user models.User
-	if err := doc.DataTo(&user); err != nil {
-		respondJSON(w, http.StatusInternalServerError, AuthResponse{
-			Success: false,
-			Message: "Database error",
-		})
-		return
-	}
-
 	// Hash new password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
@@ -189,10 +175,7 @@ func ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update password in database
-	_, err = db.FirestoreClient.Collection("users").Doc(req.Email).Update(r.Context(), []firestore.Update{
-		{Path: "password", Value: string(hashedPassword)},
-	})
-	if err != nil {
+	if err := db.Repos.Users.SetPassword(r.Context(), req.Email, string(hashedPassword)); err != nil {
 		respondJSON(w, http.StatusInternalServerError, AuthResponse{
 			Success: false,
 			Message: "Failed to update password",
@@ -9,8 +9,6 @@ import (
 	"cache-crew/cognify/internal/db"
 	"cache-crew/cognify/internal/models"
 	"cache-crew/cognify/internal/services" // Import services package
-
-	"google.golang.org/api/iterator"
 )
 
 // GetRecommendationsHandler returns AI course recommendations
@@ -54,23 +52,10 @@ func GetBattleQuestionsHandler(w http.ResponseWriter, r *http.Request) {
 	// topic := r.URL.Query().Get("topic")
 	// difficulty := r.URL.Query().Get("difficulty")
 
-	var questions []models.Question
-
-	if db.FirestoreClient != nil {
-		iter := db.FirestoreClient.Collection("questions").Limit(5).Documents(ctx)
-		for {
-			doc, err := iter.Next()
-			if err == iterator.Done {
-				break
-			}
-			if err != nil {
-				respondJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-				return
-			}
-			var q models.Question
-			doc.DataTo(&q)
-			questions = append(questions, q)
-		}
+	questions, err := db.Repos.Questions.List(ctx, 5)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
 	}
 
 	// Fallback mock if empty
@@ -95,10 +80,6 @@ func GetBattleQuestionsHandler(w http.ResponseWriter, r *http.Request) {
 // SeedBattleDataHandler initializes questions and courses
 func SeedBattleDataHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	if db.FirestoreClient == nil {
-		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "DB not valid"})
-		return
-	}
 
 	// Pre-define questions for embedding
 	q1 := models.Question{ID: "q1", Text: "What does 'setState' do?", Options: []string{"Rebuilds the widget", "Stops the app", "Nothing", "Deletes data"}, CorrectIndex: 0, Difficulty: "Easy", Topic: "Flutter", Points: 10, TimeLimit: 30}
@@ -144,14 +125,14 @@ func SeedBattleDataHandler(w http.ResponseWriter, r *http.Request) {
 		{ID: "c_dart_basics", Title: "Dart Fundamentals", Subtitle: "Core Language Skills", Description: "Learn Dart from scratch.", DifficultyRating: 1, Tags: []string{"Dart", "Basics"}, InstructorID: "inst1", Emoji: "🎯", ColorHex: "0xFF2196F3"},
 	}
 
-	for _, c := range courses {
-		db.FirestoreClient.Collection("courses").Doc(c.ID).Set(ctx, c)
+	for i := range courses {
+		db.Repos.Courses.Save(ctx, &courses[i])
 	}
 
 	// Seed Questions (including animation questions) - for global pool
 	questions := []models.Question{q1, q2, q3, animQ1, animQ2, animQ3, animQ4, animQ5, animQ6}
-	for _, q := range questions {
-		db.FirestoreClient.Collection("questions").Doc(q.ID).Set(ctx, q)
+	for i := range questions {
+		db.Repos.Questions.Save(ctx, &questions[i])
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Seeded courses and questions with Advanced Animations levels"})
@@ -178,18 +159,11 @@ func CompleteBattleHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	if db.FirestoreClient == nil {
-		respondJSON(w, http.StatusOK, map[string]string{"message": "Mock battle complete (no DB)"})
-		return
-	}
 
 	// Get UserStats
-	statsRef := db.FirestoreClient.Collection("user_stats").Doc(req.UserID)
-	doc, err := statsRef.Get(ctx)
-
 	var stats models.UserStats
-	if err == nil {
-		doc.DataTo(&stats)
+	if existing, err := db.Repos.UserStats.Get(ctx, req.UserID); err == nil {
+		stats = *existing
 	} else {
 		// New stats if missing
 		stats = models.UserStats{UserID: req.UserID, Level: 1}
@@ -211,8 +185,7 @@ func CompleteBattleHandler(w http.ResponseWriter, r *http.Request) {
 	stats.WeeklyXP[todayStr] += req.XP
 
 	// Save
-	_, err = statsRef.Set(ctx, stats)
-	if err != nil {
+	if err := db.Repos.UserStats.Save(ctx, &stats); err != nil {
 		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to update stats"})
 		return
 	}
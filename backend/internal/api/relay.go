@@ -0,0 +1,389 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/config"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-chi/chi/v5"
+)
+
+// relayQuotaWindow is the period RELAY_DAILY_QUOTA applies to
+const relayQuotaWindow = 24 * time.Hour
+
+// relayLocks serializes each instructor's quota check with their submission, so
+// concurrent requests from one instructor cannot both take the last slot. Other
+// instructors' relays go ahead meanwhile.
+var relayLocks = &walletLocks{locks: make(map[string]*walletLock)}
+
+// walletLocks hands out one mutex per wallet, dropping it once nobody holds or waits for it
+type walletLocks struct {
+	mu    sync.Mutex
+	locks map[string]*walletLock
+}
+
+type walletLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock blocks until wallet's mutex is free and returns the function that releases it
+func (l *walletLocks) lock(wallet string) func() {
+	key := strings.ToLower(wallet)
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &walletLock{}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		l.mu.Lock()
+		if lock.refs--; lock.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// RelayMintRequest is an instructor's signed MintRequest for a prepared certificate
+type RelayMintRequest struct {
+	CertificateHash string `json:"certificateHash"`
+	Deadline        int64  `json:"deadline"`  // Unix seconds, as signed
+	Signature       string `json:"signature"` // eth_signTypedData_v4 over PrepareMintResponse.RelayRequest
+}
+
+// RelayQuota is an instructor's gasless mint allowance for the current window
+type RelayQuota struct {
+	Limit     int64 `json:"limit"`
+	Used      int64 `json:"used"`
+	Remaining int64 `json:"remaining"`
+}
+
+// RelayMintHandler mints a prepared certificate from the platform wallet, so the
+// instructor pays no gas. The instructor signs an EIP-712 MintRequest off-chain;
+// it is accepted when the signer prepared the certificate, is an authorized issuer,
+// holds a valid platform authorization for it and is within their daily quota.
+// The contract records the platform wallet as the on-chain issuer; the relaying
+// instructor is kept on the relay record, since the certificate's claims are hashed.
+func RelayMintHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	wallet, _ := r.Context().Value("wallet").(string)
+	if wallet == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	wallet = common.HexToAddress(wallet).Hex()
+
+	if config.AppConfig.RelayDailyQuota <= 0 {
+		respondJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "Gasless minting is disabled"})
+		return
+	}
+
+	var req RelayMintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		return
+	}
+	certHash := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.CertificateHash), "0x"))
+	if certHash == "" || req.Signature == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "Certificate hash and signature are required",
+		})
+		return
+	}
+
+	ctx := r.Context()
+
+	cert, err := db.Repos.Certificates.Get(ctx, certHash)
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Certificate not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load certificate"})
+		return
+	}
+	if cert.IsMinted || cert.Revoked || cert.MintExpired || cert.AnchorRoot != "" {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate is not pending"})
+		return
+	}
+	if cert.InstructorWallet == "" {
+		respondJSON(w, http.StatusForbidden, map[string]string{"error": "Certificate has no issuing instructor and cannot be relayed"})
+		return
+	}
+	if !strings.EqualFold(cert.InstructorWallet, wallet) {
+		respondJSON(w, http.StatusForbidden, map[string]string{"error": "Certificate was prepared by another instructor"})
+		return
+	}
+
+	// The platform must have approved this exact mint, and the instructor must have signed it
+	now := time.Now()
+	if err := blockchain.VerifyMintAuthorization(cert.MintAuthorization, now); err != nil {
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{
+			"error": "Certificate has no valid mint authorization; prepare it again",
+		})
+		return
+	}
+	if req.Deadline > cert.MintAuthorization.Expiry {
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Deadline is after the authorization expires"})
+		return
+	}
+	err = blockchain.VerifyMintRequest(wallet, cert.MintAuthorization, req.Deadline, req.Signature, now)
+	switch {
+	case errors.Is(err, blockchain.ErrMintRequestExpired):
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Mint request deadline has passed"})
+		return
+	case err != nil:
+		log.Printf("Rejected mint request for %s from %s: %v", certHash, wallet, err)
+		respondJSON(w, http.StatusUnauthorized, map[string]string{"error": "Invalid mint request signature"})
+		return
+	}
+
+	authorized, err := blockchain.IsAuthorizedIssuerCached(wallet)
+	if err != nil {
+		log.Printf("Failed to check issuer %s: %v", wallet, err)
+		respondJSON(w, http.StatusBadGateway, map[string]string{"error": "Failed to check issuer authorization"})
+		return
+	}
+	if !authorized {
+		respondJSON(w, http.StatusForbidden, map[string]string{"error": "Wallet is not an authorized issuer"})
+		return
+	}
+
+	// The certificate is this instructor's, so their lock also keeps it from being relayed twice
+	unlock := relayLocks.lock(wallet)
+	defer unlock()
+
+	// A mint already on its way is returned as is; a failed one may be retried
+	existing, err := db.Repos.RelayedMints.Get(ctx, certHash)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		log.Printf("Failed to load relayed mint %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load relayed mint"})
+		return
+	}
+	if existing != nil && existing.Status != models.RelayStatusFailed {
+		respondJSON(w, http.StatusOK, existing)
+		return
+	}
+
+	quota, err := relayQuota(ctx, wallet)
+	if err != nil {
+		log.Printf("Failed to count relayed mints for %s: %v", wallet, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to check relay quota"})
+		return
+	}
+	if quota.Remaining <= 0 {
+		respondJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+			"error": "Daily gasless mint quota reached",
+			"quota": quota,
+		})
+		return
+	}
+
+	relay := &models.RelayedMint{
+		ID:               certHash,
+		InstructorWallet: wallet,
+		StudentWallet:    cert.MintAuthorization.StudentWallet,
+		Deadline:         req.Deadline,
+		Signature:        req.Signature,
+		Status:           models.RelayStatusSubmitted,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	txHash, err := blockchain.GetClient().Mint(certHash, cert.MintAuthorization.StudentWallet, cert.MintAuthorization.AcademicDNA)
+	if err != nil {
+		log.Printf("Failed to relay mint %s for %s: %v", certHash, wallet, err)
+		relay.Status = models.RelayStatusFailed
+		relay.Error = err.Error()
+		if saveErr := db.Repos.RelayedMints.Save(ctx, relay); saveErr != nil {
+			log.Printf("Failed to save relayed mint %s: %v", certHash, saveErr)
+		}
+		if errors.Is(err, blockchain.ErrInsufficientFunds) {
+			respondJSON(w, http.StatusServiceUnavailable, map[string]string{
+				"error": "Insufficient platform funds; the platform cannot pay for gasless mints right now",
+			})
+			return
+		}
+		respondJSON(w, http.StatusBadGateway, map[string]string{"error": "Blockchain transaction failed: " + err.Error()})
+		return
+	}
+	relay.TxHash = txHash
+
+	refreshRelay(ctx, relay)
+	if err := db.Repos.RelayedMints.Save(ctx, relay); err != nil {
+		// The transaction is already out; the indexer still marks the certificate minted
+		log.Printf("Failed to save relayed mint %s: %v", certHash, err)
+	}
+
+	log.Printf("⛽ Relayed mint of %s for %s (tx %s)", certHash, wallet, txHash)
+	respondJSON(w, http.StatusAccepted, relay)
+}
+
+// ListRelayedMintsHandler returns the calling instructor's gasless mints, newest
+// first with their current status, and what is left of their quota
+func ListRelayedMintsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	wallet, _ := r.Context().Value("wallet").(string)
+	if wallet == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	wallet = common.HexToAddress(wallet).Hex()
+
+	ctx := r.Context()
+
+	relays, err := db.Repos.RelayedMints.ListByInstructor(ctx, wallet)
+	if err != nil {
+		log.Printf("Failed to list relayed mints for %s: %v", wallet, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to list relayed mints"})
+		return
+	}
+	for i := range relays {
+		if refreshRelay(ctx, &relays[i]) {
+			saveRelay(ctx, &relays[i])
+		}
+	}
+
+	quota, err := relayQuota(ctx, wallet)
+	if err != nil {
+		log.Printf("Failed to count relayed mints for %s: %v", wallet, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to check relay quota"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"relays": relays,
+		"count":  len(relays),
+		"quota":  quota,
+	})
+}
+
+// GetRelayedMintHandler returns the status of one of the calling instructor's gasless mints
+func GetRelayedMintHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	wallet, _ := r.Context().Value("wallet").(string)
+	certHash := strings.ToLower(strings.TrimPrefix(chi.URLParam(r, "hash"), "0x"))
+
+	relay, err := db.Repos.RelayedMints.Get(r.Context(), certHash)
+	if errors.Is(err, db.ErrNotFound) || (err == nil && !strings.EqualFold(relay.InstructorWallet, wallet)) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Relayed mint not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load relayed mint %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load relayed mint"})
+		return
+	}
+
+	if refreshRelay(r.Context(), relay) {
+		saveRelay(r.Context(), relay)
+	}
+	respondJSON(w, http.StatusOK, relay)
+}
+
+// refreshRelay moves a submitted relay to mined or failed from its transaction's
+// outcome. It marks the certificate minted when the receipt shows the mint, and
+// reports whether the relay changed.
+func refreshRelay(ctx context.Context, relay *models.RelayedMint) bool {
+	if relay.Status != models.RelayStatusSubmitted {
+		return false
+	}
+
+	// Platform wallet transactions are tracked through fee bumps; the mock ledger
+	// has no transaction records and seals each transaction immediately
+	txHash := relay.TxHash
+	if tx, err := db.Repos.Transactions.Get(ctx, relay.TxHash); err == nil {
+		switch tx.Status {
+		case models.TxStatusPending:
+			return false
+		case models.TxStatusFailed, models.TxStatusDropped:
+			return failRelay(relay, "Transaction "+tx.Status)
+		}
+		txHash = tx.Hash
+	}
+
+	events, err := blockchain.GetClient().MintEvents(txHash)
+	switch {
+	case errors.Is(err, blockchain.ErrTxPending), errors.Is(err, blockchain.ErrTxNotFound):
+		return false
+	case errors.Is(err, blockchain.ErrTxReverted):
+		return failRelay(relay, "Transaction reverted")
+	case err != nil:
+		log.Printf("Failed to fetch relayed mint receipt %s: %v", txHash, err)
+		return false
+	}
+
+	for _, event := range events {
+		if event.Hash != relay.ID {
+			continue
+		}
+		err := blockchain.ConfirmMint(ctx, blockchain.GetClient(), event)
+		if blockchain.IsMintRejected(err) {
+			return failRelay(relay, "Mint rejected: "+err.Error())
+		}
+		if err != nil {
+			log.Printf("Failed to mark certificate %s minted: %v", relay.ID, err)
+			return false
+		}
+		relay.Status = models.RelayStatusMined
+		relay.BlockNumber = event.BlockNumber
+		relay.UpdatedAt = time.Now()
+		return true
+	}
+	return failRelay(relay, "Transaction did not mint this certificate")
+}
+
+func failRelay(relay *models.RelayedMint, reason string) bool {
+	relay.Status = models.RelayStatusFailed
+	relay.Error = reason
+	relay.UpdatedAt = time.Now()
+	return true
+}
+
+func saveRelay(ctx context.Context, relay *models.RelayedMint) {
+	if err := db.Repos.RelayedMints.Save(ctx, relay); err != nil {
+		log.Printf("Failed to update relayed mint %s: %v", relay.ID, err)
+	}
+}
+
+// relayQuota counts the instructor's relayed mints in the current window
+func relayQuota(ctx context.Context, wallet string) (RelayQuota, error) {
+	used, err := db.Repos.RelayedMints.CountByInstructorSince(ctx, wallet, time.Now().Add(-relayQuotaWindow))
+	if err != nil {
+		return RelayQuota{}, err
+	}
+
+	quota := RelayQuota{Limit: config.AppConfig.RelayDailyQuota, Used: int64(used)}
+	quota.Remaining = max(quota.Limit-quota.Used, 0)
+	return quota, nil
+}
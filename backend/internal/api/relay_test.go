@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/config"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+)
+
+func TestWalletLocks(t *testing.T) {
+	locks := &walletLocks{locks: make(map[string]*walletLock)}
+
+	unlockA := locks.lock("0xAAAA")
+
+	// Another wallet is not held up
+	done := make(chan struct{})
+	go func() {
+		locks.lock("0xbbbb")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("another wallet waited for the lock")
+	}
+
+	// The same wallet, in any case, waits
+	acquired := make(chan func())
+	go func() { acquired <- locks.lock("0xaaaa") }()
+	select {
+	case <-acquired:
+		t.Fatal("the same wallet took a held lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlockA()
+	select {
+	case unlock := <-acquired:
+		unlock()
+	case <-time.After(time.Second):
+		t.Fatal("the lock was not handed over")
+	}
+
+	if len(locks.locks) != 0 {
+		t.Errorf("%d locks kept after release", len(locks.locks))
+	}
+}
+
+func TestRelayMintRequiresIssuingInstructor(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	config.AppConfig.RelayDailyQuota = 1
+	t.Cleanup(func() { config.AppConfig.RelayDailyQuota = 0 })
+
+	const instructor = "0x1111111111111111111111111111111111111111"
+	tests := []struct {
+		name             string
+		instructorWallet string
+		want             int
+	}{
+		{"no issuing instructor", "", http.StatusForbidden},
+		{"another instructor", "0x2222222222222222222222222222222222222222", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := strings.Repeat("ab", 32)
+			cert := &models.Certificate{Hash: hash, InstructorWallet: tt.instructorWallet}
+			if err := db.Repos.Certificates.Save(context.Background(), cert); err != nil {
+				t.Fatal(err)
+			}
+
+			body := strings.NewReader(`{"certificateHash":"0x` + hash + `","deadline":1,"signature":"0x00"}`)
+			req := httptest.NewRequest(http.MethodPost, "/api/instructor/mint/relay", body)
+			req = req.WithContext(context.WithValue(req.Context(), "wallet", instructor))
+			rec := httptest.NewRecorder()
+			RelayMintHandler(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d (%s), want %d", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+}
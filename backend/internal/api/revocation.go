@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/services"
+)
+
+// maxRevocationReasonLength keeps reasons small, since they are stored on-chain in the event log
+const maxRevocationReasonLength = 256
+
+// RevokeCertificateRequest represents a certificate revocation request
+type RevokeCertificateRequest struct {
+	CertificateHash string `json:"certificateHash"`
+	Reason          string `json:"reason"`
+	// Mode is "submit" (default) to revoke from the platform wallet, or "prepare"
+	// to get the transaction for the instructor to sign with MetaMask
+	Mode string `json:"mode,omitempty"`
+}
+
+// RevokeCertificateHandler revokes a certificate issued by the calling instructor,
+// or any certificate when called by an admin.
+// In "submit" mode a minted certificate's revocation is sent on-chain from the platform
+// wallet and stored as pending until its CertificateRevoked event is indexed; certificates
+// without their own registry entry are revoked immediately.
+// In "prepare" mode the revokeCertificate transaction is returned unsigned; the
+// event listener records the revocation once the instructor's transaction is mined.
+func RevokeCertificateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	wallet, _ := r.Context().Value("wallet").(string)
+	if wallet == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req RevokeCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		return
+	}
+
+	certHash := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.CertificateHash), "0x"))
+	reason := strings.TrimSpace(req.Reason)
+	if certHash == "" || reason == "" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "Certificate hash and reason are required",
+		})
+		return
+	}
+	if len(reason) > maxRevocationReasonLength {
+		respondJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "Reason is too long",
+		})
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = "submit"
+	}
+	if req.Mode != "submit" && req.Mode != "prepare" {
+		respondJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "Mode must be \"submit\" or \"prepare\"",
+		})
+		return
+	}
+
+	ctx := r.Context()
+
+	cert, err := db.Repos.Certificates.Get(ctx, certHash)
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Certificate not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load certificate"})
+		return
+	}
+
+	// Certificates created before issuers were recorded have no instructor wallet;
+	// only admins may revoke those. In submit mode the platform wallet sends the
+	// transaction, so this is the only issuer check.
+	isAdmin := r.Context().Value("role") == "admin"
+	if !isAdmin && (cert.InstructorWallet == "" || !strings.EqualFold(cert.InstructorWallet, wallet)) {
+		respondJSON(w, http.StatusForbidden, map[string]string{
+			"error": "Only the issuing instructor can revoke this certificate",
+		})
+		return
+	}
+	if cert.Revoked {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate already revoked"})
+		return
+	}
+	if cert.PendingRevocation != nil {
+		respondJSON(w, http.StatusConflict, map[string]string{
+			"error":  "Certificate revocation is already pending",
+			"txHash": cert.PendingRevocation.TxHash,
+		})
+		return
+	}
+	if cert.IsMinted && !blockchain.IsActiveChain(cert.Chain) {
+		respondJSON(w, http.StatusConflict, map[string]string{
+			"error": "Certificate is on a retired registry deployment and can no longer be revoked on-chain",
+		})
+		return
+	}
+
+	if req.Mode == "prepare" {
+		prepareRevocation(w, cert.IsMinted, certHash, reason)
+		return
+	}
+
+	// Only minted certificates have their own registry entry; pending and
+	// Merkle-anchored ones are revoked in storage only
+	if cert.IsMinted {
+		submitRevocation(w, r, certHash, wallet, reason)
+		return
+	}
+
+	revokedAt := time.Now()
+	if err := services.NewRevocationService().Record(ctx, certHash, wallet, reason, revokedAt); err != nil {
+		log.Printf("Failed to record revocation for %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to record revocation",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":         true,
+		"certificateHash": certHash,
+		"status":          "revoked",
+		"reason":          reason,
+		"revokedAt":       revokedAt,
+	})
+}
+
+// submitRevocation sends the revokeCertificate transaction from the platform wallet and
+// records it as pending; the revocation is final once its CertificateRevoked event is indexed
+func submitRevocation(w http.ResponseWriter, r *http.Request, certHash, wallet, reason string) {
+	txHash, err := blockchain.GetClient().Revoke(certHash, reason)
+	if err != nil {
+		log.Printf("On-chain revocation failed for %s: %v", certHash, err)
+		respondJSON(w, http.StatusBadGateway, map[string]string{
+			"error": "Blockchain revocation failed",
+		})
+		return
+	}
+
+	pending := models.PendingRevocation{
+		TxHash:      txHash,
+		RequestedBy: wallet,
+		Reason:      reason,
+		RequestedAt: time.Now(),
+	}
+	if err := db.Repos.Certificates.MarkRevocationPending(r.Context(), certHash, pending); err != nil {
+		// The transaction is out; its event still revokes the certificate, only
+		// crediting the platform wallet instead of the requester
+		log.Printf("Failed to record pending revocation for %s (tx %s): %v", certHash, txHash, err)
+	}
+
+	respondJSON(w, http.StatusAccepted, map[string]interface{}{
+		"success":         true,
+		"certificateHash": certHash,
+		"status":          "pending_revoke",
+		"message":         "Revocation sent. It takes effect once the transaction is mined.",
+		"txHash":          txHash,
+		"reason":          reason,
+	})
+}
+
+// prepareRevocation responds with the unsigned revokeCertificate transaction
+func prepareRevocation(w http.ResponseWriter, minted bool, certHash, reason string) {
+	if !minted {
+		respondJSON(w, http.StatusConflict, map[string]string{
+			"error": "Certificate is not minted on-chain; revoke it with mode \"submit\"",
+		})
+		return
+	}
+
+	chain := blockchain.GetRealClient()
+	if chain == nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "Wallet revocation requires a real or simulated blockchain",
+		})
+		return
+	}
+
+	data, err := blockchain.EncodeRevokeCertificate(certHash, reason)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":         true,
+		"certificateHash": certHash,
+		"status":          "pending_revoke",
+		"message":         "Revocation prepared. Sign the transaction with MetaMask.",
+		"transaction": map[string]interface{}{
+			"to":      chain.ContractAddress().Hex(),
+			"data":    data,
+			"chainId": chain.ChainID().String(),
+		},
+	})
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+)
+
+func TestRevokeSubmitIsPendingUntilMined(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+
+	const instructor = "0x1111111111111111111111111111111111111111"
+	hash := strings.Repeat("cd", 32)
+	if _, err := blockchain.GetMockClient().Mint(hash, "0xabcabcabcabcabcabcabcabcabcabcabcabcabca", ""); err != nil {
+		t.Fatal(err)
+	}
+	cert := &models.Certificate{Hash: hash, InstructorWallet: instructor, IsMinted: true}
+	if err := db.Repos.Certificates.Save(ctx, cert); err != nil {
+		t.Fatal(err)
+	}
+
+	revoke := func() *httptest.ResponseRecorder {
+		body := strings.NewReader(`{"certificateHash":"0x` + hash + `","reason":"plagiarism"}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/instructor/certificates/revoke", body)
+		req = req.WithContext(context.WithValue(req.Context(), "wallet", instructor))
+		rec := httptest.NewRecorder()
+		RevokeCertificateHandler(rec, req)
+		return rec
+	}
+
+	rec := revoke()
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body, http.StatusAccepted)
+	}
+	var resp struct {
+		Status string `json:"status"`
+		TxHash string `json:"txHash"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != "pending_revoke" || resp.TxHash == "" {
+		t.Errorf("response = %+v, want pending_revoke with a txHash", resp)
+	}
+
+	// Not revoked until the CertificateRevoked event is indexed
+	stored, _ := db.Repos.Certificates.Get(ctx, hash)
+	if stored.Revoked {
+		t.Error("certificate revoked before its transaction was mined")
+	}
+	if stored.PendingRevocation == nil || stored.PendingRevocation.TxHash != resp.TxHash ||
+		stored.PendingRevocation.RequestedBy != instructor || stored.PendingRevocation.Reason != "plagiarism" {
+		t.Errorf("PendingRevocation = %+v", stored.PendingRevocation)
+	}
+
+	// A second request doesn't send another transaction
+	if rec := revoke(); rec.Code != http.StatusConflict {
+		t.Errorf("repeat status = %d (%s), want %d", rec.Code, rec.Body, http.StatusConflict)
+	}
+}
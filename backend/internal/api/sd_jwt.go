@@ -0,0 +1,318 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/services"
+	"cache-crew/cognify/internal/utils"
+)
+
+// SD-JWT certificates follow SD-JWT VC: the typ and vct the issuer JWT carries
+const (
+	sdJWTType           = "dc+sd-jwt"
+	sdJWTCredentialType = "urn:cognify:certificate"
+)
+
+// sdJWTClaims are the certificate claims a holder may disclose, in the order
+// their disclosures are issued
+var sdJWTClaims = []string{
+	"studentName", "studentId", "courseName", "courseId", "issuedAt", "marks",
+	"instructorName", "instructorWallet", "walletAddress", "academicDNA", "certificateHash",
+}
+
+// IssueSDJWTRequest names the certificate to issue and, optionally, the claims to
+// put in a ready-made presentation
+type IssueSDJWTRequest struct {
+	CertificateHash string   `json:"certificateHash"`
+	Disclose        []string `json:"disclose,omitempty"`
+}
+
+// IssueSDJWTHandler issues the holder of a minted or anchored certificate an
+// SD-JWT of it, signed by the platform DID. Every claim is selectively
+// disclosable: the holder presents the JWT with the disclosures of the claims
+// they choose to reveal, and verifiers learn nothing of the rest.
+// POST /api/certificates/sd-jwt (wallet auth; the certificate's wallet only)
+func IssueSDJWTHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	wallet, _ := r.Context().Value("wallet").(string)
+	if wallet == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req IssueSDJWTRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		return
+	}
+	for _, name := range req.Disclose {
+		if !containsString(sdJWTClaims, name) {
+			respondJSON(w, http.StatusBadRequest, map[string]string{"error": "Unknown claim: " + name})
+			return
+		}
+	}
+
+	ctx := r.Context()
+	certHash := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.CertificateHash), "0x"))
+	cert, err := db.Repos.Certificates.Get(ctx, certHash)
+	if errors.Is(err, db.ErrNotFound) || (err == nil && !strings.EqualFold(cert.WalletAddress, wallet)) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Certificate not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load certificate"})
+		return
+	}
+	if !cert.IsMinted && cert.AnchorRoot == "" {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate is not on-chain yet"})
+		return
+	}
+	if cert.Revoked {
+		respondJSON(w, http.StatusConflict, map[string]string{"error": "Certificate has been revoked"})
+		return
+	}
+	if err := ensureStatusListEntry(ctx, cert); err != nil {
+		log.Printf("Failed to assign status list entry to %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to assign certificate status"})
+		return
+	}
+
+	jwt, disclosures, err := issueSDJWT(cert)
+	if err != nil {
+		log.Printf("Failed to issue SD-JWT for %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to sign certificate"})
+		return
+	}
+
+	all := make([]string, 0, len(disclosures))
+	chosen := make([]string, 0, len(req.Disclose))
+	for _, name := range sdJWTClaims {
+		if d, ok := disclosures[name]; ok {
+			all = append(all, d)
+			if containsString(req.Disclose, name) {
+				chosen = append(chosen, d)
+			}
+		}
+	}
+
+	resp := map[string]interface{}{
+		"sdJwt":       utils.JoinSDJWT(jwt, all),
+		"disclosures": disclosures,
+	}
+	if len(req.Disclose) > 0 {
+		resp["presentation"] = utils.JoinSDJWT(jwt, chosen)
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// issueSDJWT signs cert's claims as an SD-JWT VC and returns the issuer JWT with
+// each claim's disclosure. Only the issuer, type and status are in the clear;
+// the certificate hash is disclosable too, since anyone holding it can look up
+// the full certificate.
+func issueSDJWT(cert *models.Certificate) (string, map[string]string, error) {
+	claims := cert.Claims()
+	values := map[string]interface{}{
+		"studentName":      claims.StudentName,
+		"studentId":        claims.StudentID,
+		"courseName":       claims.CourseName,
+		"courseId":         claims.CourseID,
+		"issuedAt":         cert.IssuedAt.UTC().Format(time.RFC3339),
+		"marks":            claims.Marks,
+		"instructorName":   claims.InstructorName,
+		"instructorWallet": claims.InstructorWallet,
+		"walletAddress":    claims.WalletAddress,
+		"academicDNA":      claims.AcademicDNA,
+		"certificateHash":  "0x" + cert.Hash,
+	}
+
+	digests := make([]string, 0, len(sdJWTClaims))
+	disclosures := make(map[string]string, len(sdJWTClaims))
+	for _, name := range sdJWTClaims {
+		if s, ok := values[name].(string); ok && s == "" {
+			continue
+		}
+		d, err := utils.NewSDJWTDisclosure(name, values[name])
+		if err != nil {
+			return "", nil, err
+		}
+		digests = append(digests, d.Digest())
+		disclosures[name] = d.Encoded
+	}
+	sort.Strings(digests) // Hide which digest belongs to which claim
+
+	payload := map[string]interface{}{
+		"iss":     blockchain.PlatformDID(),
+		"iat":     time.Now().Unix(),
+		"vct":     sdJWTCredentialType,
+		"_sd":     digests,
+		"_sd_alg": utils.SDJWTHashAlg,
+	}
+	if cert.StatusListID != "" {
+		// SD-JWT VC status: an index into the list, served as a Token Status List
+		payload["status"] = map[string]interface{}{
+			"status_list": map[string]interface{}{
+				"idx": cert.StatusListIndex,
+				"uri": statusListURL(cert.StatusListID),
+			},
+		}
+	}
+
+	jwt, err := blockchain.SignJWS(map[string]interface{}{"typ": sdJWTType}, payload)
+	if err != nil {
+		return "", nil, err
+	}
+	return jwt, disclosures, nil
+}
+
+// verifySDJWT checks an SD-JWT presentation: the platform's signature, that each
+// disclosure is one the platform signed, and revocation through the status list
+// and, when the certificate hash is disclosed, the registry
+func verifySDJWT(ctx context.Context, presentation string) models.SDJWTVerification {
+	result := models.SDJWTVerification{}
+
+	jwt, encoded, keyBinding, err := utils.SplitSDJWT(presentation)
+	if err != nil {
+		result.Message = "Presentation is not an SD-JWT"
+		return result
+	}
+	if keyBinding != "" {
+		result.Message = "Key binding is not supported for certificate SD-JWTs"
+		return result
+	}
+
+	// Step 1: Issuer JWT signed by the platform DID
+	signer, rawPayload, err := blockchain.VerifyJWS(jwt)
+	if err != nil || !blockchain.IsPlatformAddress(signer) {
+		log.Printf("SD-JWT rejected: %v", err)
+		result.Message = "SD-JWT signature is invalid or not from the platform"
+		return result
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(rawPayload, &payload); err != nil || payload["_sd_alg"] != utils.SDJWTHashAlg {
+		result.Message = "SD-JWT payload is malformed"
+		return result
+	}
+	// Issued before a key rotation or deployment migration, iss names an earlier platform DID
+	result.Issuer, _ = payload["iss"].(string)
+	if !blockchain.IsPlatformDID(result.Issuer) {
+		result.Message = "SD-JWT issuer is not the platform"
+		return result
+	}
+	result.SignatureValid = true
+
+	// Step 2: Each disclosure must hash to a digest the platform signed, once
+	digests := make(map[string]bool)
+	for _, digest := range credentialStrings(payload["_sd"]) {
+		digests[digest] = true
+	}
+	result.Disclosed = make(map[string]interface{}, len(encoded))
+	for _, e := range encoded {
+		d, err := utils.ParseSDJWTDisclosure(e)
+		if err != nil || !digests[d.Digest()] || payload[d.Name] != nil || result.Disclosed[d.Name] != nil {
+			result.Disclosed = nil
+			result.Message = "SD-JWT disclosures do not match the signed digests"
+			return result
+		}
+		delete(digests, d.Digest())
+		result.Disclosed[d.Name] = d.Value
+	}
+	result.HiddenClaims = len(digests)
+
+	// Step 3: Revocation, from the status list and from the registry when the hash is disclosed
+	if status, ok := payload["status"].(map[string]interface{}); ok {
+		ref, _ := status["status_list"].(map[string]interface{})
+		uri, _ := ref["uri"].(string)
+		index, isIndex := ref["idx"].(float64)
+		if !isIndex || index != float64(int(index)) {
+			result.Message = "SD-JWT status is malformed"
+			return result
+		}
+		revoked, err := statusListRevoked(ctx, uri, int(index))
+		if err != nil {
+			log.Printf("SD-JWT status check failed: %v", err)
+			result.Message = "Certificate status could not be checked"
+			return result
+		}
+		result.Revoked = revoked
+	}
+	if certHash, ok := result.Disclosed["certificateHash"].(string); ok {
+		result.CertificateHash = certHash
+		if !anchorSDJWT(ctx, &result) {
+			return result
+		}
+	}
+
+	result.Verified = !result.Revoked
+	if result.Revoked {
+		result.Message = "⚠️ CERTIFICATE REVOKED"
+	}
+	return result
+}
+
+// anchorSDJWT checks a disclosed certificate hash in the registry it was
+// recorded on, reporting false with a message when it is not there
+func anchorSDJWT(ctx context.Context, result *models.SDJWTVerification) bool {
+	certHash := strings.ToLower(strings.TrimPrefix(result.CertificateHash, "0x"))
+	stored, err := db.Repos.Certificates.Get(ctx, certHash)
+	if err != nil {
+		stored = nil
+	}
+	if stored != nil {
+		result.Chain = stored.Chain
+	}
+
+	client, err := blockchain.ClientFor(result.Chain)
+	if err != nil {
+		log.Printf("No registry client for certificate %s: %v", certHash, err)
+		result.Message = "Certificate was recorded on an unknown registry deployment"
+		return false
+	}
+	record, err := client.VerifyCertificate(certHash)
+	if err != nil {
+		log.Printf("Blockchain verification error: %v", err)
+		result.Message = "Blockchain verification failed"
+		return false
+	}
+	if !record.Exists {
+		if _, rootRecord := anchoredRoot(client, certHash, VerifyCertificateRequest{}, stored); rootRecord != nil {
+			record = rootRecord
+		}
+	}
+	if !record.Exists {
+		result.Message = "Certificate is not anchored in the registry"
+		return false
+	}
+	result.Anchored = true
+	result.Revoked = result.Revoked || record.Revoked
+	return true
+}
+
+// statusListRevoked reads the bit at index in one of the platform's own status
+// lists, named by the URL it is served at
+func statusListRevoked(ctx context.Context, listURL string, index int) (bool, error) {
+	prefix := statusListURL("")
+	if !strings.HasPrefix(listURL, prefix) {
+		return false, errors.New("status does not name a platform status list")
+	}
+
+	list, err := db.Repos.StatusLists.Get(ctx, strings.TrimPrefix(listURL, prefix))
+	if err != nil {
+		return false, err
+	}
+	return services.StatusBit(list, index), nil
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/services"
+	"cache-crew/cognify/internal/utils"
+)
+
+// newSDJWTCertificate stores and mints a certificate with a status list entry
+func newSDJWTCertificate(t *testing.T) *models.Certificate {
+	t.Helper()
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+
+	// The mock chain outlives each test, so every test mints its own certificate
+	cert := &models.Certificate{
+		StudentID:        t.Name(),
+		StudentName:      "Ada Lovelace",
+		CourseID:         "course-1",
+		CourseName:       "Analytical Engines",
+		Marks:            72,
+		WalletAddress:    "0xabcabcabcabcabcabcabcabcabcabcabcabcabca",
+		InstructorWallet: "0x1111111111111111111111111111111111111111",
+		IssuedAt:         time.Unix(1700000000, 0).UTC(),
+	}
+	if err := hashCertificate(cert); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := blockchain.GetClient().Mint(cert.Hash, cert.WalletAddress, cert.AcademicDNA); err != nil {
+		t.Fatal(err)
+	}
+	cert.IsMinted = true
+	if err := services.NewStatusListService().Assign(ctx, cert); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Repos.Certificates.Save(ctx, cert); err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestVerifySDJWT(t *testing.T) {
+	cert := newSDJWTCertificate(t)
+	jwt, disclosures, err := issueSDJWT(cert)
+	if err != nil {
+		t.Fatalf("issueSDJWT: %v", err)
+	}
+
+	forgedMarks, _ := utils.NewSDJWTDisclosure("marks", 99)
+	forgedIssuer, _ := utils.NewSDJWTDisclosure("iss", "did:ethr:0x1:0x0000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name        string
+		disclosures []string
+		verified    bool
+		disclosed   []string
+		hidden      int
+	}{
+		{"nothing disclosed", nil, true, nil, len(disclosures)},
+		{"subset", []string{disclosures["studentName"], disclosures["courseName"]}, true, []string{"studentName", "courseName"}, len(disclosures) - 2},
+		{"with the hash", []string{disclosures["marks"], disclosures["certificateHash"]}, true, []string{"marks", "certificateHash"}, len(disclosures) - 2},
+		{"forged claim", []string{disclosures["studentName"], forgedMarks.Encoded}, false, nil, 0},
+		{"repeated disclosure", []string{disclosures["marks"], disclosures["marks"]}, false, nil, 0},
+		{"overrides a clear claim", []string{forgedIssuer.Encoded}, false, nil, 0},
+		{"not a disclosure", []string{"bm90IGpzb24"}, false, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := verifySDJWT(context.Background(), utils.JoinSDJWT(jwt, tt.disclosures))
+			if result.Verified != tt.verified {
+				t.Fatalf("Verified = %v (%s), want %v", result.Verified, result.Message, tt.verified)
+			}
+			if !tt.verified {
+				if result.Disclosed != nil {
+					t.Errorf("rejected presentation disclosed %v", result.Disclosed)
+				}
+				return
+			}
+			if len(result.Disclosed) != len(tt.disclosed) || result.HiddenClaims != tt.hidden {
+				t.Errorf("Disclosed = %v, HiddenClaims = %d, want %v and %d", result.Disclosed, result.HiddenClaims, tt.disclosed, tt.hidden)
+			}
+			for _, name := range tt.disclosed {
+				if _, ok := result.Disclosed[name]; !ok {
+					t.Errorf("%s not disclosed", name)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifySDJWTRejectsTamperedJWT(t *testing.T) {
+	cert := newSDJWTCertificate(t)
+	jwt, disclosures, err := issueSDJWT(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-signing is not possible, so swapping in another certificate's JWT must fail
+	// against these disclosures, and a key binding JWT is refused
+	other := *cert
+	other.Hash = "00" + cert.Hash[2:]
+	otherJWT, _, err := issueSDJWT(&other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		presentation string
+	}{
+		{"other JWT", utils.JoinSDJWT(otherJWT, []string{disclosures["studentName"]})},
+		{"truncated signature", utils.JoinSDJWT(jwt[:len(jwt)-8], nil)},
+		{"key binding", utils.JoinSDJWT(jwt, nil) + "kb.jwt.sig"},
+		{"bare JWT", jwt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := verifySDJWT(context.Background(), tt.presentation); result.Verified {
+				t.Error("tampered presentation verified")
+			}
+		})
+	}
+}
+
+func TestVerifySDJWTRevoked(t *testing.T) {
+	cert := newSDJWTCertificate(t)
+	jwt, _, err := issueSDJWT(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the status list bit tells a verifier with no disclosed hash
+	if err := services.NewRevocationService().Record(context.Background(), cert.Hash, "admin", "test", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	result := verifySDJWT(context.Background(), utils.JoinSDJWT(jwt, nil))
+	if result.Verified || !result.Revoked {
+		t.Errorf("result = %+v, want revoked", result)
+	}
+}
+
+func TestVerifySDJWTIssuer(t *testing.T) {
+	cert := newSDJWTCertificate(t)
+	jwt, disclosures, err := issueSDJWT(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	platform, _, err := blockchain.VerifyJWS(jwt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A registry deployment the platform migrated away from
+	blockchain.SetDeployments([]models.Deployment{{Name: "mumbai", ChainID: 80001, ContractAddress: "0x3333333333333333333333333333333333333333"}})
+	t.Cleanup(func() { blockchain.SetDeployments(nil) })
+
+	tests := []struct {
+		name     string
+		issuer   string
+		verified bool
+	}{
+		{"current platform DID", blockchain.PlatformDID(), true},
+		{"platform DID on a retired deployment", blockchain.EthrDID(80001, platform), true},
+		{"platform wallet on an unknown chain", blockchain.EthrDID(5, platform), false},
+		{"another wallet", "did:ethr:0x539:0x0000000000000000000000000000000000000001", false},
+		{"not a DID", "cognify", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Re-signed by the platform with only the issuer changed
+			parts := strings.SplitN(jwt, ".", 3)
+			raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var payload map[string]interface{}
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				t.Fatal(err)
+			}
+			payload["iss"] = tt.issuer
+			resigned, err := blockchain.SignJWS(map[string]interface{}{"typ": sdJWTType}, payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := verifySDJWT(context.Background(), utils.JoinSDJWT(resigned, []string{disclosures["studentName"]}))
+			if result.Verified != tt.verified {
+				t.Errorf("Verified = %v (%s), want %v", result.Verified, result.Message, tt.verified)
+			}
+		})
+	}
+}
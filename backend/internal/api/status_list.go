@@ -0,0 +1,201 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/config"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+// statusListEntryType is the credentialStatus type of a W3C Bitstring Status List entry
+const statusListEntryType = "BitstringStatusListEntry"
+
+// statusListTokenType is the media type of a Token Status List JWT
+const statusListTokenType = "application/statuslist+jwt"
+
+// statusListMaxAge is how long clients and caches may reuse a status list credential
+const statusListMaxAge = 5 * time.Minute
+
+// signedStatusList is the signed credential for one version of a status list
+type signedStatusList struct {
+	version int64
+	body    []byte
+}
+
+// Signed status lists are cached per signer and version so fetches don't each
+// need a signature from the platform wallet
+var (
+	statusListCacheMu sync.Mutex
+	statusListCache   = make(map[string]signedStatusList)
+	statusListSigning singleflight.Group
+)
+
+// GetStatusListHandler serves a revocation status list as a BitstringStatusListCredential
+// signed by the platform DID, or as a Token Status List JWT when that is what the
+// client accepts and SD-JWTs are enabled. Credentials link here from their credentialStatus and SD-JWTs from
+// their status claim, so wallets and offline verifiers can check revocation without
+// reaching the chain.
+// GET /api/credentials/status/{id}
+func GetStatusListHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	list, err := db.Repos.StatusLists.Get(r.Context(), id)
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Status list not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load status list %s: %v", id, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load status list"})
+		return
+	}
+
+	// SD-JWT VCs reference the same list, which their verifiers fetch as a Token Status List
+	format, contentType, sign := "vc", "application/vc+ld+json", signStatusList
+	if config.AppConfig.SDJWTEnabled && strings.Contains(r.Header.Get("Accept"), statusListTokenType) {
+		format, contentType, sign = "jwt", statusListTokenType, signStatusListToken
+	}
+
+	body, err := cachedStatusList(list, format, sign)
+	if err != nil {
+		log.Printf("Failed to sign status list %s: %v", id, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to sign status list"})
+		return
+	}
+
+	// Re-signing the same version gives a new proof but the same statuses, hence a weak ETag
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Vary", "Accept")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(statusListMaxAge.Seconds())))
+	w.Header().Set("ETag", fmt.Sprintf(`W/"%s-%d-%s"`, list.ID, list.Version, format))
+	http.ServeContent(w, r, "", list.UpdatedAt, bytes.NewReader(body))
+}
+
+// cachedStatusList returns list's current version signed by sign, in the given
+// format. Signing runs outside the cache lock, once per version however many
+// requests ask for it; the key names the platform DID so a rotated key re-signs.
+func cachedStatusList(list *models.StatusList, format string, sign func(*models.StatusList) ([]byte, error)) ([]byte, error) {
+	key := blockchain.PlatformDID() + "/" + list.ID + "/" + format
+	statusListCacheMu.Lock()
+	cached, ok := statusListCache[key]
+	statusListCacheMu.Unlock()
+	if ok && cached.version == list.Version {
+		return cached.body, nil
+	}
+
+	body, err, _ := statusListSigning.Do(fmt.Sprintf("%s/%d", key, list.Version), func() (interface{}, error) {
+		body, err := sign(list)
+		if err != nil {
+			return nil, err
+		}
+		statusListCacheMu.Lock()
+		defer statusListCacheMu.Unlock()
+		// A slower signing of an older version mustn't replace a newer one
+		if cached, ok := statusListCache[key]; !ok || cached.version < list.Version {
+			statusListCache[key] = signedStatusList{version: list.Version, body: body}
+		}
+		return body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body.([]byte), nil
+}
+
+// signStatusList returns list as a signed BitstringStatusListCredential
+func signStatusList(list *models.StatusList) ([]byte, error) {
+	encoded, err := services.EncodeStatusList(list)
+	if err != nil {
+		return nil, err
+	}
+	url := statusListURL(list.ID)
+	credential := models.VerifiableCredential{
+		"@context": []string{
+			"https://www.w3.org/ns/credentials/v2",
+			"https://w3id.org/security/suites/eip712sig-2021/v1",
+		},
+		"id":        url,
+		"type":      []string{"VerifiableCredential", "BitstringStatusListCredential"},
+		"issuer":    blockchain.PlatformDID(),
+		"validFrom": list.UpdatedAt.UTC().Format(time.RFC3339),
+		"credentialSubject": map[string]interface{}{
+			"id":            url + "#list",
+			"type":          "BitstringStatusList",
+			"statusPurpose": list.Purpose,
+			"encodedList":   encoded,
+		},
+	}
+	if err := blockchain.SignCredential(credential); err != nil {
+		return nil, err
+	}
+	return json.Marshal(credential)
+}
+
+// signStatusListToken returns list as a Token Status List JWT signed by the platform DID
+func signStatusListToken(list *models.StatusList) ([]byte, error) {
+	encoded, err := services.EncodeTokenStatusList(list)
+	if err != nil {
+		return nil, err
+	}
+	token, err := blockchain.SignJWS(map[string]interface{}{"typ": "statuslist+jwt"}, map[string]interface{}{
+		"iss": blockchain.PlatformDID(),
+		"sub": statusListURL(list.ID),
+		"iat": time.Now().Unix(),
+		"ttl": int(statusListMaxAge.Seconds()),
+		"status_list": map[string]interface{}{
+			"bits": 1,
+			"lst":  encoded,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(token), nil
+}
+
+// statusListURL is the stable URL a status list is served at
+func statusListURL(id string) string {
+	return config.AppConfig.PublicURL + "/api/credentials/status/" + id
+}
+
+// addStatusListEntry makes status a BitstringStatusListEntry for cert's status
+// list bit as well. The registry fields stay alongside: EIP-712 cannot sign a
+// credentialStatus array holding differently shaped entries.
+func addStatusListEntry(status map[string]interface{}, cert *models.Certificate) {
+	if cert.StatusListID == "" {
+		return
+	}
+	url := statusListURL(cert.StatusListID)
+	status["id"] = fmt.Sprintf("%s#%d", url, cert.StatusListIndex)
+	status["type"] = statusListEntryType
+	status["statusPurpose"] = services.StatusListPurpose
+	status["statusListIndex"] = strconv.Itoa(cert.StatusListIndex)
+	status["statusListCredential"] = url
+}
+
+// ensureStatusListEntry assigns a status list index to a certificate issued
+// before status lists existed, so credentials exported for it carry one
+func ensureStatusListEntry(ctx context.Context, cert *models.Certificate) error {
+	if cert.StatusListID != "" {
+		return nil
+	}
+	if err := services.NewStatusListService().Assign(ctx, cert); err != nil {
+		return err
+	}
+	return db.Repos.Certificates.Save(ctx, cert)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"cache-crew/cognify/internal/models"
+)
+
+func TestCachedStatusListSignsOncePerVersion(t *testing.T) {
+	statusListCacheMu.Lock()
+	statusListCache = make(map[string]signedStatusList)
+	statusListCacheMu.Unlock()
+
+	list := &models.StatusList{ID: t.Name(), Version: 1}
+	var signed atomic.Int32
+	release := make(chan struct{})
+	slowSign := func(l *models.StatusList) ([]byte, error) {
+		signed.Add(1)
+		<-release
+		return []byte(fmt.Sprintf("v%d", l.Version)), nil
+	}
+
+	var wg sync.WaitGroup
+	bodies := make([][]byte, 8)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body, err := cachedStatusList(list, "vc", slowSign)
+			if err != nil {
+				t.Error(err)
+			}
+			bodies[i] = body
+		}(i)
+	}
+
+	// Another list is served while the first is still being signed
+	other := &models.StatusList{ID: t.Name() + "-other", Version: 1}
+	if body, err := cachedStatusList(other, "vc", func(*models.StatusList) ([]byte, error) { return []byte("other"), nil }); err != nil || string(body) != "other" {
+		t.Fatalf("other list = %q, %v", body, err)
+	}
+
+	close(release)
+	wg.Wait()
+	if n := signed.Load(); n != 1 {
+		t.Errorf("signed %d times for concurrent fetches, want 1", n)
+	}
+	for i, body := range bodies {
+		if string(body) != "v1" {
+			t.Errorf("fetch %d = %q, want v1", i, body)
+		}
+	}
+
+	// Cached until the list changes
+	if body, _ := cachedStatusList(list, "vc", slowSign); string(body) != "v1" || signed.Load() != 1 {
+		t.Errorf("cached fetch = %q after %d signatures", body, signed.Load())
+	}
+	list.Version = 2
+	if body, _ := cachedStatusList(list, "vc", slowSign); string(body) != "v2" || signed.Load() != 2 {
+		t.Errorf("new version = %q after %d signatures", body, signed.Load())
+	}
+}
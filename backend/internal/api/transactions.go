@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"cache-crew/cognify/internal/db"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetTransactionHandler returns the status of a platform wallet transaction.
+// The ID is the hash returned when the transaction was submitted; if its fees were
+// bumped, "hash" holds the replacement that is pending or was mined.
+func GetTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	tx, err := db.Repos.Transactions.Get(r.Context(), id)
+	if errors.Is(err, db.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, map[string]string{"error": "Transaction not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load transaction %s: %v", id, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to load transaction"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tx)
+}
+
+// ListPendingTransactionsHandler returns every platform wallet transaction not yet mined
+func ListPendingTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	txs, err := db.Repos.Transactions.ListPending(r.Context())
+	if err != nil {
+		log.Printf("Failed to list pending transactions: %v", err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to list transactions"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"transactions": txs,
+		"count":        len(txs),
+	})
+}
@@ -2,26 +2,46 @@ package api
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"cache-crew/cognify/internal/blockchain"
-	"cache-crew/cognify/internal/config"
 	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/middleware"
 	"cache-crew/cognify/internal/models"
 	"cache-crew/cognify/internal/services"
+	"cache-crew/cognify/internal/utils"
 )
 
 // VerifyCertificateRequest represents a verification request
 type VerifyCertificateRequest struct {
 	CertificateHash string `json:"certificateHash"`
-	PDFFile         string `json:"pdfFile,omitempty"` // Base64 encoded PDF (future feature)
+	PDFFile         string `json:"pdfFile,omitempty"` // Base64 encoded certificate PDF, or a data: URL of one
+
+	// Optional certificate contents. They are hashed and must produce the hash being
+	// verified (which may then be omitted), so altered names or marks fail.
+	Certificate *models.CertificateClaims `json:"certificate,omitempty"`
+
+	// Optional inclusion proof for a Merkle-anchored certificate. When omitted,
+	// the proof stored with the certificate is used.
+	MerkleRoot  string   `json:"merkleRoot,omitempty"`
+	MerkleProof []string `json:"merkleProof,omitempty"`
+
+	// Optional SD-JWT presentation. It is verified on its own, and only the
+	// claims it discloses are returned.
+	SDJWT string `json:"sdJwt,omitempty"`
 }
 
-// VerifyCertificateHandler handles public certificate verification
+// VerifyCertificateHandler handles public certificate verification.
+// A certificate verifies either through its own registry entry or through a
+// Merkle proof against a root anchored in the registry. Claims presented with
+// the request, and those stored for it, must hash to the verified hash. An SD-JWT
+// presentation is instead checked against the digests the platform signed. A
+// certificate PDF, uploaded as the "pdf" field of a multipart form or as pdfFile,
+// must carry a platform seal whose hash its printed claims reproduce.
 func VerifyCertificateHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -29,61 +49,102 @@ func VerifyCertificateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req VerifyCertificateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	pdf, err := decodeVerifyRequest(w, r, &req)
+	if err != nil {
 		respondJSON(w, http.StatusBadRequest, map[string]string{
 			"error": "Invalid request body",
 		})
 		return
 	}
 
+	ctx := r.Context()
+
+	if pdf != nil {
+		tampered, err := checkCertificatePDF(ctx, pdf, &req)
+		if errors.Is(err, services.ErrNotCertificatePDF) {
+			respondJSON(w, http.StatusBadRequest, map[string]string{
+				"error": "PDF has no Cognify certificate seal",
+			})
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to read certificate PDF: %v", err)
+			respondJSON(w, http.StatusBadRequest, map[string]string{
+				"error": "Invalid certificate PDF",
+			})
+			return
+		}
+		if tampered != nil {
+			logVerification(ctx, req.CertificateHash, false, r.RemoteAddr, r.UserAgent())
+			respondJSON(w, http.StatusOK, tampered)
+			return
+		}
+	}
+
+	if req.SDJWT != "" {
+		result := verifySDJWT(ctx, req.SDJWT)
+		logVerification(ctx, strings.TrimPrefix(result.CertificateHash, "0x"), result.Verified, r.RemoteAddr, r.UserAgent())
+		respondJSON(w, http.StatusOK, result)
+		return
+	}
+
 	// Validate hash format
 	certHash := strings.TrimSpace(req.CertificateHash)
+	claimsVerified := false
+	if req.Certificate != nil {
+		if req.Certificate.Version != models.CertificateHashVersion {
+			respondJSON(w, http.StatusBadRequest, map[string]string{
+				"error": "Unsupported certificate hash version",
+			})
+			return
+		}
+		claimsHash, err := utils.GenerateCanonicalHash(req.Certificate)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, map[string]string{
+				"error": "Invalid certificate data",
+			})
+			return
+		}
+		if certHash == "" {
+			certHash = claimsHash
+		}
+		if strings.ToLower(strings.TrimPrefix(certHash, "0x")) != claimsHash {
+			logVerification(ctx, certHash, false, r.RemoteAddr, r.UserAgent())
+			respondJSON(w, http.StatusOK, models.VerificationResponse{
+				Verified: false,
+				Message:  "Certificate data does not match its hash; it has been altered",
+			})
+			return
+		}
+		claimsVerified = true
+	}
 	if certHash == "" {
 		respondJSON(w, http.StatusBadRequest, map[string]string{
-			"error": "Certificate hash is required",
+			"error": "Certificate hash or certificate data is required",
 		})
 		return
 	}
 
-	ctx := r.Context()
-
-	// MOCK MODE: Bypass Blockchain/DB if DB is nil
-	if db.FirestoreClient == nil {
-		log.Println("[VerifyCertificateHandler] ⚠️ Firestore uninitialized. Returning MOCK verification result.")
-		response := models.VerificationResponse{
-			Verified:          true,
-			StudentName:       "Mock Student",
-			CourseName:        "Introduction to Blockchain (Mock)",
-			Issuer:            "Cognify University (Mock Mode)",
-			WalletAddress:     "0xMockWalletAddress...",
-			IssuedAt:          time.Now().Add(-24 * time.Hour),
-			BlockchainTx:      "0xMockTransactionHash...",
-			TrustScore:        95,
-			VerificationCount: 1,
-			IPFSPdfLink:       "",
-		}
-		respondJSON(w, http.StatusOK, response)
-		return
+	// Step 1: Verify on the deployment the certificate was recorded on; certificates
+	// without a recorded chain and unknown hashes are looked up on the active one
+	stored, err := db.Repos.Certificates.Get(ctx, certHash)
+	if err != nil {
+		stored = nil
 	}
-
-	// Step 1: Verify on blockchain (works with both mock and real)
-	var onChain bool
-	var err error
-
-	if config.AppConfig.BlockchainMode == "real" {
-		realClient := blockchain.GetRealClient()
-		if realClient != nil {
-			onChain, err = realClient.VerifyCertificateHash(certHash)
-		} else {
-			// Fallback to mock
-			mockClient := blockchain.GetMockClient()
-			onChain, err = mockClient.VerifyCertificateHash(certHash)
-		}
-	} else {
-		mockClient := blockchain.GetMockClient()
-		onChain, err = mockClient.VerifyCertificateHash(certHash)
+	var chainRef *models.ChainRef
+	if stored != nil {
+		chainRef = stored.Chain
+	}
+	client, err := blockchain.ClientFor(chainRef)
+	if err != nil {
+		log.Printf("No registry client for certificate %s: %v", certHash, err)
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Certificate was recorded on an unknown registry deployment",
+		})
+		return
 	}
 
+	record, err := client.VerifyCertificate(certHash)
 	if err != nil {
 		log.Printf("Blockchain verification error: %v", err)
 		respondJSON(w, http.StatusInternalServerError, map[string]string{
@@ -92,16 +153,40 @@ func VerifyCertificateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Step 2: Fetch metadata from Firebase
+	// Step 2: Match the record with the stored metadata
 	var cert models.Certificate
 	verified := false
+	proofType := ""
+	merkleRoot := ""
+	message := "Certificate hash not found in blockchain ledger or database"
+
+	if record == nil || !record.Exists {
+		// Step 2b: No entry of its own; try a Merkle proof against an anchored root
+		if root, rootRecord := anchoredRoot(client, certHash, req, stored); rootRecord != nil {
+			record = rootRecord
+			merkleRoot = root
+		}
+	}
 
-	if db.FirestoreClient != nil && onChain {
-		doc, err := db.FirestoreClient.Collection("certificates").Doc(certHash).Get(ctx)
-		if err == nil {
-			if err := doc.DataTo(&cert); err == nil {
-				verified = true
-			}
+	// A stored record whose claims no longer hash to the anchored hash was altered
+	if stored != nil && stored.HashVersion > 0 {
+		if storedHash, err := utils.GenerateCanonicalHash(stored.Claims()); err != nil || storedHash != stored.Hash {
+			log.Printf("⚠️ Stored claims of certificate %s do not match its hash", certHash)
+			stored = nil
+			message = "Certificate record does not match its anchored hash"
+		} else {
+			claimsVerified = true
+		}
+	}
+
+	if record != nil && record.Exists && stored != nil {
+		cert = *stored
+		// The chain is authoritative for revocation even if storage lags behind
+		cert.Revoked = cert.Revoked || record.Revoked
+		verified = true
+		proofType = "direct"
+		if merkleRoot != "" {
+			proofType = "merkle"
 		}
 	}
 
@@ -134,10 +219,8 @@ func VerifyCertificateHandler(w http.ResponseWriter, r *http.Request) {
 		var issuerRep *models.IssuerReputation
 		if cert.InstructorWallet != "" {
 			// Try to find instructor ID/Wallet
-			if rep, err := db.FirestoreClient.Collection("issuer_reputation").Doc(cert.InstructorWallet).Get(ctx); err == nil {
-				var ir models.IssuerReputation
-				rep.DataTo(&ir)
-				issuerRep = &ir
+			if rep, err := db.Repos.Trust.GetIssuerReputation(ctx, cert.InstructorWallet); err == nil {
+				issuerRep = rep
 			}
 		}
 
@@ -151,8 +234,10 @@ func VerifyCertificateHandler(w http.ResponseWriter, r *http.Request) {
 		// Step 5: Build response
 		response := models.VerificationResponse{
 			Verified:          verified,
+			ClaimsVerified:    claimsVerified,
 			StudentName:       cert.StudentName,
 			CourseName:        cert.CourseName,
+			Marks:             cert.Marks,
 			Issuer:            "Cognify University",
 			WalletAddress:     cert.WalletAddress,
 			IssuedAt:          cert.IssuedAt,
@@ -160,6 +245,9 @@ func VerifyCertificateHandler(w http.ResponseWriter, r *http.Request) {
 			TrustScore:        score,
 			VerificationCount: cert.VerificationCount,
 			Revoked:           cert.Revoked,
+			ProofType:         proofType,
+			MerkleRoot:        merkleRoot,
+			Chain:             chainRef,
 			TrustLevel:        "Moderate", // Default
 		}
 
@@ -193,12 +281,35 @@ func VerifyCertificateHandler(w http.ResponseWriter, r *http.Request) {
 	} else {
 		response := models.VerificationResponse{
 			Verified: false,
-			Message:  "Certificate hash not found in blockchain ledger or database",
+			Message:  message,
 		}
 		respondJSON(w, http.StatusOK, response)
 	}
 }
 
+// anchoredRoot checks a Merkle proof for certHash, taken from the request or else
+// from storage, and returns the root with its registry record if the root is anchored
+func anchoredRoot(client blockchain.BlockchainClient, certHash string, req VerifyCertificateRequest, stored *models.Certificate) (string, *blockchain.CertificateRecord) {
+	root, proof := req.MerkleRoot, req.MerkleProof
+	if root == "" && stored != nil {
+		root, proof = stored.AnchorRoot, stored.MerkleProof
+	}
+	if root == "" || !blockchain.VerifyMerkleProof(certHash, proof, root) {
+		return "", nil
+	}
+
+	record, err := client.VerifyCertificate(root)
+	if err != nil {
+		log.Printf("Blockchain verification error for root %s: %v", root, err)
+		return "", nil
+	}
+	// Only roots minted by the anchorer count; an ordinary certificate hash is not a root
+	if !record.Exists || record.Owner != blockchain.AnchorOwner {
+		return "", nil
+	}
+	return strings.TrimPrefix(root, "0x"), record
+}
+
 // GetVerificationStatsHandler returns global verification statistics
 func GetVerificationStatsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -213,37 +324,19 @@ func GetVerificationStatsHandler(w http.ResponseWriter, r *http.Request) {
 		FraudAttempts: 0,
 	}
 
-	// MOCK MODE CHECK
-	if db.FirestoreClient == nil {
-		stats.TotalIssued = 1250
-		stats.TotalVerified = 3420
-		stats.FraudAttempts = 5
-		respondJSON(w, http.StatusOK, stats)
-		return
+	// Count total certificates issued
+	if total, err := db.Repos.Certificates.Count(ctx); err == nil {
+		stats.TotalIssued = total
 	}
 
-	if db.FirestoreClient != nil {
-		// Count total certificates issued
-		certDocs, err := db.FirestoreClient.Collection("certificates").Documents(ctx).GetAll()
-		if err == nil {
-			stats.TotalIssued = len(certDocs)
-		}
-
-		// Count total verifications
-		verifyDocs, err := db.FirestoreClient.Collection("verification_logs").
-			Where("verified", "==", true).
-			Documents(ctx).GetAll()
-		if err == nil {
-			stats.TotalVerified = len(verifyDocs)
-		}
+	// Count total verifications
+	if verified, err := db.Repos.VerificationLogs.CountByResult(ctx, true); err == nil {
+		stats.TotalVerified = verified
+	}
 
-		// Count fraud attempts (failed verifications)
-		fraudDocs, err := db.FirestoreClient.Collection("verification_logs").
-			Where("verified", "==", false).
-			Documents(ctx).GetAll()
-		if err == nil {
-			stats.FraudAttempts = len(fraudDocs)
-		}
+	// Count fraud attempts (failed verifications)
+	if failed, err := db.Repos.VerificationLogs.CountByResult(ctx, false); err == nil {
+		stats.FraudAttempts = failed
 	}
 
 	respondJSON(w, http.StatusOK, stats)
@@ -268,60 +361,19 @@ func GetCertificateHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	// Normalize wallet address
 	wallet = strings.ToLower(wallet)
 
-	// MOCK MODE CHECK
-	if db.FirestoreClient == nil {
-		mockCerts := []models.Certificate{
-			{
-				ID:            "mock_cert_1",
-				Hash:          "0xMockHash123",
-				StudentName:   "Student (Mock)",
-				CourseName:    "Solidity Fundamentals",
-				Marks:         95,
-				IssuedAt:      time.Now().Add(-7 * 24 * time.Hour),
-				TrustScore:    98,
-				WalletAddress: wallet,
-			},
-			{
-				ID:            "mock_cert_2",
-				Hash:          "0xMockHash456",
-				StudentName:   "Student (Mock)",
-				CourseName:    "Advanced React Patterns",
-				Marks:         88,
-				IssuedAt:      time.Now().Add(-30 * 24 * time.Hour),
-				TrustScore:    92,
-				WalletAddress: wallet,
-			},
-		}
-
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"success":      true,
-			"wallet":       wallet,
-			"certificates": mockCerts,
-			"count":        len(mockCerts),
+	certificates, err := db.Repos.Certificates.ListByWallet(r.Context(), wallet)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Failed to fetch certificate history",
 		})
 		return
 	}
 
-	ctx := r.Context()
-	var certificates []models.Certificate
-
-	if db.FirestoreClient != nil {
-		docs, err := db.FirestoreClient.Collection("certificates").
-			Where("wallet_address", "==", wallet).
-			Documents(ctx).GetAll()
-
-		if err != nil {
-			respondJSON(w, http.StatusInternalServerError, map[string]string{
-				"error": "Failed to fetch certificate history",
-			})
-			return
-		}
-
-		for _, doc := range docs {
-			var cert models.Certificate
-			if err := doc.DataTo(&cert); err == nil {
-				certificates = append(certificates, cert)
-			}
+	// Badges the user imported from other issuers are listed alongside
+	badges := []models.ImportedBadge{}
+	if claims, ok := r.Context().Value(middleware.UserContextKey).(*services.JWTClaims); ok && claims.UserID != "" {
+		if imported, err := db.Repos.ImportedBadges.ListByUser(r.Context(), claims.UserID); err == nil {
+			badges = imported
 		}
 	}
 
@@ -330,16 +382,12 @@ func GetCertificateHistoryHandler(w http.ResponseWriter, r *http.Request) {
 		"wallet":       wallet,
 		"certificates": certificates,
 		"count":        len(certificates),
+		"badges":       badges,
 	})
 }
 
-// logVerification logs a verification attempt to Firestore
+// logVerification records a verification attempt
 func logVerification(ctx context.Context, certHash string, verified bool, ipAddr, userAgent string) {
-	if db.FirestoreClient == nil {
-		log.Println("[logVerification] ⚠️ Firestore nil. Skipping log.")
-		return
-	}
-
 	verifyLog := models.VerificationLog{
 		CertificateHash: certHash,
 		Verified:        verified,
@@ -348,8 +396,7 @@ func logVerification(ctx context.Context, certHash string, verified bool, ipAddr
 		UserAgent:       userAgent,
 	}
 
-	_, err := db.FirestoreClient.Collection("verification_logs").NewDoc().Set(ctx, verifyLog)
-	if err != nil {
+	if err := db.Repos.VerificationLogs.Add(ctx, &verifyLog); err != nil {
 		log.Printf("Failed to log verification: %v", err)
 	}
 }
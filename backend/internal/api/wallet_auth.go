@@ -15,7 +15,6 @@ import (
 	"cache-crew/cognify/internal/models"
 	"cache-crew/cognify/internal/services"
 
-	"cloud.google.com/go/firestore"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -59,19 +58,15 @@ func GenerateNonceHandler(w http.ResponseWriter, r *http.Request) {
 	nonce := hex.EncodeToString(nonceBytes)
 	message := fmt.Sprintf("Sign this message to authenticate with Cognify: %s", nonce)
 
-	if db.FirestoreClient != nil {
-		_, err := db.FirestoreClient.Collection("auth_nonces").Doc(strings.ToLower(req.WalletAddress)).Set(r.Context(), map[string]interface{}{
-			"nonce":     nonce,
-			"message":   message,
-			"expiresAt": time.Now().Add(5 * time.Minute),
-		})
-		if err != nil {
-			log.Printf("Failed to store nonce: %v", err)
-			respondJSON(w, http.StatusInternalServerError, AuthResponse{Success: false, Message: "Database error"})
-			return
-		}
-	} else {
-		log.Printf("[MOCK] Generated Nonce for %s: %s", req.WalletAddress, message)
+	err := db.Repos.AuthNonces.Save(r.Context(), req.WalletAddress, &models.AuthNonce{
+		Nonce:     nonce,
+		Message:   message,
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	})
+	if err != nil {
+		log.Printf("Failed to store nonce: %v", err)
+		respondJSON(w, http.StatusInternalServerError, AuthResponse{Success: false, Message: "Database error"})
+		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{
@@ -96,41 +91,30 @@ func WalletLoginHandler(w http.ResponseWriter, r *http.Request) {
 	walletLower := strings.ToLower(req.WalletAddress)
 
 	// 1. Retrieve Nonce and Verify Signature
-	if db.FirestoreClient != nil {
-		doc, err := db.FirestoreClient.Collection("auth_nonces").Doc(walletLower).Get(r.Context())
-		if err != nil {
-			respondJSON(w, http.StatusUnauthorized, AuthResponse{Success: false, Message: "Nonce expired or invalid. Request new nonce."})
-			return
-		}
-
-		expiresAt := doc.Data()["expiresAt"].(time.Time)
-		if time.Now().After(expiresAt) {
-			respondJSON(w, http.StatusUnauthorized, AuthResponse{Success: false, Message: "Nonce expired"})
-			return
-		}
-		storedMessage := doc.Data()["message"].(string)
-
-		// Verify Signature
-		valid, err := verifySignature(req.WalletAddress, req.Signature, storedMessage)
-		if err != nil || !valid {
-			log.Printf("Signature verification failed: %v", err)
-			respondJSON(w, http.StatusUnauthorized, AuthResponse{Success: false, Message: "Invalid signature"})
-			return
-		}
+	storedNonce, err := db.Repos.AuthNonces.Get(r.Context(), walletLower)
+	if err != nil {
+		respondJSON(w, http.StatusUnauthorized, AuthResponse{Success: false, Message: "Nonce expired or invalid. Request new nonce."})
+		return
+	}
 
-		// Invalidate nonce to prevent replay
-		_, _ = db.FirestoreClient.Collection("auth_nonces").Doc(walletLower).Delete(r.Context())
+	if time.Now().After(storedNonce.ExpiresAt) {
+		respondJSON(w, http.StatusUnauthorized, AuthResponse{Success: false, Message: "Nonce expired"})
+		return
+	}
 
-	} else {
-		if req.Signature != "mock_sig" {
-			respondJSON(w, http.StatusBadRequest, AuthResponse{Success: false, Message: "Mock mode requires 'mock_sig'"})
-			return
-		}
+	// Verify Signature
+	valid, err := verifySignature(req.WalletAddress, req.Signature, storedNonce.Message)
+	if err != nil || !valid {
+		log.Printf("Signature verification failed: %v", err)
+		respondJSON(w, http.StatusUnauthorized, AuthResponse{Success: false, Message: "Invalid signature"})
+		return
 	}
 
+	// Invalidate nonce to prevent replay
+	_ = db.Repos.AuthNonces.Delete(r.Context(), walletLower)
+
 	// 2. Get User (by Email if provided, else by Wallet)
 	var user *models.User
-	var err error
 
 	if req.Email != "" {
 		// 2FA/Linking Scenario: Fetch by Email
@@ -140,11 +124,8 @@ func WalletLoginHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		// LINK WALLET: Update user's wallet address if not set or different
-		if user.WalletAddress != walletLower && db.FirestoreClient != nil {
-			_, err := db.FirestoreClient.Collection("users").Doc(user.ID).Update(r.Context(), []firestore.Update{
-				{Path: "walletAddress", Value: walletLower},
-			})
-			if err != nil {
+		if user.WalletAddress != walletLower {
+			if err := db.Repos.Users.SetWallet(r.Context(), user.ID, walletLower); err != nil {
 				log.Printf("Failed to link wallet: %v", err)
 				// Continue anyway, just logging the error
 			}
@@ -176,18 +157,7 @@ func WalletLoginHandler(w http.ResponseWriter, r *http.Request) {
 
 // Helper to get user by email (Add this if not present or rely on existing)
 func getUserByEmail(ctx context.Context, email string) (*models.User, error) {
-	if db.FirestoreClient == nil {
-		return &models.User{ID: email, Email: email, Role: "student"}, nil
-	}
-	doc, err := db.FirestoreClient.Collection("users").Doc(email).Get(ctx)
-	if err != nil {
-		return nil, err
-	}
-	var user models.User
-	if err := doc.DataTo(&user); err != nil {
-		return nil, err
-	}
-	return &user, nil
+	return db.Repos.Users.Get(ctx, email)
 }
 
 func verifySignature(address, signature, message string) (bool, error) {
@@ -215,26 +185,5 @@ func verifySignature(address, signature, message string) (bool, error) {
 }
 
 func getUserByWallet(ctx context.Context, wallet string) (*models.User, error) {
-	if db.FirestoreClient == nil {
-		// Mock
-		return &models.User{
-			ID:            "mock_user",
-			Email:         "mock@cognify.edu",
-			Role:          "student",
-			WalletAddress: wallet,
-		}, nil
-	}
-
-	// Query users by walletAddress field
-	iter := db.FirestoreClient.Collection("users").Where("walletAddress", "==", wallet).Documents(ctx)
-	doc, err := iter.Next()
-	if err != nil {
-		return nil, err
-	}
-
-	var user models.User
-	if err := doc.DataTo(&user); err != nil {
-		return nil, err
-	}
-	return &user, nil
+	return db.Repos.Users.GetByWallet(ctx, wallet)
 }
@@ -0,0 +1,147 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+)
+
+// anchorResubmitAfter is how long a root may stay off-chain before it is minted again
+const anchorResubmitAfter = 15 * time.Minute
+
+// Anchorer batches pending certificates into Merkle trees and anchors each root
+// on-chain with a single mint, storing every certificate's inclusion proof.
+// One transaction then covers a whole batch instead of one per certificate.
+type Anchorer struct {
+	client   BlockchainClient
+	interval time.Duration
+	maxBatch int
+}
+
+// NewAnchorer creates an anchorer that runs every interval and puts at most
+// maxBatch certificates in one tree
+func NewAnchorer(client BlockchainClient, interval time.Duration, maxBatch int) *Anchorer {
+	return &Anchorer{
+		client:   client,
+		interval: interval,
+		maxBatch: maxBatch,
+	}
+}
+
+// Start anchors immediately and then on every tick
+func (a *Anchorer) Start(ctx context.Context) {
+	log.Printf("[Anchorer] 🌳 Starting Merkle anchoring (Interval: %s, Max Batch: %d)", a.interval, a.maxBatch)
+	ticker := time.NewTicker(a.interval)
+
+	go func() {
+		a.anchor(ctx)
+		for {
+			select {
+			case <-ticker.C:
+				a.anchor(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (a *Anchorer) anchor(ctx context.Context) {
+	// 1. Confirm (or retry) roots submitted by earlier runs
+	a.confirmPending(ctx)
+
+	// 2. Batch the certificates still waiting
+	pending, err := db.Repos.Certificates.ListPending(ctx, a.maxBatch)
+	if err != nil {
+		log.Printf("[Anchorer] ⚠️ Failed to list pending certificates: %v", err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	leaves := make([]string, len(pending))
+	for i, cert := range pending {
+		leaves[i] = cert.Hash
+	}
+	root, proofs, err := BuildMerkleTree(leaves)
+	if err != nil {
+		log.Printf("[Anchorer] ⚠️ Failed to build Merkle tree: %v", err)
+		return
+	}
+
+	// Save the batch before touching certificates so an interrupted run is retried, not lost
+	batch := &models.AnchorBatch{
+		ID:        root,
+		Leaves:    leaves,
+		Status:    models.AnchorStatusPending,
+		CreatedAt: time.Now(),
+	}
+	if err := db.Repos.Anchors.Save(ctx, batch); err != nil {
+		log.Printf("[Anchorer] ⚠️ Failed to save batch %s: %v", root, err)
+		return
+	}
+
+	for i, hash := range leaves {
+		if err := db.Repos.Certificates.MarkAnchored(ctx, hash, root, proofs[i], ActiveChain()); err != nil {
+			log.Printf("[Anchorer] ⚠️ Failed to store proof for %s: %v", hash, err)
+		}
+	}
+
+	a.submit(ctx, batch)
+}
+
+// confirmPending marks batches whose root reached the registry as anchored and
+// re-submits roots that have been missing for too long
+func (a *Anchorer) confirmPending(ctx context.Context) {
+	batches, err := db.Repos.Anchors.ListPending(ctx)
+	if err != nil {
+		log.Printf("[Anchorer] ⚠️ Failed to list pending batches: %v", err)
+		return
+	}
+
+	for i := range batches {
+		batch := &batches[i]
+		record, err := a.client.VerifyCertificate(batch.ID)
+		if err != nil {
+			log.Printf("[Anchorer] ⚠️ Failed to check root %s on-chain: %v", batch.ID, err)
+			continue
+		}
+
+		if record.Exists {
+			batch.Status = models.AnchorStatusAnchored
+			batch.AnchoredAt = record.Timestamp
+			if err := db.Repos.Anchors.Save(ctx, batch); err != nil {
+				log.Printf("[Anchorer] ⚠️ Failed to update batch %s: %v", batch.ID, err)
+				continue
+			}
+			log.Printf("[Anchorer] ✅ Root %s anchored (%d certificates)", batch.ID, len(batch.Leaves))
+			continue
+		}
+
+		if time.Since(batch.SubmittedAt) > anchorResubmitAfter {
+			a.submit(ctx, batch)
+		}
+	}
+}
+
+// submit mints the batch root to AnchorOwner
+func (a *Anchorer) submit(ctx context.Context, batch *models.AnchorBatch) {
+	txHash, err := a.client.Mint(batch.ID, AnchorOwner.Hex(), fmt.Sprintf("merkle-root:%d", len(batch.Leaves)))
+	if err != nil {
+		log.Printf("[Anchorer] ⚠️ Failed to anchor root %s: %v", batch.ID, err)
+		return
+	}
+
+	batch.TxHash = txHash
+	batch.SubmittedAt = time.Now()
+	if err := db.Repos.Anchors.Save(ctx, batch); err != nil {
+		log.Printf("[Anchorer] ⚠️ Failed to update batch %s: %v", batch.ID, err)
+	}
+	log.Printf("[Anchorer] 🌳 Anchoring %d certificates under root %s (tx %s)", len(batch.Leaves), batch.ID, txHash)
+}
@@ -0,0 +1,118 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+)
+
+var ErrBackfillRunning = errors.New("a backfill is already running")
+
+// backfillRunning guards against two backfills in one process
+var backfillRunning atomic.Bool
+
+// Backfill re-applies every registry event from fromBlock up to the confirmed head,
+// rebuilding minted certificates, revocations and issuer authorizations. Events are
+// applied idempotently, so running it over an already synced range changes nothing.
+// It moves the shared checkpoint back to fromBlock and pages forward through it with
+// the listener and sync worker, saving progress as a BackfillJob after every page.
+// confirmations is ignored on the simulated chain and the mock ledger, which never reorg.
+func Backfill(ctx context.Context, fromBlock, confirmations uint64) (*models.BackfillJob, error) {
+	ix, job, err := newBackfill(ctx, fromBlock, confirmations)
+	if err != nil {
+		return nil, err
+	}
+	return job, runBackfill(ctx, ix, job)
+}
+
+// StartBackfill runs Backfill in the background and returns the job as started
+func StartBackfill(fromBlock, confirmations uint64) (*models.BackfillJob, error) {
+	ctx := context.Background()
+	ix, job, err := newBackfill(ctx, fromBlock, confirmations)
+	if err != nil {
+		return nil, err
+	}
+
+	started := *job
+	go func() {
+		if err := runBackfill(ctx, ix, job); err != nil {
+			log.Printf("[Backfill] ❌ Backfill failed: %v", err)
+		}
+	}()
+	return &started, nil
+}
+
+func newBackfill(ctx context.Context, fromBlock, confirmations uint64) (*indexer, *models.BackfillJob, error) {
+	ix := &indexer{startBlock: fromBlock, confirmations: confirmations}
+	if bc := GetRealClient(); bc != nil {
+		ix.client, ix.contractAddress = bc.client, bc.contractAddr
+		if simChain != nil {
+			ix.confirmations = 0
+		}
+	} else {
+		mock := GetMockClient()
+		ix.client, ix.contractAddress, ix.confirmations = mock, mock.contract, 0
+	}
+	if !backfillRunning.CompareAndSwap(false, true) {
+		return nil, nil, ErrBackfillRunning
+	}
+
+	job := &models.BackfillJob{
+		Status:    models.BackfillStatusRunning,
+		FromBlock: fromBlock,
+		StartedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := db.Repos.SystemState.SaveBackfillJob(ctx, job); err != nil {
+		backfillRunning.Store(false)
+		return nil, nil, err
+	}
+	return ix, job, nil
+}
+
+func runBackfill(ctx context.Context, ix *indexer, job *models.BackfillJob) error {
+	defer backfillRunning.Store(false)
+	log.Printf("[Backfill] ⏪ Re-syncing registry events from block %d", job.FromBlock)
+
+	err := ix.rewind(ctx, max(job.FromBlock, 1)-1)
+	if err == nil {
+		_, err = ix.run(ctx, func(page syncPage) {
+			job.CurrentBlock = page.To
+			job.TargetBlock = page.Safe
+			job.Events += page.Applied
+			job.Progress = backfillProgress(job)
+			job.UpdatedAt = time.Now()
+			log.Printf("[Backfill] 📥 Blocks %d-%d: %d events (%.1f%% of %d)", page.From, page.To, page.Applied, job.Progress, job.TargetBlock)
+			if err := db.Repos.SystemState.SaveBackfillJob(ctx, job); err != nil {
+				log.Printf("[Backfill] ⚠️ Failed to save progress: %v", err)
+			}
+		})
+	}
+
+	job.Status = models.BackfillStatusCompleted
+	if err != nil {
+		job.Status = models.BackfillStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Progress = 100
+		log.Printf("[Backfill] ✅ Applied %d events from blocks %d-%d", job.Events, job.FromBlock, job.CurrentBlock)
+	}
+	job.FinishedAt = time.Now()
+	job.UpdatedAt = job.FinishedAt
+	if saveErr := db.Repos.SystemState.SaveBackfillJob(ctx, job); saveErr != nil {
+		log.Printf("[Backfill] ⚠️ Failed to save result: %v", saveErr)
+	}
+	return err
+}
+
+func backfillProgress(job *models.BackfillJob) float64 {
+	if job.TargetBlock < job.FromBlock || job.CurrentBlock < job.FromBlock {
+		return 0
+	}
+	return float64(job.CurrentBlock-job.FromBlock+1) / float64(job.TargetBlock-job.FromBlock+1) * 100
+}
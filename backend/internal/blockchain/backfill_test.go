@@ -0,0 +1,100 @@
+package blockchain
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBackfillReplayIsIdempotent(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+	client := GetMockClient()
+
+	const issuer = "0x3333333333333333333333333333333333333333"
+	user := &models.User{ID: "issuer", WalletAddress: issuer, Role: "student"}
+	if err := db.Repos.Users.Save(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	// A certificate minted and revoked, one only minted, and an issuer authorized then revoked
+	revoked := certificateKey(crypto.Keccak256Hash([]byte(t.Name() + "/revoked")))
+	minted := certificateKey(crypto.Keccak256Hash([]byte(t.Name() + "/minted")))
+	for _, hash := range []string{revoked, minted} {
+		cert := &models.Certificate{Hash: hash, StudentID: "student", CourseName: "Go"}
+		if err := db.Repos.Certificates.Save(ctx, cert); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Mint(hash, testStudent, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.Revoke(revoked, "plagiarism"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AuthorizeIssuer(issuer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RevokeIssuer(issuer); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := func() (map[string]models.Certificate, models.User) {
+		t.Helper()
+		certs := make(map[string]models.Certificate)
+		for _, hash := range []string{revoked, minted} {
+			cert, err := db.Repos.Certificates.Get(ctx, hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			certs[hash] = *cert
+		}
+		u, err := db.Repos.Users.Get(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return certs, *u
+	}
+
+	if _, err := Backfill(ctx, 0, 0); err != nil {
+		t.Fatalf("first Backfill: %v", err)
+	}
+	wantCerts, wantUser := snapshot()
+	if !wantCerts[revoked].Revoked || !wantCerts[minted].IsMinted || wantCerts[minted].Revoked {
+		t.Fatalf("first backfill stored %+v", wantCerts)
+	}
+	if wantUser.IsAuthorized || wantUser.Role != "instructor" {
+		t.Fatalf("first backfill left issuer %+v", wantUser)
+	}
+
+	// The student read the revocation notice; replaying mustn't raise it again
+	if err := db.Repos.Notifications.MarkRead(ctx, "cert_revoked_"+revoked); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rewind keeps the journal above block 0 in storage and re-applies every event
+	if _, err := Backfill(ctx, 0, 0); err != nil {
+		t.Fatalf("second Backfill: %v", err)
+	}
+	gotCerts, gotUser := snapshot()
+	if !reflect.DeepEqual(gotCerts, wantCerts) {
+		t.Errorf("replay changed certificates:\n got %+v\nwant %+v", gotCerts, wantCerts)
+	}
+	if !reflect.DeepEqual(gotUser, wantUser) {
+		t.Errorf("replay changed issuer: got %+v, want %+v", gotUser, wantUser)
+	}
+	notifications, err := db.Repos.Notifications.ListByUser(ctx, "student")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range notifications {
+		if !n.IsRead {
+			t.Errorf("replay re-sent notification %s", n.ID)
+		}
+	}
+}
@@ -4,105 +4,297 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
 )
 
-// MockBlockchainClient simulates blockchain interactions for development
+// MockBlockchainClient simulates the registry for development. Every transaction is
+// sealed in a block of its own, sent by a mock platform wallet and emitting the events
+// the real contract does. The chain is kept in memory unless a ledger file is set;
+// blocks are then appended to it and replayed on startup, so dev certificates
+// survive restarts.
 type MockBlockchainClient struct {
-	certificates map[string]bool // Hash -> exists on chain
+	certificates map[string]*CertificateRecord // Hash -> on-chain record
+	mints        map[string]MintEvent          // Tx hash -> mint it performed
+	issuers      map[common.Address]bool       // Authorized issuers
+	blocks       []*mockBlock                  // blocks[n] is block n; block 0 is the genesis
+	signer       Signer                        // Mock platform wallet: sends every transaction and signs mint authorizations
+	contract     common.Address                // Address the mock registry's events come from
+	ledger       *mockLedger                   // nil keeps the chain in memory only
+	logFeed      event.Feed                    // Logs of newly sealed blocks, for SubscribeFilterLogs
 	mu           sync.RWMutex
 }
 
 var (
-	mockClient *MockBlockchainClient
-	once       sync.Once
+	mockClient     *MockBlockchainClient
+	once           sync.Once
+	mockLedgerPath string
 )
 
-// InitMockBlockchain initializes the mock blockchain client
+// SetMockLedgerPath sets the file the mock chain is persisted to. It must be called
+// before the mock client is first used; an empty path keeps the chain in memory.
+func SetMockLedgerPath(path string) {
+	mockLedgerPath = path
+}
+
+// InitMockBlockchain initializes the mock blockchain client and makes it the active client
 func InitMockBlockchain() {
+	activeClient = GetMockClient()
+}
+
+// GetMockClient returns the singleton mock blockchain client
+func GetMockClient() *MockBlockchainClient {
 	once.Do(func() {
-		mockClient = &MockBlockchainClient{
-			certificates: make(map[string]bool),
+		client, err := newMockClient(mockLedgerPath)
+		if err != nil {
+			log.Fatalf("Failed to open mock ledger %s: %v", mockLedgerPath, err)
 		}
+		mockClient = client
 		log.Println("✅ Mock Blockchain client initialized")
 	})
+	return mockClient
 }
 
-// GetMockClient returns the singleton mock blockchain client
-func GetMockClient() *MockBlockchainClient {
-	if mockClient == nil {
-		InitMockBlockchain()
-	}
-	return mockClient
+// ContractAddress returns the address the mock registry's events are emitted from
+func (c *MockBlockchainClient) ContractAddress() common.Address {
+	return c.contract
+}
+
+// normalizeHash strips the 0x prefix and lowercases a certificate hash
+func normalizeHash(hash string) string {
+	return strings.ToLower(strings.TrimPrefix(hash, "0x"))
 }
 
-// VerifyCertificateHash checks if a certificate hash exists on the blockchain
-func (c *MockBlockchainClient) VerifyCertificateHash(hash string) (bool, error) {
+// VerifyCertificate returns the mock ledger record for a certificate hash
+func (c *MockBlockchainClient) VerifyCertificate(hash string) (*CertificateRecord, error) {
+	// Simulate network delay, outside the lock so concurrent callers wait in parallel
+	time.Sleep(100 * time.Millisecond)
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	
-	// Simulate network delay
-	time.Sleep(100 * time.Millisecond)
-	
-	exists := c.certificates[hash]
-	return exists, nil
+
+	hash = normalizeHash(hash)
+	record, ok := c.certificates[hash]
+	if !ok {
+		return &CertificateRecord{Hash: hash}, nil
+	}
+
+	copied := *record
+	return &copied, nil
 }
 
-// MintCertificate adds a certificate hash to the blockchain
-func (c *MockBlockchainClient) MintCertificate(hash string) (string, error) {
+// Mint adds a certificate hash to the mock ledger
+func (c *MockBlockchainClient) Mint(hash, owner, academicDNA string) (string, error) {
+	hashBytes, err := hexToBytes32(hash)
+	if err != nil {
+		return "", err
+	}
+	if !common.IsHexAddress(owner) {
+		return "", fmt.Errorf("invalid owner address: %s", owner)
+	}
+	ownerAddr := common.HexToAddress(owner)
+
+	// Simulate blockchain transaction
+	time.Sleep(200 * time.Millisecond)
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	
+
 	// Check if already minted
-	if c.certificates[hash] {
+	hash = normalizeHash(hash)
+	if _, exists := c.certificates[hash]; exists {
 		return "", errors.New("certificate already minted on blockchain")
 	}
-	
+
+	from := c.signer.Address()
+	txHash, err := c.send("mintCertificate", []mockEvent{{
+		topics: []common.Hash{EventCertificateMinted, hashBytes, addressTopic(ownerAddr), addressTopic(from)},
+	}}, hashBytes, ownerAddr, academicDNA)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("🔗 Certificate minted on blockchain: %s (tx: %s)", hash[:16]+"...", txHash[:16]+"...")
+
+	return txHash, nil
+}
+
+// Revoke marks a certificate as revoked in the mock ledger
+func (c *MockBlockchainClient) Revoke(hash, reason string) (string, error) {
+	hashBytes, err := hexToBytes32(hash)
+	if err != nil {
+		return "", err
+	}
+
 	// Simulate blockchain transaction
 	time.Sleep(200 * time.Millisecond)
-	
-	// Mark as minted
-	c.certificates[hash] = true
-	
-	// Generate mock transaction hash
-	txHash := fmt.Sprintf("0x%s", hash[:40])
-	
-	log.Printf("🔗 Certificate minted on blockchain: %s (tx: %s)", hash[:16]+"...", txHash[:16]+"...")
-	
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hash = normalizeHash(hash)
+	record, exists := c.certificates[hash]
+	if !exists {
+		return "", errors.New("certificate does not exist on blockchain")
+	}
+	if record.Revoked {
+		return "", errors.New("certificate already revoked")
+	}
+
+	data, err := registryEventData("CertificateRevoked", reason)
+	if err != nil {
+		return "", err
+	}
+	txHash, err := c.send("revokeCertificate", []mockEvent{{
+		topics: []common.Hash{EventCertificateRevoked, hashBytes, addressTopic(c.signer.Address())},
+		data:   data,
+	}}, hashBytes, reason)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("🔗 Certificate revoked on blockchain: %s (reason: %s)", hash[:16]+"...", reason)
+
+	return txHash, nil
+}
+
+// CertificatesByWallet returns the hashes of every mock certificate owned by a wallet
+func (c *MockBlockchainClient) CertificatesByWallet(wallet string) ([]string, error) {
+	if !common.IsHexAddress(wallet) {
+		return nil, fmt.Errorf("invalid wallet address: %s", wallet)
+	}
+	owner := common.HexToAddress(wallet)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	hashes := []string{}
+	for hash, record := range c.certificates {
+		if record.Owner == owner {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes, nil
+}
+
+// IssuerCertCount returns how many mock certificates an issuer has minted
+func (c *MockBlockchainClient) IssuerCertCount(issuer string) (int, error) {
+	address := common.HexToAddress(issuer)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	count := 0
+	for _, record := range c.certificates {
+		if record.Issuer == address {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CertificateCount returns the total number of certificates on chain
+func (c *MockBlockchainClient) CertificateCount() (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.certificates), nil
+}
+
+// IsAuthorizedIssuer reports whether an address may mint; the mock platform wallet always can
+func (c *MockBlockchainClient) IsAuthorizedIssuer(issuer string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.issuers[common.HexToAddress(issuer)], nil
+}
+
+// AuthorizeIssuer adds an address to the mock issuer list
+func (c *MockBlockchainClient) AuthorizeIssuer(issuer string) (string, error) {
+	return c.setIssuer(issuer, true)
+}
+
+// RevokeIssuer removes an address from the mock issuer list
+func (c *MockBlockchainClient) RevokeIssuer(issuer string) (string, error) {
+	return c.setIssuer(issuer, false)
+}
+
+func (c *MockBlockchainClient) setIssuer(issuer string, authorized bool) (string, error) {
+	if !common.IsHexAddress(issuer) {
+		return "", fmt.Errorf("invalid issuer address: %s", issuer)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	address := common.HexToAddress(issuer)
+	if c.issuers[address] == authorized {
+		if authorized {
+			return "", errors.New("issuer already authorized")
+		}
+		return "", errors.New("issuer not authorized")
+	}
+
+	method, topic, action := "authorizeIssuer", EventIssuerAuthorized, "authorized"
+	if !authorized {
+		method, topic, action = "revokeIssuer", EventIssuerRevoked, "revoked"
+	}
+	txHash, err := c.send(method, []mockEvent{{topics: []common.Hash{topic, addressTopic(address)}}}, address)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("🔗 Issuer %s on blockchain: %s", action, address.Hex())
 	return txHash, nil
 }
 
-// GetCertificateCount returns the total number of certificates on chain
-func (c *MockBlockchainClient) GetCertificateCount() int {
+// MintEvents returns the mint performed by a mock transaction
+func (c *MockBlockchainClient) MintEvents(txHash string) ([]MintEvent, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	event, ok := c.mints[strings.ToLower(txHash)]
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+	return []MintEvent{event}, nil
+}
+
+// LatestBlock returns the mock block height
+func (c *MockBlockchainClient) LatestBlock() (uint64, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return len(c.certificates)
+	return c.head().Number, nil
 }
 
-// TODO: Real blockchain implementation
-// Uncomment when ready to integrate with Ethereum/Polygon
-/*
-import (
-	"context"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/common"
-)
+// MintEventsBetween returns the mock mints recorded in blocks [from, to]
+func (c *MockBlockchainClient) MintEventsBetween(from, to uint64) ([]MintEvent, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	events := []MintEvent{}
+	for _, event := range c.mints {
+		if event.BlockNumber >= from && event.BlockNumber <= to {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].BlockNumber < events[j].BlockNumber })
+	return events, nil
+}
 
-type BlockchainClient struct {
-	client   *ethclient.Client
-	contract common.Address
+// addressTopic left-pads an address into an indexed event topic
+func addressTopic(address common.Address) common.Hash {
+	return common.BytesToHash(address.Bytes())
 }
 
-func InitBlockchain(rpcURL, contractAddr string) (*BlockchainClient, error) {
-	client, err := ethclient.Dial(rpcURL)
+// registryEventData ABI-encodes the non-indexed fields of a registry event
+func registryEventData(name string, values ...interface{}) ([]byte, error) {
+	parsed, err := SoulboundCertificateRegistryMetaData.GetAbi()
 	if err != nil {
 		return nil, err
 	}
-	
-	return &BlockchainClient{
-		client:   client,
-		contract: common.HexToAddress(contractAddr),
-	}, nil
+	return parsed.Events[name].Inputs.NonIndexed().Pack(values...)
 }
-*/
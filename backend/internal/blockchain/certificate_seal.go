@@ -0,0 +1,93 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-712 domain of certificate seals, the platform signatures printed on certificate PDFs
+const (
+	certificateSealDomainName    = "Cognify Certificate Seal"
+	certificateSealDomainVersion = "1"
+)
+
+// ErrCertificateSealInvalid is returned when a certificate seal does not check out
+var ErrCertificateSealInvalid = errors.New("invalid certificate seal")
+
+// SignCertificateSeal signs certHash with the platform wallet. It returns the
+// signer's did:ethr DID, which names the chain the seal was signed for, and the
+// signature.
+func SignCertificateSeal(certHash string) (issuer, signature string, err error) {
+	if _, err := hexToBytes32(certHash); err != nil {
+		return "", "", fmt.Errorf("invalid certificate hash %q: %w", certHash, err)
+	}
+
+	signer, chainID, _ := platformSigner()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	sig, err := signer.SignTypedData(ctx, certificateSealTypedData(certHash, chainID.Int64()))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign certificate seal: %w", err)
+	}
+	return EthrDID(chainID.Int64(), signer.Address()), hexutil.Encode(sig), nil
+}
+
+// VerifyCertificateSeal checks that signature seals certHash and was made by the
+// platform wallet, current or retired, which issuer must name
+func VerifyCertificateSeal(certHash, issuer, signature string) error {
+	chainID, address, err := parseEthrDID(issuer)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCertificateSealInvalid, err)
+	}
+	if _, err := hexToBytes32(certHash); err != nil {
+		return fmt.Errorf("%w: malformed certificate hash", ErrCertificateSealInvalid)
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(certificateSealTypedData(certHash, chainID))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCertificateSealInvalid, err)
+	}
+	recovered, err := recoverTypedDataSigner(hash, signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCertificateSealInvalid, err)
+	}
+	if recovered != address {
+		return fmt.Errorf("%w: signature does not match %s", ErrCertificateSealInvalid, issuer)
+	}
+	if !IsPlatformAddress(address) {
+		return fmt.Errorf("%w: not sealed by the platform", ErrCertificateSealInvalid)
+	}
+	return nil
+}
+
+// certificateSealTypedData is the EIP-712 message a certificate seal signs
+func certificateSealTypedData(certHash string, chainID int64) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"CertificateSeal": {
+				{Name: "certificateHash", Type: "bytes32"},
+			},
+		},
+		PrimaryType: "CertificateSeal",
+		Domain: apitypes.TypedDataDomain{
+			Name:    certificateSealDomainName,
+			Version: certificateSealDomainVersion,
+			ChainId: (*math.HexOrDecimal256)(big.NewInt(chainID)),
+		},
+		Message: apitypes.TypedDataMessage{
+			"certificateHash": "0x" + normalizeHash(certHash),
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package blockchain
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Errors returned by MintEvents
+var (
+	ErrTxNotFound = errors.New("transaction not found")
+	ErrTxPending  = errors.New("transaction not yet mined")
+	ErrTxReverted = errors.New("transaction reverted")
+)
+
+// MintEvent is a CertificateMinted event emitted by a mined transaction
+type MintEvent struct {
+	Hash        string // Certificate hash, no 0x prefix
+	Owner       common.Address
+	Issuer      common.Address
+	TxHash      string
+	BlockNumber uint64
+}
+
+// BlockchainClient is the certificate registry as seen by the rest of the backend.
+// The mock, real and simulated modes all implement it, so callers use GetClient()
+// and never need to check BLOCKCHAIN_MODE.
+//
+// Certificate hashes are hex strings with or without a 0x prefix; hashes returned
+// by the client never carry the prefix, matching how certificates are stored.
+type BlockchainClient interface {
+	// VerifyCertificate returns the on-chain record for a hash.
+	// A hash that was never minted yields a record with Exists=false.
+	VerifyCertificate(certHash string) (*CertificateRecord, error)
+
+	// Mint issues a soulbound certificate to owner and returns the transaction hash.
+	// On a real chain it returns once the transaction is broadcast, not mined.
+	Mint(certHash, owner, academicDNA string) (string, error)
+
+	// Revoke marks a certificate as revoked and returns the transaction hash.
+	// On a real chain it returns once the transaction is broadcast, not mined.
+	Revoke(certHash, reason string) (string, error)
+
+	// CertificatesByWallet returns the hashes of every certificate owned by a wallet
+	CertificatesByWallet(wallet string) ([]string, error)
+
+	// IssuerCertCount returns how many certificates an issuer has minted
+	IssuerCertCount(issuer string) (int, error)
+
+	// CertificateCount returns the total number of certificates on chain
+	CertificateCount() (int, error)
+
+	// IsAuthorizedIssuer reports whether an address may mint certificates
+	IsAuthorizedIssuer(issuer string) (bool, error)
+
+	// AuthorizeIssuer lets an address mint certificates and returns the transaction hash.
+	// Only the contract owner (the platform wallet) may call it.
+	AuthorizeIssuer(issuer string) (string, error)
+
+	// RevokeIssuer withdraws an address's minting permission and returns the transaction hash
+	RevokeIssuer(issuer string) (string, error)
+
+	// MintEvents returns the CertificateMinted events emitted by a mined transaction.
+	// It fails with ErrTxNotFound, ErrTxPending or ErrTxReverted when there is no receipt to inspect.
+	MintEvents(txHash string) ([]MintEvent, error)
+
+	// LatestBlock returns the current block height
+	LatestBlock() (uint64, error)
+
+	// MintEventsBetween returns every CertificateMinted event in blocks [from, to]
+	MintEventsBetween(from, to uint64) ([]MintEvent, error)
+}
+
+var (
+	_ BlockchainClient = (*MockBlockchainClient)(nil)
+	_ BlockchainClient = (*RealBlockchainClient)(nil)
+)
+
+// activeClient is the client selected at startup by InitMockBlockchain,
+// InitRealBlockchain or InitSimulatedBlockchain
+var activeClient BlockchainClient
+
+// GetClient returns the active blockchain client, falling back to the mock
+// if no mode has been initialized
+func GetClient() BlockchainClient {
+	if activeClient == nil {
+		InitMockBlockchain()
+	}
+	return activeClient
+}
@@ -1,46 +1,86 @@
 package blockchain
 
 import (
-	"math/big"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// CertificateRegistryContract represents the smart contract
-// This is a placeholder - will be replaced by abigen-generated code
-type CertificateRegistryContract struct {
-	address common.Address
-	backend bind.ContractBackend
-}
+// Bindings for SoulboundCertificateRegistry live in contract_bindings.go and are
+// generated from the ABI/bytecode in contracts/ (see blockchain/DEPLOYMENT.md).
+//go:generate abigen --abi contracts/SoulboundCertificateRegistry.abi --bin contracts/SoulboundCertificateRegistry.bin --pkg blockchain --type SoulboundCertificateRegistry --out contract_bindings.go
 
-// NewCertificateRegistryContract creates a new contract instance
-func NewCertificateRegistryContract(address common.Address, backend bind.ContractBackend) (*CertificateRegistryContract, error) {
-	return &CertificateRegistryContract{
-		address: address,
-		backend: backend,
-	}, nil
+// CertificateRecord is the on-chain state of a certificate as returned by verifyCertificate
+type CertificateRecord struct {
+	Hash        string
+	Exists      bool
+	Owner       common.Address
+	Issuer      common.Address
+	Timestamp   time.Time
+	AcademicDNA string
+	Revoked     bool
 }
 
-// VerifyCertificate calls the verifyCertificate function
-func (c *CertificateRegistryContract) VerifyCertificate(opts *bind.CallOpts, certHash [32]byte) (bool, common.Address, *big.Int, error) {
-	// TODO: Replace with abigen-generated code
-	// For now, return placeholder
-	return false, common.Address{}, big.NewInt(0), nil
+// hexToBytes32 converts hex string to [32]byte
+func hexToBytes32(hexStr string) ([32]byte, error) {
+	var result [32]byte
+
+	hexStr = strings.TrimPrefix(hexStr, "0x")
+	if len(hexStr) != 64 {
+		return result, fmt.Errorf("invalid hash length: expected 64, got %d", len(hexStr))
+	}
+
+	bytes, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return result, err
+	}
+
+	copy(result[:], bytes)
+	return result, nil
 }
 
-// MintCertificate calls the mintCertificate function
-func (c *CertificateRegistryContract) MintCertificate(opts *bind.TransactOpts, certHash [32]byte) (*types.Transaction, error) {
-	// TODO: Replace with abigen-generated code
-	return nil, nil
+// certificateKey formats an on-chain certificate hash the way certificates are keyed
+// in storage (64 hex chars, no 0x prefix; see utils.GenerateCanonicalHash)
+func certificateKey(hash common.Hash) string {
+	return hex.EncodeToString(hash[:])
 }
 
-// GetTotalCertificates calls the getTotalCertificates function
-func (c *CertificateRegistryContract) GetTotalCertificates(opts *bind.CallOpts) (*big.Int, error) {
-	// TODO: Replace with abigen-generated code
-	return big.NewInt(0), nil
+// decodeRevocationReason extracts the non-indexed reason string from a CertificateRevoked log
+func decodeRevocationReason(vLog types.Log) (string, error) {
+	parsed, err := SoulboundCertificateRegistryMetaData.GetAbi()
+	if err != nil {
+		return "", err
+	}
+	values, err := parsed.Unpack("CertificateRevoked", vLog.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode revocation reason: %w", err)
+	}
+	if len(values) != 1 {
+		return "", fmt.Errorf("unexpected CertificateRevoked data: %d values", len(values))
+	}
+	reason, _ := values[0].(string)
+	return reason, nil
 }
 
-// NOTE: To generate proper contract bindings, run:
-// abigen --sol=../../blockchain/contracts/CertificateRegistry.sol --pkg=blockchain --out=contract_bindings.go
+// EncodeRevokeCertificate returns the calldata for revokeCertificate(hash, reason),
+// for wallets (e.g. MetaMask) that submit the transaction themselves
+func EncodeRevokeCertificate(certHash, reason string) (string, error) {
+	hashBytes, err := hexToBytes32(certHash)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := SoulboundCertificateRegistryMetaData.GetAbi()
+	if err != nil {
+		return "", err
+	}
+	data, err := parsed.Pack("revokeCertificate", hashBytes, reason)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode revokeCertificate: %w", err)
+	}
+	return hexutil.Encode(data), nil
+}
@@ -0,0 +1,1324 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package blockchain
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SoulboundCertificateRegistryMetaData contains all meta data concerning the SoulboundCertificateRegistry contract.
+var SoulboundCertificateRegistryMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"certHash\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"issuer\",\"type\":\"address\"}],\"name\":\"CertificateMinted\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"certHash\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"issuer\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"reason\",\"type\":\"string\"}],\"name\":\"CertificateRevoked\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"issuer\",\"type\":\"address\"}],\"name\":\"IssuerAuthorized\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"issuer\",\"type\":\"address\"}],\"name\":\"IssuerRevoked\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_issuer\",\"type\":\"address\"}],\"name\":\"authorizeIssuer\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"authorizedIssuers\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"certificates\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"hash\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"issuer\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"academicDNA\",\"type\":\"string\"},{\"internalType\":\"bool\",\"name\":\"exists\",\"type\":\"bool\"},{\"internalType\":\"bool\",\"name\":\"revoked\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_wallet\",\"type\":\"address\"}],\"name\":\"getCertificatesByWallet\",\"outputs\":[{\"internalType\":\"bytes32[]\",\"name\":\"\",\"type\":\"bytes32[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_issuer\",\"type\":\"address\"}],\"name\":\"getIssuerCertCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getTotalCertificates\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"issuerCertCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_certHash\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"_owner\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_academicDNA\",\"type\":\"string\"}],\"name\":\"mintCertificate\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_certHash\",\"type\":\"bytes32\"},{\"internalType\":\"string\",\"name\":\"_reason\",\"type\":\"string\"}],\"name\":\"revokeCertificate\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_issuer\",\"type\":\"address\"}],\"name\":\"revokeIssuer\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalCertificates\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"transfer\",\"outputs\":[],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_newOwner\",\"type\":\"address\"}],\"name\":\"transferOwnership\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_certHash\",\"type\":\"bytes32\"}],\"name\":\"verifyCertificate\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"exists\",\"type\":\"bool\"},{\"internalType\":\"address\",\"name\":\"certOwner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"issuer\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"academicDNA\",\"type\":\"string\"},{\"internalType\":\"bool\",\"name\":\"revoked\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"walletCertificates\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801561000f575f80fd5b50600480546001600160a01b031916339081179091555f908152600560205260409020805460ff191660011790556112168061004a5f395ff3fe608060405234801561000f575f80fd5b50600436106100fa575f3560e01c806379ce9fac11610093578063c426cf9011610063578063c426cf9014610238578063e931e71414610260578063f2fde38b14610273578063f731fa0f14610286575f80fd5b806379ce9fac146101b5578063850c1768146101c85780638da5cb5b146101ed578063a350650f14610218575f80fd5b80635bf4adfb116100ce5780635bf4adfb146101615780636c1cf56414610174578063742f0688146101875780637843bb79146101ad575f80fd5b8062629679146100fe57806337d13d4014610113578063436693d514610145578063596b772f14610158575b5f80fd5b61011161010c366004610d6d565b6102b8565b005b610132610121366004610d6d565b60026020525f908152604090205481565b6040519081526020015b60405180910390f35b610111610153366004610d6d565b6103df565b61013260035481565b61011161016f366004610dd2565b6104f9565b610132610182366004610e1a565b610686565b61019a610195366004610e42565b6106b1565b60405161013c9796959493929190610e9c565b600354610132565b6101116101c3366004610eef565b610786565b6101db6101d6366004610e42565b6107e3565b60405161013c96959493929190610f19565b600454610200906001600160a01b031681565b6040516001600160a01b03909116815260200161013c565b61022b610226366004610d6d565b61092a565b60405161013c9190610f68565b610132610246366004610d6d565b6001600160a01b03165f9081526002602052604090205490565b61011161026e366004610fab565b610993565b610111610281366004610d6d565b610ca7565b6102a8610294366004610d6d565b60056020525f908152604090205460ff1681565b604051901515815260200161013c565b6004546001600160a01b031633146102eb5760405162461bcd60e51b81526004016102e290611001565b60405180910390fd5b6001600160a01b0381165f9081526005602052604090205460ff166103435760405162461bcd60e51b815260206004820152600e60248201526d139bdd08185d5d1a1bdc9a5e995960921b60448201526064016102e2565b6004546001600160a01b03908116908216036103975760405162461bcd60e51b815260206004820152601360248201527221b0b73737ba103932bb37b5b29037bbb732b960691b60448201526064016102e2565b6001600160a01b0381165f81815260056020526040808220805460ff19169055517f95a4c2ae425bb769549aa1a911911ff57c17ae811ae8c8962d0b9746fcb153979190a250565b6004546001600160a01b031633146104095760405162461bcd60e51b81526004016102e290611001565b6001600160a01b0381166104515760405162461bcd60e51b815260206004820152600f60248201526e496e76616c6964206164647265737360881b60448201526064016102e2565b6001600160a01b0381165f9081526005602052604090205460ff16156104ae5760405162461bcd60e51b8152602060048201526012602482015271105b1c9958591e48185d5d1a1bdc9a5e995960721b60448201526064016102e2565b6001600160a01b0381165f81815260056020526040808220805460ff19166001179055517f8b4006ca14f23d4e7aa8d120d1ab7c3761d650eefac1a5d6732dbea94b54ea249190a250565b5f8381526020819052604090206005015460ff166105515760405162461bcd60e51b815260206004820152601560248201527410d95c9d1a599a58d85d19481b9bdd08199bdd5b99605a1b60448201526064016102e2565b5f83815260208190526040902060050154610100900460ff16156105a95760405162461bcd60e51b815260206004820152600f60248201526e105b1c9958591e481c995d9bdad959608a1b60448201526064016102e2565b6004546001600160a01b03163314806105da57505f838152602081905260409020600201546001600160a01b031633145b6106265760405162461bcd60e51b815260206004820152601860248201527f4e6f7420617574686f72697a656420746f207265766f6b65000000000000000060448201526064016102e2565b5f8381526020819052604090819020600501805461ff00191661010017905551339084907f3224cf2853d6d5ae7308445d2b6a116e69358ed7e25ed585df081187ef3e5e2d906106799086908690611038565b60405180910390a3505050565b6001602052815f5260405f20818154811061069f575f80fd5b905f5260205f20015f91509150505481565b5f602081905290815260409020805460018201546002830154600384015460048501805494956001600160a01b0394851695939094169391926106f390611066565b80601f016020809104026020016040519081016040528092919081815260200182805461071f90611066565b801561076a5780601f106107415761010080835404028352916020019161076a565b820191905f5260205f20905b81548152906001019060200180831161074d57829003601f168201915b5050506005909301549192505060ff8082169161010090041687565b60405162461bcd60e51b815260206004820152602c60248201527f536f756c626f756e643a2043657274696669636174657320617265206e6f6e2d60448201526b7472616e7366657261626c6560a01b60648201526084016102e2565b5f81815260208181526040808320815160e0810183528154815260018201546001600160a01b0390811694820194909452600282015490931691830191909152600381015460608381019190915260048201805485948594859493859384939291608084019161085290611066565b80601f016020809104026020016040519081016040528092919081815260200182805461087e90611066565b80156108c95780601f106108a0576101008083540402835291602001916108c9565b820191905f5260205f20905b8154815290600101906020018083116108ac57829003601f168201915b50505091835250506005919091015460ff808216151560208085019190915261010090920416151560409283015260a083015190830151918301516060840151608085015160c090950151929d939c50909a50985091965090945092505050565b6001600160a01b0381165f9081526001602090815260409182902080548351818402810184019094528084526060939283018282801561098757602002820191905f5260205f20905b815481526020019060010190808311610973575b50505050509050919050565b335f9081526005602052604090205460ff16806109ba57506004546001600160a01b031633145b6109ff5760405162461bcd60e51b8152602060048201526016602482015275139bdd08185d5d1a1bdc9a5e9959081d1bc81b5a5b9d60521b60448201526064016102e2565b83610a4c5760405162461bcd60e51b815260206004820152601860248201527f496e76616c69642063657274696669636174652068617368000000000000000060448201526064016102e2565b6001600160a01b038316610a9a5760405162461bcd60e51b8152602060048201526015602482015274496e76616c6964206f776e6572206164647265737360581b60448201526064016102e2565b5f8481526020819052604090206005015460ff1615610afb5760405162461bcd60e51b815260206004820152601a60248201527f436572746966696361746520616c72656164792065786973747300000000000060448201526064016102e2565b6040518060e00160405280858152602001846001600160a01b03168152602001336001600160a01b0316815260200142815260200183838080601f0160208091040260200160405190810160405280939291908181526020018383808284375f920182905250938552505060016020808501829052604094850184905289845283815292849020855181559285015190830180546001600160a01b03199081166001600160a01b0393841617909155938501516002840180549095169116179092556060830151600382015560808301519091506004820190610bde9082611100565b5060a08201516005909101805460c09093015115156101000261ff00199215159290921661ffff19909316929092171790556001600160a01b0383165f908152600160208181526040808420805493840181558452818420909201879055338352600290528120805491610c51836111bc565b909155505060038054905f610c65836111bc565b909155505060405133906001600160a01b0385169086907ffed4fddf7278b7efd228f3df68bd80a9e1ef5ebe5b001cb42fee3d63693998d0905f90a450505050565b6004546001600160a01b03163314610cd15760405162461bcd60e51b81526004016102e290611001565b6001600160a01b038116610d195760405162461bcd60e51b815260206004820152600f60248201526e496e76616c6964206164647265737360881b60448201526064016102e2565b600480546001600160a01b039092166001600160a01b0319909216821790555f908152600560205260409020805460ff19166001179055565b80356001600160a01b0381168114610d68575f80fd5b919050565b5f60208284031215610d7d575f80fd5b610d8682610d52565b9392505050565b5f8083601f840112610d9d575f80fd5b50813567ffffffffffffffff811115610db4575f80fd5b602083019150836020828501011115610dcb575f80fd5b9250929050565b5f805f60408486031215610de4575f80fd5b83359250602084013567ffffffffffffffff811115610e01575f80fd5b610e0d86828701610d8d565b9497909650939450505050565b5f8060408385031215610e2b575f80fd5b610e3483610d52565b946020939093013593505050565b5f60208284031215610e52575f80fd5b5035919050565b5f81518084525f5b81811015610e7d57602081850181015186830182015201610e61565b505f602082860101526020601f19601f83011685010191505092915050565b8781526001600160a01b038781166020830152861660408201526060810185905260e0608082018190525f90610ed490830186610e59565b93151560a08301525090151560c09091015295945050505050565b5f8060408385031215610f00575f80fd5b82359150610f1060208401610d52565b90509250929050565b86151581526001600160a01b038681166020830152851660408201526060810184905260c0608082018190525f90610f5390830185610e59565b905082151560a0830152979650505050505050565b602080825282518282018190525f9190848201906040850190845b81811015610f9f57835183529284019291840191600101610f83565b50909695505050505050565b5f805f8060608587031215610fbe575f80fd5b84359350610fce60208601610d52565b9250604085013567ffffffffffffffff811115610fe9575f80fd5b610ff587828801610d8d565b95989497509550505050565b60208082526018908201527f4f6e6c79206f776e65722063616e2063616c6c20746869730000000000000000604082015260600190565b60208152816020820152818360408301375f818301604090810191909152601f909201601f19160101919050565b600181811c9082168061107a57607f821691505b60208210810361109857634e487b7160e01b5f52602260045260245ffd5b50919050565b634e487b7160e01b5f52604160045260245ffd5b601f8211156110fb575f81815260208120601f850160051c810160208610156110d85750805b601f850160051c820191505b818110156110f7578281556001016110e4565b5050505b505050565b815167ffffffffffffffff81111561111a5761111a61109e565b61112e816111288454611066565b846110b2565b602080601f831160018114611161575f841561114a5750858301515b5f19600386901b1c1916600185901b1785556110f7565b5f85815260208120601f198616915b8281101561118f57888601518255948401946001909101908401611170565b50858210156111ac57878501515f19600388901b60f8161c191681555b5050505050600190811b01905550565b5f600182016111d957634e487b7160e01b5f52601160045260245ffd5b506001019056fea26469706673582212208c97c90227c51a73fa12e95d4a1f4443de22dfc47b6fffa087567213742783e264736f6c63430008150033",
+}
+
+// SoulboundCertificateRegistryABI is the input ABI used to generate the binding from.
+// Deprecated: Use SoulboundCertificateRegistryMetaData.ABI instead.
+var SoulboundCertificateRegistryABI = SoulboundCertificateRegistryMetaData.ABI
+
+// SoulboundCertificateRegistryBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use SoulboundCertificateRegistryMetaData.Bin instead.
+var SoulboundCertificateRegistryBin = SoulboundCertificateRegistryMetaData.Bin
+
+// DeploySoulboundCertificateRegistry deploys a new Ethereum contract, binding an instance of SoulboundCertificateRegistry to it.
+func DeploySoulboundCertificateRegistry(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *SoulboundCertificateRegistry, error) {
+	parsed, err := SoulboundCertificateRegistryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(SoulboundCertificateRegistryBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &SoulboundCertificateRegistry{SoulboundCertificateRegistryCaller: SoulboundCertificateRegistryCaller{contract: contract}, SoulboundCertificateRegistryTransactor: SoulboundCertificateRegistryTransactor{contract: contract}, SoulboundCertificateRegistryFilterer: SoulboundCertificateRegistryFilterer{contract: contract}}, nil
+}
+
+// SoulboundCertificateRegistry is an auto generated Go binding around an Ethereum contract.
+type SoulboundCertificateRegistry struct {
+	SoulboundCertificateRegistryCaller     // Read-only binding to the contract
+	SoulboundCertificateRegistryTransactor // Write-only binding to the contract
+	SoulboundCertificateRegistryFilterer   // Log filterer for contract events
+}
+
+// SoulboundCertificateRegistryCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SoulboundCertificateRegistryCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SoulboundCertificateRegistryTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SoulboundCertificateRegistryTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SoulboundCertificateRegistryFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SoulboundCertificateRegistryFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SoulboundCertificateRegistrySession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SoulboundCertificateRegistrySession struct {
+	Contract     *SoulboundCertificateRegistry // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts                 // Call options to use throughout this session
+	TransactOpts bind.TransactOpts             // Transaction auth options to use throughout this session
+}
+
+// SoulboundCertificateRegistryCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SoulboundCertificateRegistryCallerSession struct {
+	Contract *SoulboundCertificateRegistryCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts                       // Call options to use throughout this session
+}
+
+// SoulboundCertificateRegistryTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SoulboundCertificateRegistryTransactorSession struct {
+	Contract     *SoulboundCertificateRegistryTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts                       // Transaction auth options to use throughout this session
+}
+
+// SoulboundCertificateRegistryRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SoulboundCertificateRegistryRaw struct {
+	Contract *SoulboundCertificateRegistry // Generic contract binding to access the raw methods on
+}
+
+// SoulboundCertificateRegistryCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SoulboundCertificateRegistryCallerRaw struct {
+	Contract *SoulboundCertificateRegistryCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SoulboundCertificateRegistryTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SoulboundCertificateRegistryTransactorRaw struct {
+	Contract *SoulboundCertificateRegistryTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSoulboundCertificateRegistry creates a new instance of SoulboundCertificateRegistry, bound to a specific deployed contract.
+func NewSoulboundCertificateRegistry(address common.Address, backend bind.ContractBackend) (*SoulboundCertificateRegistry, error) {
+	contract, err := bindSoulboundCertificateRegistry(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &SoulboundCertificateRegistry{SoulboundCertificateRegistryCaller: SoulboundCertificateRegistryCaller{contract: contract}, SoulboundCertificateRegistryTransactor: SoulboundCertificateRegistryTransactor{contract: contract}, SoulboundCertificateRegistryFilterer: SoulboundCertificateRegistryFilterer{contract: contract}}, nil
+}
+
+// NewSoulboundCertificateRegistryCaller creates a new read-only instance of SoulboundCertificateRegistry, bound to a specific deployed contract.
+func NewSoulboundCertificateRegistryCaller(address common.Address, caller bind.ContractCaller) (*SoulboundCertificateRegistryCaller, error) {
+	contract, err := bindSoulboundCertificateRegistry(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SoulboundCertificateRegistryCaller{contract: contract}, nil
+}
+
+// NewSoulboundCertificateRegistryTransactor creates a new write-only instance of SoulboundCertificateRegistry, bound to a specific deployed contract.
+func NewSoulboundCertificateRegistryTransactor(address common.Address, transactor bind.ContractTransactor) (*SoulboundCertificateRegistryTransactor, error) {
+	contract, err := bindSoulboundCertificateRegistry(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SoulboundCertificateRegistryTransactor{contract: contract}, nil
+}
+
+// NewSoulboundCertificateRegistryFilterer creates a new log filterer instance of SoulboundCertificateRegistry, bound to a specific deployed contract.
+func NewSoulboundCertificateRegistryFilterer(address common.Address, filterer bind.ContractFilterer) (*SoulboundCertificateRegistryFilterer, error) {
+	contract, err := bindSoulboundCertificateRegistry(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SoulboundCertificateRegistryFilterer{contract: contract}, nil
+}
+
+// bindSoulboundCertificateRegistry binds a generic wrapper to an already deployed contract.
+func bindSoulboundCertificateRegistry(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SoulboundCertificateRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SoulboundCertificateRegistry.Contract.SoulboundCertificateRegistryCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.SoulboundCertificateRegistryTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.SoulboundCertificateRegistryTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SoulboundCertificateRegistry.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.contract.Transact(opts, method, params...)
+}
+
+// AuthorizedIssuers is a free data retrieval call binding the contract method 0xf731fa0f.
+//
+// Solidity: function authorizedIssuers(address ) view returns(bool)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) AuthorizedIssuers(opts *bind.CallOpts, arg0 common.Address) (bool, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "authorizedIssuers", arg0)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// AuthorizedIssuers is a free data retrieval call binding the contract method 0xf731fa0f.
+//
+// Solidity: function authorizedIssuers(address ) view returns(bool)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) AuthorizedIssuers(arg0 common.Address) (bool, error) {
+	return _SoulboundCertificateRegistry.Contract.AuthorizedIssuers(&_SoulboundCertificateRegistry.CallOpts, arg0)
+}
+
+// AuthorizedIssuers is a free data retrieval call binding the contract method 0xf731fa0f.
+//
+// Solidity: function authorizedIssuers(address ) view returns(bool)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) AuthorizedIssuers(arg0 common.Address) (bool, error) {
+	return _SoulboundCertificateRegistry.Contract.AuthorizedIssuers(&_SoulboundCertificateRegistry.CallOpts, arg0)
+}
+
+// Certificates is a free data retrieval call binding the contract method 0x742f0688.
+//
+// Solidity: function certificates(bytes32 ) view returns(bytes32 hash, address owner, address issuer, uint256 timestamp, string academicDNA, bool exists, bool revoked)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) Certificates(opts *bind.CallOpts, arg0 [32]byte) (struct {
+	Hash        [32]byte
+	Owner       common.Address
+	Issuer      common.Address
+	Timestamp   *big.Int
+	AcademicDNA string
+	Exists      bool
+	Revoked     bool
+}, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "certificates", arg0)
+
+	outstruct := new(struct {
+		Hash        [32]byte
+		Owner       common.Address
+		Issuer      common.Address
+		Timestamp   *big.Int
+		AcademicDNA string
+		Exists      bool
+		Revoked     bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Hash = *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	outstruct.Owner = *abi.ConvertType(out[1], new(common.Address)).(*common.Address)
+	outstruct.Issuer = *abi.ConvertType(out[2], new(common.Address)).(*common.Address)
+	outstruct.Timestamp = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.AcademicDNA = *abi.ConvertType(out[4], new(string)).(*string)
+	outstruct.Exists = *abi.ConvertType(out[5], new(bool)).(*bool)
+	outstruct.Revoked = *abi.ConvertType(out[6], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Certificates is a free data retrieval call binding the contract method 0x742f0688.
+//
+// Solidity: function certificates(bytes32 ) view returns(bytes32 hash, address owner, address issuer, uint256 timestamp, string academicDNA, bool exists, bool revoked)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) Certificates(arg0 [32]byte) (struct {
+	Hash        [32]byte
+	Owner       common.Address
+	Issuer      common.Address
+	Timestamp   *big.Int
+	AcademicDNA string
+	Exists      bool
+	Revoked     bool
+}, error) {
+	return _SoulboundCertificateRegistry.Contract.Certificates(&_SoulboundCertificateRegistry.CallOpts, arg0)
+}
+
+// Certificates is a free data retrieval call binding the contract method 0x742f0688.
+//
+// Solidity: function certificates(bytes32 ) view returns(bytes32 hash, address owner, address issuer, uint256 timestamp, string academicDNA, bool exists, bool revoked)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) Certificates(arg0 [32]byte) (struct {
+	Hash        [32]byte
+	Owner       common.Address
+	Issuer      common.Address
+	Timestamp   *big.Int
+	AcademicDNA string
+	Exists      bool
+	Revoked     bool
+}, error) {
+	return _SoulboundCertificateRegistry.Contract.Certificates(&_SoulboundCertificateRegistry.CallOpts, arg0)
+}
+
+// GetCertificatesByWallet is a free data retrieval call binding the contract method 0xa350650f.
+//
+// Solidity: function getCertificatesByWallet(address _wallet) view returns(bytes32[])
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) GetCertificatesByWallet(opts *bind.CallOpts, _wallet common.Address) ([][32]byte, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "getCertificatesByWallet", _wallet)
+
+	if err != nil {
+		return *new([][32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
+
+	return out0, err
+
+}
+
+// GetCertificatesByWallet is a free data retrieval call binding the contract method 0xa350650f.
+//
+// Solidity: function getCertificatesByWallet(address _wallet) view returns(bytes32[])
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) GetCertificatesByWallet(_wallet common.Address) ([][32]byte, error) {
+	return _SoulboundCertificateRegistry.Contract.GetCertificatesByWallet(&_SoulboundCertificateRegistry.CallOpts, _wallet)
+}
+
+// GetCertificatesByWallet is a free data retrieval call binding the contract method 0xa350650f.
+//
+// Solidity: function getCertificatesByWallet(address _wallet) view returns(bytes32[])
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) GetCertificatesByWallet(_wallet common.Address) ([][32]byte, error) {
+	return _SoulboundCertificateRegistry.Contract.GetCertificatesByWallet(&_SoulboundCertificateRegistry.CallOpts, _wallet)
+}
+
+// GetIssuerCertCount is a free data retrieval call binding the contract method 0xc426cf90.
+//
+// Solidity: function getIssuerCertCount(address _issuer) view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) GetIssuerCertCount(opts *bind.CallOpts, _issuer common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "getIssuerCertCount", _issuer)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetIssuerCertCount is a free data retrieval call binding the contract method 0xc426cf90.
+//
+// Solidity: function getIssuerCertCount(address _issuer) view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) GetIssuerCertCount(_issuer common.Address) (*big.Int, error) {
+	return _SoulboundCertificateRegistry.Contract.GetIssuerCertCount(&_SoulboundCertificateRegistry.CallOpts, _issuer)
+}
+
+// GetIssuerCertCount is a free data retrieval call binding the contract method 0xc426cf90.
+//
+// Solidity: function getIssuerCertCount(address _issuer) view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) GetIssuerCertCount(_issuer common.Address) (*big.Int, error) {
+	return _SoulboundCertificateRegistry.Contract.GetIssuerCertCount(&_SoulboundCertificateRegistry.CallOpts, _issuer)
+}
+
+// GetTotalCertificates is a free data retrieval call binding the contract method 0x7843bb79.
+//
+// Solidity: function getTotalCertificates() view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) GetTotalCertificates(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "getTotalCertificates")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetTotalCertificates is a free data retrieval call binding the contract method 0x7843bb79.
+//
+// Solidity: function getTotalCertificates() view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) GetTotalCertificates() (*big.Int, error) {
+	return _SoulboundCertificateRegistry.Contract.GetTotalCertificates(&_SoulboundCertificateRegistry.CallOpts)
+}
+
+// GetTotalCertificates is a free data retrieval call binding the contract method 0x7843bb79.
+//
+// Solidity: function getTotalCertificates() view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) GetTotalCertificates() (*big.Int, error) {
+	return _SoulboundCertificateRegistry.Contract.GetTotalCertificates(&_SoulboundCertificateRegistry.CallOpts)
+}
+
+// IssuerCertCount is a free data retrieval call binding the contract method 0x37d13d40.
+//
+// Solidity: function issuerCertCount(address ) view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) IssuerCertCount(opts *bind.CallOpts, arg0 common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "issuerCertCount", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// IssuerCertCount is a free data retrieval call binding the contract method 0x37d13d40.
+//
+// Solidity: function issuerCertCount(address ) view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) IssuerCertCount(arg0 common.Address) (*big.Int, error) {
+	return _SoulboundCertificateRegistry.Contract.IssuerCertCount(&_SoulboundCertificateRegistry.CallOpts, arg0)
+}
+
+// IssuerCertCount is a free data retrieval call binding the contract method 0x37d13d40.
+//
+// Solidity: function issuerCertCount(address ) view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) IssuerCertCount(arg0 common.Address) (*big.Int, error) {
+	return _SoulboundCertificateRegistry.Contract.IssuerCertCount(&_SoulboundCertificateRegistry.CallOpts, arg0)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) Owner() (common.Address, error) {
+	return _SoulboundCertificateRegistry.Contract.Owner(&_SoulboundCertificateRegistry.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) Owner() (common.Address, error) {
+	return _SoulboundCertificateRegistry.Contract.Owner(&_SoulboundCertificateRegistry.CallOpts)
+}
+
+// TotalCertificates is a free data retrieval call binding the contract method 0x596b772f.
+//
+// Solidity: function totalCertificates() view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) TotalCertificates(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "totalCertificates")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TotalCertificates is a free data retrieval call binding the contract method 0x596b772f.
+//
+// Solidity: function totalCertificates() view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) TotalCertificates() (*big.Int, error) {
+	return _SoulboundCertificateRegistry.Contract.TotalCertificates(&_SoulboundCertificateRegistry.CallOpts)
+}
+
+// TotalCertificates is a free data retrieval call binding the contract method 0x596b772f.
+//
+// Solidity: function totalCertificates() view returns(uint256)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) TotalCertificates() (*big.Int, error) {
+	return _SoulboundCertificateRegistry.Contract.TotalCertificates(&_SoulboundCertificateRegistry.CallOpts)
+}
+
+// Transfer is a free data retrieval call binding the contract method 0x79ce9fac.
+//
+// Solidity: function transfer(bytes32 , address ) pure returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) Transfer(opts *bind.CallOpts, arg0 [32]byte, arg1 common.Address) error {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "transfer", arg0, arg1)
+
+	if err != nil {
+		return err
+	}
+
+	return err
+
+}
+
+// Transfer is a free data retrieval call binding the contract method 0x79ce9fac.
+//
+// Solidity: function transfer(bytes32 , address ) pure returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) Transfer(arg0 [32]byte, arg1 common.Address) error {
+	return _SoulboundCertificateRegistry.Contract.Transfer(&_SoulboundCertificateRegistry.CallOpts, arg0, arg1)
+}
+
+// Transfer is a free data retrieval call binding the contract method 0x79ce9fac.
+//
+// Solidity: function transfer(bytes32 , address ) pure returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) Transfer(arg0 [32]byte, arg1 common.Address) error {
+	return _SoulboundCertificateRegistry.Contract.Transfer(&_SoulboundCertificateRegistry.CallOpts, arg0, arg1)
+}
+
+// VerifyCertificate is a free data retrieval call binding the contract method 0x850c1768.
+//
+// Solidity: function verifyCertificate(bytes32 _certHash) view returns(bool exists, address certOwner, address issuer, uint256 timestamp, string academicDNA, bool revoked)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) VerifyCertificate(opts *bind.CallOpts, _certHash [32]byte) (struct {
+	Exists      bool
+	CertOwner   common.Address
+	Issuer      common.Address
+	Timestamp   *big.Int
+	AcademicDNA string
+	Revoked     bool
+}, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "verifyCertificate", _certHash)
+
+	outstruct := new(struct {
+		Exists      bool
+		CertOwner   common.Address
+		Issuer      common.Address
+		Timestamp   *big.Int
+		AcademicDNA string
+		Revoked     bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Exists = *abi.ConvertType(out[0], new(bool)).(*bool)
+	outstruct.CertOwner = *abi.ConvertType(out[1], new(common.Address)).(*common.Address)
+	outstruct.Issuer = *abi.ConvertType(out[2], new(common.Address)).(*common.Address)
+	outstruct.Timestamp = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.AcademicDNA = *abi.ConvertType(out[4], new(string)).(*string)
+	outstruct.Revoked = *abi.ConvertType(out[5], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// VerifyCertificate is a free data retrieval call binding the contract method 0x850c1768.
+//
+// Solidity: function verifyCertificate(bytes32 _certHash) view returns(bool exists, address certOwner, address issuer, uint256 timestamp, string academicDNA, bool revoked)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) VerifyCertificate(_certHash [32]byte) (struct {
+	Exists      bool
+	CertOwner   common.Address
+	Issuer      common.Address
+	Timestamp   *big.Int
+	AcademicDNA string
+	Revoked     bool
+}, error) {
+	return _SoulboundCertificateRegistry.Contract.VerifyCertificate(&_SoulboundCertificateRegistry.CallOpts, _certHash)
+}
+
+// VerifyCertificate is a free data retrieval call binding the contract method 0x850c1768.
+//
+// Solidity: function verifyCertificate(bytes32 _certHash) view returns(bool exists, address certOwner, address issuer, uint256 timestamp, string academicDNA, bool revoked)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) VerifyCertificate(_certHash [32]byte) (struct {
+	Exists      bool
+	CertOwner   common.Address
+	Issuer      common.Address
+	Timestamp   *big.Int
+	AcademicDNA string
+	Revoked     bool
+}, error) {
+	return _SoulboundCertificateRegistry.Contract.VerifyCertificate(&_SoulboundCertificateRegistry.CallOpts, _certHash)
+}
+
+// WalletCertificates is a free data retrieval call binding the contract method 0x6c1cf564.
+//
+// Solidity: function walletCertificates(address , uint256 ) view returns(bytes32)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCaller) WalletCertificates(opts *bind.CallOpts, arg0 common.Address, arg1 *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _SoulboundCertificateRegistry.contract.Call(opts, &out, "walletCertificates", arg0, arg1)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// WalletCertificates is a free data retrieval call binding the contract method 0x6c1cf564.
+//
+// Solidity: function walletCertificates(address , uint256 ) view returns(bytes32)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) WalletCertificates(arg0 common.Address, arg1 *big.Int) ([32]byte, error) {
+	return _SoulboundCertificateRegistry.Contract.WalletCertificates(&_SoulboundCertificateRegistry.CallOpts, arg0, arg1)
+}
+
+// WalletCertificates is a free data retrieval call binding the contract method 0x6c1cf564.
+//
+// Solidity: function walletCertificates(address , uint256 ) view returns(bytes32)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryCallerSession) WalletCertificates(arg0 common.Address, arg1 *big.Int) ([32]byte, error) {
+	return _SoulboundCertificateRegistry.Contract.WalletCertificates(&_SoulboundCertificateRegistry.CallOpts, arg0, arg1)
+}
+
+// AuthorizeIssuer is a paid mutator transaction binding the contract method 0x436693d5.
+//
+// Solidity: function authorizeIssuer(address _issuer) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactor) AuthorizeIssuer(opts *bind.TransactOpts, _issuer common.Address) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.contract.Transact(opts, "authorizeIssuer", _issuer)
+}
+
+// AuthorizeIssuer is a paid mutator transaction binding the contract method 0x436693d5.
+//
+// Solidity: function authorizeIssuer(address _issuer) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) AuthorizeIssuer(_issuer common.Address) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.AuthorizeIssuer(&_SoulboundCertificateRegistry.TransactOpts, _issuer)
+}
+
+// AuthorizeIssuer is a paid mutator transaction binding the contract method 0x436693d5.
+//
+// Solidity: function authorizeIssuer(address _issuer) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactorSession) AuthorizeIssuer(_issuer common.Address) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.AuthorizeIssuer(&_SoulboundCertificateRegistry.TransactOpts, _issuer)
+}
+
+// MintCertificate is a paid mutator transaction binding the contract method 0xe931e714.
+//
+// Solidity: function mintCertificate(bytes32 _certHash, address _owner, string _academicDNA) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactor) MintCertificate(opts *bind.TransactOpts, _certHash [32]byte, _owner common.Address, _academicDNA string) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.contract.Transact(opts, "mintCertificate", _certHash, _owner, _academicDNA)
+}
+
+// MintCertificate is a paid mutator transaction binding the contract method 0xe931e714.
+//
+// Solidity: function mintCertificate(bytes32 _certHash, address _owner, string _academicDNA) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) MintCertificate(_certHash [32]byte, _owner common.Address, _academicDNA string) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.MintCertificate(&_SoulboundCertificateRegistry.TransactOpts, _certHash, _owner, _academicDNA)
+}
+
+// MintCertificate is a paid mutator transaction binding the contract method 0xe931e714.
+//
+// Solidity: function mintCertificate(bytes32 _certHash, address _owner, string _academicDNA) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactorSession) MintCertificate(_certHash [32]byte, _owner common.Address, _academicDNA string) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.MintCertificate(&_SoulboundCertificateRegistry.TransactOpts, _certHash, _owner, _academicDNA)
+}
+
+// RevokeCertificate is a paid mutator transaction binding the contract method 0x5bf4adfb.
+//
+// Solidity: function revokeCertificate(bytes32 _certHash, string _reason) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactor) RevokeCertificate(opts *bind.TransactOpts, _certHash [32]byte, _reason string) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.contract.Transact(opts, "revokeCertificate", _certHash, _reason)
+}
+
+// RevokeCertificate is a paid mutator transaction binding the contract method 0x5bf4adfb.
+//
+// Solidity: function revokeCertificate(bytes32 _certHash, string _reason) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) RevokeCertificate(_certHash [32]byte, _reason string) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.RevokeCertificate(&_SoulboundCertificateRegistry.TransactOpts, _certHash, _reason)
+}
+
+// RevokeCertificate is a paid mutator transaction binding the contract method 0x5bf4adfb.
+//
+// Solidity: function revokeCertificate(bytes32 _certHash, string _reason) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactorSession) RevokeCertificate(_certHash [32]byte, _reason string) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.RevokeCertificate(&_SoulboundCertificateRegistry.TransactOpts, _certHash, _reason)
+}
+
+// RevokeIssuer is a paid mutator transaction binding the contract method 0x00629679.
+//
+// Solidity: function revokeIssuer(address _issuer) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactor) RevokeIssuer(opts *bind.TransactOpts, _issuer common.Address) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.contract.Transact(opts, "revokeIssuer", _issuer)
+}
+
+// RevokeIssuer is a paid mutator transaction binding the contract method 0x00629679.
+//
+// Solidity: function revokeIssuer(address _issuer) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) RevokeIssuer(_issuer common.Address) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.RevokeIssuer(&_SoulboundCertificateRegistry.TransactOpts, _issuer)
+}
+
+// RevokeIssuer is a paid mutator transaction binding the contract method 0x00629679.
+//
+// Solidity: function revokeIssuer(address _issuer) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactorSession) RevokeIssuer(_issuer common.Address) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.RevokeIssuer(&_SoulboundCertificateRegistry.TransactOpts, _issuer)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address _newOwner) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactor) TransferOwnership(opts *bind.TransactOpts, _newOwner common.Address) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.contract.Transact(opts, "transferOwnership", _newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address _newOwner) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistrySession) TransferOwnership(_newOwner common.Address) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.TransferOwnership(&_SoulboundCertificateRegistry.TransactOpts, _newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address _newOwner) returns()
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryTransactorSession) TransferOwnership(_newOwner common.Address) (*types.Transaction, error) {
+	return _SoulboundCertificateRegistry.Contract.TransferOwnership(&_SoulboundCertificateRegistry.TransactOpts, _newOwner)
+}
+
+// SoulboundCertificateRegistryCertificateMintedIterator is returned from FilterCertificateMinted and is used to iterate over the raw logs and unpacked data for CertificateMinted events raised by the SoulboundCertificateRegistry contract.
+type SoulboundCertificateRegistryCertificateMintedIterator struct {
+	Event *SoulboundCertificateRegistryCertificateMinted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SoulboundCertificateRegistryCertificateMintedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SoulboundCertificateRegistryCertificateMinted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SoulboundCertificateRegistryCertificateMinted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SoulboundCertificateRegistryCertificateMintedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SoulboundCertificateRegistryCertificateMintedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SoulboundCertificateRegistryCertificateMinted represents a CertificateMinted event raised by the SoulboundCertificateRegistry contract.
+type SoulboundCertificateRegistryCertificateMinted struct {
+	CertHash [32]byte
+	Owner    common.Address
+	Issuer   common.Address
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterCertificateMinted is a free log retrieval operation binding the contract event 0xfed4fddf7278b7efd228f3df68bd80a9e1ef5ebe5b001cb42fee3d63693998d0.
+//
+// Solidity: event CertificateMinted(bytes32 indexed certHash, address indexed owner, address indexed issuer)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) FilterCertificateMinted(opts *bind.FilterOpts, certHash [][32]byte, owner []common.Address, issuer []common.Address) (*SoulboundCertificateRegistryCertificateMintedIterator, error) {
+
+	var certHashRule []interface{}
+	for _, certHashItem := range certHash {
+		certHashRule = append(certHashRule, certHashItem)
+	}
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+
+	logs, sub, err := _SoulboundCertificateRegistry.contract.FilterLogs(opts, "CertificateMinted", certHashRule, ownerRule, issuerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SoulboundCertificateRegistryCertificateMintedIterator{contract: _SoulboundCertificateRegistry.contract, event: "CertificateMinted", logs: logs, sub: sub}, nil
+}
+
+// WatchCertificateMinted is a free log subscription operation binding the contract event 0xfed4fddf7278b7efd228f3df68bd80a9e1ef5ebe5b001cb42fee3d63693998d0.
+//
+// Solidity: event CertificateMinted(bytes32 indexed certHash, address indexed owner, address indexed issuer)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) WatchCertificateMinted(opts *bind.WatchOpts, sink chan<- *SoulboundCertificateRegistryCertificateMinted, certHash [][32]byte, owner []common.Address, issuer []common.Address) (event.Subscription, error) {
+
+	var certHashRule []interface{}
+	for _, certHashItem := range certHash {
+		certHashRule = append(certHashRule, certHashItem)
+	}
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+
+	logs, sub, err := _SoulboundCertificateRegistry.contract.WatchLogs(opts, "CertificateMinted", certHashRule, ownerRule, issuerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SoulboundCertificateRegistryCertificateMinted)
+				if err := _SoulboundCertificateRegistry.contract.UnpackLog(event, "CertificateMinted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCertificateMinted is a log parse operation binding the contract event 0xfed4fddf7278b7efd228f3df68bd80a9e1ef5ebe5b001cb42fee3d63693998d0.
+//
+// Solidity: event CertificateMinted(bytes32 indexed certHash, address indexed owner, address indexed issuer)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) ParseCertificateMinted(log types.Log) (*SoulboundCertificateRegistryCertificateMinted, error) {
+	event := new(SoulboundCertificateRegistryCertificateMinted)
+	if err := _SoulboundCertificateRegistry.contract.UnpackLog(event, "CertificateMinted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SoulboundCertificateRegistryCertificateRevokedIterator is returned from FilterCertificateRevoked and is used to iterate over the raw logs and unpacked data for CertificateRevoked events raised by the SoulboundCertificateRegistry contract.
+type SoulboundCertificateRegistryCertificateRevokedIterator struct {
+	Event *SoulboundCertificateRegistryCertificateRevoked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SoulboundCertificateRegistryCertificateRevokedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SoulboundCertificateRegistryCertificateRevoked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SoulboundCertificateRegistryCertificateRevoked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SoulboundCertificateRegistryCertificateRevokedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SoulboundCertificateRegistryCertificateRevokedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SoulboundCertificateRegistryCertificateRevoked represents a CertificateRevoked event raised by the SoulboundCertificateRegistry contract.
+type SoulboundCertificateRegistryCertificateRevoked struct {
+	CertHash [32]byte
+	Issuer   common.Address
+	Reason   string
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterCertificateRevoked is a free log retrieval operation binding the contract event 0x3224cf2853d6d5ae7308445d2b6a116e69358ed7e25ed585df081187ef3e5e2d.
+//
+// Solidity: event CertificateRevoked(bytes32 indexed certHash, address indexed issuer, string reason)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) FilterCertificateRevoked(opts *bind.FilterOpts, certHash [][32]byte, issuer []common.Address) (*SoulboundCertificateRegistryCertificateRevokedIterator, error) {
+
+	var certHashRule []interface{}
+	for _, certHashItem := range certHash {
+		certHashRule = append(certHashRule, certHashItem)
+	}
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+
+	logs, sub, err := _SoulboundCertificateRegistry.contract.FilterLogs(opts, "CertificateRevoked", certHashRule, issuerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SoulboundCertificateRegistryCertificateRevokedIterator{contract: _SoulboundCertificateRegistry.contract, event: "CertificateRevoked", logs: logs, sub: sub}, nil
+}
+
+// WatchCertificateRevoked is a free log subscription operation binding the contract event 0x3224cf2853d6d5ae7308445d2b6a116e69358ed7e25ed585df081187ef3e5e2d.
+//
+// Solidity: event CertificateRevoked(bytes32 indexed certHash, address indexed issuer, string reason)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) WatchCertificateRevoked(opts *bind.WatchOpts, sink chan<- *SoulboundCertificateRegistryCertificateRevoked, certHash [][32]byte, issuer []common.Address) (event.Subscription, error) {
+
+	var certHashRule []interface{}
+	for _, certHashItem := range certHash {
+		certHashRule = append(certHashRule, certHashItem)
+	}
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+
+	logs, sub, err := _SoulboundCertificateRegistry.contract.WatchLogs(opts, "CertificateRevoked", certHashRule, issuerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SoulboundCertificateRegistryCertificateRevoked)
+				if err := _SoulboundCertificateRegistry.contract.UnpackLog(event, "CertificateRevoked", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCertificateRevoked is a log parse operation binding the contract event 0x3224cf2853d6d5ae7308445d2b6a116e69358ed7e25ed585df081187ef3e5e2d.
+//
+// Solidity: event CertificateRevoked(bytes32 indexed certHash, address indexed issuer, string reason)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) ParseCertificateRevoked(log types.Log) (*SoulboundCertificateRegistryCertificateRevoked, error) {
+	event := new(SoulboundCertificateRegistryCertificateRevoked)
+	if err := _SoulboundCertificateRegistry.contract.UnpackLog(event, "CertificateRevoked", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SoulboundCertificateRegistryIssuerAuthorizedIterator is returned from FilterIssuerAuthorized and is used to iterate over the raw logs and unpacked data for IssuerAuthorized events raised by the SoulboundCertificateRegistry contract.
+type SoulboundCertificateRegistryIssuerAuthorizedIterator struct {
+	Event *SoulboundCertificateRegistryIssuerAuthorized // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SoulboundCertificateRegistryIssuerAuthorizedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SoulboundCertificateRegistryIssuerAuthorized)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SoulboundCertificateRegistryIssuerAuthorized)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SoulboundCertificateRegistryIssuerAuthorizedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SoulboundCertificateRegistryIssuerAuthorizedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SoulboundCertificateRegistryIssuerAuthorized represents a IssuerAuthorized event raised by the SoulboundCertificateRegistry contract.
+type SoulboundCertificateRegistryIssuerAuthorized struct {
+	Issuer common.Address
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterIssuerAuthorized is a free log retrieval operation binding the contract event 0x8b4006ca14f23d4e7aa8d120d1ab7c3761d650eefac1a5d6732dbea94b54ea24.
+//
+// Solidity: event IssuerAuthorized(address indexed issuer)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) FilterIssuerAuthorized(opts *bind.FilterOpts, issuer []common.Address) (*SoulboundCertificateRegistryIssuerAuthorizedIterator, error) {
+
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+
+	logs, sub, err := _SoulboundCertificateRegistry.contract.FilterLogs(opts, "IssuerAuthorized", issuerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SoulboundCertificateRegistryIssuerAuthorizedIterator{contract: _SoulboundCertificateRegistry.contract, event: "IssuerAuthorized", logs: logs, sub: sub}, nil
+}
+
+// WatchIssuerAuthorized is a free log subscription operation binding the contract event 0x8b4006ca14f23d4e7aa8d120d1ab7c3761d650eefac1a5d6732dbea94b54ea24.
+//
+// Solidity: event IssuerAuthorized(address indexed issuer)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) WatchIssuerAuthorized(opts *bind.WatchOpts, sink chan<- *SoulboundCertificateRegistryIssuerAuthorized, issuer []common.Address) (event.Subscription, error) {
+
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+
+	logs, sub, err := _SoulboundCertificateRegistry.contract.WatchLogs(opts, "IssuerAuthorized", issuerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SoulboundCertificateRegistryIssuerAuthorized)
+				if err := _SoulboundCertificateRegistry.contract.UnpackLog(event, "IssuerAuthorized", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseIssuerAuthorized is a log parse operation binding the contract event 0x8b4006ca14f23d4e7aa8d120d1ab7c3761d650eefac1a5d6732dbea94b54ea24.
+//
+// Solidity: event IssuerAuthorized(address indexed issuer)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) ParseIssuerAuthorized(log types.Log) (*SoulboundCertificateRegistryIssuerAuthorized, error) {
+	event := new(SoulboundCertificateRegistryIssuerAuthorized)
+	if err := _SoulboundCertificateRegistry.contract.UnpackLog(event, "IssuerAuthorized", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// SoulboundCertificateRegistryIssuerRevokedIterator is returned from FilterIssuerRevoked and is used to iterate over the raw logs and unpacked data for IssuerRevoked events raised by the SoulboundCertificateRegistry contract.
+type SoulboundCertificateRegistryIssuerRevokedIterator struct {
+	Event *SoulboundCertificateRegistryIssuerRevoked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SoulboundCertificateRegistryIssuerRevokedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SoulboundCertificateRegistryIssuerRevoked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SoulboundCertificateRegistryIssuerRevoked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SoulboundCertificateRegistryIssuerRevokedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SoulboundCertificateRegistryIssuerRevokedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SoulboundCertificateRegistryIssuerRevoked represents a IssuerRevoked event raised by the SoulboundCertificateRegistry contract.
+type SoulboundCertificateRegistryIssuerRevoked struct {
+	Issuer common.Address
+	Raw    types.Log // Blockchain specific contextual infos
+}
+
+// FilterIssuerRevoked is a free log retrieval operation binding the contract event 0x95a4c2ae425bb769549aa1a911911ff57c17ae811ae8c8962d0b9746fcb15397.
+//
+// Solidity: event IssuerRevoked(address indexed issuer)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) FilterIssuerRevoked(opts *bind.FilterOpts, issuer []common.Address) (*SoulboundCertificateRegistryIssuerRevokedIterator, error) {
+
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+
+	logs, sub, err := _SoulboundCertificateRegistry.contract.FilterLogs(opts, "IssuerRevoked", issuerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SoulboundCertificateRegistryIssuerRevokedIterator{contract: _SoulboundCertificateRegistry.contract, event: "IssuerRevoked", logs: logs, sub: sub}, nil
+}
+
+// WatchIssuerRevoked is a free log subscription operation binding the contract event 0x95a4c2ae425bb769549aa1a911911ff57c17ae811ae8c8962d0b9746fcb15397.
+//
+// Solidity: event IssuerRevoked(address indexed issuer)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) WatchIssuerRevoked(opts *bind.WatchOpts, sink chan<- *SoulboundCertificateRegistryIssuerRevoked, issuer []common.Address) (event.Subscription, error) {
+
+	var issuerRule []interface{}
+	for _, issuerItem := range issuer {
+		issuerRule = append(issuerRule, issuerItem)
+	}
+
+	logs, sub, err := _SoulboundCertificateRegistry.contract.WatchLogs(opts, "IssuerRevoked", issuerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SoulboundCertificateRegistryIssuerRevoked)
+				if err := _SoulboundCertificateRegistry.contract.UnpackLog(event, "IssuerRevoked", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseIssuerRevoked is a log parse operation binding the contract event 0x95a4c2ae425bb769549aa1a911911ff57c17ae811ae8c8962d0b9746fcb15397.
+//
+// Solidity: event IssuerRevoked(address indexed issuer)
+func (_SoulboundCertificateRegistry *SoulboundCertificateRegistryFilterer) ParseIssuerRevoked(log types.Log) (*SoulboundCertificateRegistryIssuerRevoked, error) {
+	event := new(SoulboundCertificateRegistryIssuerRevoked)
+	if err := _SoulboundCertificateRegistry.contract.UnpackLog(event, "IssuerRevoked", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,329 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Verifiable credentials are signed by the platform wallet with the
+// EthereumEip712Signature2021 suite: the credential itself is the EIP-712 message,
+// with types derived from its JSON, so every Signer backend can issue them and the
+// issuer is simply the wallet's did:ethr DID.
+const (
+	CredentialProofType = "EthereumEip712Signature2021"
+
+	credentialDomainName    = "Cognify Verifiable Credential"
+	credentialDomainVersion = "1"
+	credentialPrimaryType   = "VerifiableCredential"
+)
+
+// ErrCredentialProofInvalid is returned when a credential's proof does not check out
+var ErrCredentialProofInvalid = errors.New("invalid credential proof")
+
+// PlatformDID returns the did:ethr DID of the platform wallet on the active chain
+func PlatformDID() string {
+	signer, chainID, _ := platformSigner()
+	return EthrDID(chainID.Int64(), signer.Address())
+}
+
+// IsPlatformDID reports whether did names a platform wallet, current or retired
+// (see SetRetiredSigners), on a chain the platform has used: the active chain, a
+// registered deployment's, or one named by a retired platform DID. DIDs issued
+// before a key rotation or deployment migration stay the platform's.
+func IsPlatformDID(did string) bool {
+	chainID, address, err := parseEthrDID(did)
+	if err != nil || !IsPlatformAddress(address) {
+		return false
+	}
+	if _, active, _ := platformSigner(); chainID == active.Int64() {
+		return true
+	}
+	for _, d := range Deployments() {
+		if d.ChainID == chainID {
+			return true
+		}
+	}
+	retiredSignersMu.RLock()
+	defer retiredSignersMu.RUnlock()
+	return retiredChains[chainID]
+}
+
+// EthrDID returns the did:ethr DID of an address on a chain
+func EthrDID(chainID int64, address common.Address) string {
+	return fmt.Sprintf("did:ethr:%s:%s", hexutil.EncodeUint64(uint64(chainID)), address.Hex())
+}
+
+// parseEthrDID returns the chain and address a did:ethr DID or DID URL names.
+// A DID without a network is on mainnet.
+func parseEthrDID(did string) (int64, common.Address, error) {
+	did = strings.SplitN(did, "#", 2)[0]
+	if !strings.HasPrefix(did, "did:ethr:") {
+		return 0, common.Address{}, fmt.Errorf("%q is not a did:ethr DID", did)
+	}
+	parts := strings.Split(strings.TrimPrefix(did, "did:ethr:"), ":")
+	if len(parts) > 2 || !common.IsHexAddress(parts[len(parts)-1]) {
+		return 0, common.Address{}, fmt.Errorf("%q does not name an address", did)
+	}
+
+	chainID := uint64(1)
+	if len(parts) == 2 {
+		parsed, err := hexutil.DecodeUint64(parts[0])
+		if err != nil {
+			return 0, common.Address{}, fmt.Errorf("unsupported did:ethr network %q", parts[0])
+		}
+		chainID = parsed
+	}
+	return int64(chainID), common.HexToAddress(parts[len(parts)-1]), nil
+}
+
+// SignCredential adds a proof by the platform wallet to doc, replacing any it had.
+// doc's issuer should be PlatformDID.
+func SignCredential(doc models.VerifiableCredential) error {
+	signer, chainID, _ := platformSigner()
+	proof := map[string]interface{}{
+		"type":               CredentialProofType,
+		"created":            time.Now().UTC().Format(time.RFC3339),
+		"proofPurpose":       "assertionMethod",
+		"verificationMethod": EthrDID(chainID.Int64(), signer.Address()) + "#controller",
+	}
+
+	delete(doc, "proof")
+	data, err := credentialTypedData(doc, proof, chainID.Int64())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	sig, err := signer.SignTypedData(ctx, data)
+	if err != nil {
+		return fmt.Errorf("failed to sign credential: %w", err)
+	}
+
+	// The types travel with the proof so other verifiers need not derive them
+	proof["proofValue"] = hexutil.Encode(sig)
+	proof["eip712"] = map[string]interface{}{
+		"domain":      data.Domain.Map(),
+		"types":       data.Types,
+		"primaryType": data.PrimaryType,
+	}
+	doc["proof"] = proof
+	return nil
+}
+
+// VerifyCredentialProof checks that doc carries a valid proof by the platform
+// wallet, current or retired, and that the wallet's DID is doc's issuer
+func VerifyCredentialProof(doc models.VerifiableCredential) error {
+	address, err := CredentialSigner(doc)
+	if err != nil {
+		return err
+	}
+	if !IsPlatformAddress(address) {
+		return fmt.Errorf("%w: not issued by the platform", ErrCredentialProofInvalid)
+	}
+	return nil
+}
+
+// CredentialSigner checks doc's EthereumEip712Signature2021 proof and returns the
+// address that signed it, which must also be named by doc's did:ethr issuer. The
+// EIP-712 types are derived from doc again rather than taken from the proof.
+func CredentialSigner(doc models.VerifiableCredential) (common.Address, error) {
+	proof, ok := doc["proof"].(map[string]interface{})
+	if !ok {
+		return common.Address{}, fmt.Errorf("%w: no proof", ErrCredentialProofInvalid)
+	}
+	if proof["type"] != CredentialProofType {
+		return common.Address{}, fmt.Errorf("%w: unsupported proof type %v", ErrCredentialProofInvalid, proof["type"])
+	}
+	method, _ := proof["verificationMethod"].(string)
+	chainID, address, err := parseEthrDID(method)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrCredentialProofInvalid, err)
+	}
+	signature, _ := proof["proofValue"].(string)
+
+	options := make(map[string]interface{}, len(proof))
+	for key, value := range proof {
+		if key != "proofValue" && key != "eip712" {
+			options[key] = value
+		}
+	}
+	body := make(models.VerifiableCredential, len(doc))
+	for key, value := range doc {
+		if key != "proof" {
+			body[key] = value
+		}
+	}
+
+	data, err := credentialTypedData(body, options, chainID)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrCredentialProofInvalid, err)
+	}
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrCredentialProofInvalid, err)
+	}
+	recovered, err := recoverTypedDataSigner(hash, signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrCredentialProofInvalid, err)
+	}
+	if recovered != address {
+		return common.Address{}, fmt.Errorf("%w: signature does not match %s", ErrCredentialProofInvalid, method)
+	}
+
+	issuer, _ := doc["issuer"].(string)
+	if issuerObj, ok := doc["issuer"].(map[string]interface{}); ok {
+		issuer, _ = issuerObj["id"].(string)
+	}
+	if _, issuerAddress, err := parseEthrDID(issuer); err != nil || issuerAddress != address {
+		return common.Address{}, fmt.Errorf("%w: not signed by the issuer", ErrCredentialProofInvalid)
+	}
+	return address, nil
+}
+
+// credentialTypedData returns doc with the proof options embedded as EIP-712 typed data
+func credentialTypedData(doc models.VerifiableCredential, proofOptions map[string]interface{}, chainID int64) (apitypes.TypedData, error) {
+	withProof := make(map[string]interface{}, len(doc)+1)
+	for key, value := range doc {
+		withProof[key] = value
+	}
+	withProof["proof"] = proofOptions
+
+	// Round-trip through JSON so the message holds only JSON values
+	raw, err := json.Marshal(withProof)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	var message map[string]interface{}
+	if err := decoder.Decode(&message); err != nil {
+		return apitypes.TypedData{}, err
+	}
+
+	types := apitypes.Types{
+		"EIP712Domain": {
+			{Name: "name", Type: "string"},
+			{Name: "version", Type: "string"},
+			{Name: "chainId", Type: "uint256"},
+		},
+	}
+	if err := deriveCredentialTypes(credentialPrimaryType, message, types); err != nil {
+		return apitypes.TypedData{}, err
+	}
+
+	return apitypes.TypedData{
+		Types:       types,
+		PrimaryType: credentialPrimaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:    credentialDomainName,
+			Version: credentialDomainVersion,
+			ChainId: (*math.HexOrDecimal256)(big.NewInt(chainID)),
+		},
+		Message: message,
+	}, nil
+}
+
+// deriveCredentialTypes adds the EIP-712 type of obj, and of the objects nested in
+// it, to types. Fields are sorted by name; strings, booleans and non-negative
+// integers map to string, bool and uint256, and a nested object gets a struct
+// type named after its key. Numbers in obj are rewritten as decimal strings,
+// the form the EIP-712 encoder takes integers in.
+func deriveCredentialTypes(name string, obj map[string]interface{}, types apitypes.Types) error {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]apitypes.Type, 0, len(keys))
+	for _, key := range keys {
+		fieldType, err := credentialFieldType(key, obj[key], types)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if number, ok := obj[key].(json.Number); ok {
+			obj[key] = number.String()
+		}
+		fields = append(fields, apitypes.Type{Name: key, Type: fieldType})
+	}
+
+	if existing, ok := types[name]; ok && !reflect.DeepEqual(existing, fields) {
+		return fmt.Errorf("objects named %s have different fields", name)
+	}
+	types[name] = fields
+	return nil
+}
+
+func credentialFieldType(key string, value interface{}, types apitypes.Types) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return "string", nil
+	case bool:
+		return "bool", nil
+	case json.Number:
+		if n, ok := new(big.Int).SetString(v.String(), 10); !ok || n.Sign() < 0 {
+			return "", fmt.Errorf("only non-negative integers can be signed, got %s", v)
+		}
+		return "uint256", nil
+	case map[string]interface{}:
+		typeName := credentialTypeName(key)
+		if err := deriveCredentialTypes(typeName, v, types); err != nil {
+			return "", err
+		}
+		return typeName, nil
+	case []interface{}:
+		if len(v) == 0 {
+			return "", errors.New("empty arrays cannot be typed")
+		}
+		elemType := ""
+		for i, elem := range v {
+			t, err := credentialFieldType(key, elem, types)
+			if err != nil {
+				return "", err
+			}
+			if strings.HasSuffix(t, "[]") || (elemType != "" && t != elemType) {
+				return "", errors.New("arrays must hold values of one type")
+			}
+			if number, ok := elem.(json.Number); ok {
+				v[i] = number.String()
+			}
+			elemType = t
+		}
+		return elemType + "[]", nil
+	default:
+		return "", fmt.Errorf("%T values cannot be signed", value)
+	}
+}
+
+// credentialTypeName turns a JSON key into a struct type name: credentialSubject
+// becomes CredentialSubject
+func credentialTypeName(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	name := []rune(b.String())
+	if len(name) == 0 {
+		return "Object"
+	}
+	name[0] = unicode.ToUpper(name[0])
+	return string(name)
+}
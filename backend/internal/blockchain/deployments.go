@@ -0,0 +1,134 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	// ErrUnknownDeployment is returned for a ChainRef missing from the deployment registry
+	ErrUnknownDeployment = errors.New("unknown registry deployment")
+	// ErrReadOnlyDeployment is returned when sending a transaction to a retired deployment
+	ErrReadOnlyDeployment = errors.New("registry deployment is read-only")
+)
+
+var (
+	deploymentsMu sync.Mutex
+	deployments   []models.Deployment
+	// Read-only clients for retired deployments, dialed on first use
+	deploymentClients = make(map[models.ChainRef]*RealBlockchainClient)
+)
+
+// SetDeployments registers every known registry deployment, active or retired
+func SetDeployments(list []models.Deployment) {
+	deploymentsMu.Lock()
+	defer deploymentsMu.Unlock()
+	deployments = append([]models.Deployment(nil), list...)
+}
+
+// Deployments returns the registered deployments
+func Deployments() []models.Deployment {
+	deploymentsMu.Lock()
+	defer deploymentsMu.Unlock()
+	return append([]models.Deployment(nil), deployments...)
+}
+
+// ActiveChain returns the deployment new certificates are minted and anchored on.
+// The mock ledger reports chain 1337 and its own registry address.
+func ActiveChain() models.ChainRef {
+	if bc, ok := GetClient().(*RealBlockchainClient); ok {
+		return models.ChainRef{ChainID: bc.chainID.Int64(), Contract: bc.contractAddr.Hex()}
+	}
+	return models.ChainRef{ChainID: mockChainID, Contract: GetMockClient().contract.Hex()}
+}
+
+// IsActiveChain reports whether ref is the active deployment. A nil ref belongs to
+// a certificate recorded before deployments were tracked and counts as active.
+func IsActiveChain(ref *models.ChainRef) bool {
+	return ref == nil || normalizeChainRef(*ref) == normalizeChainRef(ActiveChain())
+}
+
+// ClientFor returns a client for the deployment holding ref: the active client,
+// or a read-only client for a retired deployment in the registry
+func ClientFor(ref *models.ChainRef) (BlockchainClient, error) {
+	if IsActiveChain(ref) {
+		return GetClient(), nil
+	}
+	key := normalizeChainRef(*ref)
+
+	deploymentsMu.Lock()
+	defer deploymentsMu.Unlock()
+
+	if bc, ok := deploymentClients[key]; ok {
+		return bc, nil
+	}
+	for _, d := range deployments {
+		if normalizeChainRef(d.Ref()) != key {
+			continue
+		}
+		bc, err := dialDeployment(d)
+		if err != nil {
+			return nil, err
+		}
+		deploymentClients[key] = bc
+		return bc, nil
+	}
+	return nil, fmt.Errorf("%w: chain %d, contract %s", ErrUnknownDeployment, ref.ChainID, ref.Contract)
+}
+
+// dialDeployment connects to a retired deployment for reads. It has no signer,
+// so transactions fail with ErrReadOnlyDeployment.
+func dialDeployment(d models.Deployment) (*RealBlockchainClient, error) {
+	client, err := ethclient.Dial(d.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to deployment %s: %w", d.Name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get chain ID of deployment %s: %w", d.Name, err)
+	}
+	if chainID.Int64() != d.ChainID {
+		client.Close()
+		return nil, fmt.Errorf("deployment %s: node is on chain %s, expected %d", d.Name, chainID, d.ChainID)
+	}
+
+	contractAddr := common.HexToAddress(d.ContractAddress)
+	contract, err := NewSoulboundCertificateRegistry(contractAddr, client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to bind deployment %s: %w", d.Name, err)
+	}
+
+	log.Printf("🔗 Connected to retired deployment %s (chain %d, contract %s)", d.Name, d.ChainID, contractAddr.Hex())
+	return &RealBlockchainClient{
+		client:       client,
+		contract:     contract,
+		contractAddr: contractAddr,
+		chainID:      chainID,
+	}, nil
+}
+
+// normalizeChainRef checksums the contract address so refs compare by value
+func normalizeChainRef(ref models.ChainRef) models.ChainRef {
+	if common.IsHexAddress(ref.Contract) {
+		ref.Contract = common.HexToAddress(ref.Contract).Hex()
+	} else {
+		ref.Contract = strings.ToLower(ref.Contract)
+	}
+	return ref
+}
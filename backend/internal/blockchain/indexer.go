@@ -0,0 +1,478 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/services"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// journalDepth is how many blocks below the checkpoint keep their applied events,
+// which bounds the deepest reorg that can be undone
+const journalDepth = 256
+
+// logPageSize caps the block span of one eth_getLogs request. filterLogs splits a
+// page further when the provider still reports too many results.
+const logPageSize = 2000
+
+// tooManyResultsErrors are fragments of the errors providers return when an
+// eth_getLogs range has too many results or is too wide
+var tooManyResultsErrors = []string{
+	"too many results",
+	"query returned more than",
+	"response size exceeded",
+	"range is too large",
+	"range too large",
+	"range is too wide",
+	"limit exceeded",
+}
+
+// indexMu serializes every indexer, so the listener, the sync worker and a backfill
+// never apply the same block range twice
+var indexMu sync.Mutex
+
+// indexer applies registry events to storage once they have enough confirmations.
+// Progress lives in a single checkpoint (system_state/sync_state) shared by the
+// event listener and the sync worker. Applied events are journaled per block so a
+// reorg can be detected by block hash and rolled back before the new chain is replayed.
+type indexer struct {
+	client          logClient
+	contractAddress common.Address
+	confirmations   uint64
+	startBlock      uint64 // First block scanned when there is no checkpoint yet (the deployment block)
+}
+
+// syncPage describes one page of blocks applied by the indexer
+type syncPage struct {
+	From    uint64
+	To      uint64
+	Safe    uint64 // Newest confirmed block when the page was fetched
+	Applied int
+}
+
+// sync applies every confirmed block after the checkpoint and returns how many events it applied
+func (ix *indexer) sync(ctx context.Context) (int, error) {
+	return ix.run(ctx, nil)
+}
+
+// run applies confirmed blocks one page at a time until the checkpoint reaches the
+// confirmed head, calling progress after each page. The lock is released between
+// pages so other indexers can interleave.
+func (ix *indexer) run(ctx context.Context, progress func(syncPage)) (int, error) {
+	applied := 0
+	for {
+		page, done, err := ix.syncPage(ctx)
+		applied += page.Applied
+		if err != nil {
+			return applied, err
+		}
+		if progress != nil && page.To > 0 {
+			progress(page)
+		}
+		if done {
+			return applied, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return applied, err
+		}
+	}
+}
+
+// syncPage applies at most logPageSize blocks after the checkpoint. done is true
+// once the checkpoint has caught up with the confirmed head.
+func (ix *indexer) syncPage(ctx context.Context) (syncPage, bool, error) {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+
+	state, err := ix.loadState(ctx)
+	if err != nil {
+		return syncPage{}, false, err
+	}
+
+	head, err := ix.client.BlockNumber(ctx)
+	if err != nil {
+		return syncPage{}, false, fmt.Errorf("failed to get current block: %w", err)
+	}
+	if head < ix.confirmations {
+		return syncPage{}, true, nil
+	}
+	safe := head - ix.confirmations
+
+	// 1. Undo anything applied from blocks that are no longer canonical
+	rolledBack, err := ix.checkReorg(ctx, state)
+	if err != nil {
+		return syncPage{}, false, err
+	}
+	if state.LastSyncedBlock >= safe {
+		if rolledBack {
+			return syncPage{}, true, db.Repos.SystemState.SaveSyncState(ctx, state)
+		}
+		return syncPage{}, true, nil
+	}
+
+	// 2. Fetch the next page of the confirmed range
+	page := syncPage{
+		From: state.LastSyncedBlock + 1,
+		To:   min(state.LastSyncedBlock+logPageSize, safe),
+		Safe: safe,
+	}
+	toHash, err := ix.canonicalHash(ctx, page.To)
+	if err != nil {
+		return page, false, err
+	}
+	logs, err := ix.filterLogs(ctx, page.From, page.To)
+	if err != nil {
+		return page, false, err
+	}
+
+	// A reorg while fetching would mix forks; leave the range for the next run
+	if err := ix.checkCanonical(ctx, logs); err != nil {
+		return page, false, err
+	}
+
+	// 3. Apply and journal
+	for _, vLog := range logs {
+		if vLog.Removed {
+			continue
+		}
+		event, err := applyLog(ctx, ix.client, vLog)
+		if err != nil {
+			// Stop before this block so all of it is retried; re-applying its earlier events is harmless
+			cause := fmt.Errorf("failed to apply event in block %d: %w", vLog.BlockNumber, err)
+			if n := len(state.Journal); n > 0 && state.Journal[n-1].Number == vLog.BlockNumber {
+				state.Journal = state.Journal[:n-1]
+			}
+			// Genesis holds no logs, but a provider reporting block 0 mustn't wrap the checkpoint around
+			block, hash := uint64(0), ""
+			if vLog.BlockNumber > 0 {
+				block = vLog.BlockNumber - 1
+				hash, _ = ix.canonicalHash(ctx, block)
+			}
+			return page, false, ix.saveProgress(ctx, state, block, hash, cause)
+		}
+		if event != nil {
+			journalEvent(state, vLog, *event)
+			page.Applied++
+		}
+	}
+
+	return page, page.To >= safe, ix.saveProgress(ctx, state, page.To, toHash, nil)
+}
+
+// loadState returns the checkpoint, starting just before startBlock when there is
+// none yet or it is older than the deployment
+func (ix *indexer) loadState(ctx context.Context) (*models.SystemState, error) {
+	state, err := db.Repos.SystemState.GetSyncState(ctx)
+	if errors.Is(err, db.ErrNotFound) {
+		state = &models.SystemState{}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load sync state: %w", err)
+	}
+
+	if ix.startBlock > 0 && state.LastSyncedBlock+1 < ix.startBlock {
+		// Nothing before the deployment can hold registry events
+		state.LastSyncedBlock = ix.startBlock - 1
+		state.LastSyncedHash = ""
+		state.Journal = nil
+	}
+	return state, nil
+}
+
+// rewind moves the checkpoint back to block so the blocks after it are applied again.
+// Events journaled above block are not undone, since re-applying them ends in the same
+// state: mints already recorded from the same transaction are skipped (RecordMint),
+// repeated revocations only set their status list bit again (RevocationService.Record),
+// and issuer events set the authorization flag in chain order, so it ends as the last
+// event left it. Until the replay passes an issuer's last event the stored flag may
+// show an earlier value; authorization checks read the chain first (IsAuthorizedIssuerCached).
+func (ix *indexer) rewind(ctx context.Context, block uint64) error {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+
+	state, err := ix.loadState(ctx)
+	if err != nil {
+		return err
+	}
+	if block >= state.LastSyncedBlock {
+		return nil
+	}
+
+	hash := ""
+	if block > 0 {
+		if hash, err = ix.canonicalHash(ctx, block); err != nil {
+			return err
+		}
+	}
+
+	kept := state.Journal[:0]
+	for _, b := range state.Journal {
+		if b.Number <= block {
+			kept = append(kept, b)
+		}
+	}
+	state.Journal = kept
+	state.LastSyncedBlock = block
+	state.LastSyncedHash = hash
+	return db.Repos.SystemState.SaveSyncState(ctx, state)
+}
+
+// filterLogs fetches the registry's logs in [from, to], halving the range for as
+// long as the provider rejects it for returning too many results
+func (ix *indexer) filterLogs(ctx context.Context, from, to uint64) ([]types.Log, error) {
+	logs, err := ix.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{ix.contractAddress},
+	})
+	if err == nil {
+		return logs, nil
+	}
+	if from == to || !isTooManyResults(err) {
+		return nil, fmt.Errorf("failed to filter logs in blocks %d-%d: %w", from, to, err)
+	}
+
+	mid := from + (to-from)/2
+	log.Printf("[Indexer] ✂️ Too many results for blocks %d-%d, splitting at %d", from, to, mid)
+	first, err := ix.filterLogs(ctx, from, mid)
+	if err != nil {
+		return nil, err
+	}
+	second, err := ix.filterLogs(ctx, mid+1, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+func isTooManyResults(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range tooManyResultsErrors {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveProgress moves the checkpoint to block, prunes the journal and saves the state.
+// cause is returned as is (or joined with a save failure) so callers can report both.
+func (ix *indexer) saveProgress(ctx context.Context, state *models.SystemState, block uint64, hash string, cause error) error {
+	if block < state.LastSyncedBlock {
+		block = state.LastSyncedBlock
+		hash = state.LastSyncedHash
+	}
+
+	state.LastSyncedBlock = block
+	state.LastSyncedHash = hash
+	if hash != "" && (len(state.Journal) == 0 || state.Journal[len(state.Journal)-1].Number < block) {
+		// Record the checkpoint itself so later reorg checks have a reference point
+		state.Journal = append(state.Journal, models.SyncedBlock{Number: block, Hash: hash})
+	}
+
+	pruned := state.Journal[:0]
+	for _, b := range state.Journal {
+		if b.Number+journalDepth > block {
+			pruned = append(pruned, b)
+		}
+	}
+	state.Journal = pruned
+
+	if err := db.Repos.SystemState.SaveSyncState(ctx, state); err != nil {
+		return errors.Join(cause, fmt.Errorf("failed to save sync state: %w", err))
+	}
+	return cause
+}
+
+// checkReorg compares the checkpoint's block hash with the canonical chain and, on
+// a mismatch, undoes journaled events back to the newest block that is still canonical
+func (ix *indexer) checkReorg(ctx context.Context, state *models.SystemState) (bool, error) {
+	if state.LastSyncedHash == "" {
+		return false, nil
+	}
+	canonical, err := ix.canonicalHash(ctx, state.LastSyncedBlock)
+	if err != nil {
+		return false, err
+	}
+	if canonical == state.LastSyncedHash {
+		return false, nil
+	}
+
+	log.Printf("[Indexer] 🔀 Reorg detected at block %d (had %s, chain has %s)", state.LastSyncedBlock, state.LastSyncedHash, canonical)
+
+	for i := len(state.Journal) - 1; i >= 0; i-- {
+		block := state.Journal[i]
+		canonical, err := ix.canonicalHash(ctx, block.Number)
+		if err != nil {
+			return false, err
+		}
+		if canonical == block.Hash {
+			// Common ancestor: everything above it has been undone
+			state.Journal = state.Journal[:i+1]
+			state.LastSyncedBlock = block.Number
+			state.LastSyncedHash = block.Hash
+			log.Printf("[Indexer] ↩️ Rolled back to block %d", block.Number)
+			return true, nil
+		}
+		for j := len(block.Events) - 1; j >= 0; j-- {
+			if err := undoEvent(ctx, block.Events[j]); err != nil {
+				return false, fmt.Errorf("failed to undo %s of %s: %w", block.Events[j].Type, block.Events[j].Key, err)
+			}
+		}
+	}
+
+	// The reorg is deeper than the journal: replay from just before its oldest block
+	restart := state.LastSyncedBlock - min(state.LastSyncedBlock, journalDepth)
+	if len(state.Journal) > 0 && state.Journal[0].Number > 0 {
+		restart = state.Journal[0].Number - 1
+	}
+	log.Printf("[Indexer] ⚠️ Reorg deeper than the journal; replaying from block %d", restart+1)
+	state.Journal = nil
+	state.LastSyncedBlock = restart
+	state.LastSyncedHash = ""
+	return true, nil
+}
+
+// checkCanonical verifies that every block the logs came from is still on the canonical chain
+func (ix *indexer) checkCanonical(ctx context.Context, logs []types.Log) error {
+	checked := make(map[uint64]bool)
+	for _, vLog := range logs {
+		if checked[vLog.BlockNumber] {
+			continue
+		}
+		canonical, err := ix.canonicalHash(ctx, vLog.BlockNumber)
+		if err != nil {
+			return err
+		}
+		if canonical != vLog.BlockHash.Hex() {
+			return fmt.Errorf("block %d changed while fetching logs", vLog.BlockNumber)
+		}
+		checked[vLog.BlockNumber] = true
+	}
+	return nil
+}
+
+func (ix *indexer) canonicalHash(ctx context.Context, number uint64) (string, error) {
+	header, err := ix.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return "", fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+	return header.Hash().Hex(), nil
+}
+
+// journalEvent records an applied event under its block
+func journalEvent(state *models.SystemState, vLog types.Log, event models.SyncedEvent) {
+	n := len(state.Journal)
+	if n == 0 || state.Journal[n-1].Number != vLog.BlockNumber {
+		state.Journal = append(state.Journal, models.SyncedBlock{Number: vLog.BlockNumber, Hash: vLog.BlockHash.Hex()})
+		n++
+	}
+	state.Journal[n-1].Events = append(state.Journal[n-1].Events, event)
+}
+
+// applyLog writes one registry event to storage and returns what it did,
+// or nil for events that don't touch storage
+func applyLog(ctx context.Context, client logClient, vLog types.Log) (*models.SyncedEvent, error) {
+	if len(vLog.Topics) == 0 {
+		return nil, nil
+	}
+
+	switch vLog.Topics[0] {
+	case EventCertificateMinted:
+		// Topics: [Signature, CertHash, Owner, Issuer]
+		if len(vLog.Topics) < 4 {
+			return nil, nil
+		}
+		owner := common.BytesToAddress(vLog.Topics[2].Bytes())
+		if owner == AnchorOwner {
+			// A Merkle root, not a certificate; the anchorer tracks it
+			return nil, nil
+		}
+		hash := certificateKey(vLog.Topics[1])
+		issuer := common.BytesToAddress(vLog.Topics[3].Bytes())
+		log.Printf("[Blockchain] 🟢 Certificate Minted: %s (Owner: %s) by %s", hash, owner.Hex(), issuer.Hex())
+		event := MintEvent{Hash: hash, Owner: owner, Issuer: issuer, TxHash: vLog.TxHash.Hex(), BlockNumber: vLog.BlockNumber}
+		if err := RecordMint(ctx, GetClient(), event); err != nil {
+			return nil, err
+		}
+		return &models.SyncedEvent{Type: models.SyncedEventMinted, Key: hash, TxHash: vLog.TxHash.Hex()}, nil
+
+	case EventCertificateRevoked:
+		// Topics: [Signature, CertHash, Revoker]; Data: [Reason string]
+		if len(vLog.Topics) < 3 {
+			return nil, nil
+		}
+		hash := certificateKey(vLog.Topics[1])
+		log.Printf("[Blockchain] 🔴 Certificate Revoked: %s", hash)
+		if err := recordRevocation(ctx, client, vLog); err != nil {
+			return nil, err
+		}
+		return &models.SyncedEvent{Type: models.SyncedEventRevoked, Key: hash, TxHash: vLog.TxHash.Hex()}, nil
+
+	case EventIssuerAuthorized, EventIssuerRevoked:
+		// Topics: [Signature, IssuerAddress]
+		if len(vLog.Topics) < 2 {
+			return nil, nil
+		}
+		issuer := common.BytesToAddress(vLog.Topics[1].Bytes()).Hex()
+		authorized := vLog.Topics[0] == EventIssuerAuthorized
+		eventType := models.SyncedEventIssuerRevoked
+		if authorized {
+			eventType = models.SyncedEventIssuerAuthorized
+			log.Printf("[Blockchain] 🛡️ Issuer Authorized: %s", issuer)
+		} else {
+			log.Printf("[Blockchain] 🚫 Issuer Revoked: %s", issuer)
+		}
+		// Update every user bound to this wallet (authorizing also forces the instructor role)
+		if err := db.Repos.Users.SetAuthorization(ctx, issuer, authorized); err != nil {
+			return nil, err
+		}
+		ForgetIssuer(issuer)
+		return &models.SyncedEvent{Type: eventType, Key: issuer, TxHash: vLog.TxHash.Hex()}, nil
+	}
+	return nil, nil
+}
+
+// undoEvent reverses an applied event whose block was reorged out
+func undoEvent(ctx context.Context, event models.SyncedEvent) error {
+	log.Printf("[Indexer] ↩️ Undoing %s of %s (tx %s)", event.Type, event.Key, event.TxHash)
+
+	var err error
+	switch event.Type {
+	case models.SyncedEventMinted:
+		err = db.Repos.Certificates.UnmarkMinted(ctx, event.Key)
+	case models.SyncedEventRevoked:
+		if err = db.Repos.Certificates.ClearRevocation(ctx, event.Key); err == nil {
+			err = clearStatusListBit(ctx, event.Key)
+		}
+	case models.SyncedEventIssuerAuthorized:
+		err = db.Repos.Users.SetAuthorization(ctx, event.Key, false)
+		ForgetIssuer(event.Key)
+	case models.SyncedEventIssuerRevoked:
+		err = db.Repos.Users.SetAuthorization(ctx, event.Key, true)
+		ForgetIssuer(event.Key)
+	}
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// clearStatusListBit withdraws an undone revocation from the certificate's status list
+func clearStatusListBit(ctx context.Context, certHash string) error {
+	cert, err := db.Repos.Certificates.Get(ctx, certHash)
+	if err != nil {
+		return err
+	}
+	return services.NewStatusListService().SetRevoked(ctx, cert, false)
+}
@@ -0,0 +1,171 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testRegistryAddress = common.HexToAddress("0x4444444444444444444444444444444444444444")
+
+// scriptedChain is a logClient over blocks built by the test; rebuilding blocks on
+// another fork simulates a reorg
+type scriptedChain struct {
+	mu      sync.Mutex
+	headers []*types.Header
+	logs    map[uint64][]types.Log
+}
+
+func newScriptedChain(length int) *scriptedChain {
+	c := &scriptedChain{logs: make(map[uint64][]types.Log)}
+	c.fork(0, length, "main")
+	return c
+}
+
+// fork replaces every block from number on with length-number blocks tagged name,
+// dropping their logs
+func (c *scriptedChain) fork(number, length int, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.headers = c.headers[:number]
+	for n := number; n < length; n++ {
+		header := &types.Header{Number: big.NewInt(int64(n)), Extra: []byte(name), Difficulty: common.Big0}
+		if n > 0 {
+			header.ParentHash = c.headers[n-1].Hash()
+		}
+		c.headers = append(c.headers, header)
+		delete(c.logs, uint64(n))
+	}
+}
+
+// emit adds a registry log to a block
+func (c *scriptedChain) emit(number uint64, data []byte, topics ...common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logs[number] = append(c.logs[number], types.Log{
+		Address:     testRegistryAddress,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: number,
+		TxHash:      crypto.Keccak256Hash(topics[1].Bytes(), []byte{byte(number)}),
+	})
+}
+
+func (c *scriptedChain) BlockNumber(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(len(c.headers) - 1), nil
+}
+
+func (c *scriptedChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !number.IsUint64() || number.Uint64() >= uint64(len(c.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return c.headers[number.Uint64()], nil
+}
+
+func (c *scriptedChain) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var logs []types.Log
+	for n := q.FromBlock.Uint64(); n <= q.ToBlock.Uint64() && n < uint64(len(c.headers)); n++ {
+		for _, vLog := range c.logs[n] {
+			vLog.BlockHash = c.headers[n].Hash()
+			logs = append(logs, vLog)
+		}
+	}
+	return logs, nil
+}
+
+func (c *scriptedChain) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("subscriptions not supported")
+}
+
+func TestIndexerUndoesReorgedEvents(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+
+	const issuer = "0x5555555555555555555555555555555555555555"
+	hash := crypto.Keccak256Hash([]byte(t.Name()))
+	cert := &models.Certificate{Hash: certificateKey(hash), WalletAddress: testStudent}
+	if err := db.Repos.Certificates.Save(ctx, cert); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Repos.Users.Save(ctx, &models.User{ID: "issuer", WalletAddress: issuer}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Synced to block 2 first, so the journal holds it as a reference point
+	chain := newScriptedChain(3)
+	ix := &indexer{client: chain, contractAddress: testRegistryAddress}
+	if _, err := ix.sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Blocks 3-7: the certificate is minted in 3 and revoked in 4, the issuer authorized in 5
+	chain.fork(3, 8, "main")
+	chain.emit(3, nil, EventCertificateMinted, hash, common.BytesToHash(common.HexToAddress(testStudent).Bytes()), common.BytesToHash(common.HexToAddress(issuer).Bytes()))
+	reason, err := registryEventData("CertificateRevoked", "plagiarism")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.emit(4, reason, EventCertificateRevoked, hash, common.BytesToHash(common.HexToAddress(issuer).Bytes()))
+	chain.emit(5, nil, EventIssuerAuthorized, common.BytesToHash(common.HexToAddress(issuer).Bytes()))
+
+	if applied, err := ix.sync(ctx); err != nil || applied != 3 {
+		t.Fatalf("sync = %d, %v, want 3 events", applied, err)
+	}
+	stored, _ := db.Repos.Certificates.Get(ctx, cert.Hash)
+	user, _ := db.Repos.Users.Get(ctx, "issuer")
+	if !stored.IsMinted || !stored.Revoked || !user.IsAuthorized {
+		t.Fatalf("before the reorg: minted %v, revoked %v, issuer authorized %v", stored.IsMinted, stored.Revoked, user.IsAuthorized)
+	}
+
+	// A longer fork from block 2 drops all three events
+	chain.fork(3, 10, "reorg")
+	state, err := db.Repos.SystemState.GetSyncState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rolledBack, err := ix.checkReorg(ctx, state)
+	if err != nil || !rolledBack {
+		t.Fatalf("checkReorg = %v, %v, want a rollback", rolledBack, err)
+	}
+	ancestor, _ := chain.HeaderByNumber(ctx, big.NewInt(2))
+	if state.LastSyncedBlock != 2 || state.LastSyncedHash != ancestor.Hash().Hex() {
+		t.Errorf("checkpoint = %d %s, want the common ancestor 2 %s", state.LastSyncedBlock, state.LastSyncedHash, ancestor.Hash().Hex())
+	}
+
+	stored, _ = db.Repos.Certificates.Get(ctx, cert.Hash)
+	user, _ = db.Repos.Users.Get(ctx, "issuer")
+	if stored.IsMinted || stored.Revoked || stored.BlockchainTx != "" || user.IsAuthorized {
+		t.Errorf("after the reorg: minted %v (tx %q), revoked %v, issuer authorized %v", stored.IsMinted, stored.BlockchainTx, stored.Revoked, user.IsAuthorized)
+	}
+
+	// Syncing follows the new fork to its head
+	if _, err := ix.sync(ctx); err != nil {
+		t.Fatalf("sync after the reorg: %v", err)
+	}
+	state, _ = db.Repos.SystemState.GetSyncState(ctx)
+	head, _ := chain.HeaderByNumber(ctx, big.NewInt(9))
+	if state.LastSyncedBlock != 9 || state.LastSyncedHash != head.Hash().Hex() {
+		t.Errorf("checkpoint = %d %s, want the new head 9 %s", state.LastSyncedBlock, state.LastSyncedHash, head.Hash().Hex())
+	}
+	for _, block := range state.Journal {
+		if len(block.Events) > 0 {
+			t.Errorf("journal still holds events of block %d: %+v", block.Number, block.Events)
+		}
+	}
+}
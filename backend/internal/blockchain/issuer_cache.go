@@ -0,0 +1,51 @@
+package blockchain
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// issuerCacheTTL is how long an on-chain authorization answer is reused. Issuer
+// events and admin actions drop the cached answer early.
+const issuerCacheTTL = 5 * time.Minute
+
+type issuerCacheEntry struct {
+	authorized bool
+	checkedAt  time.Time
+}
+
+var (
+	issuerCache   = make(map[string]issuerCacheEntry)
+	issuerCacheMu sync.Mutex
+)
+
+// IsAuthorizedIssuerCached reports whether an address may mint, asking the registry
+// at most once per issuerCacheTTL
+func IsAuthorizedIssuerCached(issuer string) (bool, error) {
+	key := strings.ToLower(issuer)
+
+	issuerCacheMu.Lock()
+	entry, ok := issuerCache[key]
+	issuerCacheMu.Unlock()
+	if ok && time.Since(entry.checkedAt) < issuerCacheTTL {
+		return entry.authorized, nil
+	}
+
+	authorized, err := GetClient().IsAuthorizedIssuer(issuer)
+	if err != nil {
+		return false, err
+	}
+
+	issuerCacheMu.Lock()
+	issuerCache[key] = issuerCacheEntry{authorized: authorized, checkedAt: time.Now()}
+	issuerCacheMu.Unlock()
+	return authorized, nil
+}
+
+// ForgetIssuer drops the cached authorization of an address so the next check asks the registry
+func ForgetIssuer(issuer string) {
+	issuerCacheMu.Lock()
+	delete(issuerCache, strings.ToLower(issuer))
+	issuerCacheMu.Unlock()
+}
@@ -0,0 +1,183 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Compact JWS tokens (such as SD-JWTs) are signed by the platform wallet with
+// ES256K: ECDSA on secp256k1 over the SHA-256 of the signing input, the same key
+// the wallet signs transactions with. The header's kid is the signer's did:ethr
+// DID URL and its jwk the public key, so standard JOSE libraries can verify.
+const JWSAlgES256K = "ES256K"
+
+// jwkProbe is signed once per signer to recover its public key, which signers
+// don't otherwise expose
+var jwkProbe = sha256.Sum256([]byte("cognify:jws:public-key"))
+
+var (
+	// ErrJWSInvalid is returned when a compact JWS does not check out
+	ErrJWSInvalid = errors.New("invalid JWS")
+	// ErrJWSUnsupportedSigner is returned when the platform signer cannot sign raw hashes
+	ErrJWSUnsupportedSigner = errors.New("signer backend cannot sign JWS")
+
+	jwsPublicKeys sync.Map // common.Address -> *ecdsa.PublicKey
+)
+
+// CanSignJWS reports whether the platform signer can sign JWS. Clef can't; startup
+// refuses to combine it with SD-JWT issuance.
+func CanSignJWS() bool {
+	signer, _, _ := platformSigner()
+	_, ok := signer.(hashSigner)
+	return ok
+}
+
+// SignJWS returns payload as a compact JWS signed by the platform wallet. alg, kid
+// and jwk are set in header, which may carry other parameters such as typ.
+func SignJWS(header, payload map[string]interface{}) (string, error) {
+	base, chainID, _ := platformSigner()
+	signer, ok := base.(hashSigner)
+	if !ok {
+		return "", ErrJWSUnsupportedSigner
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pub, err := jwsPublicKey(ctx, signer)
+	if err != nil {
+		return "", err
+	}
+
+	fullHeader := make(map[string]interface{}, len(header)+3)
+	for key, value := range header {
+		fullHeader[key] = value
+	}
+	fullHeader["alg"] = JWSAlgES256K
+	fullHeader["kid"] = EthrDID(chainID.Int64(), signer.Address()) + "#controller"
+	fullHeader["jwk"] = secp256k1JWK(pub)
+
+	rawHeader, err := json.Marshal(fullHeader)
+	if err != nil {
+		return "", err
+	}
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(rawHeader) + "." + base64.RawURLEncoding.EncodeToString(rawPayload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := signer.SignHash(ctx, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWS: %w", err)
+	}
+	// JWS carries R || S only; the recovery id is dropped
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig[:64]), nil
+}
+
+// VerifyJWS checks an ES256K compact JWS and returns the address named by its kid,
+// which signed it, and its decoded payload. A jwk header must be that address's key.
+func VerifyJWS(token string) (common.Address, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return common.Address{}, nil, fmt.Errorf("%w: not a compact JWS", ErrJWSInvalid)
+	}
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("%w: malformed header", ErrJWSInvalid)
+	}
+	var header struct {
+		Alg string            `json:"alg"`
+		Kid string            `json:"kid"`
+		JWK map[string]string `json:"jwk"`
+	}
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return common.Address{}, nil, fmt.Errorf("%w: malformed header", ErrJWSInvalid)
+	}
+	if header.Alg != JWSAlgES256K {
+		return common.Address{}, nil, fmt.Errorf("%w: unsupported alg %q", ErrJWSInvalid, header.Alg)
+	}
+	_, address, err := parseEthrDID(header.Kid)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("%w: %v", ErrJWSInvalid, err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(sig) != 64 {
+		return common.Address{}, nil, fmt.Errorf("%w: malformed signature", ErrJWSInvalid)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	pub := recoverJWSKey(digest[:], sig, address)
+	if pub == nil {
+		return common.Address{}, nil, fmt.Errorf("%w: signature does not match %s", ErrJWSInvalid, header.Kid)
+	}
+	if header.JWK != nil {
+		want := secp256k1JWK(pub)
+		if header.JWK["kty"] != want["kty"] || header.JWK["crv"] != want["crv"] || header.JWK["x"] != want["x"] || header.JWK["y"] != want["y"] {
+			return common.Address{}, nil, fmt.Errorf("%w: jwk is not the key of %s", ErrJWSInvalid, header.Kid)
+		}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("%w: malformed payload", ErrJWSInvalid)
+	}
+	return address, payload, nil
+}
+
+// recoverJWSKey returns the public key of address if sig (R || S) is its
+// signature of digest. High-S signatures are rejected.
+func recoverJWSKey(digest, sig []byte, address common.Address) *ecdsa.PublicKey {
+	for v := byte(0); v < 2; v++ {
+		pub, err := crypto.SigToPub(digest, append(append([]byte(nil), sig...), v))
+		if err != nil || crypto.PubkeyToAddress(*pub) != address {
+			continue
+		}
+		if !crypto.VerifySignature(crypto.FromECDSAPub(pub), digest, sig) {
+			return nil
+		}
+		return pub
+	}
+	return nil
+}
+
+// jwsPublicKey returns signer's public key, recovering it from a signature the
+// first time
+func jwsPublicKey(ctx context.Context, signer hashSigner) (*ecdsa.PublicKey, error) {
+	if pub, ok := jwsPublicKeys.Load(signer.Address()); ok {
+		return pub.(*ecdsa.PublicKey), nil
+	}
+	sig, err := signer.SignHash(ctx, jwkProbe[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign JWS: %w", err)
+	}
+	pub, err := crypto.SigToPub(jwkProbe[:], sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != signer.Address() {
+		return nil, errors.New("failed to recover the signer's public key")
+	}
+	jwsPublicKeys.Store(signer.Address(), pub)
+	return pub, nil
+}
+
+// secp256k1JWK is pub as a JSON Web Key
+func secp256k1JWK(pub *ecdsa.PublicKey) map[string]string {
+	raw := crypto.FromECDSAPub(pub) // 0x04 || X || Y
+	return map[string]string{
+		"kty": "EC",
+		"crv": "secp256k1",
+		"x":   base64.RawURLEncoding.EncodeToString(raw[1:33]),
+		"y":   base64.RawURLEncoding.EncodeToString(raw[33:65]),
+	}
+}
@@ -0,0 +1,102 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestJWSRoundTrip(t *testing.T) {
+	token, err := SignJWS(map[string]interface{}{"typ": "dc+sd-jwt"}, map[string]interface{}{"iss": PlatformDID(), "n": 1})
+	if err != nil {
+		t.Fatalf("SignJWS: %v", err)
+	}
+
+	signer, payload, err := VerifyJWS(token)
+	if err != nil {
+		t.Fatalf("VerifyJWS: %v", err)
+	}
+	if !IsPlatformAddress(signer) {
+		t.Errorf("signer = %s, want the platform wallet", signer.Hex())
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims["iss"] != PlatformDID() {
+		t.Errorf("payload = %s", payload)
+	}
+
+	// Any ES256K verifier can check the token with the jwk header alone
+	parts := strings.Split(token, ".")
+	rawHeader, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	var header struct {
+		Alg string            `json:"alg"`
+		Typ string            `json:"typ"`
+		JWK map[string]string `json:"jwk"`
+	}
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Alg != "ES256K" || header.Typ != "dc+sd-jwt" || header.JWK["crv"] != "secp256k1" {
+		t.Errorf("header = %s", rawHeader)
+	}
+	x, _ := base64.RawURLEncoding.DecodeString(header.JWK["x"])
+	y, _ := base64.RawURLEncoding.DecodeString(header.JWK["y"])
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !crypto.VerifySignature(append(append([]byte{4}, x...), y...), digest[:], sig) {
+		t.Error("signature does not verify against the jwk")
+	}
+}
+
+func TestVerifyJWSRejects(t *testing.T) {
+	token, err := SignJWS(map[string]interface{}{"typ": "dc+sd-jwt"}, map[string]interface{}{"marks": 72})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	rawHeader, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	encode := func(v interface{}) string {
+		raw, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	withHeader := func(change func(map[string]interface{})) string {
+		var header map[string]interface{}
+		json.Unmarshal(rawHeader, &header)
+		change(header)
+		return encode(header) + "." + parts[1] + "." + parts[2]
+	}
+
+	otherKey, _ := crypto.GenerateKey()
+	otherDID := EthrDID(mockChainID, crypto.PubkeyToAddress(otherKey.PublicKey)) + "#controller"
+
+	// The same signature with s replaced by n - s is valid ECDSA but malleated
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	s := new(big.Int).SetBytes(sig[32:])
+	highS := append(append([]byte(nil), sig[:32]...), s.Sub(crypto.S256().Params().N, s).FillBytes(make([]byte, 32))...)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not compact", parts[0] + "." + parts[1]},
+		{"tampered payload", parts[0] + "." + encode(map[string]interface{}{"marks": 99}) + "." + parts[2]},
+		{"truncated signature", parts[0] + "." + parts[1] + "." + parts[2][:40]},
+		{"alg none", withHeader(func(h map[string]interface{}) { h["alg"] = "none" })},
+		{"alg ES256", withHeader(func(h map[string]interface{}) { h["alg"] = "ES256" })},
+		{"other kid", withHeader(func(h map[string]interface{}) { h["kid"] = otherDID })},
+		{"foreign jwk", withHeader(func(h map[string]interface{}) { h["jwk"] = secp256k1JWK(&otherKey.PublicKey) })},
+		{"high s", parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString(highS)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := VerifyJWS(tt.token); !errors.Is(err, ErrJWSInvalid) {
+				t.Errorf("VerifyJWS error = %v, want ErrJWSInvalid", err)
+			}
+		})
+	}
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math/big"
 	"time"
 
-	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/services"
 
-	"cloud.google.com/go/firestore"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -24,179 +24,144 @@ var (
 	EventIssuerRevoked      = crypto.Keccak256Hash([]byte("IssuerRevoked(address)"))
 )
 
-// EventListener handles blockchain subscriptions
+// logClient is the subset of an Ethereum client the listener and sync worker need
+type logClient interface {
+	ethereum.LogFilterer
+	ethereum.BlockNumberReader
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// listenerPollInterval is how often the listener checks for newly confirmed blocks.
+// A subscription only makes it check sooner; it is never the source of truth.
+const listenerPollInterval = 10 * time.Second
+
+// listenerResubscribeInterval is how long the listener polls before trying to subscribe again
+const listenerResubscribeInterval = time.Minute
+
+// EventListener keeps storage in sync with the registry. It applies events once they
+// are confirmed, subscribing to logs when the RPC supports it and falling back to
+// eth_getLogs polling when it doesn't.
 type EventListener struct {
-	client          *ethclient.Client
-	contractAddress common.Address
-	rpcURL          string
+	indexer *indexer
+	rpcURL  string
 }
 
-// NewEventListener creates a new listener
-func NewEventListener(rpcURL, contractAddr string) (*EventListener, error) {
+// NewEventListener creates a new listener that scans from startBlock on a fresh database
+// and applies events once they have confirmations blocks on top
+func NewEventListener(rpcURL, contractAddr string, startBlock, confirmations uint64) (*EventListener, error) {
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Ethereum RPC: %v", err)
 	}
 
+	l := NewEventListenerWithClient(client, common.HexToAddress(contractAddr), startBlock, confirmations)
+	l.rpcURL = rpcURL
+	return l, nil
+}
+
+// NewEventListenerWithClient creates a listener on an existing client (e.g. the simulated chain).
+// Reconnects re-subscribe on the same client instead of re-dialing.
+func NewEventListenerWithClient(client logClient, contractAddr common.Address, startBlock, confirmations uint64) *EventListener {
 	return &EventListener{
-		client:          client,
-		contractAddress: common.HexToAddress(contractAddr),
-		rpcURL:          rpcURL,
-	}, nil
+		indexer: &indexer{
+			client:          client,
+			contractAddress: contractAddr,
+			confirmations:   confirmations,
+			startBlock:      startBlock,
+		},
+	}
 }
 
 // Start begins listening to events
 func (l *EventListener) Start(ctx context.Context) {
-	query := ethereum.FilterQuery{
-		Addresses: []common.Address{l.contractAddress},
-	}
-
-	logs := make(chan types.Log)
-	sub, err := l.client.SubscribeFilterLogs(ctx, query, logs)
-	if err != nil {
-		log.Printf("[Blockchain] ⚠️ Failed to subscribe to logs (WebSocket might be missing). Polling fallback not implemented yet. Error: %v", err)
-		return // In production, implement polling fallback or ensure WS RPC
-	}
-
-	log.Printf("[Blockchain] 🎧 Listening for events on %s", l.contractAddress.Hex())
+	log.Printf("[Blockchain] 🎧 Listening for events on %s (Confirmations: %d)", l.indexer.contractAddress.Hex(), l.indexer.confirmations)
 
 	go func() {
+		ticker := time.NewTicker(listenerPollInterval)
+		defer ticker.Stop()
+
+		l.sync(ctx)
 		for {
-			select {
-			case err := <-sub.Err():
-				log.Printf("[Blockchain] Subscription error: %v. Reconnecting...", err)
-				l.reconnect(ctx)
-				return
-			case vLog := <-logs:
-				l.handleLog(vLog)
-			case <-ctx.Done():
-				return
+			logs, sub := l.subscribe(ctx)
+			var subErr <-chan error
+			resubscribe := time.After(listenerResubscribeInterval)
+			if sub != nil {
+				subErr = sub.Err()
+				resubscribe = nil
+			}
+
+		loop:
+			for {
+				select {
+				case <-logs:
+					// Something new was mined; apply whatever is confirmed by now
+					l.sync(ctx)
+				case err := <-subErr:
+					log.Printf("[Blockchain] Subscription error: %v. Polling until resubscribed...", err)
+					sub.Unsubscribe()
+					l.redial()
+					logs, subErr, sub = nil, nil, nil
+					resubscribe = time.After(listenerResubscribeInterval)
+				case <-resubscribe:
+					break loop
+				case <-ticker.C:
+					l.sync(ctx)
+				case <-ctx.Done():
+					if sub != nil {
+						sub.Unsubscribe()
+					}
+					return
+				}
 			}
 		}
 	}()
 }
 
-func (l *EventListener) reconnect(ctx context.Context) {
-	time.Sleep(5 * time.Second) // Backoff
-	client, err := ethclient.Dial(l.rpcURL)
-	if err == nil {
-		l.client = client
-		l.Start(ctx)
-	} else {
-		log.Printf("[Blockchain] Reconnect failed: %v", err)
-		l.reconnect(ctx) // Infinite retry
-	}
-}
-
-func (l *EventListener) handleLog(vLog types.Log) {
-	switch vLog.Topics[0] {
-	case EventCertificateMinted:
-		l.handleCertificateMinted(vLog)
-	case EventCertificateRevoked:
-		l.handleCertificateRevoked(vLog)
-	case EventIssuerAuthorized:
-		l.handleIssuerAuthorized(vLog)
-	case EventIssuerRevoked:
-		l.handleIssuerRevoked(vLog)
+// subscribe opens a log subscription, or returns nils when the RPC can't provide one
+func (l *EventListener) subscribe(ctx context.Context) (chan types.Log, ethereum.Subscription) {
+	logs := make(chan types.Log, 64)
+	sub, err := l.indexer.client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{l.indexer.contractAddress},
+	}, logs)
+	if err != nil {
+		log.Printf("[Blockchain] ⚠️ Log subscription unavailable, falling back to eth_getLogs polling every %s: %v", listenerPollInterval, err)
+		return nil, nil
 	}
+	return logs, sub
 }
 
-func (l *EventListener) handleCertificateMinted(vLog types.Log) {
-	// Topics: [Signature, CertHash, Owner, Issuer]
-	if len(vLog.Topics) < 4 {
+// redial replaces a dropped RPC connection; listeners on a shared client keep it
+func (l *EventListener) redial() {
+	if l.rpcURL == "" {
 		return
 	}
-	certHash := vLog.Topics[1]
-	owner := common.HexToAddress(vLog.Topics[2].Hex())
-	// issuer := common.HexToAddress(vLog.Topics[3].Hex())
-	// log.Printf("[Blockchain] 🟢 Certificate Minted: %s (Owner: %s)", certHash.Hex(), owner.Hex())
-	log.Printf("[Blockchain] 🟢 Certificate Minted: %s (Owner: %s) by %s", certHash.Hex(), owner.Hex(), vLog.Topics[3].Hex())
-
-	// Sync to Firebase
-	if db.FirestoreClient != nil {
-		updates := map[string]interface{}{
-			"isMinted": true,
-			"txHash":   vLog.TxHash.Hex(),
-			"block":    vLog.BlockNumber,
-			"mintedAt": time.Now(),
-			"revoked":  false,
-		}
-
-		_, err := db.FirestoreClient.Collection("certificates").Doc(certHash.Hex()).Set(context.Background(), updates, firestore.MergeAll)
-		if err != nil {
-			log.Printf("[Blockchain] Failed to sync mint event: %v", err)
-		}
-	}
-}
-
-func (l *EventListener) handleCertificateRevoked(vLog types.Log) {
-	// Topics: [Signature, CertHash, Issuer]
-	// Data: [Reason string] - Handling omitted for simplicity
-	if len(vLog.Topics) < 3 {
+	client, err := ethclient.Dial(l.rpcURL)
+	if err != nil {
+		log.Printf("[Blockchain] Reconnect failed: %v", err)
 		return
 	}
-	certHash := vLog.Topics[1]
-
-	log.Printf("[Blockchain] 🔴 Certificate Revoked: %s", certHash.Hex())
-
-	if db.FirestoreClient != nil {
-		_, err := db.FirestoreClient.Collection("certificates").Doc(certHash.Hex()).Set(context.Background(), map[string]interface{}{
-			"revoked":   true,
-			"revokedAt": time.Now(),
-		}, firestore.MergeAll)
-		if err != nil {
-			log.Printf("[Blockchain] Failed to sync revoke event: %v", err)
-		}
-	}
+	l.indexer.client = client
 }
 
-func (l *EventListener) handleIssuerAuthorized(vLog types.Log) {
-	// Topics: [Signature, IssuerAddress]
-	if len(vLog.Topics) < 2 {
-		return
-	}
-	issuer := common.HexToAddress(vLog.Topics[1].Hex())
-	log.Printf("[Blockchain] 🛡️ Issuer Authorized: %s", issuer.Hex())
-
-	if db.FirestoreClient != nil {
-		// Find user by wallet address and update
-		// Assuming wallet_address is indexed or we use query
-		// Ideally, we might store users by ID, but searching by wallet is needed.
-		// Or we can maintain a separate 'authorized_issuers' collection for quick lookup
-
-		// 1. Update User Record (if exists)
-		iter := db.FirestoreClient.Collection("users").Where("wallet_address", "==", issuer.Hex()).Documents(context.Background())
-		for {
-			doc, err := iter.Next()
-			if err != nil {
-				break
-			}
-			doc.Ref.Set(context.Background(), map[string]interface{}{
-				"isAuthorized": true,
-				"role":         "instructor", // Force role update
-			}, firestore.MergeAll)
-		}
+func (l *EventListener) sync(ctx context.Context) {
+	if _, err := l.indexer.sync(ctx); err != nil {
+		log.Printf("[Blockchain] ⚠️ Failed to sync events: %v", err)
 	}
 }
 
-func (l *EventListener) handleIssuerRevoked(vLog types.Log) {
-	// Topics: [Signature, IssuerAddress]
-	if len(vLog.Topics) < 2 {
-		return
+// recordRevocation decodes a CertificateRevoked log and stores it with the
+// revoker, reason and block time. Shared by the listener and the sync worker.
+func recordRevocation(ctx context.Context, client logClient, vLog types.Log) error {
+	reason, err := decodeRevocationReason(vLog)
+	if err != nil {
+		return err
 	}
-	issuer := common.HexToAddress(vLog.Topics[1].Hex())
-	log.Printf("[Blockchain] 🚫 Issuer Revoked: %s", issuer.Hex())
+	revoker := common.HexToAddress(vLog.Topics[2].Hex())
 
-	if db.FirestoreClient != nil {
-		iter := db.FirestoreClient.Collection("users").Where("wallet_address", "==", issuer.Hex()).Documents(context.Background())
-		for {
-			doc, err := iter.Next()
-			if err != nil {
-				break
-			}
-			doc.Ref.Set(context.Background(), map[string]interface{}{
-				"isAuthorized": false,
-			}, firestore.MergeAll)
-		}
+	revokedAt := time.Now()
+	if header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(vLog.BlockNumber)); err == nil {
+		revokedAt = time.Unix(int64(header.Time), 0)
 	}
+
+	return services.NewRevocationService().Record(ctx, certificateKey(vLog.Topics[1]), revoker.Hex(), reason, revokedAt)
 }
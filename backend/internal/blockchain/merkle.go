@@ -0,0 +1,105 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// AnchorOwner is the registry owner of every Merkle root. The registry only knows
+// mintCertificate, so a root is minted like a certificate to this address, which no
+// one holds a key for; the listener, sync worker and reconciler skip its events.
+var AnchorOwner = common.BytesToAddress(crypto.Keccak256([]byte("cognify-merkle-anchor"))[12:])
+
+// Domain prefixes keep a leaf from ever being mistaken for an inner node
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+// BuildMerkleTree builds a Keccak-256 Merkle tree over certificate hashes and returns
+// the root and each hash's proof, in input order. Pairs are sorted before hashing, so
+// proofs are plain sibling lists with no left/right flags. An odd node is carried up
+// to the next level unchanged. Hashes in the result have no 0x prefix.
+func BuildMerkleTree(certHashes []string) (string, [][]string, error) {
+	if len(certHashes) == 0 {
+		return "", nil, errors.New("no certificate hashes to anchor")
+	}
+
+	level := make([]common.Hash, len(certHashes))
+	for i, h := range certHashes {
+		hashBytes, err := hexToBytes32(h)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid certificate hash %q: %w", h, err)
+		}
+		level[i] = merkleLeaf(hashBytes)
+	}
+
+	proofs := make([][]string, len(certHashes))
+	positions := make([]int, len(certHashes)) // Index of each leaf's ancestor in the current level
+	for i := range positions {
+		positions[i] = i
+	}
+
+	for len(level) > 1 {
+		for leaf, pos := range positions {
+			sibling := pos ^ 1
+			if sibling < len(level) {
+				proofs[leaf] = append(proofs[leaf], certificateKey(level[sibling]))
+			}
+			positions[leaf] = pos / 2
+		}
+
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, merkleNode(level[i], level[i+1]))
+		}
+		level = next
+	}
+
+	for i := range proofs {
+		if proofs[i] == nil {
+			proofs[i] = []string{} // Single-leaf tree: the leaf is the root
+		}
+	}
+	return certificateKey(level[0]), proofs, nil
+}
+
+// VerifyMerkleProof reports whether proof links certHash to root
+func VerifyMerkleProof(certHash string, proof []string, root string) bool {
+	hashBytes, err := hexToBytes32(certHash)
+	if err != nil {
+		return false
+	}
+	rootBytes, err := hexToBytes32(root)
+	if err != nil {
+		return false
+	}
+
+	node := merkleLeaf(hashBytes)
+	for _, sibling := range proof {
+		siblingBytes, err := hexToBytes32(sibling)
+		if err != nil {
+			return false
+		}
+		node = merkleNode(node, siblingBytes)
+	}
+	return node == common.Hash(rootBytes)
+}
+
+func merkleLeaf(certHash [32]byte) common.Hash {
+	return crypto.Keccak256Hash([]byte{merkleLeafPrefix}, certHash[:])
+}
+
+func merkleNode(a, b common.Hash) common.Hash {
+	if a.Cmp(b) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash([]byte{merkleNodePrefix}, a[:], b[:])
+}
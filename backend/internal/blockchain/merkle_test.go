@@ -0,0 +1,134 @@
+package blockchain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testCertHashes returns n distinct certificate hashes, without a 0x prefix
+func testCertHashes(n int) []string {
+	hashes := make([]string, n)
+	for i := range hashes {
+		hashes[i] = certificateKey(crypto.Keccak256Hash([]byte(fmt.Sprintf("certificate-%d", i))))
+	}
+	return hashes
+}
+
+func TestMerkleProofs(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 13, 17} {
+		t.Run(fmt.Sprintf("%d leaves", n), func(t *testing.T) {
+			hashes := testCertHashes(n)
+			root, proofs, err := BuildMerkleTree(hashes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(proofs) != n {
+				t.Fatalf("got %d proofs, want %d", len(proofs), n)
+			}
+			for i, h := range hashes {
+				if !VerifyMerkleProof(h, proofs[i], root) {
+					t.Errorf("proof %d does not verify", i)
+				}
+				if !VerifyMerkleProof("0x"+h, proofs[i], "0x"+root) {
+					t.Errorf("proof %d does not verify with 0x prefixes", i)
+				}
+				if n > 1 && VerifyMerkleProof(h, proofs[(i+1)%n], root) {
+					t.Errorf("proof %d verifies for hash %d", (i+1)%n, i)
+				}
+			}
+		})
+	}
+}
+
+func TestMerkleOddLeafCarriedUp(t *testing.T) {
+	hashes := testCertHashes(3)
+	root, proofs, err := BuildMerkleTree(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The third leaf has no sibling at the first level, so its proof is just the
+	// node above the first two
+	leaves := make([][32]byte, 3)
+	for i, h := range hashes {
+		leaves[i], _ = hexToBytes32(h)
+	}
+	left := merkleNode(merkleLeaf(leaves[0]), merkleLeaf(leaves[1]))
+	if want := certificateKey(merkleNode(left, merkleLeaf(leaves[2]))); root != want {
+		t.Errorf("root = %s, want %s", root, want)
+	}
+	if len(proofs[2]) != 1 || proofs[2][0] != certificateKey(left) {
+		t.Errorf("proof of the odd leaf = %v, want [%s]", proofs[2], certificateKey(left))
+	}
+	if len(proofs[0]) != 2 || len(proofs[1]) != 2 {
+		t.Errorf("proofs of the paired leaves = %v, %v, want two siblings each", proofs[0], proofs[1])
+	}
+}
+
+func TestMerkleSingleLeaf(t *testing.T) {
+	hashes := testCertHashes(1)
+	root, proofs, err := BuildMerkleTree(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The root is the leaf node, never the bare certificate hash
+	if root == hashes[0] {
+		t.Error("root is the certificate hash itself")
+	}
+	if proofs[0] == nil || len(proofs[0]) != 0 {
+		t.Errorf("proof = %#v, want an empty list", proofs[0])
+	}
+}
+
+func TestVerifyMerkleProofRejects(t *testing.T) {
+	hashes := testCertHashes(5)
+	root, proofs, err := BuildMerkleTree(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := testCertHashes(6)[5]
+	flipped := append([]string(nil), proofs[4]...)
+	flipped[0] = other
+
+	// A node of the tree presented as a leaf must not verify: leaves and nodes are
+	// hashed under different prefixes
+	leaf0, _ := hexToBytes32(hashes[0])
+	leaf1, _ := hexToBytes32(hashes[1])
+	inner := certificateKey(merkleNode(merkleLeaf(leaf0), merkleLeaf(leaf1)))
+
+	tests := []struct {
+		name     string
+		certHash string
+		proof    []string
+		root     string
+	}{
+		{"hash not in tree", other, proofs[0], root},
+		{"altered sibling", hashes[4], flipped, root},
+		{"truncated proof", hashes[0], proofs[0][:len(proofs[0])-1], root},
+		{"extra sibling", hashes[0], append(append([]string(nil), proofs[0]...), other), root},
+		{"other root", hashes[0], proofs[0], other},
+		{"inner node as leaf", inner, proofs[0][1:], root},
+		{"malformed hash", "0xzz", proofs[0], root},
+		{"short sibling", hashes[0], []string{strings.Repeat("a", 62)}, root},
+		{"malformed root", hashes[0], proofs[0], "root"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if VerifyMerkleProof(tt.certHash, tt.proof, tt.root) {
+				t.Error("proof verified")
+			}
+		})
+	}
+}
+
+func TestBuildMerkleTreeErrors(t *testing.T) {
+	if _, _, err := BuildMerkleTree(nil); err == nil {
+		t.Error("empty tree built")
+	}
+	if _, _, err := BuildMerkleTree(append(testCertHashes(2), "not-a-hash")); err == nil {
+		t.Error("tree built over a malformed hash")
+	}
+}
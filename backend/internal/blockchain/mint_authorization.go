@@ -0,0 +1,254 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-712 domain of platform mint authorizations
+const (
+	mintAuthorizationDomainName    = "Cognify Certificate Registry"
+	mintAuthorizationDomainVersion = "1"
+)
+
+// mockChainID is the chain ID mint authorizations carry in mock mode
+const mockChainID = 1337
+
+// Errors returned by VerifyMintAuthorization and ConfirmMint
+var (
+	ErrMintAuthorizationInvalid = errors.New("invalid mint authorization")
+	ErrMintAuthorizationExpired = errors.New("mint authorization expired")
+	ErrMintUnauthorized         = errors.New("certificate has no platform mint authorization")
+	ErrMintMismatch             = errors.New("mint does not match its authorization")
+)
+
+var registryDomainType = []apitypes.Type{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+}
+
+var mintAuthorizationTypes = apitypes.Types{
+	"EIP712Domain": registryDomainType,
+	"MintAuthorization": {
+		{Name: "student", Type: "address"},
+		{Name: "certHash", Type: "bytes32"},
+		{Name: "academicDNA", Type: "string"},
+		{Name: "issuedAt", Type: "uint256"},
+		{Name: "expiry", Type: "uint256"},
+	},
+}
+
+// SignMintAuthorization signs an EIP-712 MintAuthorization with the platform key,
+// approving certHash to be minted to studentWallet with academicDNA until issuedAt+ttl.
+// The domain binds it to the active chain and registry contract.
+func SignMintAuthorization(studentWallet, certHash, academicDNA string, issuedAt time.Time, ttl time.Duration) (*models.MintAuthorization, error) {
+	if !common.IsHexAddress(studentWallet) {
+		return nil, fmt.Errorf("invalid student wallet: %s", studentWallet)
+	}
+	if _, err := hexToBytes32(certHash); err != nil {
+		return nil, fmt.Errorf("invalid certificate hash %q: %w", certHash, err)
+	}
+
+	signer, chainID, contract := platformSigner()
+	auth := &models.MintAuthorization{
+		StudentWallet:   common.HexToAddress(studentWallet).Hex(),
+		CertificateHash: normalizeHash(certHash),
+		AcademicDNA:     academicDNA,
+		IssuedAt:        issuedAt.Unix(),
+		Expiry:          issuedAt.Add(ttl).Unix(),
+		ChainID:         chainID.Int64(),
+		Contract:        contract.Hex(),
+		Signer:          signer.Address().Hex(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	sig, err := signer.SignTypedData(ctx, MintAuthorizationTypedData(auth))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign mint authorization: %w", err)
+	}
+	auth.Signature = hexutil.Encode(sig)
+	return auth, nil
+}
+
+// VerifyMintAuthorization checks that auth was signed by the platform key, current
+// or retired, for the active chain and contract, and had not expired at the given time
+func VerifyMintAuthorization(auth *models.MintAuthorization, at time.Time) error {
+	if auth == nil {
+		return ErrMintAuthorizationInvalid
+	}
+
+	_, chainID, contract := platformSigner()
+	if auth.ChainID != chainID.Int64() || !strings.EqualFold(auth.Contract, contract.Hex()) {
+		return fmt.Errorf("%w: issued for another chain or contract", ErrMintAuthorizationInvalid)
+	}
+
+	hash, err := mintAuthorizationHash(auth)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMintAuthorizationInvalid, err)
+	}
+	recovered, err := recoverTypedDataSigner(hash, auth.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMintAuthorizationInvalid, err)
+	}
+	if !IsPlatformAddress(recovered) {
+		return fmt.Errorf("%w: not signed by the platform", ErrMintAuthorizationInvalid)
+	}
+
+	if at.Unix() > auth.Expiry {
+		return ErrMintAuthorizationExpired
+	}
+	return nil
+}
+
+// ConfirmMint marks the certificate minted by event as minted, once the mint checks
+// out against the platform's authorization for it. The confirm endpoint, relays and
+// the reconciler enforce the check through here; the event indexer goes through
+// RecordMint instead. Mints of certificates missing from storage have nothing to
+// check against and are recorded as they are; the reconciler reports them as orphans.
+func ConfirmMint(ctx context.Context, client BlockchainClient, event MintEvent) error {
+	cert, err := db.Repos.Certificates.Get(ctx, event.Hash)
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+	case err != nil:
+		return err
+	default:
+		if err := checkMintAuthorization(client, cert, event.Hash); err != nil {
+			return err
+		}
+	}
+	return db.Repos.Certificates.MarkMinted(ctx, event.Hash, event.TxHash, event.BlockNumber, ActiveChain())
+}
+
+// RecordMint records a mint the chain already holds, for the event indexer and
+// backfill. Unlike ConfirmMint it never drops the mint: one that fails its
+// authorization check (a certificate issued before mint authorizations existed, or
+// one authorized by a key the platform no longer accepts) is recorded as minted and
+// flagged MintUnauthorized, so storage keeps matching the chain and the index can be
+// rebuilt. A mint already recorded from the same transaction is left as is, so
+// replaying events (a backfill) doesn't touch certificates the chain hasn't changed.
+func RecordMint(ctx context.Context, client BlockchainClient, event MintEvent) error {
+	if cert, err := db.Repos.Certificates.Get(ctx, event.Hash); err == nil && cert.IsMinted && cert.BlockchainTx == event.TxHash {
+		return nil
+	}
+
+	err := ConfirmMint(ctx, client, event)
+	if !IsMintRejected(err) {
+		return err
+	}
+
+	log.Printf("[Blockchain] 🚨 UNAUTHORIZED MINT of %s in tx %s (owner %s, issuer %s): %v; recording it flagged",
+		event.Hash, event.TxHash, event.Owner.Hex(), event.Issuer.Hex(), err)
+	if err := db.Repos.Certificates.MarkMinted(ctx, event.Hash, event.TxHash, event.BlockNumber, ActiveChain()); err != nil {
+		return err
+	}
+	return db.Repos.Certificates.MarkMintUnauthorized(ctx, event.Hash, err.Error())
+}
+
+// IsMintRejected reports whether ConfirmMint refused a mint for not matching its
+// authorization, rather than failing to check it
+func IsMintRejected(err error) bool {
+	return errors.Is(err, ErrMintUnauthorized) || errors.Is(err, ErrMintMismatch) ||
+		errors.Is(err, ErrMintAuthorizationInvalid) || errors.Is(err, ErrMintAuthorizationExpired)
+}
+
+// checkMintAuthorization verifies cert's platform authorization against the on-chain
+// record of its mint: it must be the platform's, unexpired when the mint was mined,
+// and name the owner and Academic DNA the registry holds
+func checkMintAuthorization(client BlockchainClient, cert *models.Certificate, certHash string) error {
+	auth := cert.MintAuthorization
+	if auth == nil {
+		return ErrMintUnauthorized
+	}
+
+	record, err := client.VerifyCertificate(certHash)
+	if err != nil {
+		return fmt.Errorf("failed to load on-chain certificate %s: %w", certHash, err)
+	}
+	if err := VerifyMintAuthorization(auth, record.Timestamp); err != nil {
+		return err
+	}
+
+	if auth.CertificateHash != certHash || common.HexToAddress(auth.StudentWallet) != record.Owner || auth.AcademicDNA != record.AcademicDNA {
+		return ErrMintMismatch
+	}
+	return nil
+}
+
+// MintAuthorizationTypedData returns auth as EIP-712 typed data, the form wallets
+// display and eth_signTypedData_v4 expects
+func MintAuthorizationTypedData(auth *models.MintAuthorization) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types:       mintAuthorizationTypes,
+		PrimaryType: "MintAuthorization",
+		Domain:      registryDomain(auth),
+		Message: apitypes.TypedDataMessage{
+			"student":     auth.StudentWallet,
+			"certHash":    "0x" + auth.CertificateHash,
+			"academicDNA": auth.AcademicDNA,
+			"issuedAt":    fmt.Sprint(auth.IssuedAt),
+			"expiry":      fmt.Sprint(auth.Expiry),
+		},
+	}
+}
+
+func mintAuthorizationHash(auth *models.MintAuthorization) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(MintAuthorizationTypedData(auth))
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash mint authorization: %w", err)
+	}
+	return hash, nil
+}
+
+// registryDomain is the EIP-712 domain of the chain and contract auth was issued for
+func registryDomain(auth *models.MintAuthorization) apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              mintAuthorizationDomainName,
+		Version:           mintAuthorizationDomainVersion,
+		ChainId:           (*math.HexOrDecimal256)(big.NewInt(auth.ChainID)),
+		VerifyingContract: auth.Contract,
+	}
+}
+
+// recoverTypedDataSigner returns the address that signed hash, accepting v as 0/1 or 27/28
+func recoverTypedDataSigner(hash []byte, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return common.Address{}, errors.New("malformed signature")
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// platformSigner returns the signer of mint authorizations and the domain it signs
+// for: the platform wallet on a real or simulated chain, or the mock ledger's key
+// in mock mode
+func platformSigner() (Signer, *big.Int, common.Address) {
+	if bc := GetRealClient(); bc != nil {
+		return bc.signer, bc.chainID, bc.contractAddr
+	}
+	mock := GetMockClient()
+	return mock.signer, big.NewInt(mockChainID), mock.contract
+}
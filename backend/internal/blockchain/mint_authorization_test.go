@@ -0,0 +1,290 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const testStudent = "0xabcabcabcabcabcabcabcabcabcabcabcabcabca"
+
+func newTestMintAuthorization(t *testing.T) (*models.MintAuthorization, time.Time) {
+	t.Helper()
+	issuedAt := time.Unix(1700000000, 0)
+	auth, err := SignMintAuthorization(testStudent, "0x"+testCertHash, "dna-v1", issuedAt, time.Hour)
+	if err != nil {
+		t.Fatalf("SignMintAuthorization: %v", err)
+	}
+	return auth, issuedAt
+}
+
+func TestMintAuthorizationSigner(t *testing.T) {
+	auth, issuedAt := newTestMintAuthorization(t)
+	signer, chainID, contract := platformSigner()
+
+	if auth.Signer != signer.Address().Hex() || auth.ChainID != chainID.Int64() || auth.Contract != contract.Hex() {
+		t.Errorf("auth = %+v, want the platform signer and domain", auth)
+	}
+	if auth.CertificateHash != testCertHash || auth.StudentWallet != common.HexToAddress(testStudent).Hex() {
+		t.Errorf("auth = %+v, want a normalized hash and checksummed wallet", auth)
+	}
+
+	// Recovering from the EIP-712 digest the way a wallet or contract would names the platform
+	hash, _, err := apitypes.TypedDataAndHash(MintAuthorizationTypedData(auth))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := hexutil.MustDecode(auth.Signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := crypto.PubkeyToAddress(*pub); got != signer.Address() {
+		t.Errorf("recovered %s, want %s", got.Hex(), signer.Address().Hex())
+	}
+
+	if err := VerifyMintAuthorization(auth, issuedAt.Add(time.Minute)); err != nil {
+		t.Errorf("VerifyMintAuthorization: %v", err)
+	}
+}
+
+func TestRecoverTypedDataSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	want := crypto.PubkeyToAddress(key.PublicKey)
+	hash := crypto.Keccak256([]byte("typed data"))
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := append([]byte(nil), sig...)
+	legacy[64] += 27
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   bool
+	}{
+		{"v 0/1", hexutil.Encode(sig), false},
+		{"v 27/28", hexutil.Encode(legacy), false},
+		{"no 0x", common.Bytes2Hex(sig), true},
+		{"short", hexutil.Encode(sig[:64]), true},
+		{"empty", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := recoverTypedDataSigner(hash, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != want {
+				t.Errorf("recovered %s, want %s", got.Hex(), want.Hex())
+			}
+		})
+	}
+}
+
+func TestVerifyMintAuthorizationRejects(t *testing.T) {
+	auth, issuedAt := newTestMintAuthorization(t)
+
+	otherKey, _ := crypto.GenerateKey()
+	foreign := *auth
+	hash, _, _ := apitypes.TypedDataAndHash(MintAuthorizationTypedData(&foreign))
+	sig, _ := crypto.Sign(hash, otherKey)
+	foreign.Signature = hexutil.Encode(sig)
+
+	tests := []struct {
+		name   string
+		change func(*models.MintAuthorization)
+		at     time.Time
+		want   error
+	}{
+		{"other student", func(a *models.MintAuthorization) { a.StudentWallet = common.HexToAddress(testOwner).Hex() }, issuedAt, ErrMintAuthorizationInvalid},
+		{"other hash", func(a *models.MintAuthorization) { a.CertificateHash = "00" + testCertHash[2:] }, issuedAt, ErrMintAuthorizationInvalid},
+		{"other DNA", func(a *models.MintAuthorization) { a.AcademicDNA = "dna-v2" }, issuedAt, ErrMintAuthorizationInvalid},
+		{"extended expiry", func(a *models.MintAuthorization) { a.Expiry += 3600 }, issuedAt, ErrMintAuthorizationInvalid},
+		{"other chain", func(a *models.MintAuthorization) { a.ChainID = 137 }, issuedAt, ErrMintAuthorizationInvalid},
+		{"other contract", func(a *models.MintAuthorization) { a.Contract = testOwner }, issuedAt, ErrMintAuthorizationInvalid},
+		{"foreign signer", func(a *models.MintAuthorization) { *a = foreign }, issuedAt, ErrMintAuthorizationInvalid},
+		{"malformed signature", func(a *models.MintAuthorization) { a.Signature = "0x1234" }, issuedAt, ErrMintAuthorizationInvalid},
+		{"expired", func(*models.MintAuthorization) {}, issuedAt.Add(time.Hour + time.Second), ErrMintAuthorizationExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := *auth
+			tt.change(&changed)
+			if err := VerifyMintAuthorization(&changed, tt.at); !errors.Is(err, tt.want) {
+				t.Errorf("VerifyMintAuthorization error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := VerifyMintAuthorization(nil, issuedAt); !errors.Is(err, ErrMintAuthorizationInvalid) {
+		t.Errorf("nil authorization: error = %v", err)
+	}
+}
+
+func TestSignMintAuthorizationRejects(t *testing.T) {
+	if _, err := SignMintAuthorization("not-a-wallet", testCertHash, "", time.Now(), time.Hour); err == nil {
+		t.Error("signed for an invalid wallet")
+	}
+	if _, err := SignMintAuthorization(testStudent, "abc", "", time.Now(), time.Hour); err == nil {
+		t.Error("signed an invalid hash")
+	}
+}
+
+func TestConfirmMint(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+	client := GetMockClient()
+
+	tests := []struct {
+		name      string
+		authorize func(hash string) *models.MintAuthorization
+		owner     string
+		dna       string
+		want      error
+	}{
+		{"authorized", func(hash string) *models.MintAuthorization {
+			auth, _ := SignMintAuthorization(testStudent, hash, "dna-v1", time.Now(), time.Hour)
+			return auth
+		}, testStudent, "dna-v1", nil},
+		{"no authorization", func(string) *models.MintAuthorization { return nil }, testStudent, "dna-v1", ErrMintUnauthorized},
+		{"other owner", func(hash string) *models.MintAuthorization {
+			auth, _ := SignMintAuthorization(testStudent, hash, "dna-v1", time.Now(), time.Hour)
+			return auth
+		}, testOwner, "dna-v1", ErrMintMismatch},
+		{"other DNA", func(hash string) *models.MintAuthorization {
+			auth, _ := SignMintAuthorization(testStudent, hash, "dna-v1", time.Now(), time.Hour)
+			return auth
+		}, testStudent, "dna-v2", ErrMintMismatch},
+		{"expired", func(hash string) *models.MintAuthorization {
+			auth, _ := SignMintAuthorization(testStudent, hash, "dna-v1", time.Now().Add(-2*time.Hour), time.Hour)
+			return auth
+		}, testStudent, "dna-v1", ErrMintAuthorizationExpired},
+		{"forged", func(hash string) *models.MintAuthorization {
+			auth, _ := SignMintAuthorization(testStudent, hash, "dna-v1", time.Now(), time.Hour)
+			auth.Expiry += 3600
+			return auth
+		}, testStudent, "dna-v1", ErrMintAuthorizationInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := certificateKey(crypto.Keccak256Hash([]byte(t.Name())))
+			cert := &models.Certificate{Hash: hash, WalletAddress: testStudent, MintAuthorization: tt.authorize(hash)}
+			if err := db.Repos.Certificates.Save(ctx, cert); err != nil {
+				t.Fatal(err)
+			}
+			txHash, err := client.Mint(hash, tt.owner, tt.dna)
+			if err != nil {
+				t.Fatal(err)
+			}
+			events, err := client.MintEvents(txHash)
+			if err != nil || len(events) != 1 {
+				t.Fatalf("MintEvents = %v, %v", events, err)
+			}
+
+			err = ConfirmMint(ctx, client, events[0])
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ConfirmMint error = %v, want %v", err, tt.want)
+			}
+			if IsMintRejected(err) != (tt.want != nil) {
+				t.Errorf("IsMintRejected(%v) = %v", err, tt.want == nil)
+			}
+			stored, _ := db.Repos.Certificates.Get(ctx, hash)
+			if stored.IsMinted != (tt.want == nil) {
+				t.Errorf("IsMinted = %v", stored.IsMinted)
+			}
+			if tt.want == nil && stored.BlockchainTx != txHash {
+				t.Errorf("BlockchainTx = %s, want %s", stored.BlockchainTx, txHash)
+			}
+		})
+	}
+}
+
+func TestConfirmMintOrphan(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	client := GetMockClient()
+	hash := certificateKey(crypto.Keccak256Hash([]byte(t.Name())))
+	txHash, err := client.Mint(hash, testStudent, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, _ := client.MintEvents(txHash)
+
+	// Nothing in storage to check it against: the mint is recorded for the orphan report
+	if err := ConfirmMint(context.Background(), client, events[0]); err != nil {
+		t.Fatalf("ConfirmMint: %v", err)
+	}
+	if stored, err := db.Repos.Certificates.Get(context.Background(), hash); err != nil || !stored.IsMinted {
+		t.Errorf("orphan mint not recorded: %+v, %v", stored, err)
+	}
+}
+
+func TestRecordMint(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+	client := GetMockClient()
+
+	record := func(t *testing.T, auth func(hash string) *models.MintAuthorization) *models.Certificate {
+		t.Helper()
+		hash := certificateKey(crypto.Keccak256Hash([]byte(t.Name())))
+		cert := &models.Certificate{Hash: hash, WalletAddress: testStudent, MintAuthorization: auth(hash)}
+		if err := db.Repos.Certificates.Save(ctx, cert); err != nil {
+			t.Fatal(err)
+		}
+		txHash, err := client.Mint(hash, testStudent, "dna-v1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		events, _ := client.MintEvents(txHash)
+		if err := RecordMint(ctx, client, events[0]); err != nil {
+			t.Fatalf("RecordMint: %v", err)
+		}
+		stored, err := db.Repos.Certificates.Get(ctx, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !stored.IsMinted || stored.BlockchainTx != txHash {
+			t.Errorf("mint not recorded: %+v", stored)
+		}
+		return stored
+	}
+
+	t.Run("authorized", func(t *testing.T) {
+		stored := record(t, func(hash string) *models.MintAuthorization {
+			auth, _ := SignMintAuthorization(testStudent, hash, "dna-v1", time.Now(), time.Hour)
+			return auth
+		})
+		if stored.MintUnauthorized || stored.MintRejection != "" {
+			t.Errorf("authorized mint flagged: %q", stored.MintRejection)
+		}
+	})
+
+	// A certificate issued before mint authorizations existed is still indexed, flagged
+	t.Run("no authorization", func(t *testing.T) {
+		stored := record(t, func(string) *models.MintAuthorization { return nil })
+		if !stored.MintUnauthorized || stored.MintRejection != ErrMintUnauthorized.Error() {
+			t.Errorf("MintUnauthorized = %v, MintRejection = %q", stored.MintUnauthorized, stored.MintRejection)
+		}
+
+		// Undoing the mint clears the flag with it
+		if err := db.Repos.Certificates.UnmarkMinted(ctx, stored.Hash); err != nil {
+			t.Fatal(err)
+		}
+		if undone, _ := db.Repos.Certificates.Get(ctx, stored.Hash); undone.IsMinted || undone.MintUnauthorized || undone.MintRejection != "" {
+			t.Errorf("UnmarkMinted left %+v", undone)
+		}
+	})
+}
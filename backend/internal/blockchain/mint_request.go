@@ -0,0 +1,74 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Errors returned by VerifyMintRequest
+var (
+	ErrMintRequestInvalid = errors.New("invalid mint request")
+	ErrMintRequestExpired = errors.New("mint request deadline passed")
+)
+
+// mintRequestTypes describe the request an instructor signs for a gasless mint.
+// Like an EIP-2771 ForwardRequest it names the signer and a deadline; the
+// certificate hash can only be minted once, so it doubles as the nonce.
+var mintRequestTypes = apitypes.Types{
+	"EIP712Domain": registryDomainType,
+	"MintRequest": {
+		{Name: "issuer", Type: "address"},
+		{Name: "student", Type: "address"},
+		{Name: "certHash", Type: "bytes32"},
+		{Name: "academicDNA", Type: "string"},
+		{Name: "deadline", Type: "uint256"},
+	},
+}
+
+// MintRequestTypedData returns the MintRequest an issuer signs to have the platform
+// mint an authorized certificate for them. It shares the authorization's domain.
+func MintRequestTypedData(issuer string, auth *models.MintAuthorization, deadline int64) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types:       mintRequestTypes,
+		PrimaryType: "MintRequest",
+		Domain:      registryDomain(auth),
+		Message: apitypes.TypedDataMessage{
+			"issuer":      common.HexToAddress(issuer).Hex(),
+			"student":     auth.StudentWallet,
+			"certHash":    "0x" + auth.CertificateHash,
+			"academicDNA": auth.AcademicDNA,
+			"deadline":    fmt.Sprint(deadline),
+		},
+	}
+}
+
+// VerifyMintRequest checks that issuer signed a MintRequest for the certificate auth
+// approves, and that its deadline had not passed at the given time
+func VerifyMintRequest(issuer string, auth *models.MintAuthorization, deadline int64, signature string, at time.Time) error {
+	if auth == nil || !common.IsHexAddress(issuer) {
+		return ErrMintRequestInvalid
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(MintRequestTypedData(issuer, auth, deadline))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMintRequestInvalid, err)
+	}
+	signer, err := recoverTypedDataSigner(hash, signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMintRequestInvalid, err)
+	}
+	if signer != common.HexToAddress(issuer) {
+		return fmt.Errorf("%w: not signed by %s", ErrMintRequestInvalid, issuer)
+	}
+
+	if at.Unix() > deadline {
+		return ErrMintRequestExpired
+	}
+	return nil
+}
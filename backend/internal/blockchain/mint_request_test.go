@@ -0,0 +1,91 @@
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// signMintRequest signs a MintRequest with key the way eth_signTypedData_v4 does,
+// with v as 27/28
+func signMintRequest(t *testing.T, key *ecdsa.PrivateKey, issuer string, auth *models.MintAuthorization, deadline int64) string {
+	t.Helper()
+	hash, _, err := apitypes.TypedDataAndHash(MintRequestTypedData(issuer, auth, deadline))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+	return hexutil.Encode(sig)
+}
+
+func TestVerifyMintRequest(t *testing.T) {
+	auth, issuedAt := newTestMintAuthorization(t)
+	issuerKey, _ := crypto.GenerateKey()
+	issuer := crypto.PubkeyToAddress(issuerKey.PublicKey).Hex()
+	otherKey, _ := crypto.GenerateKey()
+	deadline := issuedAt.Add(10 * time.Minute).Unix()
+	signature := signMintRequest(t, issuerKey, issuer, auth, deadline)
+
+	otherAuth := *auth
+	otherAuth.AcademicDNA = "dna-v2"
+
+	tests := []struct {
+		name      string
+		issuer    string
+		auth      *models.MintAuthorization
+		deadline  int64
+		signature string
+		at        time.Time
+		want      error
+	}{
+		{"signed by the issuer", issuer, auth, deadline, signature, issuedAt, nil},
+		{"lowercase issuer", strings.ToLower(issuer), auth, deadline, signature, issuedAt, nil},
+		{"at the deadline", issuer, auth, deadline, signature, time.Unix(deadline, 0), nil},
+		{"past the deadline", issuer, auth, deadline, signature, time.Unix(deadline+1, 0), ErrMintRequestExpired},
+		{"extended deadline", issuer, auth, deadline + 60, signature, issuedAt, ErrMintRequestInvalid},
+		{"other certificate", issuer, &otherAuth, deadline, signature, issuedAt, ErrMintRequestInvalid},
+		{"signed by someone else", issuer, auth, deadline, signMintRequest(t, otherKey, issuer, auth, deadline), issuedAt, ErrMintRequestInvalid},
+		{"claimed by someone else", crypto.PubkeyToAddress(otherKey.PublicKey).Hex(), auth, deadline, signature, issuedAt, ErrMintRequestInvalid},
+		{"invalid issuer", "issuer", auth, deadline, signature, issuedAt, ErrMintRequestInvalid},
+		{"no authorization", issuer, nil, deadline, signature, issuedAt, ErrMintRequestInvalid},
+		{"malformed signature", issuer, auth, deadline, "0xdeadbeef", issuedAt, ErrMintRequestInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyMintRequest(tt.issuer, tt.auth, tt.deadline, tt.signature, tt.at)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyMintRequest error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMintRequestSharesAuthorizationDomain(t *testing.T) {
+	auth, _ := newTestMintAuthorization(t)
+	request := MintRequestTypedData(testOwner, auth, 0)
+	authorization := MintAuthorizationTypedData(auth)
+
+	requestDomain, err := request.HashStruct("EIP712Domain", request.Domain.Map())
+	if err != nil {
+		t.Fatal(err)
+	}
+	authDomain, err := authorization.HashStruct("EIP712Domain", authorization.Domain.Map())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requestDomain.String() != authDomain.String() {
+		t.Error("a mint request is signed under another domain than its authorization")
+	}
+}
@@ -0,0 +1,487 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// mockBlockGasLimit is the gas limit recorded in mock block headers
+const mockBlockGasLimit = 30_000_000
+
+// mockGenesis is the first entry of a ledger file: the mock chain's ID, its
+// registry address and start time, and the mock platform wallet's key. The key is
+// a throwaway development key, kept in plaintext so the platform wallet, and the
+// mint authorizations, credentials and seals it signed, stay the same across
+// restarts; the file is only readable by its owner.
+type mockGenesis struct {
+	ChainID     int64          `json:"chainId"`
+	PlatformKey string         `json:"platformKey"`
+	Contract    common.Address `json:"contract"`
+	Time        uint64         `json:"timestamp"`
+}
+
+// mockTx is the one transaction in a mock block
+type mockTx struct {
+	Hash  common.Hash    `json:"hash"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Nonce uint64         `json:"nonce"`
+	Input hexutil.Bytes  `json:"input"` // ABI-encoded registry call, replayed on startup
+}
+
+// mockBlock is a sealed block of the mock chain
+type mockBlock struct {
+	Number     uint64       `json:"number"`
+	Hash       common.Hash  `json:"hash"`
+	ParentHash common.Hash  `json:"parentHash"`
+	Time       uint64       `json:"timestamp"`
+	Tx         *mockTx      `json:"transaction,omitempty"` // nil for the genesis
+	Logs       []*types.Log `json:"logs,omitempty"`
+}
+
+// header returns the block's header; its hash is the block hash
+func (b *mockBlock) header() *types.Header {
+	txRoot := types.EmptyTxsHash
+	if b.Tx != nil {
+		// Stands in for the transaction trie root of a one-transaction block
+		txRoot = b.Tx.Hash
+	}
+	return &types.Header{
+		ParentHash:  b.ParentHash,
+		UncleHash:   types.EmptyUncleHash,
+		Root:        types.EmptyRootHash,
+		TxHash:      txRoot,
+		ReceiptHash: types.EmptyReceiptsHash,
+		Difficulty:  new(big.Int),
+		Number:      new(big.Int).SetUint64(b.Number),
+		GasLimit:    mockBlockGasLimit,
+		Time:        b.Time,
+	}
+}
+
+// mockLedgerEntry is one line of the ledger file: the genesis first, then one block per line
+type mockLedgerEntry struct {
+	Genesis *mockGenesis `json:"genesis,omitempty"`
+	Block   *mockBlock   `json:"block,omitempty"`
+}
+
+// mockEvent is an event a mock transaction emits, before it is placed in a block
+type mockEvent struct {
+	topics []common.Hash
+	data   []byte
+}
+
+// mockLedger is the append-only JSON Lines file behind the mock chain
+type mockLedger struct {
+	path string
+	file *os.File
+}
+
+// openMockLedger reads every entry of the ledger at path and opens it for appending.
+// A last line cut short by a crash is dropped; any other damage is an error, since
+// replaying past it would silently lose blocks.
+func openMockLedger(path string) (*mockLedger, []mockLedgerEntry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, err
+	}
+	if len(raw) > 0 && raw[len(raw)-1] != '\n' {
+		cut := bytes.LastIndexByte(raw, '\n') + 1
+		log.Printf("⚠️  Mock ledger %s ends in a partial block; dropping it", path)
+		if err := os.Truncate(path, int64(cut)); err != nil {
+			return nil, nil, err
+		}
+		raw = raw[:cut]
+	}
+
+	var entries []mockLedgerEntry
+	for i, line := range bytes.Split(raw, []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry mockLedgerEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &mockLedger{path: path, file: file}, entries, nil
+}
+
+// append writes one entry and syncs it to disk
+func (l *mockLedger) append(entry mockLedgerEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write mock ledger %s: %w", l.path, err)
+	}
+	return l.file.Sync()
+}
+
+// newMockClient replays the ledger at path, or starts a new chain when the file is
+// missing or path is empty
+func newMockClient(path string) (*MockBlockchainClient, error) {
+	c := &MockBlockchainClient{
+		certificates: make(map[string]*CertificateRecord),
+		mints:        make(map[string]MintEvent),
+		issuers:      make(map[common.Address]bool),
+	}
+	if path == "" {
+		return c, c.genesis(nil)
+	}
+
+	ledger, entries, err := openMockLedger(path)
+	if err != nil {
+		return nil, err
+	}
+	c.ledger = ledger
+	if len(entries) == 0 {
+		if err := c.genesis(ledger); err != nil {
+			return nil, err
+		}
+		log.Printf("📒 Started mock ledger %s", path)
+		return c, nil
+	}
+	if err := c.restore(entries); err != nil {
+		return nil, err
+	}
+	log.Printf("📒 Replayed mock ledger %s: %d blocks, %d certificates", path, c.head().Number, len(c.certificates))
+	return c, nil
+}
+
+// genesis creates the mock platform wallet and deploys the mock registry at block 0
+func (c *MockBlockchainClient) genesis(ledger *mockLedger) error {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return fmt.Errorf("failed to generate mock platform key: %w", err)
+	}
+	platform := crypto.PubkeyToAddress(key.PublicKey)
+	g := &mockGenesis{
+		ChainID:     mockChainID,
+		PlatformKey: hex.EncodeToString(crypto.FromECDSA(key)),
+		Contract:    crypto.CreateAddress(platform, 0), // Deployed by the platform wallet's first transaction
+		Time:        uint64(time.Now().Unix()),
+	}
+	if ledger != nil {
+		if err := ledger.append(mockLedgerEntry{Genesis: g}); err != nil {
+			return err
+		}
+	}
+	return c.start(g)
+}
+
+// start sets up the chain described by a genesis entry, as the registry's constructor would
+func (c *MockBlockchainClient) start(g *mockGenesis) error {
+	key, err := crypto.HexToECDSA(g.PlatformKey)
+	if err != nil {
+		return fmt.Errorf("invalid mock platform key: %w", err)
+	}
+	c.signer = NewKeySigner(key)
+	c.contract = g.Contract
+	c.issuers[c.signer.Address()] = true
+
+	block := &mockBlock{Number: 0, Time: g.Time}
+	block.Hash = block.header().Hash()
+	c.blocks = []*mockBlock{block}
+	return nil
+}
+
+// restore rebuilds the chain from ledger entries, checking that the blocks link up
+func (c *MockBlockchainClient) restore(entries []mockLedgerEntry) error {
+	if entries[0].Genesis == nil {
+		return errors.New("ledger does not start with a genesis entry")
+	}
+	if err := c.start(entries[0].Genesis); err != nil {
+		return err
+	}
+
+	for _, entry := range entries[1:] {
+		block := entry.Block
+		if block == nil || block.Tx == nil {
+			return errors.New("ledger entry after the genesis is not a block")
+		}
+		parent := c.head()
+		if block.Number != parent.Number+1 || block.ParentHash != parent.Hash || block.Hash != block.header().Hash() {
+			return fmt.Errorf("block %d does not extend block %d", block.Number, parent.Number)
+		}
+		if err := c.apply(block); err != nil {
+			return fmt.Errorf("block %d: %w", block.Number, err)
+		}
+	}
+	return nil
+}
+
+// head returns the latest block
+func (c *MockBlockchainClient) head() *mockBlock {
+	return c.blocks[len(c.blocks)-1]
+}
+
+// send seals a registry call from the mock platform wallet into a new block with the
+// events it emits, persists the block and applies it. Callers hold c.mu and have
+// already checked that the call would succeed on the real contract.
+func (c *MockBlockchainClient) send(method string, events []mockEvent, args ...interface{}) (string, error) {
+	parsed, err := SoulboundCertificateRegistryMetaData.GetAbi()
+	if err != nil {
+		return "", err
+	}
+	input, err := parsed.Pack(method, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", method, err)
+	}
+
+	parent := c.head()
+	number := parent.Number + 1
+	from := c.signer.Address()
+
+	// The deployment used nonce 0, so each block's transaction has the block's number as nonce
+	var nonce [8]byte
+	binary.BigEndian.PutUint64(nonce[:], number)
+	tx := &mockTx{
+		Hash:  crypto.Keccak256Hash(from.Bytes(), nonce[:], input),
+		From:  from,
+		To:    c.contract,
+		Nonce: number,
+		Input: input,
+	}
+
+	blockTime := uint64(time.Now().Unix())
+	if blockTime < parent.Time {
+		blockTime = parent.Time
+	}
+	block := &mockBlock{Number: number, ParentHash: parent.Hash, Time: blockTime, Tx: tx}
+	block.Hash = block.header().Hash()
+	for i, e := range events {
+		block.Logs = append(block.Logs, &types.Log{
+			Address:     c.contract,
+			Topics:      e.topics,
+			Data:        e.data,
+			BlockNumber: number,
+			TxHash:      tx.Hash,
+			TxIndex:     0,
+			BlockHash:   block.Hash,
+			Index:       uint(i),
+		})
+	}
+
+	if c.ledger != nil {
+		if err := c.ledger.append(mockLedgerEntry{Block: block}); err != nil {
+			return "", err
+		}
+	}
+	if err := c.apply(block); err != nil {
+		return "", err
+	}
+
+	logs := make([]types.Log, len(block.Logs))
+	for i, l := range block.Logs {
+		logs[i] = *l
+	}
+	go func() {
+		for _, l := range logs {
+			c.logFeed.Send(l)
+		}
+	}()
+
+	return strings.ToLower(tx.Hash.Hex()), nil
+}
+
+// apply executes a block's transaction against the mock registry state
+func (c *MockBlockchainClient) apply(block *mockBlock) error {
+	parsed, err := SoulboundCertificateRegistryMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	input := block.Tx.Input
+	if len(input) < 4 {
+		return errors.New("transaction has no method selector")
+	}
+	method, err := parsed.MethodById(input[:4])
+	if err != nil {
+		return err
+	}
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", method.Name, err)
+	}
+
+	txHash := strings.ToLower(block.Tx.Hash.Hex())
+	switch method.Name {
+	case "mintCertificate":
+		certHash, owner, dna := args[0].([32]byte), args[1].(common.Address), args[2].(string)
+		key := certificateKey(certHash)
+		c.certificates[key] = &CertificateRecord{
+			Hash:        key,
+			Exists:      true,
+			Owner:       owner,
+			Issuer:      block.Tx.From,
+			Timestamp:   time.Unix(int64(block.Time), 0),
+			AcademicDNA: dna,
+		}
+		c.mints[txHash] = MintEvent{
+			Hash:        key,
+			Owner:       owner,
+			Issuer:      block.Tx.From,
+			TxHash:      txHash,
+			BlockNumber: block.Number,
+		}
+	case "revokeCertificate":
+		certHash := args[0].([32]byte)
+		if record, ok := c.certificates[certificateKey(certHash)]; ok {
+			record.Revoked = true
+		}
+	case "authorizeIssuer":
+		c.issuers[args[0].(common.Address)] = true
+	case "revokeIssuer":
+		c.issuers[args[0].(common.Address)] = false
+	default:
+		return fmt.Errorf("unsupported registry method %s", method.Name)
+	}
+
+	c.blocks = append(c.blocks, block)
+	return nil
+}
+
+// The mock serves logs and headers like an RPC node, so the event listener, sync
+// worker and backfill index it exactly as they index a real chain.
+
+// BlockNumber returns the mock block height
+func (c *MockBlockchainClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.LatestBlock()
+}
+
+// HeaderByNumber returns a mock block header; nil means the latest block
+func (c *MockBlockchainClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if number == nil {
+		return c.head().header(), nil
+	}
+	if !number.IsUint64() || number.Uint64() >= uint64(len(c.blocks)) {
+		return nil, ethereum.NotFound
+	}
+	return c.blocks[number.Uint64()].header(), nil
+}
+
+// FilterLogs returns the mock registry's logs matching q
+func (c *MockBlockchainClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if q.BlockHash != nil {
+		for _, block := range c.blocks {
+			if block.Hash == *q.BlockHash {
+				return filterBlockLogs(block, q), nil
+			}
+		}
+		return nil, ethereum.NotFound
+	}
+
+	latest := c.head().Number
+	from, to := latest, latest
+	if q.FromBlock != nil {
+		from = q.FromBlock.Uint64()
+	}
+	if q.ToBlock != nil && q.ToBlock.Uint64() < to {
+		to = q.ToBlock.Uint64()
+	}
+
+	logs := []types.Log{}
+	for n := from; n <= to; n++ {
+		logs = append(logs, filterBlockLogs(c.blocks[n], q)...)
+	}
+	return logs, nil
+}
+
+// SubscribeFilterLogs delivers the logs of every block sealed from now on. Like a
+// node, it only filters by the query's addresses and topics.
+func (c *MockBlockchainClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	feed := make(chan types.Log, 64)
+	sub := c.logFeed.Subscribe(feed)
+	go func() {
+		for {
+			select {
+			case l := <-feed:
+				if matchLog(&l, q) {
+					select {
+					case ch <- l:
+					case <-sub.Err():
+						return
+					}
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// filterBlockLogs returns copies of a block's logs that match q
+func filterBlockLogs(block *mockBlock, q ethereum.FilterQuery) []types.Log {
+	logs := []types.Log{}
+	for _, l := range block.Logs {
+		if matchLog(l, q) {
+			logs = append(logs, *l)
+		}
+	}
+	return logs
+}
+
+// matchLog applies a filter query's address and topic criteria to a log
+func matchLog(l *types.Log, q ethereum.FilterQuery) bool {
+	if len(q.Addresses) > 0 {
+		found := false
+		for _, address := range q.Addresses {
+			if address == l.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(q.Topics) > len(l.Topics) {
+		return false
+	}
+	for i, options := range q.Topics {
+		if len(options) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range options {
+			if topic == l.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -2,7 +2,6 @@ package blockchain
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -12,21 +11,36 @@ import (
 	"sync"
 	"time"
 
+	"cache-crew/cognify/internal/db"
+
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// chainBackend is the RPC surface the real client needs. Both *ethclient.Client
+// and go-ethereum's simulated backend client satisfy it.
+type chainBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ethereum.BlockNumberReader
+	ethereum.ChainIDReader
+	ethereum.ChainStateReader
+	ethereum.TransactionReader
+}
+
 // RealBlockchainClient handles real blockchain interactions
 type RealBlockchainClient struct {
-	client       *ethclient.Client
+	client       chainBackend
+	contract     *SoulboundCertificateRegistry
 	contractAddr common.Address
-	privateKey   *ecdsa.PrivateKey
-	publicKey    *ecdsa.PublicKey
+	signer       Signer
 	address      common.Address
 	chainID      *big.Int
-	mu           sync.RWMutex
+	txm          *txManager
 }
 
 var (
@@ -34,8 +48,8 @@ var (
 	realClientOnce sync.Once
 )
 
-// InitRealBlockchain initializes the real blockchain client
-func InitRealBlockchain(rpcURL, contractAddr, privateKeyHex string) error {
+// InitRealBlockchain initializes the real blockchain client and starts tracking its transactions
+func InitRealBlockchain(rpcURL, contractAddr string, signer Signer, txConfig TxConfig) error {
 	var initErr error
 
 	realClientOnce.Do(func() {
@@ -46,196 +60,328 @@ func InitRealBlockchain(rpcURL, contractAddr, privateKeyHex string) error {
 			return
 		}
 
-		// Parse private key
-		privateKeyHex = strings.TrimPrefix(privateKeyHex, "0x")
-		privateKey, err := crypto.HexToECDSA(privateKeyHex)
+		bc, err := NewRealBlockchainClient(client, common.HexToAddress(contractAddr), signer, txConfig)
 		if err != nil {
-			initErr = fmt.Errorf("invalid private key: %w", err)
-			return
-		}
-
-		// Derive public key and address
-		publicKey := privateKey.Public()
-		publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-		if !ok {
-			initErr = errors.New("failed to cast public key to ECDSA")
+			initErr = err
 			return
 		}
-
-		address := crypto.PubkeyToAddress(*publicKeyECDSA)
-
-		// Get chain ID
-		chainID, err := client.ChainID(context.Background())
-		if err != nil {
-			initErr = fmt.Errorf("failed to get chain ID: %w", err)
-			return
-		}
-
-		realClient = &RealBlockchainClient{
-			client:       client,
-			contractAddr: common.HexToAddress(contractAddr),
-			privateKey:   privateKey,
-			publicKey:    publicKeyECDSA,
-			address:      address,
-			chainID:      chainID,
-		}
+		bc.txm.start(context.Background())
+		realClient = bc
+		activeClient = bc
 
 		log.Printf("✅ Real Blockchain client initialized")
 		log.Printf("   RPC: %s", rpcURL)
 		log.Printf("   Contract: %s", contractAddr)
-		log.Printf("   Wallet: %s", address.Hex())
-		log.Printf("   Chain ID: %s", chainID.String())
+		log.Printf("   Wallet: %s", bc.address.Hex())
+		log.Printf("   Chain ID: %s", bc.chainID.String())
 	})
 
 	return initErr
 }
 
+// NewRealBlockchainClient binds the certificate registry at contractAddr on the given
+// backend, signing transactions with signer within the bounds of txConfig
+func NewRealBlockchainClient(client chainBackend, contractAddr common.Address, signer Signer, txConfig TxConfig) (*RealBlockchainClient, error) {
+	address := signer.Address()
+
+	// Get chain ID
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if txConfig.ChainID != 0 && chainID.Int64() != txConfig.ChainID {
+		return nil, fmt.Errorf("node is on chain %s but CHAIN_ID is %d", chainID, txConfig.ChainID)
+	}
+
+	contract, err := NewSoulboundCertificateRegistry(contractAddr, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind contract: %w", err)
+	}
+
+	return &RealBlockchainClient{
+		client:       client,
+		contract:     contract,
+		contractAddr: contractAddr,
+		signer:       signer,
+		address:      address,
+		chainID:      chainID,
+		txm:          newTxManager(client, signer, chainID, txConfig),
+	}, nil
+}
+
 // GetRealClient returns the singleton real blockchain client
 func GetRealClient() *RealBlockchainClient {
 	return realClient
 }
 
-// VerifyCertificateHash checks if a certificate exists on the blockchain
-func (bc *RealBlockchainClient) VerifyCertificateHash(certHash string) (bool, error) {
-	bc.mu.RLock()
-	defer bc.mu.RUnlock()
+// Address returns the platform wallet used to sign transactions
+func (bc *RealBlockchainClient) Address() common.Address {
+	return bc.address
+}
+
+// ContractAddress returns the address of the certificate registry
+func (bc *RealBlockchainClient) ContractAddress() common.Address {
+	return bc.contractAddr
+}
+
+// ChainID returns the chain the client is connected to
+func (bc *RealBlockchainClient) ChainID() *big.Int {
+	return new(big.Int).Set(bc.chainID)
+}
 
+// VerifyCertificate returns the full on-chain record for a certificate hash.
+// A hash that was never minted yields a record with Exists=false.
+func (bc *RealBlockchainClient) VerifyCertificate(certHash string) (*CertificateRecord, error) {
 	if bc.client == nil {
-		return false, errors.New("blockchain client not initialized")
+		return nil, errors.New("blockchain client not initialized")
 	}
 
 	// Convert hash to bytes32
 	hashBytes, err := hexToBytes32(certHash)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	// Call contract's verifyCertificate function
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Create contract instance
-	contract, err := bc.getContractInstance()
-	if err != nil {
-		return false, err
-	}
-
 	// Call verifyCertificate
-	exists, issuer, timestamp, err := contract.VerifyCertificate(&bind.CallOpts{Context: ctx}, hashBytes)
+	result, err := bc.contract.VerifyCertificate(&bind.CallOpts{Context: ctx}, hashBytes)
 	if err != nil {
-		return false, fmt.Errorf("contract call failed: %w", err)
+		return nil, fmt.Errorf("contract call failed: %w", err)
 	}
 
-	log.Printf("🔍 Certificate verification: exists=%v, issuer=%s, timestamp=%d", exists, issuer.Hex(), timestamp)
-
-	return exists, nil
+	log.Printf("🔍 Certificate verification: exists=%v, issuer=%s, timestamp=%s, revoked=%v", result.Exists, result.Issuer.Hex(), result.Timestamp, result.Revoked)
+
+	return &CertificateRecord{
+		Hash:        strings.TrimPrefix(certHash, "0x"),
+		Exists:      result.Exists,
+		Owner:       result.CertOwner,
+		Issuer:      result.Issuer,
+		Timestamp:   time.Unix(result.Timestamp.Int64(), 0),
+		AcademicDNA: result.AcademicDNA,
+		Revoked:     result.Revoked,
+	}, nil
 }
 
-// MintCertificate mints a new certificate on the blockchain
-func (bc *RealBlockchainClient) MintCertificate(certHash string) (string, error) {
-	bc.mu.Lock()
-	defer bc.mu.Unlock()
-
-	if bc.client == nil {
-		return "", errors.New("blockchain client not initialized")
+// Mint sends a mintCertificate transaction and returns its hash without waiting for it to be mined
+func (bc *RealBlockchainClient) Mint(certHash, owner, academicDNA string) (string, error) {
+	// Convert hash to bytes32
+	hashBytes, err := hexToBytes32(certHash)
+	if err != nil {
+		return "", err
+	}
+	if !common.IsHexAddress(owner) {
+		return "", fmt.Errorf("invalid owner address: %s", owner)
 	}
 
-	// Convert hash to bytes32
+	return bc.transact("mint certificate", normalizeHash(certHash), "mintCertificate", hashBytes, common.HexToAddress(owner), academicDNA)
+}
+
+// Revoke sends a revokeCertificate transaction, recording reason in the event log
+func (bc *RealBlockchainClient) Revoke(certHash, reason string) (string, error) {
 	hashBytes, err := hexToBytes32(certHash)
 	if err != nil {
 		return "", err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	return bc.transact("revoke certificate", normalizeHash(certHash), "revokeCertificate", hashBytes, reason)
+}
+
+// AuthorizeIssuer allows an address to mint certificates (contract owner only)
+func (bc *RealBlockchainClient) AuthorizeIssuer(issuer string) (string, error) {
+	if !common.IsHexAddress(issuer) {
+		return "", fmt.Errorf("invalid issuer address: %s", issuer)
+	}
+
+	return bc.transact("authorize issuer", issuer, "authorizeIssuer", common.HexToAddress(issuer))
+}
+
+// RevokeIssuer removes an address's minting permission (contract owner only)
+func (bc *RealBlockchainClient) RevokeIssuer(issuer string) (string, error) {
+	if !common.IsHexAddress(issuer) {
+		return "", fmt.Errorf("invalid issuer address: %s", issuer)
+	}
+
+	return bc.transact("revoke issuer", issuer, "revokeIssuer", common.HexToAddress(issuer))
+}
+
+// IsAuthorizedIssuer reports whether an address may mint certificates
+func (bc *RealBlockchainClient) IsAuthorizedIssuer(issuer string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Get nonce
-	nonce, err := bc.client.PendingNonceAt(ctx, bc.address)
+	authorized, err := bc.contract.AuthorizedIssuers(&bind.CallOpts{Context: ctx}, common.HexToAddress(issuer))
 	if err != nil {
-		return "", fmt.Errorf("failed to get nonce: %w", err)
+		return false, fmt.Errorf("failed to check issuer authorization: %w", err)
 	}
+	return authorized, nil
+}
 
-	// Get gas price
-	gasPrice, err := bc.client.SuggestGasPrice(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to get gas price: %w", err)
+// CertificatesByWallet returns the hashes of every certificate owned by a wallet
+func (bc *RealBlockchainClient) CertificatesByWallet(wallet string) ([]string, error) {
+	if !common.IsHexAddress(wallet) {
+		return nil, fmt.Errorf("invalid wallet address: %s", wallet)
 	}
 
-	// Create auth
-	auth, err := bind.NewKeyedTransactorWithChainID(bc.privateKey, bc.chainID)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	hashes, err := bc.contract.GetCertificatesByWallet(&bind.CallOpts{Context: ctx}, common.HexToAddress(wallet))
 	if err != nil {
-		return "", fmt.Errorf("failed to create transactor: %w", err)
+		return nil, fmt.Errorf("failed to get wallet certificates: %w", err)
 	}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(300000)
-	auth.GasPrice = gasPrice
-	auth.Context = ctx
+	result := make([]string, len(hashes))
+	for i, h := range hashes {
+		result[i] = hex.EncodeToString(h[:])
+	}
+	return result, nil
+}
 
-	// Get contract instance
-	contract, err := bc.getContractInstance()
+// IssuerCertCount returns how many certificates an issuer has minted
+func (bc *RealBlockchainClient) IssuerCertCount(issuer string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	count, err := bc.contract.GetIssuerCertCount(&bind.CallOpts{Context: ctx}, common.HexToAddress(issuer))
 	if err != nil {
-		return "", err
+		return 0, fmt.Errorf("failed to get issuer certificate count: %w", err)
 	}
+	return int(count.Int64()), nil
+}
 
-	// Send transaction
-	tx, err := contract.MintCertificate(auth, hashBytes)
-	if err != nil {
-		return "", fmt.Errorf("failed to mint certificate: %w", err)
+// CertificateCount returns total certificates on chain
+func (bc *RealBlockchainClient) CertificateCount() (int, error) {
+	if bc.client == nil {
+		return 0, errors.New("blockchain client not initialized")
 	}
 
-	log.Printf("🔗 Certificate minting transaction sent: %s", tx.Hash().Hex())
-	log.Printf("   Gas Price: %s wei", gasPrice.String())
-	log.Printf("   Nonce: %d", nonce)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	// Wait for transaction to be mined
-	receipt, err := bind.WaitMined(ctx, bc.client, tx)
+	count, err := bc.contract.GetTotalCertificates(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return "", fmt.Errorf("transaction mining failed: %w", err)
+		return 0, fmt.Errorf("failed to get certificate count: %w", err)
 	}
 
-	if receipt.Status == 0 {
-		return "", errors.New("transaction failed")
+	return int(count.Int64()), nil
+}
+
+// MintEvents returns the CertificateMinted events the registry emitted in a mined transaction
+func (bc *RealBlockchainClient) MintEvents(txHash string) ([]MintEvent, error) {
+	hash, err := hexutil.Decode(txHash)
+	if err != nil || len(hash) != common.HashLength {
+		return nil, fmt.Errorf("invalid transaction hash: %s", txHash)
 	}
 
-	log.Printf("✅ Certificate minted successfully!")
-	log.Printf("   Block: %d", receipt.BlockNumber.Uint64())
-	log.Printf("   Gas Used: %d", receipt.GasUsed)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	return tx.Hash().Hex(), nil
+	receipt, err := bc.receipt(ctx, common.BytesToHash(hash))
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return nil, ErrTxReverted
+	}
+
+	events := []MintEvent{}
+	for _, vLog := range receipt.Logs {
+		if vLog.Address != bc.contractAddr || len(vLog.Topics) == 0 || vLog.Topics[0] != EventCertificateMinted {
+			continue
+		}
+		minted, err := bc.contract.ParseCertificateMinted(*vLog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode CertificateMinted event: %w", err)
+		}
+		events = append(events, MintEvent{
+			Hash:        hex.EncodeToString(minted.CertHash[:]),
+			Owner:       minted.Owner,
+			Issuer:      minted.Issuer,
+			TxHash:      receipt.TxHash.Hex(),
+			BlockNumber: receipt.BlockNumber.Uint64(),
+		})
+	}
+	return events, nil
 }
 
-// GetCertificateCount returns total certificates on chain
-func (bc *RealBlockchainClient) GetCertificateCount() (int, error) {
-	bc.mu.RLock()
-	defer bc.mu.RUnlock()
+// receipt returns the receipt of a transaction. Platform wallet transactions are
+// known by their first broadcast's hash, but after a fee bump a replacement may be
+// the one mined, so every broadcast the transaction manager recorded is tried.
+func (bc *RealBlockchainClient) receipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	hashes := []common.Hash{txHash}
+	if record, err := db.Repos.Transactions.Get(ctx, txHash.Hex()); err == nil && len(record.Hashes) > 0 {
+		hashes = make([]common.Hash, 0, len(record.Hashes))
+		for i := len(record.Hashes) - 1; i >= 0; i-- {
+			hashes = append(hashes, common.HexToHash(record.Hashes[i]))
+		}
+	}
 
-	if bc.client == nil {
-		return 0, errors.New("blockchain client not initialized")
+	pending := false
+	for _, hash := range hashes {
+		receipt, err := bc.client.TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+		}
+		// No receipt yet: either still in the mempool or unknown to the node
+		if _, isPending, txErr := bc.client.TransactionByHash(ctx, hash); txErr == nil && isPending {
+			pending = true
+		}
 	}
+	if pending {
+		return nil, ErrTxPending
+	}
+	return nil, ErrTxNotFound
+}
 
+// LatestBlock returns the current block height
+func (bc *RealBlockchainClient) LatestBlock() (uint64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	contract, err := bc.getContractInstance()
+	header, err := bc.client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
 	}
+	return header.Number.Uint64(), nil
+}
 
-	count, err := contract.GetTotalCertificates(&bind.CallOpts{Context: ctx})
+// MintEventsBetween returns every CertificateMinted event the registry emitted in blocks [from, to]
+func (bc *RealBlockchainClient) MintEventsBetween(from, to uint64) ([]MintEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	it, err := bc.contract.FilterCertificateMinted(&bind.FilterOpts{Start: from, End: &to, Context: ctx}, nil, nil, nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get certificate count: %w", err)
+		return nil, fmt.Errorf("failed to filter mint events: %w", err)
 	}
-
-	return int(count.Int64()), nil
+	defer it.Close()
+
+	events := []MintEvent{}
+	for it.Next() {
+		events = append(events, MintEvent{
+			Hash:        hex.EncodeToString(it.Event.CertHash[:]),
+			Owner:       it.Event.Owner,
+			Issuer:      it.Event.Issuer,
+			TxHash:      it.Event.Raw.TxHash.Hex(),
+			BlockNumber: it.Event.Raw.BlockNumber,
+		})
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read mint events: %w", err)
+	}
+	return events, nil
 }
 
 // GetBalance returns the wallet balance in wei
 func (bc *RealBlockchainClient) GetBalance() (*big.Int, error) {
-	bc.mu.RLock()
-	defer bc.mu.RUnlock()
-
 	if bc.client == nil {
 		return nil, errors.New("blockchain client not initialized")
 	}
@@ -251,27 +397,27 @@ func (bc *RealBlockchainClient) GetBalance() (*big.Int, error) {
 	return balance, nil
 }
 
-// getContractInstance returns a contract instance
-func (bc *RealBlockchainClient) getContractInstance() (*CertificateRegistryContract, error) {
-	// This will use the generated contract bindings
-	// For now, return a placeholder
-	return NewCertificateRegistryContract(bc.contractAddr, bc.client)
-}
-
-// hexToBytes32 converts hex string to [32]byte
-func hexToBytes32(hexStr string) ([32]byte, error) {
-	var result [32]byte
-
-	hexStr = strings.TrimPrefix(hexStr, "0x")
-	if len(hexStr) != 64 {
-		return result, fmt.Errorf("invalid hash length: expected 64, got %d", len(hexStr))
+// transact hands a registry call to the transaction manager and returns the hash
+// of the first broadcast, which also identifies it in the transactions store.
+// Whether it was mined is recorded there later; MintEvents finds the receipt even
+// when a fee bump replaced the first broadcast.
+func (bc *RealBlockchainClient) transact(action, reference, method string, args ...interface{}) (string, error) {
+	if bc.txm == nil {
+		return "", ErrReadOnlyDeployment
 	}
 
-	bytes, err := hex.DecodeString(hexStr)
+	parsed, err := SoulboundCertificateRegistryMetaData.GetAbi()
 	if err != nil {
-		return result, err
+		return "", err
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", method, err)
 	}
 
-	copy(result[:], bytes)
-	return result, nil
+	tx, err := bc.txm.submit(action, reference, bc.contractAddr, data)
+	if err != nil {
+		return "", err
+	}
+	return tx.ID, nil
 }
@@ -0,0 +1,270 @@
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"cache-crew/cognify/internal/db"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// testRegistry is a certificate registry deployed on a simulated backend, with a
+// client for its owner
+type testRegistry struct {
+	backend  *simulated.Backend
+	ownerKey *ecdsa.PrivateKey
+	client   *RealBlockchainClient
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	t.Helper()
+	db.Repos = db.NewMemoryRepositories()
+
+	ownerKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(ownerKey.PublicKey): {Balance: simulatedFunding},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	auth, err := bind.NewKeyedTransactorWithChainID(ownerKey, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contractAddr, _, _, err := DeploySoulboundCertificateRegistry(auth, backend.Client())
+	if err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+	backend.Commit()
+
+	client, err := NewRealBlockchainClient(backend.Client(), contractAddr, NewKeySigner(ownerKey), TxConfig{ChainID: 1337})
+	if err != nil {
+		t.Fatalf("NewRealBlockchainClient: %v", err)
+	}
+	return &testRegistry{backend: backend, ownerKey: ownerKey, client: client}
+}
+
+// clientFor returns a client of the same registry that signs with key
+func (r *testRegistry) clientFor(t *testing.T, key *ecdsa.PrivateKey) *RealBlockchainClient {
+	t.Helper()
+	client, err := NewRealBlockchainClient(r.backend.Client(), r.client.ContractAddress(), NewKeySigner(key), TxConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+const (
+	testCertHash = "8f434346648f6b96df89dda901c5176b10a6d83961dd3c1ac88b59b2dc327aa4"
+	testOwner    = "0x1111111111111111111111111111111111111111"
+)
+
+func TestRealClientChainID(t *testing.T) {
+	r := newTestRegistry(t)
+	if got := r.client.ChainID().Int64(); got != 1337 {
+		t.Errorf("ChainID() = %d, want 1337", got)
+	}
+
+	_, err := NewRealBlockchainClient(r.backend.Client(), r.client.ContractAddress(), NewKeySigner(r.ownerKey), TxConfig{ChainID: 80001})
+	if err == nil {
+		t.Error("client for CHAIN_ID 80001 connected to chain 1337")
+	}
+}
+
+func TestRealClientMintAndVerify(t *testing.T) {
+	r := newTestRegistry(t)
+
+	record, err := r.client.VerifyCertificate(testCertHash)
+	if err != nil {
+		t.Fatalf("VerifyCertificate before mint: %v", err)
+	}
+	if record.Exists {
+		t.Fatal("certificate exists before it was minted")
+	}
+
+	txHash, err := r.client.Mint("0x"+testCertHash, testOwner, "dna-1")
+	if err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+	if _, err := r.client.MintEvents(txHash); !errors.Is(err, ErrTxPending) {
+		t.Errorf("MintEvents before the block = %v, want ErrTxPending", err)
+	}
+	r.backend.Commit()
+
+	record, err = r.client.VerifyCertificate(testCertHash)
+	if err != nil {
+		t.Fatalf("VerifyCertificate: %v", err)
+	}
+	if !record.Exists || record.Revoked {
+		t.Fatalf("record = %+v, want an unrevoked certificate", record)
+	}
+	if !strings.EqualFold(record.Owner.Hex(), testOwner) {
+		t.Errorf("Owner = %s, want %s", record.Owner.Hex(), testOwner)
+	}
+	if record.Issuer != r.client.Address() {
+		t.Errorf("Issuer = %s, want %s", record.Issuer.Hex(), r.client.Address().Hex())
+	}
+	if record.AcademicDNA != "dna-1" {
+		t.Errorf("AcademicDNA = %q, want dna-1", record.AcademicDNA)
+	}
+
+	events, err := r.client.MintEvents(txHash)
+	if err != nil {
+		t.Fatalf("MintEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Hash != testCertHash || events[0].TxHash != txHash {
+		t.Errorf("MintEvents = %+v, want one event for %s", events, testCertHash)
+	}
+
+	latest, err := r.client.LatestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	between, err := r.client.MintEventsBetween(0, latest)
+	if err != nil || len(between) != 1 {
+		t.Errorf("MintEventsBetween = %+v, %v, want one event", between, err)
+	}
+
+	hashes, err := r.client.CertificatesByWallet(testOwner)
+	if err != nil || len(hashes) != 1 || hashes[0] != testCertHash {
+		t.Errorf("CertificatesByWallet = %v, %v, want [%s]", hashes, err, testCertHash)
+	}
+	if count, err := r.client.CertificateCount(); err != nil || count != 1 {
+		t.Errorf("CertificateCount = %d, %v, want 1", count, err)
+	}
+	if count, err := r.client.IssuerCertCount(r.client.Address().Hex()); err != nil || count != 1 {
+		t.Errorf("IssuerCertCount = %d, %v, want 1", count, err)
+	}
+
+	// Transactions are tracked under their hash until the poller records the outcome
+	tx, err := db.Repos.Transactions.Get(t.Context(), txHash)
+	if err != nil {
+		t.Fatalf("transaction not tracked: %v", err)
+	}
+	if tx.Reference != testCertHash {
+		t.Errorf("tracked Reference = %q, want %s", tx.Reference, testCertHash)
+	}
+}
+
+func TestRealClientRevoke(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if _, err := r.client.Mint(testCertHash, testOwner, "dna-1"); err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+	r.backend.Commit()
+
+	if _, err := r.client.Revoke(testCertHash, "issued in error"); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	r.backend.Commit()
+
+	record, err := r.client.VerifyCertificate(testCertHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !record.Exists || !record.Revoked {
+		t.Errorf("record = %+v, want a revoked certificate", record)
+	}
+
+	// Reverting calls fail during gas estimation, before anything is sent
+	if _, err := r.client.Revoke(testCertHash, "again"); err == nil {
+		t.Error("revoking a revoked certificate succeeded")
+	}
+}
+
+func TestRealClientErrors(t *testing.T) {
+	r := newTestRegistry(t)
+	if _, err := r.client.Mint(testCertHash, testOwner, "dna-1"); err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+	r.backend.Commit()
+
+	// An issuer with no funds, authorized by the owner
+	brokeKey, _ := crypto.GenerateKey()
+	brokeAddr := crypto.PubkeyToAddress(brokeKey.PublicKey)
+	if _, err := r.client.AuthorizeIssuer(brokeAddr.Hex()); err != nil {
+		t.Fatalf("AuthorizeIssuer: %v", err)
+	}
+	r.backend.Commit()
+	if ok, err := r.client.IsAuthorizedIssuer(brokeAddr.Hex()); err != nil || !ok {
+		t.Fatalf("IsAuthorizedIssuer = %v, %v, want true", ok, err)
+	}
+
+	strangerKey, _ := crypto.GenerateKey()
+	stranger := r.clientFor(t, strangerKey)
+	broke := r.clientFor(t, brokeKey)
+
+	const otherHash = "0000000000000000000000000000000000000000000000000000000000000001"
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error // nil: any error
+	}{
+		{"malformed hash", func() error { _, err := r.client.Mint("0xnothex", testOwner, "dna"); return err }, nil},
+		{"short hash", func() error { _, err := r.client.VerifyCertificate("abcd"); return err }, nil},
+		{"invalid owner", func() error { _, err := r.client.Mint(otherHash, "not-an-address", "dna"); return err }, nil},
+		{"duplicate mint", func() error { _, err := r.client.Mint(testCertHash, testOwner, "dna"); return err }, nil},
+		{"revoke unknown", func() error { _, err := r.client.Revoke(otherHash, "reason"); return err }, nil},
+		{"unauthorized minter", func() error { _, err := stranger.Mint(otherHash, testOwner, "dna"); return err }, nil},
+		{"unauthorized revoker", func() error { _, err := stranger.Revoke(testCertHash, "reason"); return err }, nil},
+		{"unfunded issuer", func() error { _, err := broke.Mint(otherHash, testOwner, "dna"); return err }, ErrInsufficientFunds},
+		{"malformed tx hash", func() error { _, err := r.client.MintEvents("0x1234"); return err }, nil},
+		{"unknown tx", func() error { _, err := r.client.MintEvents("0x" + otherHash); return err }, ErrTxNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if count, err := r.client.CertificateCount(); err != nil || count != 1 {
+		t.Errorf("CertificateCount after failed calls = %d, %v, want 1", count, err)
+	}
+}
+
+func TestRealClientMintEventsAfterFeeBump(t *testing.T) {
+	r := newTestRegistry(t)
+
+	txID, err := r.client.Mint(testCertHash, testOwner, "dna-1")
+	if err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+	record, err := db.Repos.Transactions.Get(t.Context(), txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.client.txm.bump(t.Context(), record)
+	if len(record.Hashes) != 2 || record.Hash == txID {
+		t.Fatalf("Hashes = %v after the bump, want a replacement", record.Hashes)
+	}
+	r.backend.Commit()
+
+	// Only the replacement is mined, but callers still hold the first hash
+	events, err := r.client.MintEvents(txID)
+	if err != nil {
+		t.Fatalf("MintEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Hash != testCertHash || !strings.EqualFold(events[0].TxHash, record.Hash) {
+		t.Errorf("MintEvents = %+v, want the mint in %s", events, record.Hash)
+	}
+	if _, err := r.client.MintEvents(record.Hash); err != nil {
+		t.Errorf("MintEvents of the replacement: %v", err)
+	}
+}
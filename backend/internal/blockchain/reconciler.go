@@ -0,0 +1,274 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/services"
+)
+
+const (
+	// reconcileBatchSize caps how many pending certificates one run checks
+	reconcileBatchSize = 500
+	// reconcileBlockRange caps the block span of a single event query (RPC providers limit it)
+	reconcileBlockRange = 5000
+)
+
+// Reconciler periodically matches pending certificates against the chain.
+// It marks certificates that were minted without a confirmation as minted, expires
+// ones that were never minted within the TTL, settles pending revocations, and reports
+// on-chain certificates that have no metadata in storage.
+type Reconciler struct {
+	client     BlockchainClient
+	interval   time.Duration
+	ttl        time.Duration
+	startBlock uint64
+}
+
+// NewReconciler creates a reconciler that runs every interval and expires pending
+// certificates older than ttl. The first event scan starts at startBlock
+// (normally the registry's deployment block).
+func NewReconciler(client BlockchainClient, interval, ttl time.Duration, startBlock uint64) *Reconciler {
+	return &Reconciler{
+		client:     client,
+		interval:   interval,
+		ttl:        ttl,
+		startBlock: startBlock,
+	}
+}
+
+// Start runs a reconciliation immediately and then on every tick
+func (r *Reconciler) Start(ctx context.Context) {
+	log.Printf("[Reconciler] 🧹 Starting pending-mint reconciliation (Interval: %s, TTL: %s)", r.interval, r.ttl)
+	ticker := time.NewTicker(r.interval)
+
+	go func() {
+		r.reconcile(ctx)
+		for {
+			select {
+			case <-ticker.C:
+				r.reconcile(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (r *Reconciler) reconcile(ctx context.Context) {
+	report := &models.ReconciliationReport{
+		RunAt:             time.Now(),
+		Confirmed:         []string{},
+		Expired:           []string{},
+		Revoked:           []string{},
+		RevocationsFailed: []string{},
+	}
+
+	var previousOrphans []models.OrphanCertificate
+	scanFrom := r.startBlock
+	if previous, err := db.Repos.SystemState.GetReconciliationReport(ctx); err == nil {
+		previousOrphans = previous.Orphans
+		report.LastScannedBlock = previous.LastScannedBlock
+		scanFrom = previous.LastScannedBlock + 1
+	} else if !errors.Is(err, db.ErrNotFound) {
+		log.Printf("[Reconciler] ⚠️ Failed to load previous report: %v", err)
+		return
+	}
+
+	// 1. Collect mints since the last run
+	minted := r.scanMints(scanFrom, report)
+
+	// 2. Resolve pending certificates
+	pending, err := db.Repos.Certificates.ListPending(ctx, reconcileBatchSize)
+	if err != nil {
+		log.Printf("[Reconciler] ⚠️ Failed to list pending certificates: %v", err)
+		return
+	}
+	report.PendingChecked = len(pending)
+
+	for _, cert := range pending {
+		r.resolvePending(ctx, cert, minted, report)
+	}
+
+	// 3. Resolve revocations sent from the platform wallet
+	revoking, err := db.Repos.Certificates.ListRevocationPending(ctx, reconcileBatchSize)
+	if err != nil {
+		log.Printf("[Reconciler] ⚠️ Failed to list pending revocations: %v", err)
+	}
+	for _, cert := range revoking {
+		r.resolveRevocation(ctx, cert, report)
+	}
+
+	// 4. Report on-chain certificates without metadata
+	report.Orphans = r.findOrphans(ctx, previousOrphans, minted)
+
+	if err := db.Repos.SystemState.SaveReconciliationReport(ctx, report); err != nil {
+		log.Printf("[Reconciler] ⚠️ Failed to save report: %v", err)
+	}
+
+	if len(report.Confirmed) > 0 || len(report.Expired) > 0 {
+		log.Printf("[Reconciler] ✅ Checked %d pending: %d confirmed, %d expired", report.PendingChecked, len(report.Confirmed), len(report.Expired))
+	}
+	if len(report.Revoked) > 0 || len(report.RevocationsFailed) > 0 {
+		log.Printf("[Reconciler] ✅ Pending revocations: %d revoked, %d failed", len(report.Revoked), len(report.RevocationsFailed))
+	}
+	if len(report.Orphans) > 0 {
+		log.Printf("[Reconciler] 🚨 %d on-chain certificates have no metadata (see admin reconciliation report)", len(report.Orphans))
+	}
+}
+
+// scanMints returns mint events from scanFrom to the latest block, keyed by certificate
+// hash, and advances report.LastScannedBlock as far as the scan got
+func (r *Reconciler) scanMints(scanFrom uint64, report *models.ReconciliationReport) map[string]MintEvent {
+	minted := make(map[string]MintEvent)
+
+	latest, err := r.client.LatestBlock()
+	if err != nil {
+		log.Printf("[Reconciler] ⚠️ Failed to get latest block: %v", err)
+		return minted
+	}
+
+	for from := scanFrom; from <= latest; from += reconcileBlockRange {
+		to := min(from+reconcileBlockRange-1, latest)
+		events, err := r.client.MintEventsBetween(from, to)
+		if err != nil {
+			log.Printf("[Reconciler] ⚠️ Failed to scan blocks %d-%d: %v", from, to, err)
+			break
+		}
+		for _, event := range events {
+			if event.Owner == AnchorOwner {
+				continue // Merkle roots are not certificates
+			}
+			minted[event.Hash] = event
+		}
+		report.LastScannedBlock = to
+	}
+	return minted
+}
+
+func (r *Reconciler) resolvePending(ctx context.Context, cert models.Certificate, minted map[string]MintEvent, report *models.ReconciliationReport) {
+	event, found := minted[cert.Hash]
+	if !found {
+		// Minted before the scan window (e.g. first run after a restart): ask the registry directly
+		record, err := r.client.VerifyCertificate(cert.Hash)
+		if err != nil {
+			log.Printf("[Reconciler] ⚠️ Failed to check %s on-chain: %v", cert.Hash, err)
+			return
+		}
+		if record.Exists {
+			found = true
+			event = MintEvent{Hash: cert.Hash, Owner: record.Owner, Issuer: record.Issuer}
+		}
+	}
+
+	if found {
+		err := ConfirmMint(ctx, r.client, event)
+		if IsMintRejected(err) {
+			// Left pending, so it expires unless a valid mint turns up
+			log.Printf("[Reconciler] ⚠️ Not recording mint of %s: %v", cert.Hash, err)
+			return
+		}
+		if err != nil {
+			log.Printf("[Reconciler] ⚠️ Failed to mark %s minted: %v", cert.Hash, err)
+			return
+		}
+		report.Confirmed = append(report.Confirmed, cert.Hash)
+		return
+	}
+
+	if time.Since(cert.IssuedAt) > r.ttl {
+		if err := db.Repos.Certificates.MarkExpired(ctx, cert.Hash, time.Now()); err != nil {
+			log.Printf("[Reconciler] ⚠️ Failed to expire %s: %v", cert.Hash, err)
+			return
+		}
+		report.Expired = append(report.Expired, cert.Hash)
+	}
+}
+
+// resolveRevocation finalizes a pending revocation the listener missed once the
+// registry shows it, and drops one whose transaction reverted or was replaced
+func (r *Reconciler) resolveRevocation(ctx context.Context, cert models.Certificate, report *models.ReconciliationReport) {
+	pending := cert.PendingRevocation
+
+	record, err := r.client.VerifyCertificate(cert.Hash)
+	if err != nil {
+		log.Printf("[Reconciler] ⚠️ Failed to check %s on-chain: %v", cert.Hash, err)
+		return
+	}
+	if record.Revoked {
+		if err := services.NewRevocationService().Record(ctx, cert.Hash, pending.RequestedBy, pending.Reason, time.Now()); err != nil {
+			log.Printf("[Reconciler] ⚠️ Failed to record revocation of %s: %v", cert.Hash, err)
+			return
+		}
+		report.Revoked = append(report.Revoked, cert.Hash)
+		return
+	}
+
+	// Not revoked yet: still pending unless the transaction manager saw it fail
+	tx, err := db.Repos.Transactions.Get(ctx, pending.TxHash)
+	if errors.Is(err, db.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("[Reconciler] ⚠️ Failed to load revocation transaction %s: %v", pending.TxHash, err)
+		return
+	}
+	if tx.Status != models.TxStatusFailed && tx.Status != models.TxStatusDropped {
+		return
+	}
+	if err := db.Repos.Certificates.ClearRevocationPending(ctx, cert.Hash); err != nil {
+		log.Printf("[Reconciler] ⚠️ Failed to clear pending revocation of %s: %v", cert.Hash, err)
+		return
+	}
+	log.Printf("[Reconciler] ⚠️ Revocation of %s not applied: transaction %s %s", cert.Hash, pending.TxHash, tx.Status)
+	report.RevocationsFailed = append(report.RevocationsFailed, cert.Hash)
+}
+
+// findOrphans re-checks previously reported orphans and adds newly minted hashes
+// whose certificate is missing from storage or has no metadata
+func (r *Reconciler) findOrphans(ctx context.Context, previous []models.OrphanCertificate, minted map[string]MintEvent) []models.OrphanCertificate {
+	orphans := []models.OrphanCertificate{}
+	seen := make(map[string]bool)
+
+	for _, orphan := range previous {
+		if !r.hasMetadata(ctx, orphan.Hash) {
+			orphans = append(orphans, orphan)
+		}
+		seen[orphan.Hash] = true
+	}
+
+	for hash, event := range minted {
+		if seen[hash] || r.hasMetadata(ctx, hash) {
+			continue
+		}
+		orphans = append(orphans, models.OrphanCertificate{
+			Hash:        hash,
+			Owner:       event.Owner.Hex(),
+			Issuer:      event.Issuer.Hex(),
+			TxHash:      event.TxHash,
+			BlockNumber: event.BlockNumber,
+			DetectedAt:  time.Now(),
+		})
+	}
+	return orphans
+}
+
+// hasMetadata reports whether storage holds more than the bare on-chain sync state
+// (which MarkMinted creates) for a certificate
+func (r *Reconciler) hasMetadata(ctx context.Context, hash string) bool {
+	cert, err := db.Repos.Certificates.Get(ctx, hash)
+	if errors.Is(err, db.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		// Don't report an orphan on a storage hiccup; the next run re-checks it
+		log.Printf("[Reconciler] ⚠️ Failed to load %s: %v", hash, err)
+		return true
+	}
+	return cert.StudentName != "" || cert.UserName != "" || cert.CourseName != "" || cert.CourseTitle != ""
+}
@@ -0,0 +1,180 @@
+package blockchain
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// reconcileOnce runs one reconciliation against the mock chain and returns its report
+func reconcileOnce(t *testing.T) *models.ReconciliationReport {
+	t.Helper()
+	ctx := context.Background()
+	NewReconciler(GetMockClient(), time.Hour, 72*time.Hour, 0).reconcile(ctx)
+	report, err := db.Repos.SystemState.GetReconciliationReport(ctx)
+	if err != nil {
+		t.Fatalf("GetReconciliationReport: %v", err)
+	}
+	return report
+}
+
+func TestReconcilerResolvesRevocations(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+	client := GetMockClient()
+
+	// mintPending stores a minted certificate with a revocation sent from the platform wallet
+	mintPending := func(t *testing.T, txHash string) string {
+		t.Helper()
+		hash := certificateKey(crypto.Keccak256Hash([]byte(t.Name())))
+		if _, err := client.Mint(hash, testStudent, ""); err != nil {
+			t.Fatal(err)
+		}
+		cert := &models.Certificate{Hash: hash, WalletAddress: testStudent, IsMinted: true}
+		if err := db.Repos.Certificates.Save(ctx, cert); err != nil {
+			t.Fatal(err)
+		}
+		pending := models.PendingRevocation{TxHash: txHash, RequestedBy: testOwner, Reason: "plagiarism", RequestedAt: time.Now()}
+		if err := db.Repos.Certificates.MarkRevocationPending(ctx, hash, pending); err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	t.Run("mined", func(t *testing.T) {
+		hash := mintPending(t, "0xmined")
+		if _, err := client.Revoke(hash, "plagiarism"); err != nil {
+			t.Fatal(err)
+		}
+
+		report := reconcileOnce(t)
+		stored, _ := db.Repos.Certificates.Get(ctx, hash)
+		if !stored.Revoked || stored.PendingRevocation != nil {
+			t.Fatalf("Revoked = %v, PendingRevocation = %+v", stored.Revoked, stored.PendingRevocation)
+		}
+		// Credited to the requester, not the platform wallet that sent it
+		if stored.RevokedBy != testOwner || stored.RevocationReason != "plagiarism" {
+			t.Errorf("RevokedBy = %s, RevocationReason = %q", stored.RevokedBy, stored.RevocationReason)
+		}
+		if !slices.Contains(report.Revoked, hash) {
+			t.Errorf("report.Revoked = %v, want %s", report.Revoked, hash)
+		}
+	})
+
+	t.Run("in flight", func(t *testing.T) {
+		hash := mintPending(t, "0xinflight")
+		if err := db.Repos.Transactions.Save(ctx, &models.ChainTransaction{ID: "0xinflight", Status: models.TxStatusPending}); err != nil {
+			t.Fatal(err)
+		}
+
+		reconcileOnce(t)
+		stored, _ := db.Repos.Certificates.Get(ctx, hash)
+		if stored.Revoked || stored.PendingRevocation == nil {
+			t.Errorf("Revoked = %v, PendingRevocation = %+v, want still pending", stored.Revoked, stored.PendingRevocation)
+		}
+	})
+
+	for _, status := range []string{models.TxStatusFailed, models.TxStatusDropped} {
+		t.Run(status, func(t *testing.T) {
+			txHash := "0x" + status
+			hash := mintPending(t, txHash)
+			if err := db.Repos.Transactions.Save(ctx, &models.ChainTransaction{ID: txHash, Status: status}); err != nil {
+				t.Fatal(err)
+			}
+
+			report := reconcileOnce(t)
+			stored, _ := db.Repos.Certificates.Get(ctx, hash)
+			if stored.Revoked || stored.PendingRevocation != nil {
+				t.Errorf("Revoked = %v, PendingRevocation = %+v, want the pending revocation dropped", stored.Revoked, stored.PendingRevocation)
+			}
+			if !slices.Contains(report.RevocationsFailed, hash) {
+				t.Errorf("report.RevocationsFailed = %v, want %s", report.RevocationsFailed, hash)
+			}
+		})
+	}
+}
+
+func TestReconcilerResolvesPendingMints(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+	client := GetMockClient()
+
+	// savePending stores an unminted certificate issued at issuedAt, authorized for the test student
+	savePending := func(t *testing.T, issuedAt time.Time) string {
+		t.Helper()
+		hash := certificateKey(crypto.Keccak256Hash([]byte(t.Name())))
+		auth, err := SignMintAuthorization(testStudent, hash, "dna-v1", time.Now(), time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert := &models.Certificate{Hash: hash, WalletAddress: testStudent, AcademicDNA: "dna-v1", IssuedAt: issuedAt, MintAuthorization: auth}
+		if err := db.Repos.Certificates.Save(ctx, cert); err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	t.Run("mined", func(t *testing.T) {
+		hash := savePending(t, time.Now())
+		txHash, err := client.Mint(hash, testStudent, "dna-v1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		report := reconcileOnce(t)
+		stored, _ := db.Repos.Certificates.Get(ctx, hash)
+		if !stored.IsMinted || stored.BlockchainTx != txHash || stored.MintExpired {
+			t.Errorf("IsMinted = %v, BlockchainTx = %s, MintExpired = %v, want minted in %s", stored.IsMinted, stored.BlockchainTx, stored.MintExpired, txHash)
+		}
+		if stored.Chain == nil || *stored.Chain != ActiveChain() {
+			t.Errorf("Chain = %+v, want %+v", stored.Chain, ActiveChain())
+		}
+		if !slices.Contains(report.Confirmed, hash) {
+			t.Errorf("report.Confirmed = %v, want %s", report.Confirmed, hash)
+		}
+	})
+
+	t.Run("minted without its authorization", func(t *testing.T) {
+		hash := savePending(t, time.Now())
+		if _, err := client.Mint(hash, testStudent, "dna-tampered"); err != nil {
+			t.Fatal(err)
+		}
+
+		report := reconcileOnce(t)
+		stored, _ := db.Repos.Certificates.Get(ctx, hash)
+		if stored.IsMinted || slices.Contains(report.Confirmed, hash) {
+			t.Errorf("IsMinted = %v, Confirmed = %v, want left pending", stored.IsMinted, report.Confirmed)
+		}
+	})
+
+	t.Run("abandoned", func(t *testing.T) {
+		hash := savePending(t, time.Now().Add(-73*time.Hour))
+
+		report := reconcileOnce(t)
+		stored, _ := db.Repos.Certificates.Get(ctx, hash)
+		if !stored.MintExpired || stored.IsMinted {
+			t.Errorf("MintExpired = %v, IsMinted = %v, want expired", stored.MintExpired, stored.IsMinted)
+		}
+		if !slices.Contains(report.Expired, hash) {
+			t.Errorf("report.Expired = %v, want %s", report.Expired, hash)
+		}
+	})
+
+	t.Run("orphan", func(t *testing.T) {
+		hash := certificateKey(crypto.Keccak256Hash([]byte(t.Name())))
+		if _, err := client.Mint(hash, testStudent, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		report := reconcileOnce(t)
+		if !slices.ContainsFunc(report.Orphans, func(o models.OrphanCertificate) bool { return o.Hash == hash }) {
+			t.Errorf("report.Orphans = %+v, want %s", report.Orphans, hash)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"cache-crew/cognify/internal/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer backends, selected with SignerConfig.Backend
+const (
+	SignerBackendEnv      = "env"      // PBKDF2/AES-GCM encrypted key in PRIVATE_KEY_ENCRYPTED
+	SignerBackendKeystore = "keystore" // go-ethereum keystore JSON file
+	SignerBackendClef     = "clef"     // External signer speaking Clef's account_* JSON-RPC
+)
+
+// Signer signs for the platform wallet. The key either lives in this process
+// (env, keystore) or stays with an external signer that is asked for every signature.
+type Signer interface {
+	Address() common.Address
+	// SignTx signs tx for chainID
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignTypedData returns a 65-byte EIP-712 signature with v as 27 or 28
+	SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error)
+}
+
+// hashSigner is a Signer that can also sign an arbitrary 32-byte hash, as JWS
+// algorithms need. The env and keystore backends can; Clef only signs
+// transactions and structured data, so SD-JWTs and Token Status Lists can't be
+// issued with it (see CanSignJWS).
+type hashSigner interface {
+	Signer
+	// SignHash returns a 65-byte [R || S || V] signature with v as 0 or 1
+	SignHash(ctx context.Context, hash []byte) ([]byte, error)
+}
+
+// SignerConfig selects and configures the platform wallet's signer.
+// Rotating the key is a configuration change: point it at the new key, list the
+// old wallet in PLATFORM_PREVIOUS_ADDRESSES (see SetRetiredSigners) so what it
+// signed keeps verifying, and restart.
+type SignerConfig struct {
+	Backend      string // SignerBackend*; empty means env
+	EncryptedKey string // env: ciphertext produced by scripts/encrypt_key
+	Passphrase   string // env and keystore: unlocks the key
+	KeystorePath string // keystore: path to the JSON key file
+	Endpoint     string // clef: IPC socket path or http(s) URL
+	Address      string // clef: account to sign with; empty uses the signer's first account
+}
+
+var (
+	retiredSignersMu sync.RWMutex
+	retiredSigners   = make(map[common.Address]bool) // Platform wallets from before a key rotation
+	retiredChains    = make(map[int64]bool)          // Chains named by retired platform DIDs
+)
+
+// SetRetiredSigners registers the platform wallets used before key rotations.
+// Each entry is an address or a did:ethr DID; a DID also makes its chain a known
+// platform chain (see IsPlatformDID). Mint authorizations, credentials, seals and
+// JWS signed by these wallets keep verifying; nothing new is signed with them.
+func SetRetiredSigners(entries []string) error {
+	signers := make(map[common.Address]bool, len(entries))
+	chains := make(map[int64]bool)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue
+		case common.IsHexAddress(entry):
+			signers[common.HexToAddress(entry)] = true
+		default:
+			chainID, address, err := parseEthrDID(entry)
+			if err != nil {
+				return fmt.Errorf("retired platform signer: %w", err)
+			}
+			signers[address] = true
+			chains[chainID] = true
+		}
+	}
+
+	retiredSignersMu.Lock()
+	defer retiredSignersMu.Unlock()
+	retiredSigners, retiredChains = signers, chains
+	return nil
+}
+
+// IsPlatformAddress reports whether address is the platform wallet, current or retired
+func IsPlatformAddress(address common.Address) bool {
+	signer, _, _ := platformSigner()
+	if signer.Address() == address {
+		return true
+	}
+	retiredSignersMu.RLock()
+	defer retiredSignersMu.RUnlock()
+	return retiredSigners[address]
+}
+
+// NewSigner opens the signer described by cfg
+func NewSigner(ctx context.Context, cfg SignerConfig) (Signer, error) {
+	switch cfg.Backend {
+	case SignerBackendEnv, "":
+		return newEnvSigner(cfg.EncryptedKey, cfg.Passphrase)
+	case SignerBackendKeystore:
+		return newKeystoreSigner(cfg.KeystorePath, cfg.Passphrase)
+	case SignerBackendClef:
+		return newClefSigner(ctx, cfg.Endpoint, cfg.Address)
+	default:
+		return nil, fmt.Errorf("unknown signer backend %q", cfg.Backend)
+	}
+}
+
+// keySigner signs with a private key held in memory. The env backend and the
+// simulated chain's generated wallet use it.
+type keySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner returns a Signer for a key already in memory
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &keySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func newEnvSigner(encryptedKey, passphrase string) (Signer, error) {
+	keyHex, err := utils.DecryptPrivateKey(encryptedKey, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt private key: %w", err)
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(keyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return NewKeySigner(key), nil
+}
+
+func (s *keySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *keySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *keySigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27 // Wallet convention for v
+	return sig, nil
+}
+
+func (s *keySigner) SignHash(ctx context.Context, hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+// newKeystoreSigner decrypts a go-ethereum keystore JSON key file. Only that file
+// is read, not the other keys in its directory, and the decrypted key then signs
+// like the env backend's.
+func newKeystoreSigner(path, passphrase string) (Signer, error) {
+	if path == "" {
+		return nil, errors.New("keystore path is empty")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %w", err)
+	}
+	key, err := keystore.DecryptKey(raw, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock keystore file %s: %w", path, err)
+	}
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+// clefSigner asks an external signer for every signature over JSON-RPC, usually
+// Clef on a local IPC socket. The key never enters this process; the signer's
+// rules or operator approve each request.
+type clefSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+func newClefSigner(ctx context.Context, endpoint, address string) (Signer, error) {
+	if endpoint == "" {
+		return nil, errors.New("external signer endpoint is empty")
+	}
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to external signer: %w", err)
+	}
+
+	var available []common.Address
+	if err := client.CallContext(ctx, &available, "account_list"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list external signer accounts: %w", err)
+	}
+	for _, account := range available {
+		if address == "" || account == common.HexToAddress(address) {
+			return &clefSigner{client: client, address: account}, nil
+		}
+	}
+	client.Close()
+	if address == "" {
+		return nil, errors.New("external signer has no accounts")
+	}
+	return nil, fmt.Errorf("external signer does not hold account %s", address)
+}
+
+func (s *clefSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *clefSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	to := common.NewMixedcaseAddress(*tx.To())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		To:      &to,
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.LegacyTxType {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	}
+
+	var res struct {
+		Raw hexutil.Bytes      `json:"raw"`
+		Tx  *types.Transaction `json:"tx"`
+	}
+	if err := s.client.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("external signer refused transaction: %w", err)
+	}
+	if res.Tx == nil {
+		return nil, errors.New("external signer returned no transaction")
+	}
+	if err := checkSignedTx(tx, res.Tx, chainID, s.address); err != nil {
+		return nil, fmt.Errorf("external signer returned another transaction: %w", err)
+	}
+	return res.Tx, nil
+}
+
+// checkSignedTx verifies that signed is tx as requested, signed by from for chainID.
+// An external signer could otherwise hand back a different transaction to broadcast.
+func checkSignedTx(tx, signed *types.Transaction, chainID *big.Int, from common.Address) error {
+	switch {
+	case signed.Type() != tx.Type():
+		return fmt.Errorf("type %d, requested %d", signed.Type(), tx.Type())
+	case signed.Nonce() != tx.Nonce():
+		return fmt.Errorf("nonce %d, requested %d", signed.Nonce(), tx.Nonce())
+	case signed.To() == nil || tx.To() == nil || *signed.To() != *tx.To():
+		return fmt.Errorf("recipient %v, requested %v", signed.To(), tx.To())
+	case signed.Value().Cmp(tx.Value()) != 0:
+		return fmt.Errorf("value %s, requested %s", signed.Value(), tx.Value())
+	case !bytes.Equal(signed.Data(), tx.Data()):
+		return errors.New("calldata differs from the request")
+	case signed.Gas() != tx.Gas() || signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 || signed.GasTipCap().Cmp(tx.GasTipCap()) != 0:
+		return errors.New("gas or fees differ from the request")
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if sender != from {
+		return fmt.Errorf("signed by %s, not %s", sender.Hex(), from.Hex())
+	}
+	return nil
+}
+
+func (s *clefSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := s.client.CallContext(ctx, &sig, "account_signTypedData", common.NewMixedcaseAddress(s.address), data); err != nil {
+		return nil, fmt.Errorf("external signer refused typed data: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("external signer returned a %d-byte signature", len(sig))
+	}
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	return sig, nil
+}
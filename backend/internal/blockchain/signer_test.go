@@ -0,0 +1,273 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// rotatePlatformKey makes a new key the mock platform wallet until the test ends
+func rotatePlatformKey(t *testing.T) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock := GetMockClient()
+	previous := mock.signer
+	mock.signer = NewKeySigner(key)
+	t.Cleanup(func() {
+		mock.signer = previous
+		SetRetiredSigners(nil)
+	})
+}
+
+func TestKeyRotation(t *testing.T) {
+	// Everything is signed with key A
+	oldKey, _, _ := platformSigner()
+	oldDID := PlatformDID()
+	issuedAt := time.Now()
+	auth, err := SignMintAuthorization(testStudent, testCertHash, "dna-v1", issuedAt, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential := models.VerifiableCredential{
+		"@context":          []interface{}{"https://www.w3.org/ns/credentials/v2"},
+		"type":              []interface{}{"VerifiableCredential"},
+		"issuer":            oldDID,
+		"credentialSubject": map[string]interface{}{"id": "did:example:student", "course": "Analytical Engines"},
+	}
+	if err := SignCredential(credential); err != nil {
+		t.Fatal(err)
+	}
+	sealIssuer, seal, err := SignCertificateSeal(testCertHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := SignJWS(map[string]interface{}{"typ": "JWT"}, map[string]interface{}{"iss": oldDID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func() map[string]error {
+		results := map[string]error{
+			"mint authorization": VerifyMintAuthorization(auth, issuedAt),
+			"credential":         VerifyCredentialProof(credential),
+			"seal":               VerifyCertificateSeal(testCertHash, sealIssuer, seal),
+		}
+		if signer, _, err := VerifyJWS(token); err != nil || !IsPlatformAddress(signer) {
+			results["JWS"] = ErrJWSInvalid
+		} else {
+			results["JWS"] = nil
+		}
+		if !IsPlatformDID(oldDID) {
+			results["DID"] = ErrCredentialProofInvalid
+		} else {
+			results["DID"] = nil
+		}
+		return results
+	}
+	for name, err := range verify() {
+		if err != nil {
+			t.Fatalf("%s before rotation: %v", name, err)
+		}
+	}
+
+	// Rotating to key B alone orphans what key A signed
+	rotatePlatformKey(t)
+	if newKey, _, _ := platformSigner(); newKey.Address() == oldKey.Address() {
+		t.Fatal("platform key did not rotate")
+	}
+	for name, err := range verify() {
+		if err == nil {
+			t.Errorf("%s signed by a key the platform no longer knows still verifies", name)
+		}
+	}
+
+	// Listing key A as retired, by address or DID, keeps it verifying
+	for _, retired := range []string{oldKey.Address().Hex(), oldDID} {
+		if err := SetRetiredSigners([]string{retired}); err != nil {
+			t.Fatal(err)
+		}
+		for name, err := range verify() {
+			if err != nil {
+				t.Errorf("%s with %s retired: %v", name, retired, err)
+			}
+		}
+	}
+
+	// Nothing new is signed with a retired key
+	if did := PlatformDID(); did == oldDID {
+		t.Error("PlatformDID still names the retired key")
+	}
+}
+
+func TestSetRetiredSigners(t *testing.T) {
+	t.Cleanup(func() { SetRetiredSigners(nil) })
+	if err := SetRetiredSigners([]string{"did:web:example.com"}); err == nil {
+		t.Error("accepted a non-ethr DID")
+	}
+	if err := SetRetiredSigners([]string{"0x123"}); err == nil {
+		t.Error("accepted a malformed address")
+	}
+
+	other := "0x2222222222222222222222222222222222222222"
+	if err := SetRetiredSigners([]string{" " + other + " ", "", "did:ethr:0x89:" + testOwner}); err != nil {
+		t.Fatal(err)
+	}
+	if !IsPlatformDID("did:ethr:0x539:" + other) {
+		t.Error("retired address not a platform DID on the active chain")
+	}
+	if !IsPlatformDID("did:ethr:0x89:" + testOwner) {
+		t.Error("retired DID not a platform DID")
+	}
+	if IsPlatformDID("did:ethr:0x2a:" + other) {
+		t.Error("platform DID on a chain the platform never used")
+	}
+}
+
+func TestKeystoreSignerLoadsOnlyItsFile(t *testing.T) {
+	dir := t.TempDir()
+	writeKey := func(name, passphrase string) common.Address {
+		key, _ := crypto.GenerateKey()
+		raw, err := keystore.EncryptKey(&keystore.Key{
+			Address:    crypto.PubkeyToAddress(key.PublicKey),
+			PrivateKey: key,
+		}, passphrase, keystore.LightScryptN, keystore.LightScryptP)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), raw, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return crypto.PubkeyToAddress(key.PublicKey)
+	}
+	want := writeKey("platform.json", "secret")
+	writeKey("other.json", "other")
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := newKeystoreSigner(filepath.Join(dir, "platform.json"), "secret")
+	if err != nil {
+		t.Fatalf("newKeystoreSigner: %v", err)
+	}
+	if signer.Address() != want {
+		t.Errorf("Address = %s, want %s", signer.Address().Hex(), want.Hex())
+	}
+	if _, ok := signer.(hashSigner); !ok {
+		t.Error("keystore signer cannot sign JWS")
+	}
+
+	if _, err := newKeystoreSigner(filepath.Join(dir, "platform.json"), "wrong"); err == nil {
+		t.Error("unlocked with the wrong passphrase")
+	}
+	if _, err := newKeystoreSigner(filepath.Join(dir, "notes.txt"), "secret"); err == nil {
+		t.Error("opened a file that is not a key")
+	}
+}
+
+// fakeClef serves Clef's account_* API for key, letting a test change the
+// transaction it signs
+type fakeClef struct {
+	key    *ecdsa.PrivateKey
+	tamper func(*types.DynamicFeeTx)
+}
+
+func (f *fakeClef) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(f.key.PublicKey)}
+}
+
+func (f *fakeClef) SignTransaction(args apitypes.SendTxArgs) (map[string]interface{}, error) {
+	to := args.To.Address()
+	inner := &types.DynamicFeeTx{
+		ChainID:   (*big.Int)(args.ChainID),
+		Nonce:     uint64(args.Nonce),
+		GasTipCap: (*big.Int)(args.MaxPriorityFeePerGas),
+		GasFeeCap: (*big.Int)(args.MaxFeePerGas),
+		Gas:       uint64(args.Gas),
+		To:        &to,
+		Value:     (*big.Int)(&args.Value),
+		Data:      *args.Input,
+	}
+	if f.tamper != nil {
+		f.tamper(inner)
+	}
+	signed, err := types.SignNewTx(f.key, types.LatestSignerForChainID(inner.ChainID), inner)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := signed.MarshalBinary()
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+}
+
+func TestClefSignTxChecksTheResult(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	chainID := big.NewInt(1337)
+	to := common.HexToAddress(testOwner)
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(100),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0xca, 0xfe},
+	})
+
+	tests := []struct {
+		name    string
+		key     *ecdsa.PrivateKey
+		tamper  func(*types.DynamicFeeTx)
+		wantErr bool
+	}{
+		{"as requested", key, nil, false},
+		{"other recipient", key, func(tx *types.DynamicFeeTx) { tx.To = &common.Address{1} }, true},
+		{"other nonce", key, func(tx *types.DynamicFeeTx) { tx.Nonce++ }, true},
+		{"other value", key, func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(1) }, true},
+		{"other data", key, func(tx *types.DynamicFeeTx) { tx.Data = []byte{0xde, 0xad} }, true},
+		{"other fees", key, func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(1000) }, true},
+		{"other chain", key, func(tx *types.DynamicFeeTx) { tx.ChainID = big.NewInt(1) }, true},
+		{"other signer", otherKey, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := rpc.NewServer()
+			if err := server.RegisterName("account", &fakeClef{key: tt.key, tamper: tt.tamper}); err != nil {
+				t.Fatal(err)
+			}
+			defer server.Stop()
+			client := rpc.DialInProc(server)
+			defer client.Close()
+
+			signer := &clefSigner{client: client, address: crypto.PubkeyToAddress(key.PublicKey)}
+			signed, err := signer.SignTx(context.Background(), tx, chainID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignTx error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && signed.Hash() == tx.Hash() {
+				t.Error("returned the unsigned transaction")
+			}
+		})
+	}
+
+	if _, ok := Signer(&clefSigner{}).(hashSigner); ok {
+		t.Error("clef signer claims to sign JWS")
+	}
+}
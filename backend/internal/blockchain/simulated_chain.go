@@ -0,0 +1,168 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// simulatedFunding is the genesis balance of the platform wallet (1000 ETH)
+var simulatedFunding = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+
+// SimulatedChain is an in-process Ethereum chain with SoulboundCertificateRegistry
+// deployed at startup. Blocks are sealed on a fixed interval like a real network.
+// State lives in memory and is lost on restart.
+type SimulatedChain struct {
+	backend      *simulated.Backend
+	client       simulated.Client
+	contractAddr common.Address
+	platformKey  *ecdsa.PrivateKey
+	blockTime    time.Duration
+	stop         chan struct{}
+	stopOnce     sync.Once
+}
+
+var simChain *SimulatedChain
+
+// InitSimulatedBlockchain starts an in-process chain, deploys the certificate registry
+// from a freshly generated platform wallet and points the real client at it.
+// If rpcAddr is set (host:port) the chain's JSON-RPC is also served over HTTP.
+// txConfig.ChainID is ignored: the simulated chain is always 1337.
+func InitSimulatedBlockchain(blockTime time.Duration, rpcAddr string, txConfig TxConfig) error {
+	if blockTime <= 0 {
+		blockTime = 2 * time.Second
+	}
+
+	platformKey, err := crypto.GenerateKey()
+	if err != nil {
+		return fmt.Errorf("failed to generate platform key: %w", err)
+	}
+	platformAddr := crypto.PubkeyToAddress(platformKey.PublicKey)
+
+	var options []func(*node.Config, *ethconfig.Config)
+	if rpcAddr != "" {
+		host, portStr, err := net.SplitHostPort(rpcAddr)
+		if err != nil {
+			return fmt.Errorf("invalid simulated RPC address %q: %w", rpcAddr, err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return fmt.Errorf("invalid simulated RPC port %q: %w", portStr, err)
+		}
+		options = append(options, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+			nodeConf.HTTPHost = host
+			nodeConf.HTTPPort = port
+			nodeConf.HTTPModules = []string{"eth", "net", "web3"}
+			nodeConf.HTTPCors = []string{"*"}
+			nodeConf.HTTPVirtualHosts = []string{"*"}
+		})
+	}
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		platformAddr: {Balance: simulatedFunding},
+	}, options...)
+
+	chain := &SimulatedChain{
+		backend:     backend,
+		client:      backend.Client(),
+		platformKey: platformKey,
+		blockTime:   blockTime,
+		stop:        make(chan struct{}),
+	}
+
+	// Deploy the registry; the deployer becomes owner and first authorized issuer
+	auth, err := bind.NewKeyedTransactorWithChainID(platformKey, big.NewInt(1337))
+	if err != nil {
+		backend.Close()
+		return fmt.Errorf("failed to create transactor: %w", err)
+	}
+	contractAddr, _, _, err := DeploySoulboundCertificateRegistry(auth, chain.client)
+	if err != nil {
+		backend.Close()
+		return fmt.Errorf("failed to deploy certificate registry: %w", err)
+	}
+	backend.Commit()
+	chain.contractAddr = contractAddr
+
+	txConfig.ChainID = 0
+	bc, err := NewRealBlockchainClient(chain.client, contractAddr, NewKeySigner(platformKey), txConfig)
+	if err != nil {
+		backend.Close()
+		return err
+	}
+
+	go chain.seal()
+	bc.txm.start(context.Background())
+
+	simChain = chain
+	realClient = bc
+	activeClient = bc
+
+	log.Printf("✅ Simulated Blockchain initialized")
+	log.Printf("   Contract: %s", contractAddr.Hex())
+	log.Printf("   Platform Wallet: %s", platformAddr.Hex())
+	log.Printf("   Chain ID: 1337 (block time %s)", blockTime)
+	if rpcAddr != "" {
+		log.Printf("   JSON-RPC: http://%s", rpcAddr)
+	}
+	return nil
+}
+
+// GetSimulatedChain returns the running simulated chain, or nil outside simulated mode
+func GetSimulatedChain() *SimulatedChain {
+	return simChain
+}
+
+// Client returns an RPC client connected to the simulated chain
+func (c *SimulatedChain) Client() simulated.Client {
+	return c.client
+}
+
+// ContractAddress returns the address of the deployed certificate registry
+func (c *SimulatedChain) ContractAddress() common.Address {
+	return c.contractAddr
+}
+
+// Close stops block production and shuts the chain down
+func (c *SimulatedChain) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		c.backend.Close()
+	})
+}
+
+// seal commits a new block every blockTime so pending transactions get mined
+func (c *SimulatedChain) seal() {
+	ticker := time.NewTicker(c.blockTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.backend.Commit()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// CloseSimulatedBlockchain shuts down the simulated chain if one is running
+func CloseSimulatedBlockchain() {
+	if simChain != nil {
+		simChain.Close()
+	}
+}
@@ -0,0 +1,194 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// defaultGasPerMint is charged per mint when no GAS_LIMIT bounds transactions
+const defaultGasPerMint = 300000
+
+var (
+	// Metrics holds the backend's own metrics, served in Prometheus format on /metrics
+	Metrics = metrics.NewRegistry()
+
+	treasuryBalance        = metrics.NewRegisteredGaugeFloat64("cognify/treasury/balance_eth", Metrics)
+	treasuryFeePerGas      = metrics.NewRegisteredGaugeFloat64("cognify/treasury/fee_per_gas_gwei", Metrics)
+	treasuryMintsRemaining = metrics.NewRegisteredGauge("cognify/treasury/mints_remaining", Metrics)
+	treasuryLowFunds       = metrics.NewRegisteredGauge("cognify/treasury/low_funds", Metrics)
+)
+
+var (
+	treasuryMu     sync.RWMutex
+	treasuryStatus *models.TreasuryStatus
+)
+
+// TreasuryMonitor periodically samples the platform wallet's balance, estimates how
+// many mints it still covers at current fees, and alerts admins when that drops
+// below a threshold. Admins are alerted once per episode; the alert re-arms once
+// the wallet is topped up.
+type TreasuryMonitor struct {
+	client   *RealBlockchainClient
+	interval time.Duration
+	lowMints int64
+	alerted  bool
+}
+
+// NewTreasuryMonitor creates a monitor for client's wallet that samples every
+// interval and alerts admins below lowMints remaining mints
+func NewTreasuryMonitor(client *RealBlockchainClient, interval time.Duration, lowMints int64) *TreasuryMonitor {
+	return &TreasuryMonitor{
+		client:   client,
+		interval: interval,
+		lowMints: lowMints,
+	}
+}
+
+// Start samples immediately and then on every tick
+func (t *TreasuryMonitor) Start(ctx context.Context) {
+	log.Printf("[Treasury] 💰 Monitoring platform wallet %s (Interval: %s, Alert below: %d mints)", t.client.Address().Hex(), t.interval, t.lowMints)
+	ticker := time.NewTicker(t.interval)
+
+	go func() {
+		t.sample(ctx)
+		for {
+			select {
+			case <-ticker.C:
+				t.sample(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// GetTreasuryStatus returns the latest treasury sample, or nil before the first one
+// or when no monitor runs (mock mode)
+func GetTreasuryStatus() *models.TreasuryStatus {
+	treasuryMu.RLock()
+	defer treasuryMu.RUnlock()
+	if treasuryStatus == nil {
+		return nil
+	}
+	status := *treasuryStatus
+	return &status
+}
+
+func setTreasuryStatus(status *models.TreasuryStatus) {
+	treasuryMu.Lock()
+	defer treasuryMu.Unlock()
+	treasuryStatus = status
+}
+
+func (t *TreasuryMonitor) sample(ctx context.Context) {
+	status := &models.TreasuryStatus{
+		Wallet:        t.client.Address().Hex(),
+		LowFundsMints: t.lowMints,
+		SampledAt:     time.Now(),
+	}
+	if err := t.measure(ctx, status); err != nil {
+		log.Printf("[Treasury] ⚠️ Failed to sample platform wallet: %v", err)
+		// Keep the last good figures, flagged as stale
+		if previous := GetTreasuryStatus(); previous != nil {
+			status = previous
+		}
+		status.Error = err.Error()
+		setTreasuryStatus(status)
+		return
+	}
+	setTreasuryStatus(status)
+
+	if status.LowFunds && !t.alerted {
+		t.alert(ctx, status)
+		t.alerted = true
+	} else if !status.LowFunds && t.alerted {
+		log.Printf("[Treasury] ✅ Platform wallet topped up: %d mints remaining", status.MintsRemaining)
+		t.alerted = false
+	}
+}
+
+// measure fills in the balance, fee and mint estimate. A mint is charged its full
+// gas limit at the fee cap, which is what the node requires the wallet to cover
+// before it accepts the transaction.
+func (t *TreasuryMonitor) measure(ctx context.Context, status *models.TreasuryStatus) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	balance, err := t.client.GetBalance()
+	if err != nil {
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+	_, feeCap, _, err := t.client.txm.fees(ctx)
+	if err != nil {
+		return err
+	}
+
+	gas := t.client.txm.config.GasLimit
+	if gas == 0 {
+		gas = defaultGasPerMint
+	}
+	costPerMint := new(big.Int).Mul(new(big.Int).SetUint64(gas), feeCap)
+	mints := new(big.Int).Div(balance, costPerMint)
+
+	status.BalanceWei = balance.String()
+	status.FeePerGasWei = feeCap.String()
+	status.GasPerMint = gas
+	status.MintsRemaining = mints.Int64()
+	if !mints.IsInt64() {
+		status.MintsRemaining = 1<<63 - 1
+	}
+	status.LowFunds = status.MintsRemaining < t.lowMints
+
+	treasuryBalance.Update(weiToUnit(balance, params.Ether))
+	treasuryFeePerGas.Update(weiToUnit(feeCap, params.GWei))
+	treasuryMintsRemaining.Update(status.MintsRemaining)
+	if status.LowFunds {
+		treasuryLowFunds.Update(1)
+	} else {
+		treasuryLowFunds.Update(0)
+	}
+	return nil
+}
+
+// alert notifies every admin that the platform wallet is running low
+func (t *TreasuryMonitor) alert(ctx context.Context, status *models.TreasuryStatus) {
+	log.Printf("[Treasury] 🚨 Platform wallet %s is low on funds: %d mints remaining (balance %s wei)", status.Wallet, status.MintsRemaining, status.BalanceWei)
+
+	admins, err := db.Repos.Users.ListByRole(ctx, "admin")
+	if err != nil {
+		log.Printf("[Treasury] Failed to list admins: %v", err)
+		return
+	}
+	body := fmt.Sprintf("The platform wallet %s covers about %d more mints at current gas prices. Top it up to keep minting.", status.Wallet, status.MintsRemaining)
+	for _, admin := range admins {
+		notification := &models.Notification{
+			ID:        "treasury_low_funds_" + admin.ID, // One per admin, so repeats overwrite
+			UserID:    admin.ID,
+			Title:     "Platform Wallet Low on Funds",
+			Body:      body,
+			Type:      "system",
+			IsRead:    false,
+			CreatedAt: status.SampledAt,
+		}
+		if err := db.Repos.Notifications.Save(ctx, notification); err != nil {
+			log.Printf("[Treasury] Failed to notify admin %s: %v", admin.ID, err)
+		}
+	}
+}
+
+// weiToUnit converts wei to a float in the given unit (params.Ether, params.GWei)
+func weiToUnit(wei *big.Int, unit float64) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(unit)).Float64()
+	return f
+}
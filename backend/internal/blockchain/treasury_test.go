@@ -0,0 +1,80 @@
+package blockchain
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+)
+
+func TestTreasuryMonitorAlertsBelowThreshold(t *testing.T) {
+	r := newTestRegistry(t)
+	ctx := context.Background()
+	if err := db.Repos.Users.Save(ctx, &models.User{ID: "admin", Role: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	const alertID = "treasury_low_funds_admin"
+	alert := func() *models.Notification {
+		t.Helper()
+		notifications, err := db.Repos.Notifications.ListByUser(ctx, "admin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range notifications {
+			if n.ID == alertID {
+				return &n
+			}
+		}
+		return nil
+	}
+
+	monitor := NewTreasuryMonitor(r.client, time.Hour, 0)
+	monitor.sample(ctx)
+	status := GetTreasuryStatus()
+	if status == nil || status.Error != "" || status.LowFunds {
+		t.Fatalf("status = %+v, want a funded wallet", status)
+	}
+	// The balance left after deploying, over the gas of a mint at the fee cap
+	balance, _ := new(big.Int).SetString(status.BalanceWei, 10)
+	fee, _ := new(big.Int).SetString(status.FeePerGasWei, 10)
+	want := new(big.Int).Div(balance, new(big.Int).Mul(big.NewInt(defaultGasPerMint), fee)).Int64()
+	if balance.Cmp(simulatedFunding) >= 0 || status.GasPerMint != defaultGasPerMint || status.MintsRemaining != want {
+		t.Errorf("status = %+v, want %d mints remaining", status, want)
+	}
+	if alert() != nil {
+		t.Error("admins alerted while above the threshold")
+	}
+
+	// Below the threshold: admins are alerted once per episode
+	monitor.lowMints = math.MaxInt64
+	monitor.sample(ctx)
+	if status := GetTreasuryStatus(); !status.LowFunds || treasuryLowFunds.Snapshot().Value() != 1 {
+		t.Fatalf("status = %+v, low funds gauge %d", status, treasuryLowFunds.Snapshot().Value())
+	}
+	if a := alert(); a == nil || a.IsRead {
+		t.Fatalf("alert = %+v, want an unread notification for the admin", a)
+	}
+	if err := db.Repos.Notifications.MarkRead(ctx, alertID); err != nil {
+		t.Fatal(err)
+	}
+	monitor.sample(ctx)
+	if !alert().IsRead {
+		t.Error("admins alerted again in the same low-funds episode")
+	}
+
+	// Topped up, then low again: the alert re-arms
+	monitor.lowMints = 0
+	monitor.sample(ctx)
+	if GetTreasuryStatus().LowFunds || treasuryLowFunds.Snapshot().Value() != 0 {
+		t.Error("still low on funds above the threshold")
+	}
+	monitor.lowMints = math.MaxInt64
+	monitor.sample(ctx)
+	if alert().IsRead {
+		t.Error("admins not alerted when funds ran low again")
+	}
+}
@@ -0,0 +1,395 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// txPollInterval is how often pending transactions are checked for receipts
+	txPollInterval = 5 * time.Second
+	// gasEstimateBuffer is the headroom added to estimated gas, in percent
+	gasEstimateBuffer = 20
+	// feeBumpPercent raises both fee caps when re-sending a stuck transaction.
+	// Nodes only accept a replacement that pays at least 10% more.
+	feeBumpPercent = 25
+)
+
+var (
+	// ErrFeeAboveMax is returned when the network base fee exceeds MAX_GAS_PRICE
+	ErrFeeAboveMax = errors.New("network fee exceeds the configured max gas price")
+	// ErrInsufficientFunds is returned when the platform wallet cannot pay for a transaction
+	ErrInsufficientFunds = errors.New("insufficient platform funds")
+)
+
+// TxConfig bounds the transactions the platform wallet sends
+type TxConfig struct {
+	ChainID     int64         // Chain the node must report; 0 skips the check
+	GasLimit    uint64        // Upper bound for estimated gas; 0 means no bound
+	MaxGasPrice *big.Int      // Cap on the fee per gas in wei; nil means no cap
+	BumpAfter   time.Duration // Re-send a pending transaction with higher fees after this long
+}
+
+// txManager sends transactions from the platform wallet without waiting for them
+// to be mined. It keeps the account nonce locally so transactions can be sent back
+// to back, persists every transaction, and a background loop records the outcome
+// and re-sends stuck transactions with higher fees.
+type txManager struct {
+	client  chainBackend
+	signer  Signer
+	from    common.Address
+	chainID *big.Int
+	config  TxConfig
+
+	mu          sync.Mutex // Serializes nonce allocation and broadcasts
+	nonce       uint64
+	nonceSynced bool
+}
+
+func newTxManager(client chainBackend, signer Signer, chainID *big.Int, config TxConfig) *txManager {
+	if config.BumpAfter <= 0 {
+		config.BumpAfter = 90 * time.Second
+	}
+	return &txManager{
+		client:  client,
+		signer:  signer,
+		from:    signer.Address(),
+		chainID: chainID,
+		config:  config,
+	}
+}
+
+// submit signs and broadcasts a call to `to` and returns the tracked transaction
+// as soon as the node accepts it. Calls that would revert fail here, during gas estimation.
+func (m *txManager) submit(action, reference string, to common.Address, data []byte) (*models.ChainTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	gas, err := m.estimateGas(ctx, to, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	tip, feeCap, legacy, err := m.fees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	// The node rejects a transaction unless the wallet covers gas * feeCap up front
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gas), feeCap)
+	balance, err := m.client.BalanceAt(ctx, m.from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	if balance.Cmp(cost) < 0 {
+		return nil, fmt.Errorf("failed to %s: %w: balance %s wei, need %s wei", action, ErrInsufficientFunds, balance, cost)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.nonceSynced {
+		nonce, err := m.client.PendingNonceAt(ctx, m.from)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nonce: %w", err)
+		}
+		m.nonce = nonce
+		m.nonceSynced = true
+	}
+
+	tx, err := m.sign(ctx, m.nonce, to, data, gas, tip, feeCap, legacy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := m.client.SendTransaction(ctx, tx); err != nil {
+		// The node may or may not have taken the nonce; re-read it next time
+		m.nonceSynced = false
+		if strings.Contains(err.Error(), "insufficient funds") {
+			// Transactions still pending had already committed part of the balance
+			return nil, fmt.Errorf("failed to %s: %w: %v", action, ErrInsufficientFunds, err)
+		}
+		return nil, fmt.Errorf("failed to %s: %w", action, err)
+	}
+	m.nonce++
+
+	now := time.Now()
+	record := &models.ChainTransaction{
+		ID:          tx.Hash().Hex(),
+		Action:      action,
+		Reference:   reference,
+		Status:      models.TxStatusPending,
+		Nonce:       tx.Nonce(),
+		To:          to.Hex(),
+		Data:        hexutil.Encode(data),
+		GasLimit:    gas,
+		Legacy:      legacy,
+		GasFeeCap:   feeCap.String(),
+		Hash:        tx.Hash().Hex(),
+		Hashes:      []string{tx.Hash().Hex()},
+		SubmittedAt: now,
+		BroadcastAt: now,
+		UpdatedAt:   now,
+	}
+	if tip != nil {
+		record.GasTipCap = tip.String()
+	}
+	if err := db.Repos.Transactions.Save(ctx, record); err != nil {
+		// The transaction is already out; the listener still picks up its events
+		log.Printf("⚠️ Failed to persist transaction %s: %v", record.ID, err)
+	}
+
+	log.Printf("🔗 Transaction sent (%s): %s", action, record.ID)
+	log.Printf("   Nonce: %d, Gas: %d, Max Fee: %s wei", record.Nonce, gas, feeCap.String())
+
+	return record, nil
+}
+
+// estimateGas estimates the call and adds headroom, bounded by the configured gas limit
+func (m *txManager) estimateGas(ctx context.Context, to common.Address, data []byte) (uint64, error) {
+	estimate, err := m.client.EstimateGas(ctx, ethereum.CallMsg{From: m.from, To: &to, Data: data})
+	if err != nil {
+		return 0, fmt.Errorf("gas estimation failed: %w", err)
+	}
+	if m.config.GasLimit > 0 && estimate > m.config.GasLimit {
+		return 0, fmt.Errorf("transaction needs %d gas, above the configured limit of %d", estimate, m.config.GasLimit)
+	}
+
+	gas := estimate * (100 + gasEstimateBuffer) / 100
+	if m.config.GasLimit > 0 && gas > m.config.GasLimit {
+		gas = m.config.GasLimit
+	}
+	return gas, nil
+}
+
+// fees returns the tip and fee cap for a new EIP-1559 transaction, or the gas price
+// (as feeCap, with legacy set) on chains without a base fee
+func (m *txManager) fees(ctx context.Context) (tip, feeCap *big.Int, legacy bool, err error) {
+	head, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	if head.BaseFee == nil {
+		price, err := m.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to get gas price: %w", err)
+		}
+		return nil, m.capFee(price), true, nil
+	}
+
+	tip, err = m.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to get gas tip: %w", err)
+	}
+
+	// Twice the base fee keeps the transaction valid through several full blocks
+	feeCap = m.capFee(new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip))
+	if feeCap.Cmp(head.BaseFee) < 0 {
+		return nil, nil, false, fmt.Errorf("%w: base fee %s wei, max %s wei", ErrFeeAboveMax, head.BaseFee, m.config.MaxGasPrice)
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip = new(big.Int).Set(feeCap)
+	}
+	return tip, feeCap, false, nil
+}
+
+// capFee limits a fee per gas to the configured maximum
+func (m *txManager) capFee(fee *big.Int) *big.Int {
+	if m.config.MaxGasPrice != nil && fee.Cmp(m.config.MaxGasPrice) > 0 {
+		return new(big.Int).Set(m.config.MaxGasPrice)
+	}
+	return fee
+}
+
+func (m *txManager) sign(ctx context.Context, nonce uint64, to common.Address, data []byte, gas uint64, tip, feeCap *big.Int, legacy bool) (*types.Transaction, error) {
+	if legacy {
+		return m.signer.SignTx(ctx, types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: feeCap,
+			Gas:      gas,
+			To:       &to,
+			Data:     data,
+		}), m.chainID)
+	}
+	return m.signer.SignTx(ctx, types.NewTx(&types.DynamicFeeTx{
+		ChainID:   m.chainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        &to,
+		Data:      data,
+	}), m.chainID)
+}
+
+// start watches pending transactions until ctx is cancelled. Transactions left
+// pending by a previous run are picked up from storage.
+func (m *txManager) start(ctx context.Context) {
+	ticker := time.NewTicker(txPollInterval)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				m.checkPending(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (m *txManager) checkPending(ctx context.Context) {
+	pending, err := db.Repos.Transactions.ListPending(ctx)
+	if err != nil {
+		log.Printf("⚠️ Failed to list pending transactions: %v", err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	// Read the mined nonce before the receipts: if a receipt is missing and the
+	// nonce was already used, the transaction can no longer be mined
+	minedNonce, err := m.client.NonceAt(ctx, m.from, nil)
+	if err != nil {
+		log.Printf("⚠️ Failed to get account nonce: %v", err)
+		return
+	}
+
+	for i := range pending {
+		m.check(ctx, &pending[i], minedNonce)
+	}
+}
+
+// check records the outcome of a pending transaction, or bumps its fees if it is stuck
+func (m *txManager) check(ctx context.Context, record *models.ChainTransaction, minedNonce uint64) {
+	// Any broadcast may be the one that got mined; the newest is the most likely
+	for i := len(record.Hashes) - 1; i >= 0; i-- {
+		receipt, err := m.client.TransactionReceipt(ctx, common.HexToHash(record.Hashes[i]))
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			log.Printf("⚠️ Failed to get receipt for %s: %v", record.Hashes[i], err)
+			return
+		}
+
+		record.Status = models.TxStatusMined
+		if receipt.Status == types.ReceiptStatusFailed {
+			record.Status = models.TxStatusFailed
+		}
+		record.Hash = record.Hashes[i]
+		record.BlockNumber = receipt.BlockNumber.Uint64()
+		record.GasUsed = receipt.GasUsed
+		m.save(ctx, record)
+
+		if record.Status == models.TxStatusFailed {
+			log.Printf("❌ Transaction reverted (%s): %s", record.Action, record.Hash)
+		} else {
+			log.Printf("✅ Transaction mined (%s): %s in block %d", record.Action, record.Hash, record.BlockNumber)
+		}
+		return
+	}
+
+	if minedNonce > record.Nonce {
+		record.Status = models.TxStatusDropped
+		m.save(ctx, record)
+		log.Printf("⚠️ Transaction dropped (%s): nonce %d was used by another transaction", record.Action, record.Nonce)
+		return
+	}
+
+	if time.Since(record.BroadcastAt) >= m.config.BumpAfter {
+		m.bump(ctx, record)
+	}
+}
+
+// bump re-sends a stuck transaction with the same nonce and higher fees
+func (m *txManager) bump(ctx context.Context, record *models.ChainTransaction) {
+	oldFeeCap, _ := new(big.Int).SetString(record.GasFeeCap, 10)
+	oldTip, _ := new(big.Int).SetString(record.GasTipCap, 10)
+	if oldFeeCap == nil {
+		log.Printf("⚠️ Cannot bump transaction %s: invalid stored fee", record.ID)
+		return
+	}
+
+	feeCap := m.capFee(raiseFee(oldFeeCap))
+	minFeeCap := new(big.Int).Div(new(big.Int).Mul(oldFeeCap, big.NewInt(110)), big.NewInt(100))
+	if feeCap.Cmp(minFeeCap) < 0 {
+		// Already at MAX_GAS_PRICE: nodes would reject the replacement, so wait for the network
+		return
+	}
+	var tip *big.Int
+	if !record.Legacy {
+		if oldTip == nil {
+			oldTip = new(big.Int)
+		}
+		tip = raiseFee(oldTip)
+		if tip.Cmp(feeCap) > 0 {
+			tip = new(big.Int).Set(feeCap)
+		}
+	}
+
+	data, err := hexutil.Decode(record.Data)
+	if err != nil {
+		log.Printf("⚠️ Cannot bump transaction %s: invalid stored calldata", record.ID)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx, err := m.sign(ctx, record.Nonce, common.HexToAddress(record.To), data, record.GasLimit, tip, feeCap, record.Legacy)
+	if err != nil {
+		log.Printf("⚠️ Failed to sign replacement for %s: %v", record.ID, err)
+		return
+	}
+	if err := m.client.SendTransaction(ctx, tx); err != nil {
+		// e.g. "nonce too low" when an earlier broadcast was just mined; the next check records it
+		log.Printf("⚠️ Failed to re-send transaction %s: %v", record.ID, err)
+		return
+	}
+
+	record.Hash = tx.Hash().Hex()
+	record.Hashes = append(record.Hashes, record.Hash)
+	record.GasFeeCap = feeCap.String()
+	if tip != nil {
+		record.GasTipCap = tip.String()
+	}
+	record.BroadcastAt = time.Now()
+	m.save(ctx, record)
+
+	log.Printf("⛽ Transaction bumped (%s): %s -> %s (max fee %s wei)", record.Action, record.ID, record.Hash, feeCap.String())
+}
+
+func (m *txManager) save(ctx context.Context, record *models.ChainTransaction) {
+	record.UpdatedAt = time.Now()
+	if err := db.Repos.Transactions.Save(ctx, record); err != nil {
+		log.Printf("⚠️ Failed to update transaction %s: %v", record.ID, err)
+	}
+}
+
+// raiseFee returns fee increased by feeBumpPercent, and by at least 1 wei
+func raiseFee(fee *big.Int) *big.Int {
+	raised := new(big.Int).Mul(fee, big.NewInt(100+feeBumpPercent))
+	raised.Div(raised, big.NewInt(100))
+	if raised.Cmp(fee) <= 0 {
+		raised.Add(fee, big.NewInt(1))
+	}
+	return raised
+}
@@ -1,14 +1,22 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"math/big"
 	"os"
 	"strconv"
+	"strings"
+
+	"cache-crew/cognify/internal/models"
 )
 
 type Config struct {
 	Port                    string
+	PublicURL               string // Base URL the API is reached at, for links in issued credentials
+	VerifyURL               string // Public verification page; certificate PDFs link to it by hash
 	FirebaseCredentials     string
 	FirebaseCredentialsPath string
 	GoogleProjectID         string
@@ -17,15 +25,62 @@ type Config struct {
 	GeminiAPIKey            string
 	ResendAPIKey            string
 
+	// Storage Configuration
+	StorageBackend string // "firestore", "memory", "sqlite" or "postgres"
+	DatabaseURL    string // SQLite file path or Postgres connection string
+
 	// Blockchain Configuration
-	BlockchainMode      string // "mock" or "real"
+	BlockchainMode      string // "mock", "real" or "simulated"
 	BlockchainRPC       string
 	ContractAddress     string
-	PrivateKeyEncrypted string
-	EncryptionPass      string
-	ChainID             int64
-	GasLimit            uint64
-	MaxGasPrice         *big.Int
+	ContractDeployBlock uint64 // First block worth scanning for registry events
+	SignerBackend       string // "env", "keystore" or "clef"
+	PrivateKeyEncrypted string // env signer
+	EncryptionPass      string // Unlocks the env or keystore key
+	KeystorePath        string // keystore signer: JSON key file
+	SignerEndpoint      string // clef signer: IPC socket path or URL
+	SignerAddress       string // clef signer: account to use (empty = first)
+	// Platform wallets (addresses or did:ethr DIDs) used before a key rotation; what
+	// they signed keeps verifying
+	PlatformPreviousAddresses []string
+	ChainID                   int64
+	GasLimit                  uint64
+	MaxGasPrice               *big.Int
+	TxBumpAfterSeconds        int64 // Re-send a pending transaction with higher fees after this long
+	BlockConfirmations        int64 // Blocks on top of an event before it is applied to storage
+
+	// Registry deployments. The active one supplies BlockchainRPC, ContractAddress,
+	// ChainID and ContractDeployBlock; retired ones are only read to verify old certificates.
+	DeploymentsFile string
+	Deployments     []models.Deployment
+
+	// Mock ledger (BLOCKCHAIN_MODE=mock, or as fallback)
+	MockLedgerPath string // Append-only file the mock chain is kept in; "memory" keeps it in memory
+
+	// Simulated chain (BLOCKCHAIN_MODE=simulated)
+	SimulatedBlockTime int64  // Seconds between sealed blocks
+	SimulatedRPCAddr   string // Optional host:port to serve the chain's JSON-RPC on
+
+	// Pending-mint reconciliation
+	ReconcileIntervalMinutes int64
+	PendingMintTTLHours      int64 // Pending certificates older than this are expired
+
+	// Platform wallet monitoring
+	TreasuryIntervalMinutes int64
+	TreasuryLowMints        int64 // Alert admins when the wallet covers fewer mints than this
+
+	// Certificate anchoring
+	AnchorMode            string // "direct" (one mint per certificate) or "merkle" (batched Merkle roots)
+	AnchorIntervalMinutes int64
+	AnchorMaxBatch        int64 // Most certificates per Merkle tree
+
+	// Minting
+	MintAuthorizationTTLMinutes int64 // How long a platform mint authorization stays valid
+	RelayDailyQuota             int64 // Gasless mints each issuer may relay per 24 hours; 0 disables the relayer
+
+	// SD-JWT VCs and Token Status Lists, which are JWS signed by the platform wallet.
+	// The clef signer can't sign JWS, so it needs them turned off.
+	SDJWTEnabled bool
 
 	// Platform Secret (for Academic DNA generation)
 	PlatformSecret string
@@ -44,15 +99,54 @@ func Load() {
 		GeminiAPIKey:            getEnv("GEMINI_API_KEY", ""),
 		ResendAPIKey:            getEnv("RESEND_API_KEY", ""),
 
+		// Storage
+		StorageBackend: getEnv("STORAGE_BACKEND", "firestore"), // Falls back to memory if Firestore is unavailable
+		DatabaseURL:    getEnv("DATABASE_URL", ""),
+
 		// Blockchain
 		BlockchainMode:      getEnv("BLOCKCHAIN_MODE", "mock"), // Default to mock
 		BlockchainRPC:       getEnv("BLOCKCHAIN_RPC_URL", ""),
 		ContractAddress:     getEnv("CONTRACT_ADDRESS", ""),
+		ContractDeployBlock: getEnvUint64("CONTRACT_DEPLOY_BLOCK", 0),
+		SignerBackend:       getEnv("SIGNER_BACKEND", "env"),
 		PrivateKeyEncrypted: getEnv("PRIVATE_KEY_ENCRYPTED", ""),
 		EncryptionPass:      getEnv("ENCRYPTION_PASSPHRASE", ""),
+		KeystorePath:        getEnv("KEYSTORE_PATH", ""),
+		SignerEndpoint:      getEnv("SIGNER_ENDPOINT", ""),
+		SignerAddress:       getEnv("SIGNER_ADDRESS", ""),
 		ChainID:             getEnvInt64("CHAIN_ID", 80001), // Default to Mumbai
 		GasLimit:            getEnvUint64("GAS_LIMIT", 300000),
+		TxBumpAfterSeconds:  getEnvInt64("TX_BUMP_AFTER_SECONDS", 90),
+		BlockConfirmations:  getEnvInt64("BLOCK_CONFIRMATIONS", 12),
+		DeploymentsFile:     getEnv("DEPLOYMENTS_FILE", ""),
 		PlatformSecret:      getEnv("PLATFORM_SECRET", "COGNIFY_PLATFORM_SECRET_V1"),
+
+		// Mock ledger
+		MockLedgerPath: getEnv("MOCK_LEDGER_PATH", "mock_ledger.jsonl"),
+
+		// Simulated chain
+		SimulatedBlockTime: getEnvInt64("SIMULATED_BLOCK_TIME", 2),
+		SimulatedRPCAddr:   getEnv("SIMULATED_RPC_ADDR", ""),
+
+		// Reconciliation
+		ReconcileIntervalMinutes: getEnvInt64("RECONCILE_INTERVAL_MINUTES", 10),
+		PendingMintTTLHours:      getEnvInt64("PENDING_MINT_TTL_HOURS", 72),
+
+		// Treasury
+		TreasuryIntervalMinutes: getEnvInt64("TREASURY_INTERVAL_MINUTES", 5),
+		TreasuryLowMints:        getEnvInt64("TREASURY_LOW_MINTS", 100),
+
+		// Anchoring
+		AnchorMode:            getEnv("ANCHOR_MODE", "direct"),
+		AnchorIntervalMinutes: getEnvInt64("ANCHOR_INTERVAL_MINUTES", 60),
+		AnchorMaxBatch:        getEnvInt64("ANCHOR_MAX_BATCH", 5000),
+
+		// Minting
+		MintAuthorizationTTLMinutes: getEnvInt64("MINT_AUTH_TTL_MINUTES", 60),
+		RelayDailyQuota:             getEnvInt64("RELAY_DAILY_QUOTA", 25),
+
+		// Selective disclosure
+		SDJWTEnabled: getEnvBool("SD_JWT_ENABLED", true),
 	}
 
 	// Parse max gas price
@@ -64,15 +158,88 @@ func Load() {
 		AppConfig.MaxGasPrice = big.NewInt(100000000000)
 	}
 
+	AppConfig.PlatformPreviousAddresses = getEnvList("PLATFORM_PREVIOUS_ADDRESSES")
+
+	AppConfig.PublicURL = strings.TrimSuffix(getEnv("PUBLIC_URL", "http://localhost:"+AppConfig.Port), "/")
+	AppConfig.VerifyURL = getEnv("VERIFY_URL", "https://verify.cognify.app/cert/")
+
+	if AppConfig.MockLedgerPath == "memory" {
+		AppConfig.MockLedgerPath = ""
+	}
+
+	if err := loadDeployments(); err != nil {
+		log.Fatalf("❌ Invalid deployments file %s: %v", AppConfig.DeploymentsFile, err)
+	}
+
 	log.Println("✅ Configuration loaded")
 	log.Printf("   Port: %s", AppConfig.Port)
+	log.Printf("   Storage Backend: %s", AppConfig.StorageBackend)
 	log.Printf("   Blockchain Mode: %s", AppConfig.BlockchainMode)
 	if AppConfig.BlockchainMode == "real" {
 		log.Printf("   Chain ID: %d", AppConfig.ChainID)
 		log.Printf("   Contract: %s", AppConfig.ContractAddress)
+		log.Printf("   Deployments: %d", len(AppConfig.Deployments))
 	}
 }
 
+// loadDeployments reads DEPLOYMENTS_FILE, a JSON array of models.Deployment with
+// exactly one active entry, and points the single-chain settings at the active one.
+// Without a file the single-chain settings form the only deployment.
+func loadDeployments() error {
+	if AppConfig.DeploymentsFile == "" {
+		if AppConfig.ContractAddress != "" {
+			AppConfig.Deployments = []models.Deployment{{
+				Name:            "default",
+				ChainID:         AppConfig.ChainID,
+				RPCURL:          AppConfig.BlockchainRPC,
+				ContractAddress: AppConfig.ContractAddress,
+				DeployBlock:     AppConfig.ContractDeployBlock,
+				Active:          true,
+			}}
+		}
+		return nil
+	}
+
+	raw, err := os.ReadFile(AppConfig.DeploymentsFile)
+	if err != nil {
+		return err
+	}
+	var deployments []models.Deployment
+	if err := json.Unmarshal(raw, &deployments); err != nil {
+		return err
+	}
+
+	var active *models.Deployment
+	seen := make(map[string]bool)
+	for i := range deployments {
+		d := &deployments[i]
+		key := fmt.Sprintf("%d:%s", d.ChainID, strings.ToLower(d.ContractAddress))
+		if d.ChainID == 0 || d.RPCURL == "" || d.ContractAddress == "" {
+			return fmt.Errorf("deployment %q needs chainId, rpcUrl and contractAddress", d.Name)
+		}
+		if seen[key] {
+			return fmt.Errorf("deployment %q is listed twice", d.Name)
+		}
+		seen[key] = true
+		if d.Active {
+			if active != nil {
+				return fmt.Errorf("deployments %q and %q are both active", active.Name, d.Name)
+			}
+			active = d
+		}
+	}
+	if active == nil {
+		return errors.New("no active deployment")
+	}
+
+	AppConfig.Deployments = deployments
+	AppConfig.BlockchainRPC = active.RPCURL
+	AppConfig.ContractAddress = active.ContractAddress
+	AppConfig.ChainID = active.ChainID
+	AppConfig.ContractDeployBlock = active.DeployBlock
+	return nil
+}
+
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -81,6 +248,17 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+// getEnvList splits a comma-separated variable, dropping empty entries
+func getEnvList(key string) []string {
+	var list []string
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
 func getEnvInt64(key string, defaultValue int64) int64 {
 	value := os.Getenv(key)
 	if value == "" {
@@ -93,6 +271,18 @@ func getEnvInt64(key string, defaultValue int64) int64 {
 	return intValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return boolValue
+}
+
 func getEnvUint64(key string, defaultValue uint64) uint64 {
 	value := os.Getenv(key)
 	if value == "" {
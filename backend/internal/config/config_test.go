@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDeployments(t *testing.T) {
+	const (
+		amoy   = `{"name":"amoy","chainId":80002,"rpcUrl":"https://amoy.example","contractAddress":"0x1111111111111111111111111111111111111111","deployBlock":100,"active":true}`
+		mumbai = `{"name":"mumbai","chainId":80001,"rpcUrl":"https://mumbai.example","contractAddress":"0x2222222222222222222222222222222222222222","deployBlock":5,"active":false}`
+	)
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"one active", "[" + amoy + "," + mumbai + "]", ""},
+		{"no active", "[" + mumbai + "]", "no active deployment"},
+		{"none", "[]", "no active deployment"},
+		{"two active", "[" + amoy + "," + strings.Replace(mumbai, `"active":false`, `"active":true`, 1) + "]", "both active"},
+		{"listed twice", "[" + amoy + "," + strings.Replace(amoy, `"active":true`, `"active":false`, 1) + "]", "listed twice"},
+		{"missing rpc", `[{"name":"bare","chainId":80002,"contractAddress":"0x1111111111111111111111111111111111111111","active":true}]`, "needs chainId, rpcUrl and contractAddress"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "deployments.json")
+			if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			AppConfig = Config{DeploymentsFile: path, ChainID: 1, BlockchainRPC: "https://previous.example"}
+
+			err := loadDeployments()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadDeployments error = %v, want %q", err, tt.wantErr)
+				}
+				if AppConfig.Deployments != nil || AppConfig.ChainID != 1 {
+					t.Errorf("a rejected file changed the config: %+v", AppConfig)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadDeployments: %v", err)
+			}
+			// The single-chain settings follow the active deployment
+			if len(AppConfig.Deployments) != 2 || AppConfig.ChainID != 80002 || AppConfig.BlockchainRPC != "https://amoy.example" ||
+				AppConfig.ContractAddress != "0x1111111111111111111111111111111111111111" || AppConfig.ContractDeployBlock != 100 {
+				t.Errorf("config = %+v, want the amoy deployment active", AppConfig)
+			}
+		})
+	}
+}
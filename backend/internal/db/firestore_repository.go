@@ -0,0 +1,1002 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"cache-crew/cognify/internal/models"
+)
+
+// NewFirestoreRepositories builds repositories backed by a Firestore client
+func NewFirestoreRepositories(client *firestore.Client) *Repositories {
+	return &Repositories{
+		Users:               &firestoreUsers{client},
+		Courses:             &firestoreCourses{client},
+		Questions:           &firestoreQuestions{client},
+		Enrollments:         &firestoreEnrollments{client},
+		Certificates:        &firestoreCertificates{client},
+		Posts:               &firestorePosts{client},
+		Comments:            &firestoreComments{client},
+		UserStats:           &firestoreUserStats{client},
+		Achievements:        &firestoreAchievements{client},
+		Notifications:       &firestoreNotifications{client},
+		VerificationLogs:    &firestoreVerificationLogs{client},
+		VerificationMetrics: &firestoreVerificationMetrics{client},
+		Trust:               &firestoreTrust{client},
+		Instructors:         &firestoreInstructors{client},
+		AuthNonces:          &firestoreAuthNonces{client},
+		SystemState:         &firestoreSystemState{client},
+		Transactions:        &firestoreTransactions{client},
+		Anchors:             &firestoreAnchors{client},
+		IssuerApplications:  &firestoreIssuerApplications{client},
+		RelayedMints:        &firestoreRelayedMints{client},
+		ImportedBadges:      &firestoreImportedBadges{client},
+		StatusLists:         &firestoreStatusLists{client},
+	}
+}
+
+// mapFirestoreError converts Firestore "not found" errors to ErrNotFound
+func mapFirestoreError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// getDoc reads a single document into dst
+func getDoc(ctx context.Context, ref *firestore.DocumentRef, dst interface{}) error {
+	doc, err := ref.Get(ctx)
+	if err != nil {
+		return mapFirestoreError(err)
+	}
+	return doc.DataTo(dst)
+}
+
+// queryAll runs a query and decodes every document, skipping malformed ones
+func queryAll[T any](ctx context.Context, q firestore.Query) ([]T, error) {
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var results []T
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var item T
+		if err := doc.DataTo(&item); err != nil {
+			continue
+		}
+		results = append(results, item)
+	}
+	return results, nil
+}
+
+// countQuery runs a COUNT aggregation on a query
+func countQuery(ctx context.Context, q firestore.Query) (int, error) {
+	results, err := q.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if val, ok := results["total"]; ok {
+		if v, ok := val.(interface{ GetIntegerValue() int64 }); ok {
+			return int(v.GetIntegerValue()), nil
+		}
+	}
+	return 0, nil
+}
+
+// -------------------------------------------------------------------
+// USERS
+// -------------------------------------------------------------------
+
+type firestoreUsers struct{ client *firestore.Client }
+
+func (r *firestoreUsers) col() *firestore.CollectionRef { return r.client.Collection("users") }
+
+func (r *firestoreUsers) Get(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	if err := getDoc(ctx, r.col().Doc(id), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *firestoreUsers) GetByWallet(ctx context.Context, wallet string) (*models.User, error) {
+	wallet = strings.ToLower(wallet)
+	users, err := queryAll[models.User](ctx, r.col().Where("wallet_address", "==", wallet).Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		// Older documents stored the wallet under the JSON field name
+		users, err = queryAll[models.User](ctx, r.col().Where("walletAddress", "==", wallet).Limit(1))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(users) == 0 {
+		return nil, ErrNotFound
+	}
+	return &users[0], nil
+}
+
+func (r *firestoreUsers) Save(ctx context.Context, user *models.User) error {
+	_, err := r.col().Doc(user.ID).Set(ctx, user)
+	return err
+}
+
+func (r *firestoreUsers) UpdateProfile(ctx context.Context, id string, update UserProfileUpdate) error {
+	updates := map[string]interface{}{
+		"updatedAt": time.Now(),
+	}
+	if update.Name != "" {
+		updates["name"] = update.Name
+	}
+	if update.Username != "" {
+		updates["username"] = update.Username
+	}
+	if update.Bio != "" {
+		updates["bio"] = update.Bio
+	}
+	if update.AvatarEmoji != "" {
+		updates["avatarEmoji"] = update.AvatarEmoji
+	}
+	if update.Institution != "" {
+		updates["institution"] = update.Institution
+	}
+	_, err := r.col().Doc(id).Set(ctx, updates, firestore.MergeAll)
+	return err
+}
+
+func (r *firestoreUsers) SetPassword(ctx context.Context, id, passwordHash string) error {
+	_, err := r.col().Doc(id).Update(ctx, []firestore.Update{
+		{Path: "password", Value: passwordHash},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreUsers) SetProgress(ctx context.Context, id string, xp, level int) error {
+	_, err := r.col().Doc(id).Update(ctx, []firestore.Update{
+		{Path: "xp", Value: xp},
+		{Path: "level", Value: level},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreUsers) SetWallet(ctx context.Context, id, wallet string) error {
+	_, err := r.col().Doc(id).Update(ctx, []firestore.Update{
+		{Path: "wallet_address", Value: strings.ToLower(wallet)},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreUsers) SetAuthorization(ctx context.Context, wallet string, authorized bool) error {
+	iter := r.col().Where("wallet_address", "==", strings.ToLower(wallet)).Documents(ctx)
+	defer iter.Stop()
+
+	updates := map[string]interface{}{"is_authorized": authorized}
+	if authorized {
+		updates["role"] = "instructor"
+	}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := doc.Ref.Set(ctx, updates, firestore.MergeAll); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *firestoreUsers) ListByRole(ctx context.Context, role string) ([]models.User, error) {
+	return queryAll[models.User](ctx, r.col().Where("role", "==", role))
+}
+
+// -------------------------------------------------------------------
+// COURSES & QUESTIONS
+// -------------------------------------------------------------------
+
+type firestoreCourses struct{ client *firestore.Client }
+
+func (r *firestoreCourses) col() *firestore.CollectionRef { return r.client.Collection("courses") }
+
+func (r *firestoreCourses) List(ctx context.Context) ([]models.Course, error) {
+	return queryAll[models.Course](ctx, r.col().Query)
+}
+
+func (r *firestoreCourses) ListByInstructor(ctx context.Context, instructorID string) ([]models.Course, error) {
+	return queryAll[models.Course](ctx, r.col().Where("instructorId", "==", instructorID))
+}
+
+func (r *firestoreCourses) Get(ctx context.Context, id string) (*models.Course, error) {
+	var course models.Course
+	if err := getDoc(ctx, r.col().Doc(id), &course); err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (r *firestoreCourses) Save(ctx context.Context, course *models.Course) error {
+	_, err := r.col().Doc(course.ID).Set(ctx, course)
+	return err
+}
+
+type firestoreQuestions struct{ client *firestore.Client }
+
+func (r *firestoreQuestions) col() *firestore.CollectionRef { return r.client.Collection("questions") }
+
+func (r *firestoreQuestions) List(ctx context.Context, limit int) ([]models.Question, error) {
+	q := r.col().Query
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	return queryAll[models.Question](ctx, q)
+}
+
+func (r *firestoreQuestions) Get(ctx context.Context, id string) (*models.Question, error) {
+	var q models.Question
+	if err := getDoc(ctx, r.col().Doc(id), &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *firestoreQuestions) Save(ctx context.Context, question *models.Question) error {
+	_, err := r.col().Doc(question.ID).Set(ctx, question)
+	return err
+}
+
+// -------------------------------------------------------------------
+// ENROLLMENTS
+// -------------------------------------------------------------------
+
+type firestoreEnrollments struct{ client *firestore.Client }
+
+func (r *firestoreEnrollments) col() *firestore.CollectionRef {
+	return r.client.Collection("enrollments")
+}
+
+func (r *firestoreEnrollments) Get(ctx context.Context, id string) (*models.Enrollment, error) {
+	var e models.Enrollment
+	if err := getDoc(ctx, r.col().Doc(id), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *firestoreEnrollments) ListByUser(ctx context.Context, userID string) ([]models.Enrollment, error) {
+	return queryAll[models.Enrollment](ctx, r.col().Where("userId", "==", userID))
+}
+
+func (r *firestoreEnrollments) Save(ctx context.Context, enrollment *models.Enrollment) error {
+	_, err := r.col().Doc(enrollment.ID).Set(ctx, enrollment)
+	return err
+}
+
+// -------------------------------------------------------------------
+// CERTIFICATES
+// -------------------------------------------------------------------
+
+type firestoreCertificates struct{ client *firestore.Client }
+
+func (r *firestoreCertificates) col() *firestore.CollectionRef {
+	return r.client.Collection("certificates")
+}
+
+func (r *firestoreCertificates) Get(ctx context.Context, hash string) (*models.Certificate, error) {
+	var cert models.Certificate
+	if err := getDoc(ctx, r.col().Doc(hash), &cert); err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+func (r *firestoreCertificates) Save(ctx context.Context, cert *models.Certificate) error {
+	_, err := r.col().Doc(cert.Hash).Set(ctx, cert)
+	return err
+}
+
+func (r *firestoreCertificates) ListByWallet(ctx context.Context, wallet string) ([]models.Certificate, error) {
+	return queryAll[models.Certificate](ctx, r.col().Where("wallet_address", "==", wallet))
+}
+
+func (r *firestoreCertificates) ListByInstructor(ctx context.Context, instructorWallet string) ([]models.Certificate, error) {
+	return queryAll[models.Certificate](ctx, r.col().
+		Where("instructor_wallet", "==", instructorWallet).
+		OrderBy("issuedAt", firestore.Desc))
+}
+
+func (r *firestoreCertificates) Count(ctx context.Context) (int, error) {
+	// Select() with no fields gives a Query we can aggregate on
+	return countQuery(ctx, r.col().Select())
+}
+
+func (r *firestoreCertificates) CountBelowTrustScore(ctx context.Context, score int) (int, error) {
+	return countQuery(ctx, r.col().Where("trust_score", "<", score))
+}
+
+func (r *firestoreCertificates) MarkMinted(ctx context.Context, hash, txHash string, block uint64, chain models.ChainRef) error {
+	_, err := r.col().Doc(hash).Set(ctx, map[string]interface{}{
+		"hash":              hash,
+		"is_minted":         true,
+		"blockchain_tx":     txHash,
+		"block_number":      block,
+		"chain":             chain,
+		"minted_at":         time.Now(),
+		"revoked":           false,
+		"mint_expired":      false,
+		"mint_unauthorized": false,
+		"mint_rejection":    "",
+	}, firestore.MergeAll)
+	return err
+}
+
+func (r *firestoreCertificates) MarkMintUnauthorized(ctx context.Context, hash, reason string) error {
+	_, err := r.col().Doc(hash).Update(ctx, []firestore.Update{
+		{Path: "mint_unauthorized", Value: true},
+		{Path: "mint_rejection", Value: reason},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreCertificates) MarkRevoked(ctx context.Context, hash, revokedBy, reason string, revokedAt time.Time) error {
+	_, err := r.col().Doc(hash).Set(ctx, map[string]interface{}{
+		"hash":               hash,
+		"revoked":            true,
+		"revoked_at":         revokedAt,
+		"revoked_by":         revokedBy,
+		"revocation_reason":  reason,
+		"pending_revocation": firestore.Delete,
+	}, firestore.MergeAll)
+	return err
+}
+
+func (r *firestoreCertificates) MarkRevocationPending(ctx context.Context, hash string, pending models.PendingRevocation) error {
+	_, err := r.col().Doc(hash).Update(ctx, []firestore.Update{
+		{Path: "pending_revocation", Value: pending},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreCertificates) ClearRevocationPending(ctx context.Context, hash string) error {
+	_, err := r.col().Doc(hash).Update(ctx, []firestore.Update{
+		{Path: "pending_revocation", Value: firestore.Delete},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreCertificates) ListRevocationPending(ctx context.Context, limit int) ([]models.Certificate, error) {
+	// Ordering by a field leaves out documents without it, i.e. those with nothing pending
+	q := r.col().OrderBy("pending_revocation.requested_at", firestore.Asc)
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	return queryAll[models.Certificate](ctx, q)
+}
+
+func (r *firestoreCertificates) UnmarkMinted(ctx context.Context, hash string) error {
+	_, err := r.col().Doc(hash).Update(ctx, []firestore.Update{
+		{Path: "is_minted", Value: false},
+		{Path: "blockchain_tx", Value: ""},
+		{Path: "block_number", Value: firestore.Delete},
+		{Path: "chain", Value: firestore.Delete},
+		{Path: "minted_at", Value: firestore.Delete},
+		{Path: "mint_unauthorized", Value: false},
+		{Path: "mint_rejection", Value: firestore.Delete},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreCertificates) ClearRevocation(ctx context.Context, hash string) error {
+	_, err := r.col().Doc(hash).Update(ctx, []firestore.Update{
+		{Path: "revoked", Value: false},
+		{Path: "revoked_at", Value: firestore.Delete},
+		{Path: "revoked_by", Value: firestore.Delete},
+		{Path: "revocation_reason", Value: firestore.Delete},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreCertificates) ListPending(ctx context.Context, limit int) ([]models.Certificate, error) {
+	// Expired and anchored certificates may lack the fields entirely, so they are filtered here instead of in the query
+	q := r.col().Where("is_minted", "==", false).Where("revoked", "==", false)
+	certs, err := queryAll[models.Certificate](ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]models.Certificate, 0, len(certs))
+	for _, c := range certs {
+		if !c.MintExpired && c.AnchorRoot == "" {
+			pending = append(pending, c)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].IssuedAt.Before(pending[j].IssuedAt) })
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+func (r *firestoreCertificates) MarkExpired(ctx context.Context, hash string, expiredAt time.Time) error {
+	_, err := r.col().Doc(hash).Update(ctx, []firestore.Update{
+		{Path: "mint_expired", Value: true},
+		{Path: "mint_expired_at", Value: expiredAt},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreCertificates) MarkAnchored(ctx context.Context, hash, root string, proof []string, chain models.ChainRef) error {
+	_, err := r.col().Doc(hash).Update(ctx, []firestore.Update{
+		{Path: "anchor_root", Value: root},
+		{Path: "merkle_proof", Value: proof},
+		{Path: "chain", Value: chain},
+	})
+	return mapFirestoreError(err)
+}
+
+// -------------------------------------------------------------------
+// FORUM
+// -------------------------------------------------------------------
+
+type firestorePosts struct{ client *firestore.Client }
+
+func (r *firestorePosts) col() *firestore.CollectionRef { return r.client.Collection("posts") }
+
+func (r *firestorePosts) List(ctx context.Context, courseID string) ([]models.Post, error) {
+	q := r.col().OrderBy("createdAt", firestore.Asc)
+	if courseID != "" {
+		q = r.col().Where("courseId", "==", courseID).OrderBy("createdAt", firestore.Asc)
+	}
+	return queryAll[models.Post](ctx, q)
+}
+
+func (r *firestorePosts) Get(ctx context.Context, id string) (*models.Post, error) {
+	var post models.Post
+	if err := getDoc(ctx, r.col().Doc(id), &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (r *firestorePosts) Save(ctx context.Context, post *models.Post) error {
+	_, err := r.col().Doc(post.ID).Set(ctx, post)
+	return err
+}
+
+func (r *firestorePosts) IncrementCommentCount(ctx context.Context, id string) error {
+	_, err := r.col().Doc(id).Update(ctx, []firestore.Update{
+		{Path: "commentCount", Value: firestore.Increment(1)},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestorePosts) IncrementViewCount(ctx context.Context, id string) error {
+	_, err := r.col().Doc(id).Update(ctx, []firestore.Update{
+		{Path: "viewCount", Value: firestore.Increment(1)},
+	})
+	return mapFirestoreError(err)
+}
+
+type firestoreComments struct{ client *firestore.Client }
+
+func (r *firestoreComments) col() *firestore.CollectionRef { return r.client.Collection("comments") }
+
+func (r *firestoreComments) ListByPost(ctx context.Context, postID string) ([]models.Comment, error) {
+	// Query without OrderBy to avoid requiring a composite index, sort in memory
+	comments, err := queryAll[models.Comment](ctx, r.col().Where("postId", "==", postID))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+	return comments, nil
+}
+
+func (r *firestoreComments) Get(ctx context.Context, id string) (*models.Comment, error) {
+	var comment models.Comment
+	if err := getDoc(ctx, r.col().Doc(id), &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *firestoreComments) Save(ctx context.Context, comment *models.Comment) error {
+	_, err := r.col().Doc(comment.ID).Set(ctx, comment)
+	return err
+}
+
+// -------------------------------------------------------------------
+// GAMIFICATION
+// -------------------------------------------------------------------
+
+type firestoreUserStats struct{ client *firestore.Client }
+
+func (r *firestoreUserStats) col() *firestore.CollectionRef { return r.client.Collection("user_stats") }
+
+func (r *firestoreUserStats) Get(ctx context.Context, userID string) (*models.UserStats, error) {
+	var stats models.UserStats
+	if err := getDoc(ctx, r.col().Doc(userID), &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (r *firestoreUserStats) Save(ctx context.Context, stats *models.UserStats) error {
+	_, err := r.col().Doc(stats.UserID).Set(ctx, stats)
+	return err
+}
+
+func (r *firestoreUserStats) Increment(ctx context.Context, userID, counter string, delta int) error {
+	_, err := r.col().Doc(userID).Update(ctx, []firestore.Update{
+		{Path: counter, Value: firestore.Increment(delta)},
+	})
+	return mapFirestoreError(err)
+}
+
+func (r *firestoreUserStats) ListByXP(ctx context.Context, limit int) ([]models.UserStats, error) {
+	q := r.col().OrderBy("totalXp", firestore.Desc)
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	return queryAll[models.UserStats](ctx, q)
+}
+
+func (r *firestoreUserStats) SetGlobalRank(ctx context.Context, userID string, rank int) error {
+	_, err := r.col().Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "globalRank", Value: rank},
+	})
+	return mapFirestoreError(err)
+}
+
+type firestoreAchievements struct{ client *firestore.Client }
+
+func (r *firestoreAchievements) col() *firestore.CollectionRef {
+	return r.client.Collection("user_achievements")
+}
+
+func (r *firestoreAchievements) ListByUser(ctx context.Context, userID string) ([]models.UserAchievement, error) {
+	return queryAll[models.UserAchievement](ctx, r.col().Where("userId", "==", userID))
+}
+
+func (r *firestoreAchievements) Save(ctx context.Context, achievement *models.UserAchievement) error {
+	_, err := r.col().Doc(achievement.ID).Set(ctx, achievement)
+	return err
+}
+
+// -------------------------------------------------------------------
+// NOTIFICATIONS
+// -------------------------------------------------------------------
+
+type firestoreNotifications struct{ client *firestore.Client }
+
+func (r *firestoreNotifications) col() *firestore.CollectionRef {
+	return r.client.Collection("notifications")
+}
+
+func (r *firestoreNotifications) ListByUser(ctx context.Context, userID string) ([]models.Notification, error) {
+	return queryAll[models.Notification](ctx, r.col().Where("userId", "==", userID))
+}
+
+func (r *firestoreNotifications) Save(ctx context.Context, notification *models.Notification) error {
+	_, err := r.col().Doc(notification.ID).Set(ctx, notification)
+	return err
+}
+
+func (r *firestoreNotifications) MarkRead(ctx context.Context, id string) error {
+	_, err := r.col().Doc(id).Update(ctx, []firestore.Update{
+		{Path: "isRead", Value: true},
+	})
+	return mapFirestoreError(err)
+}
+
+// -------------------------------------------------------------------
+// VERIFICATION & TRUST
+// -------------------------------------------------------------------
+
+type firestoreVerificationLogs struct{ client *firestore.Client }
+
+func (r *firestoreVerificationLogs) col() *firestore.CollectionRef {
+	return r.client.Collection("verification_logs")
+}
+
+func (r *firestoreVerificationLogs) Add(ctx context.Context, entry *models.VerificationLog) error {
+	_, err := r.col().NewDoc().Set(ctx, entry)
+	return err
+}
+
+func (r *firestoreVerificationLogs) CountByResult(ctx context.Context, verified bool) (int, error) {
+	return countQuery(ctx, r.col().Where("verified", "==", verified))
+}
+
+func (r *firestoreVerificationLogs) CountByCertificate(ctx context.Context, certHash string) (int, error) {
+	return countQuery(ctx, r.col().Where("certificate_hash", "==", certHash))
+}
+
+type firestoreVerificationMetrics struct{ client *firestore.Client }
+
+func (r *firestoreVerificationMetrics) col() *firestore.CollectionRef {
+	return r.client.Collection("verification_metrics")
+}
+
+func (r *firestoreVerificationMetrics) Get(ctx context.Context, certHash string) (*models.VerificationMetric, error) {
+	var metric models.VerificationMetric
+	if err := getDoc(ctx, r.col().Doc(certHash), &metric); err != nil {
+		return nil, err
+	}
+	return &metric, nil
+}
+
+func (r *firestoreVerificationMetrics) Update(ctx context.Context, certHash string, fn func(*models.VerificationMetric) (*models.VerificationMetric, error)) error {
+	docRef := r.col().Doc(certHash)
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var current *models.VerificationMetric
+		doc, err := tx.Get(docRef)
+		if err == nil {
+			var metric models.VerificationMetric
+			if err := doc.DataTo(&metric); err != nil {
+				return err
+			}
+			current = &metric
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		updated, err := fn(current)
+		if err != nil {
+			return err
+		}
+		return tx.Set(docRef, updated)
+	})
+}
+
+type firestoreTrust struct{ client *firestore.Client }
+
+func (r *firestoreTrust) GetIssuerReputation(ctx context.Context, wallet string) (*models.IssuerReputation, error) {
+	var rep models.IssuerReputation
+	if err := getDoc(ctx, r.client.Collection("issuer_reputation").Doc(wallet), &rep); err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
+
+func (r *firestoreTrust) SaveIssuerReputation(ctx context.Context, rep *models.IssuerReputation) error {
+	_, err := r.client.Collection("issuer_reputation").Doc(rep.InstructorID).Set(ctx, rep)
+	return err
+}
+
+func (r *firestoreTrust) AddHistory(ctx context.Context, event *models.TrustHistoryEvent) error {
+	_, _, err := r.client.Collection("trust_history").Add(ctx, event)
+	return err
+}
+
+// -------------------------------------------------------------------
+// INSTRUCTOR DASHBOARD
+// -------------------------------------------------------------------
+
+type firestoreInstructors struct{ client *firestore.Client }
+
+func (r *firestoreInstructors) GetStats(ctx context.Context, instructorID string) (*models.InstructorStats, error) {
+	var stats models.InstructorStats
+	if err := getDoc(ctx, r.client.Collection("instructor_stats").Doc(instructorID), &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (r *firestoreInstructors) SaveStats(ctx context.Context, stats *models.InstructorStats) error {
+	_, err := r.client.Collection("instructor_stats").Doc(stats.InstructorID).Set(ctx, stats)
+	return err
+}
+
+func (r *firestoreInstructors) GetActivities(ctx context.Context, instructorID string) ([]models.ActivityItem, error) {
+	var wrapper struct {
+		Items []models.ActivityItem `firestore:"items"`
+	}
+	if err := getDoc(ctx, r.client.Collection("instructor_activities").Doc(instructorID), &wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper.Items, nil
+}
+
+func (r *firestoreInstructors) SaveActivities(ctx context.Context, instructorID string, items []models.ActivityItem) error {
+	_, err := r.client.Collection("instructor_activities").Doc(instructorID).Set(ctx, map[string]interface{}{
+		"items": items,
+	})
+	return err
+}
+
+func (r *firestoreInstructors) GetAnalytics(ctx context.Context, instructorID string) (*models.InstructorAnalytics, error) {
+	var analytics models.InstructorAnalytics
+	if err := getDoc(ctx, r.client.Collection("instructor_analytics").Doc(instructorID), &analytics); err != nil {
+		return nil, err
+	}
+	return &analytics, nil
+}
+
+func (r *firestoreInstructors) SaveAnalytics(ctx context.Context, analytics *models.InstructorAnalytics) error {
+	_, err := r.client.Collection("instructor_analytics").Doc(analytics.InstructorID).Set(ctx, analytics)
+	return err
+}
+
+// -------------------------------------------------------------------
+// AUTH NONCES & SYSTEM STATE
+// -------------------------------------------------------------------
+
+type firestoreAuthNonces struct{ client *firestore.Client }
+
+func (r *firestoreAuthNonces) col() *firestore.CollectionRef {
+	return r.client.Collection("auth_nonces")
+}
+
+func (r *firestoreAuthNonces) Get(ctx context.Context, wallet string) (*models.AuthNonce, error) {
+	var nonce models.AuthNonce
+	if err := getDoc(ctx, r.col().Doc(strings.ToLower(wallet)), &nonce); err != nil {
+		return nil, err
+	}
+	return &nonce, nil
+}
+
+func (r *firestoreAuthNonces) Save(ctx context.Context, wallet string, nonce *models.AuthNonce) error {
+	_, err := r.col().Doc(strings.ToLower(wallet)).Set(ctx, nonce)
+	return err
+}
+
+func (r *firestoreAuthNonces) Delete(ctx context.Context, wallet string) error {
+	_, err := r.col().Doc(strings.ToLower(wallet)).Delete(ctx)
+	return err
+}
+
+type firestoreSystemState struct{ client *firestore.Client }
+
+func (r *firestoreSystemState) GetSyncState(ctx context.Context) (*models.SystemState, error) {
+	var state models.SystemState
+	if err := getDoc(ctx, r.client.Collection("system_state").Doc("sync_state"), &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *firestoreSystemState) SaveSyncState(ctx context.Context, state *models.SystemState) error {
+	state.ID = "sync_state"
+	state.UpdatedAt = time.Now()
+	_, err := r.client.Collection("system_state").Doc(state.ID).Set(ctx, state)
+	return err
+}
+
+func (r *firestoreSystemState) GetReconciliationReport(ctx context.Context) (*models.ReconciliationReport, error) {
+	var report models.ReconciliationReport
+	if err := getDoc(ctx, r.client.Collection("system_state").Doc("reconciliation"), &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *firestoreSystemState) SaveReconciliationReport(ctx context.Context, report *models.ReconciliationReport) error {
+	report.ID = "reconciliation"
+	_, err := r.client.Collection("system_state").Doc(report.ID).Set(ctx, report)
+	return err
+}
+
+func (r *firestoreSystemState) GetBackfillJob(ctx context.Context) (*models.BackfillJob, error) {
+	var job models.BackfillJob
+	if err := getDoc(ctx, r.client.Collection("system_state").Doc("backfill"), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *firestoreSystemState) SaveBackfillJob(ctx context.Context, job *models.BackfillJob) error {
+	job.ID = "backfill"
+	_, err := r.client.Collection("system_state").Doc(job.ID).Set(ctx, job)
+	return err
+}
+
+type firestoreTransactions struct{ client *firestore.Client }
+
+func (r *firestoreTransactions) col() *firestore.CollectionRef {
+	return r.client.Collection("chain_transactions")
+}
+
+func (r *firestoreTransactions) Get(ctx context.Context, id string) (*models.ChainTransaction, error) {
+	var tx models.ChainTransaction
+	if err := getDoc(ctx, r.col().Doc(id), &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+func (r *firestoreTransactions) Save(ctx context.Context, tx *models.ChainTransaction) error {
+	_, err := r.col().Doc(tx.ID).Set(ctx, tx)
+	return err
+}
+
+func (r *firestoreTransactions) ListPending(ctx context.Context) ([]models.ChainTransaction, error) {
+	txs, err := queryAll[models.ChainTransaction](ctx, r.col().Where("status", "==", models.TxStatusPending))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].Nonce < txs[j].Nonce })
+	return txs, nil
+}
+
+type firestoreAnchors struct{ client *firestore.Client }
+
+func (r *firestoreAnchors) col() *firestore.CollectionRef {
+	return r.client.Collection("anchor_batches")
+}
+
+func (r *firestoreAnchors) Get(ctx context.Context, root string) (*models.AnchorBatch, error) {
+	var batch models.AnchorBatch
+	if err := getDoc(ctx, r.col().Doc(root), &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *firestoreAnchors) Save(ctx context.Context, batch *models.AnchorBatch) error {
+	_, err := r.col().Doc(batch.ID).Set(ctx, batch)
+	return err
+}
+
+func (r *firestoreAnchors) ListPending(ctx context.Context) ([]models.AnchorBatch, error) {
+	batches, err := queryAll[models.AnchorBatch](ctx, r.col().Where("status", "==", models.AnchorStatusPending))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].CreatedAt.Before(batches[j].CreatedAt) })
+	return batches, nil
+}
+
+type firestoreIssuerApplications struct{ client *firestore.Client }
+
+func (r *firestoreIssuerApplications) col() *firestore.CollectionRef {
+	return r.client.Collection("issuer_applications")
+}
+
+func (r *firestoreIssuerApplications) Get(ctx context.Context, wallet string) (*models.IssuerApplication, error) {
+	var app models.IssuerApplication
+	if err := getDoc(ctx, r.col().Doc(strings.ToLower(wallet)), &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+func (r *firestoreIssuerApplications) Save(ctx context.Context, app *models.IssuerApplication) error {
+	app.ID = strings.ToLower(app.Wallet)
+	_, err := r.col().Doc(app.ID).Set(ctx, app)
+	return err
+}
+
+func (r *firestoreIssuerApplications) List(ctx context.Context, status string) ([]models.IssuerApplication, error) {
+	query := r.col().Query
+	if status != "" {
+		query = query.Where("status", "==", status)
+	}
+	apps, err := queryAll[models.IssuerApplication](ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].SubmittedAt.Before(apps[j].SubmittedAt) })
+	return apps, nil
+}
+
+type firestoreRelayedMints struct{ client *firestore.Client }
+
+func (r *firestoreRelayedMints) col() *firestore.CollectionRef {
+	return r.client.Collection("relayed_mints")
+}
+
+func (r *firestoreRelayedMints) Get(ctx context.Context, certHash string) (*models.RelayedMint, error) {
+	var relay models.RelayedMint
+	if err := getDoc(ctx, r.col().Doc(certHash), &relay); err != nil {
+		return nil, err
+	}
+	return &relay, nil
+}
+
+func (r *firestoreRelayedMints) Save(ctx context.Context, relay *models.RelayedMint) error {
+	_, err := r.col().Doc(relay.ID).Set(ctx, relay)
+	return err
+}
+
+func (r *firestoreRelayedMints) ListByInstructor(ctx context.Context, instructorWallet string) ([]models.RelayedMint, error) {
+	relays, err := queryAll[models.RelayedMint](ctx, r.col().Where("instructor_wallet", "==", instructorWallet))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(relays, func(i, j int) bool { return relays[i].CreatedAt.After(relays[j].CreatedAt) })
+	return relays, nil
+}
+
+func (r *firestoreRelayedMints) CountByInstructorSince(ctx context.Context, instructorWallet string, since time.Time) (int, error) {
+	relays, err := queryAll[models.RelayedMint](ctx, r.col().
+		Where("instructor_wallet", "==", instructorWallet).
+		Where("created_at", ">", since))
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, relay := range relays {
+		if relay.Status != models.RelayStatusFailed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type firestoreImportedBadges struct{ client *firestore.Client }
+
+func (r *firestoreImportedBadges) col() *firestore.CollectionRef {
+	return r.client.Collection("imported_badges")
+}
+
+func (r *firestoreImportedBadges) Save(ctx context.Context, badge *models.ImportedBadge) error {
+	_, err := r.col().Doc(badge.ID).Set(ctx, badge)
+	return err
+}
+
+func (r *firestoreImportedBadges) ListByUser(ctx context.Context, userID string) ([]models.ImportedBadge, error) {
+	badges, err := queryAll[models.ImportedBadge](ctx, r.col().Where("user_id", "==", userID))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(badges, func(i, j int) bool { return badges[i].ImportedAt.After(badges[j].ImportedAt) })
+	return badges, nil
+}
+
+type firestoreStatusLists struct{ client *firestore.Client }
+
+func (r *firestoreStatusLists) col() *firestore.CollectionRef {
+	return r.client.Collection("status_lists")
+}
+
+func (r *firestoreStatusLists) Get(ctx context.Context, id string) (*models.StatusList, error) {
+	var list models.StatusList
+	if err := getDoc(ctx, r.col().Doc(id), &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *firestoreStatusLists) Update(ctx context.Context, id string, fn func(*models.StatusList) (*models.StatusList, error)) error {
+	docRef := r.col().Doc(id)
+	return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var current *models.StatusList
+		doc, err := tx.Get(docRef)
+		if err == nil {
+			var list models.StatusList
+			if err := doc.DataTo(&list); err != nil {
+				return err
+			}
+			current = &list
+		} else if status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		updated, err := fn(current)
+		if err != nil {
+			return err
+		}
+		return tx.Set(docRef, updated)
+	})
+}
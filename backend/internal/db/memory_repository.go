@@ -0,0 +1,920 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cache-crew/cognify/internal/models"
+)
+
+// memoryCollection is a mutex-guarded map of documents keyed by ID.
+// Documents are deep-copied on the way in and out so callers never share state,
+// not even the maps, slices and pointers inside them.
+type memoryCollection[T any] struct {
+	mu   sync.RWMutex
+	docs map[string]T
+}
+
+func newMemoryCollection[T any]() *memoryCollection[T] {
+	return &memoryCollection[T]{docs: make(map[string]T)}
+}
+
+func (c *memoryCollection[T]) get(id string) (*T, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	doc, ok := c.docs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	doc = deepCopy(doc)
+	return &doc, nil
+}
+
+func (c *memoryCollection[T]) set(id string, doc T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.docs[id] = deepCopy(doc)
+}
+
+func (c *memoryCollection[T]) delete(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.docs, id)
+}
+
+// update applies fn to a document under the write lock
+func (c *memoryCollection[T]) update(id string, fn func(doc *T)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	doc, ok := c.docs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	doc = deepCopy(doc)
+	fn(&doc)
+	c.docs[id] = doc
+	return nil
+}
+
+// upsert applies fn to a document under the write lock, creating it if missing
+func (c *memoryCollection[T]) upsert(id string, fn func(doc *T)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	doc := deepCopy(c.docs[id])
+	fn(&doc)
+	c.docs[id] = doc
+}
+
+// filter returns every document matching keep (all documents when keep is nil)
+func (c *memoryCollection[T]) filter(keep func(T) bool) []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	results := make([]T, 0, len(c.docs))
+	for _, doc := range c.docs {
+		if keep == nil || keep(doc) {
+			results = append(results, deepCopy(doc))
+		}
+	}
+	return results
+}
+
+func (c *memoryCollection[T]) count(keep func(T) bool) int {
+	return len(c.filter(keep))
+}
+
+// deepCopy returns a copy of v that shares no maps, slices or pointers with it.
+// A JSON round-trip would drop fields hidden from JSON, such as password hashes.
+func deepCopy[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	copyValue(dst, src)
+	return dst.Interface().(T)
+}
+
+func copyValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if !src.IsNil() {
+			elem := reflect.New(src.Type().Elem())
+			copyValue(elem.Elem(), src.Elem())
+			dst.Set(elem)
+		}
+	case reflect.Interface:
+		if !src.IsNil() {
+			elem := reflect.New(src.Elem().Type()).Elem()
+			copyValue(elem, src.Elem())
+			dst.Set(elem)
+		}
+	case reflect.Slice:
+		if !src.IsNil() {
+			slice := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+			for i := 0; i < src.Len(); i++ {
+				copyValue(slice.Index(i), src.Index(i))
+			}
+			dst.Set(slice)
+		}
+	case reflect.Map:
+		if !src.IsNil() {
+			m := reflect.MakeMapWithSize(src.Type(), src.Len())
+			for iter := src.MapRange(); iter.Next(); {
+				value := reflect.New(iter.Value().Type()).Elem()
+				copyValue(value, iter.Value())
+				m.SetMapIndex(iter.Key(), value)
+			}
+			dst.Set(m)
+		}
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Struct:
+		// Unexported fields (such as time.Time's) are copied as they are
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				copyValue(dst.Field(i), src.Field(i))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// NewMemoryRepositories builds repositories that keep everything in process memory
+func NewMemoryRepositories() *Repositories {
+	return &Repositories{
+		Users:               &memoryUsers{newMemoryCollection[models.User]()},
+		Courses:             &memoryCourses{newMemoryCollection[models.Course]()},
+		Questions:           &memoryQuestions{newMemoryCollection[models.Question]()},
+		Enrollments:         &memoryEnrollments{newMemoryCollection[models.Enrollment]()},
+		Certificates:        &memoryCertificates{newMemoryCollection[models.Certificate]()},
+		Posts:               &memoryPosts{newMemoryCollection[models.Post]()},
+		Comments:            &memoryComments{newMemoryCollection[models.Comment]()},
+		UserStats:           &memoryUserStats{newMemoryCollection[models.UserStats]()},
+		Achievements:        &memoryAchievements{newMemoryCollection[models.UserAchievement]()},
+		Notifications:       &memoryNotifications{newMemoryCollection[models.Notification]()},
+		VerificationLogs:    &memoryVerificationLogs{},
+		VerificationMetrics: &memoryVerificationMetrics{docs: newMemoryCollection[models.VerificationMetric]()},
+		Trust: &memoryTrust{
+			reputations: newMemoryCollection[models.IssuerReputation](),
+		},
+		Instructors: &memoryInstructors{
+			stats:      newMemoryCollection[models.InstructorStats](),
+			activities: newMemoryCollection[[]models.ActivityItem](),
+			analytics:  newMemoryCollection[models.InstructorAnalytics](),
+		},
+		AuthNonces: &memoryAuthNonces{newMemoryCollection[models.AuthNonce]()},
+		SystemState: &memorySystemState{
+			docs:    newMemoryCollection[models.SystemState](),
+			reports: newMemoryCollection[models.ReconciliationReport](),
+			jobs:    newMemoryCollection[models.BackfillJob](),
+		},
+		Transactions:       &memoryTransactions{newMemoryCollection[models.ChainTransaction]()},
+		Anchors:            &memoryAnchors{newMemoryCollection[models.AnchorBatch]()},
+		IssuerApplications: &memoryIssuerApplications{newMemoryCollection[models.IssuerApplication]()},
+		RelayedMints:       &memoryRelayedMints{newMemoryCollection[models.RelayedMint]()},
+		ImportedBadges:     &memoryImportedBadges{newMemoryCollection[models.ImportedBadge]()},
+		StatusLists:        &memoryStatusLists{docs: newMemoryCollection[models.StatusList]()},
+	}
+}
+
+// -------------------------------------------------------------------
+// USERS
+// -------------------------------------------------------------------
+
+type memoryUsers struct {
+	docs *memoryCollection[models.User]
+}
+
+func (r *memoryUsers) Get(ctx context.Context, id string) (*models.User, error) {
+	return r.docs.get(id)
+}
+
+func (r *memoryUsers) GetByWallet(ctx context.Context, wallet string) (*models.User, error) {
+	wallet = strings.ToLower(wallet)
+	users := r.docs.filter(func(u models.User) bool {
+		return strings.ToLower(u.WalletAddress) == wallet
+	})
+	if len(users) == 0 {
+		return nil, ErrNotFound
+	}
+	return &users[0], nil
+}
+
+func (r *memoryUsers) Save(ctx context.Context, user *models.User) error {
+	r.docs.set(user.ID, *user)
+	return nil
+}
+
+func (r *memoryUsers) UpdateProfile(ctx context.Context, id string, update UserProfileUpdate) error {
+	r.docs.upsert(id, func(u *models.User) {
+		if u.ID == "" {
+			u.ID = id
+		}
+		if update.Name != "" {
+			u.Name = update.Name
+		}
+		if update.Username != "" {
+			u.Username = update.Username
+		}
+		if update.Bio != "" {
+			u.Bio = update.Bio
+		}
+		if update.AvatarEmoji != "" {
+			u.AvatarEmoji = update.AvatarEmoji
+		}
+		if update.Institution != "" {
+			u.Institution = update.Institution
+		}
+		u.UpdatedAt = time.Now()
+	})
+	return nil
+}
+
+func (r *memoryUsers) SetPassword(ctx context.Context, id, passwordHash string) error {
+	return r.docs.update(id, func(u *models.User) { u.Password = passwordHash })
+}
+
+func (r *memoryUsers) SetProgress(ctx context.Context, id string, xp, level int) error {
+	return r.docs.update(id, func(u *models.User) {
+		u.XP = xp
+		u.Level = level
+	})
+}
+
+func (r *memoryUsers) SetWallet(ctx context.Context, id, wallet string) error {
+	return r.docs.update(id, func(u *models.User) { u.WalletAddress = strings.ToLower(wallet) })
+}
+
+func (r *memoryUsers) SetAuthorization(ctx context.Context, wallet string, authorized bool) error {
+	wallet = strings.ToLower(wallet)
+	for _, u := range r.docs.filter(func(u models.User) bool {
+		return strings.ToLower(u.WalletAddress) == wallet
+	}) {
+		r.docs.update(u.ID, func(u *models.User) {
+			u.IsAuthorized = authorized
+			if authorized {
+				u.Role = "instructor"
+			}
+		})
+	}
+	return nil
+}
+
+func (r *memoryUsers) ListByRole(ctx context.Context, role string) ([]models.User, error) {
+	return r.docs.filter(func(u models.User) bool { return u.Role == role }), nil
+}
+
+// -------------------------------------------------------------------
+// COURSES & QUESTIONS
+// -------------------------------------------------------------------
+
+type memoryCourses struct {
+	docs *memoryCollection[models.Course]
+}
+
+func (r *memoryCourses) List(ctx context.Context) ([]models.Course, error) {
+	courses := r.docs.filter(nil)
+	sort.SliceStable(courses, func(i, j int) bool { return courses[i].ID < courses[j].ID })
+	return courses, nil
+}
+
+func (r *memoryCourses) ListByInstructor(ctx context.Context, instructorID string) ([]models.Course, error) {
+	return r.docs.filter(func(c models.Course) bool { return c.InstructorID == instructorID }), nil
+}
+
+func (r *memoryCourses) Get(ctx context.Context, id string) (*models.Course, error) {
+	return r.docs.get(id)
+}
+
+func (r *memoryCourses) Save(ctx context.Context, course *models.Course) error {
+	r.docs.set(course.ID, *course)
+	return nil
+}
+
+type memoryQuestions struct {
+	docs *memoryCollection[models.Question]
+}
+
+func (r *memoryQuestions) List(ctx context.Context, limit int) ([]models.Question, error) {
+	questions := r.docs.filter(nil)
+	sort.SliceStable(questions, func(i, j int) bool { return questions[i].ID < questions[j].ID })
+	if limit > 0 && len(questions) > limit {
+		questions = questions[:limit]
+	}
+	return questions, nil
+}
+
+func (r *memoryQuestions) Get(ctx context.Context, id string) (*models.Question, error) {
+	return r.docs.get(id)
+}
+
+func (r *memoryQuestions) Save(ctx context.Context, question *models.Question) error {
+	r.docs.set(question.ID, *question)
+	return nil
+}
+
+// -------------------------------------------------------------------
+// ENROLLMENTS
+// -------------------------------------------------------------------
+
+type memoryEnrollments struct {
+	docs *memoryCollection[models.Enrollment]
+}
+
+func (r *memoryEnrollments) Get(ctx context.Context, id string) (*models.Enrollment, error) {
+	return r.docs.get(id)
+}
+
+func (r *memoryEnrollments) ListByUser(ctx context.Context, userID string) ([]models.Enrollment, error) {
+	return r.docs.filter(func(e models.Enrollment) bool { return e.UserID == userID }), nil
+}
+
+func (r *memoryEnrollments) Save(ctx context.Context, enrollment *models.Enrollment) error {
+	r.docs.set(enrollment.ID, *enrollment)
+	return nil
+}
+
+// -------------------------------------------------------------------
+// CERTIFICATES
+// -------------------------------------------------------------------
+
+type memoryCertificates struct {
+	docs *memoryCollection[models.Certificate]
+}
+
+func (r *memoryCertificates) Get(ctx context.Context, hash string) (*models.Certificate, error) {
+	return r.docs.get(hash)
+}
+
+func (r *memoryCertificates) Save(ctx context.Context, cert *models.Certificate) error {
+	r.docs.set(cert.Hash, *cert)
+	return nil
+}
+
+func (r *memoryCertificates) ListByWallet(ctx context.Context, wallet string) ([]models.Certificate, error) {
+	return r.docs.filter(func(c models.Certificate) bool { return c.WalletAddress == wallet }), nil
+}
+
+func (r *memoryCertificates) ListByInstructor(ctx context.Context, instructorWallet string) ([]models.Certificate, error) {
+	certs := r.docs.filter(func(c models.Certificate) bool { return c.InstructorWallet == instructorWallet })
+	sort.SliceStable(certs, func(i, j int) bool { return certs[i].IssuedAt.After(certs[j].IssuedAt) })
+	return certs, nil
+}
+
+func (r *memoryCertificates) Count(ctx context.Context) (int, error) {
+	return r.docs.count(nil), nil
+}
+
+func (r *memoryCertificates) CountBelowTrustScore(ctx context.Context, score int) (int, error) {
+	return r.docs.count(func(c models.Certificate) bool { return c.TrustScore < score }), nil
+}
+
+func (r *memoryCertificates) MarkMinted(ctx context.Context, hash, txHash string, block uint64, chain models.ChainRef) error {
+	r.docs.upsert(hash, func(c *models.Certificate) {
+		c.Hash = hash
+		c.IsMinted = true
+		c.BlockchainTx = txHash
+		c.BlockNumber = block
+		c.Chain = &chain
+		c.MintedAt = time.Now()
+		c.Revoked = false
+		c.MintExpired = false
+		c.MintUnauthorized = false
+		c.MintRejection = ""
+	})
+	return nil
+}
+
+func (r *memoryCertificates) MarkMintUnauthorized(ctx context.Context, hash, reason string) error {
+	return r.docs.update(hash, func(c *models.Certificate) {
+		c.MintUnauthorized = true
+		c.MintRejection = reason
+	})
+}
+
+func (r *memoryCertificates) MarkRevoked(ctx context.Context, hash, revokedBy, reason string, revokedAt time.Time) error {
+	r.docs.upsert(hash, func(c *models.Certificate) {
+		c.Hash = hash
+		c.Revoked = true
+		c.RevokedAt = revokedAt
+		c.RevokedBy = revokedBy
+		c.RevocationReason = reason
+		c.PendingRevocation = nil
+	})
+	return nil
+}
+
+func (r *memoryCertificates) MarkRevocationPending(ctx context.Context, hash string, pending models.PendingRevocation) error {
+	return r.docs.update(hash, func(c *models.Certificate) {
+		c.PendingRevocation = &pending
+	})
+}
+
+func (r *memoryCertificates) ClearRevocationPending(ctx context.Context, hash string) error {
+	return r.docs.update(hash, func(c *models.Certificate) {
+		c.PendingRevocation = nil
+	})
+}
+
+func (r *memoryCertificates) ListRevocationPending(ctx context.Context, limit int) ([]models.Certificate, error) {
+	certs := r.docs.filter(func(c models.Certificate) bool { return c.PendingRevocation != nil })
+	sort.Slice(certs, func(i, j int) bool {
+		return certs[i].PendingRevocation.RequestedAt.Before(certs[j].PendingRevocation.RequestedAt)
+	})
+	if limit > 0 && len(certs) > limit {
+		certs = certs[:limit]
+	}
+	return certs, nil
+}
+
+func (r *memoryCertificates) UnmarkMinted(ctx context.Context, hash string) error {
+	return r.docs.update(hash, func(c *models.Certificate) {
+		c.IsMinted = false
+		c.BlockchainTx = ""
+		c.BlockNumber = 0
+		c.Chain = nil
+		c.MintedAt = time.Time{}
+		c.MintUnauthorized = false
+		c.MintRejection = ""
+	})
+}
+
+func (r *memoryCertificates) ClearRevocation(ctx context.Context, hash string) error {
+	return r.docs.update(hash, func(c *models.Certificate) {
+		c.Revoked = false
+		c.RevokedAt = time.Time{}
+		c.RevokedBy = ""
+		c.RevocationReason = ""
+	})
+}
+
+func (r *memoryCertificates) ListPending(ctx context.Context, limit int) ([]models.Certificate, error) {
+	certs := r.docs.filter(func(c models.Certificate) bool {
+		return !c.IsMinted && !c.Revoked && !c.MintExpired && c.AnchorRoot == ""
+	})
+	sort.Slice(certs, func(i, j int) bool { return certs[i].IssuedAt.Before(certs[j].IssuedAt) })
+	if limit > 0 && len(certs) > limit {
+		certs = certs[:limit]
+	}
+	return certs, nil
+}
+
+func (r *memoryCertificates) MarkExpired(ctx context.Context, hash string, expiredAt time.Time) error {
+	return r.docs.update(hash, func(c *models.Certificate) {
+		c.MintExpired = true
+		c.MintExpiredAt = expiredAt
+	})
+}
+
+func (r *memoryCertificates) MarkAnchored(ctx context.Context, hash, root string, proof []string, chain models.ChainRef) error {
+	return r.docs.update(hash, func(c *models.Certificate) {
+		c.AnchorRoot = root
+		c.MerkleProof = proof
+		c.Chain = &chain
+	})
+}
+
+// -------------------------------------------------------------------
+// FORUM
+// -------------------------------------------------------------------
+
+type memoryPosts struct {
+	docs *memoryCollection[models.Post]
+}
+
+func (r *memoryPosts) List(ctx context.Context, courseID string) ([]models.Post, error) {
+	posts := r.docs.filter(func(p models.Post) bool { return courseID == "" || p.CourseID == courseID })
+	sort.SliceStable(posts, func(i, j int) bool { return posts[i].CreatedAt.Before(posts[j].CreatedAt) })
+	return posts, nil
+}
+
+func (r *memoryPosts) Get(ctx context.Context, id string) (*models.Post, error) {
+	return r.docs.get(id)
+}
+
+func (r *memoryPosts) Save(ctx context.Context, post *models.Post) error {
+	r.docs.set(post.ID, *post)
+	return nil
+}
+
+func (r *memoryPosts) IncrementCommentCount(ctx context.Context, id string) error {
+	return r.docs.update(id, func(p *models.Post) { p.CommentCount++ })
+}
+
+func (r *memoryPosts) IncrementViewCount(ctx context.Context, id string) error {
+	return r.docs.update(id, func(p *models.Post) { p.ViewCount++ })
+}
+
+type memoryComments struct {
+	docs *memoryCollection[models.Comment]
+}
+
+func (r *memoryComments) ListByPost(ctx context.Context, postID string) ([]models.Comment, error) {
+	comments := r.docs.filter(func(c models.Comment) bool { return c.PostID == postID })
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].CreatedAt.Before(comments[j].CreatedAt) })
+	return comments, nil
+}
+
+func (r *memoryComments) Get(ctx context.Context, id string) (*models.Comment, error) {
+	return r.docs.get(id)
+}
+
+func (r *memoryComments) Save(ctx context.Context, comment *models.Comment) error {
+	r.docs.set(comment.ID, *comment)
+	return nil
+}
+
+// -------------------------------------------------------------------
+// GAMIFICATION
+// -------------------------------------------------------------------
+
+type memoryUserStats struct {
+	docs *memoryCollection[models.UserStats]
+}
+
+func (r *memoryUserStats) Get(ctx context.Context, userID string) (*models.UserStats, error) {
+	return r.docs.get(userID)
+}
+
+func (r *memoryUserStats) Save(ctx context.Context, stats *models.UserStats) error {
+	r.docs.set(stats.UserID, *stats)
+	return nil
+}
+
+func (r *memoryUserStats) Increment(ctx context.Context, userID, counter string, delta int) error {
+	return r.docs.update(userID, func(s *models.UserStats) {
+		switch counter {
+		case StatForumPosts:
+			s.ForumPosts += delta
+		case StatForumComments:
+			s.ForumComments += delta
+		case StatCoursesCompleted:
+			s.CoursesCompleted += delta
+		case StatTotalXP:
+			s.TotalXP += delta
+		}
+	})
+}
+
+func (r *memoryUserStats) ListByXP(ctx context.Context, limit int) ([]models.UserStats, error) {
+	stats := r.docs.filter(nil)
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].TotalXP > stats[j].TotalXP })
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats, nil
+}
+
+func (r *memoryUserStats) SetGlobalRank(ctx context.Context, userID string, rank int) error {
+	return r.docs.update(userID, func(s *models.UserStats) { s.GlobalRank = rank })
+}
+
+type memoryAchievements struct {
+	docs *memoryCollection[models.UserAchievement]
+}
+
+func (r *memoryAchievements) ListByUser(ctx context.Context, userID string) ([]models.UserAchievement, error) {
+	return r.docs.filter(func(a models.UserAchievement) bool { return a.UserID == userID }), nil
+}
+
+func (r *memoryAchievements) Save(ctx context.Context, achievement *models.UserAchievement) error {
+	r.docs.set(achievement.ID, *achievement)
+	return nil
+}
+
+// -------------------------------------------------------------------
+// NOTIFICATIONS
+// -------------------------------------------------------------------
+
+type memoryNotifications struct {
+	docs *memoryCollection[models.Notification]
+}
+
+func (r *memoryNotifications) ListByUser(ctx context.Context, userID string) ([]models.Notification, error) {
+	return r.docs.filter(func(n models.Notification) bool { return n.UserID == userID }), nil
+}
+
+func (r *memoryNotifications) Save(ctx context.Context, notification *models.Notification) error {
+	r.docs.set(notification.ID, *notification)
+	return nil
+}
+
+func (r *memoryNotifications) MarkRead(ctx context.Context, id string) error {
+	return r.docs.update(id, func(n *models.Notification) { n.IsRead = true })
+}
+
+// -------------------------------------------------------------------
+// VERIFICATION & TRUST
+// -------------------------------------------------------------------
+
+type memoryVerificationLogs struct {
+	mu      sync.RWMutex
+	entries []models.VerificationLog
+}
+
+func (r *memoryVerificationLogs) Add(ctx context.Context, entry *models.VerificationLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *memoryVerificationLogs) CountByResult(ctx context.Context, verified bool) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, e := range r.entries {
+		if e.Verified == verified {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryVerificationLogs) CountByCertificate(ctx context.Context, certHash string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, e := range r.entries {
+		if e.CertificateHash == certHash {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type memoryVerificationMetrics struct {
+	mu   sync.Mutex // serializes Update so read-modify-write is atomic
+	docs *memoryCollection[models.VerificationMetric]
+}
+
+func (r *memoryVerificationMetrics) Get(ctx context.Context, certHash string) (*models.VerificationMetric, error) {
+	return r.docs.get(certHash)
+}
+
+func (r *memoryVerificationMetrics) Update(ctx context.Context, certHash string, fn func(*models.VerificationMetric) (*models.VerificationMetric, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.docs.get(certHash)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	updated, err := fn(current)
+	if err != nil {
+		return err
+	}
+	r.docs.set(certHash, *updated)
+	return nil
+}
+
+type memoryTrust struct {
+	reputations *memoryCollection[models.IssuerReputation]
+
+	mu      sync.Mutex
+	history []models.TrustHistoryEvent
+}
+
+func (r *memoryTrust) GetIssuerReputation(ctx context.Context, wallet string) (*models.IssuerReputation, error) {
+	return r.reputations.get(wallet)
+}
+
+func (r *memoryTrust) SaveIssuerReputation(ctx context.Context, rep *models.IssuerReputation) error {
+	r.reputations.set(rep.InstructorID, *rep)
+	return nil
+}
+
+func (r *memoryTrust) AddHistory(ctx context.Context, event *models.TrustHistoryEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = append(r.history, *event)
+	return nil
+}
+
+// -------------------------------------------------------------------
+// INSTRUCTOR DASHBOARD
+// -------------------------------------------------------------------
+
+type memoryInstructors struct {
+	stats      *memoryCollection[models.InstructorStats]
+	activities *memoryCollection[[]models.ActivityItem]
+	analytics  *memoryCollection[models.InstructorAnalytics]
+}
+
+func (r *memoryInstructors) GetStats(ctx context.Context, instructorID string) (*models.InstructorStats, error) {
+	return r.stats.get(instructorID)
+}
+
+func (r *memoryInstructors) SaveStats(ctx context.Context, stats *models.InstructorStats) error {
+	r.stats.set(stats.InstructorID, *stats)
+	return nil
+}
+
+func (r *memoryInstructors) GetActivities(ctx context.Context, instructorID string) ([]models.ActivityItem, error) {
+	items, err := r.activities.get(instructorID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]models.ActivityItem(nil), (*items)...), nil
+}
+
+func (r *memoryInstructors) SaveActivities(ctx context.Context, instructorID string, items []models.ActivityItem) error {
+	r.activities.set(instructorID, append([]models.ActivityItem(nil), items...))
+	return nil
+}
+
+func (r *memoryInstructors) GetAnalytics(ctx context.Context, instructorID string) (*models.InstructorAnalytics, error) {
+	return r.analytics.get(instructorID)
+}
+
+func (r *memoryInstructors) SaveAnalytics(ctx context.Context, analytics *models.InstructorAnalytics) error {
+	r.analytics.set(analytics.InstructorID, *analytics)
+	return nil
+}
+
+// -------------------------------------------------------------------
+// AUTH NONCES & SYSTEM STATE
+// -------------------------------------------------------------------
+
+type memoryAuthNonces struct {
+	docs *memoryCollection[models.AuthNonce]
+}
+
+func (r *memoryAuthNonces) Get(ctx context.Context, wallet string) (*models.AuthNonce, error) {
+	return r.docs.get(strings.ToLower(wallet))
+}
+
+func (r *memoryAuthNonces) Save(ctx context.Context, wallet string, nonce *models.AuthNonce) error {
+	r.docs.set(strings.ToLower(wallet), *nonce)
+	return nil
+}
+
+func (r *memoryAuthNonces) Delete(ctx context.Context, wallet string) error {
+	r.docs.delete(strings.ToLower(wallet))
+	return nil
+}
+
+type memorySystemState struct {
+	docs    *memoryCollection[models.SystemState]
+	reports *memoryCollection[models.ReconciliationReport]
+	jobs    *memoryCollection[models.BackfillJob]
+}
+
+func (r *memorySystemState) GetSyncState(ctx context.Context) (*models.SystemState, error) {
+	return r.docs.get("sync_state")
+}
+
+func (r *memorySystemState) SaveSyncState(ctx context.Context, state *models.SystemState) error {
+	state.ID = "sync_state"
+	state.UpdatedAt = time.Now()
+	r.docs.set(state.ID, *state)
+	return nil
+}
+
+func (r *memorySystemState) GetReconciliationReport(ctx context.Context) (*models.ReconciliationReport, error) {
+	return r.reports.get("reconciliation")
+}
+
+func (r *memorySystemState) SaveReconciliationReport(ctx context.Context, report *models.ReconciliationReport) error {
+	report.ID = "reconciliation"
+	r.reports.set(report.ID, *report)
+	return nil
+}
+
+func (r *memorySystemState) GetBackfillJob(ctx context.Context) (*models.BackfillJob, error) {
+	return r.jobs.get("backfill")
+}
+
+func (r *memorySystemState) SaveBackfillJob(ctx context.Context, job *models.BackfillJob) error {
+	job.ID = "backfill"
+	r.jobs.set(job.ID, *job)
+	return nil
+}
+
+type memoryTransactions struct {
+	docs *memoryCollection[models.ChainTransaction]
+}
+
+func (r *memoryTransactions) Get(ctx context.Context, id string) (*models.ChainTransaction, error) {
+	return r.docs.get(id)
+}
+
+func (r *memoryTransactions) Save(ctx context.Context, tx *models.ChainTransaction) error {
+	r.docs.set(tx.ID, *tx)
+	return nil
+}
+
+func (r *memoryTransactions) ListPending(ctx context.Context) ([]models.ChainTransaction, error) {
+	txs := r.docs.filter(func(tx models.ChainTransaction) bool { return tx.Status == models.TxStatusPending })
+	sort.Slice(txs, func(i, j int) bool { return txs[i].Nonce < txs[j].Nonce })
+	return txs, nil
+}
+
+type memoryAnchors struct {
+	docs *memoryCollection[models.AnchorBatch]
+}
+
+func (r *memoryAnchors) Get(ctx context.Context, root string) (*models.AnchorBatch, error) {
+	return r.docs.get(root)
+}
+
+func (r *memoryAnchors) Save(ctx context.Context, batch *models.AnchorBatch) error {
+	r.docs.set(batch.ID, *batch)
+	return nil
+}
+
+func (r *memoryAnchors) ListPending(ctx context.Context) ([]models.AnchorBatch, error) {
+	batches := r.docs.filter(func(b models.AnchorBatch) bool { return b.Status == models.AnchorStatusPending })
+	sort.Slice(batches, func(i, j int) bool { return batches[i].CreatedAt.Before(batches[j].CreatedAt) })
+	return batches, nil
+}
+
+type memoryIssuerApplications struct {
+	docs *memoryCollection[models.IssuerApplication]
+}
+
+func (r *memoryIssuerApplications) Get(ctx context.Context, wallet string) (*models.IssuerApplication, error) {
+	return r.docs.get(strings.ToLower(wallet))
+}
+
+func (r *memoryIssuerApplications) Save(ctx context.Context, app *models.IssuerApplication) error {
+	app.ID = strings.ToLower(app.Wallet)
+	r.docs.set(app.ID, *app)
+	return nil
+}
+
+func (r *memoryIssuerApplications) List(ctx context.Context, status string) ([]models.IssuerApplication, error) {
+	apps := r.docs.filter(func(a models.IssuerApplication) bool { return status == "" || a.Status == status })
+	sort.Slice(apps, func(i, j int) bool { return apps[i].SubmittedAt.Before(apps[j].SubmittedAt) })
+	return apps, nil
+}
+
+type memoryRelayedMints struct {
+	docs *memoryCollection[models.RelayedMint]
+}
+
+func (r *memoryRelayedMints) Get(ctx context.Context, certHash string) (*models.RelayedMint, error) {
+	return r.docs.get(certHash)
+}
+
+func (r *memoryRelayedMints) Save(ctx context.Context, relay *models.RelayedMint) error {
+	r.docs.set(relay.ID, *relay)
+	return nil
+}
+
+func (r *memoryRelayedMints) ListByInstructor(ctx context.Context, instructorWallet string) ([]models.RelayedMint, error) {
+	relays := r.docs.filter(func(m models.RelayedMint) bool { return strings.EqualFold(m.InstructorWallet, instructorWallet) })
+	sort.Slice(relays, func(i, j int) bool { return relays[i].CreatedAt.After(relays[j].CreatedAt) })
+	return relays, nil
+}
+
+func (r *memoryRelayedMints) CountByInstructorSince(ctx context.Context, instructorWallet string, since time.Time) (int, error) {
+	return r.docs.count(func(m models.RelayedMint) bool {
+		return strings.EqualFold(m.InstructorWallet, instructorWallet) && m.CreatedAt.After(since) && m.Status != models.RelayStatusFailed
+	}), nil
+}
+
+type memoryImportedBadges struct {
+	docs *memoryCollection[models.ImportedBadge]
+}
+
+func (r *memoryImportedBadges) Save(ctx context.Context, badge *models.ImportedBadge) error {
+	r.docs.set(badge.ID, *badge)
+	return nil
+}
+
+func (r *memoryImportedBadges) ListByUser(ctx context.Context, userID string) ([]models.ImportedBadge, error) {
+	badges := r.docs.filter(func(b models.ImportedBadge) bool { return b.UserID == userID })
+	sort.Slice(badges, func(i, j int) bool { return badges[i].ImportedAt.After(badges[j].ImportedAt) })
+	return badges, nil
+}
+
+type memoryStatusLists struct {
+	mu   sync.Mutex
+	docs *memoryCollection[models.StatusList]
+}
+
+func (r *memoryStatusLists) Get(ctx context.Context, id string) (*models.StatusList, error) {
+	return r.docs.get(id)
+}
+
+func (r *memoryStatusLists) Update(ctx context.Context, id string, fn func(*models.StatusList) (*models.StatusList, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.docs.get(id)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	updated, err := fn(current)
+	if err != nil {
+		return err
+	}
+	r.docs.set(id, *updated)
+	return nil
+}
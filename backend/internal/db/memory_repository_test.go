@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/models"
+)
+
+func TestMemoryCollectionCopies(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMemoryRepositories()
+
+	cert := &models.Certificate{
+		Hash:              "a",
+		IssuedAt:          time.Unix(1700000000, 0).UTC(),
+		MerkleProof:       []string{"p0", "p1"},
+		MintAuthorization: &models.MintAuthorization{StudentWallet: "0xabc"},
+		Chain:             &models.ChainRef{ChainID: 137},
+	}
+	if err := repos.Certificates.Save(ctx, cert); err != nil {
+		t.Fatal(err)
+	}
+	want := *cert
+	want.MerkleProof = []string{"p0", "p1"}
+	want.MintAuthorization = &models.MintAuthorization{StudentWallet: "0xabc"}
+	want.Chain = &models.ChainRef{ChainID: 137}
+
+	// Changing what was saved doesn't reach the store
+	cert.MerkleProof[0] = "saved"
+	cert.MintAuthorization.StudentWallet = "saved"
+	cert.Chain.ChainID = 1
+
+	got, err := repos.Certificates.Get(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Fatalf("Get = %+v, want %+v", *got, want)
+	}
+
+	// Nor does changing what was read
+	got.MerkleProof[1] = "read"
+	got.MintAuthorization.StudentWallet = "read"
+	listed, _ := repos.Certificates.ListByWallet(ctx, "")
+	listed[0].Chain.ChainID = 2
+
+	got, _ = repos.Certificates.Get(ctx, "a")
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("stored certificate changed through a read copy: %+v", *got)
+	}
+
+	// An update starts from the stored values, not from what callers changed
+	if err := repos.Certificates.MarkMinted(ctx, "a", "0xtx", 7, models.ChainRef{ChainID: 137}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repos.Certificates.Get(ctx, "a")
+	if !reflect.DeepEqual(got.MerkleProof, want.MerkleProof) || *got.MintAuthorization != *want.MintAuthorization {
+		t.Errorf("update started from a changed copy: %+v", *got)
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	type inner struct {
+		Values []int
+		hidden *int
+	}
+	type doc struct {
+		Password string `json:"-"`
+		Claims   map[string]interface{}
+		Nested   *inner
+		Fixed    [2][]byte
+		Empty    []string
+		Nil      []string
+		At       time.Time
+	}
+	hidden := 1
+	at := time.Now()
+	src := doc{
+		Password: "hash",
+		Claims:   map[string]interface{}{"marks": 72, "skills": []string{"go"}, "sub": map[string]interface{}{"n": 1}},
+		Nested:   &inner{Values: []int{1, 2}, hidden: &hidden},
+		Fixed:    [2][]byte{{1}, {2}},
+		Empty:    []string{},
+		At:       at,
+	}
+
+	dst := deepCopy(src)
+	if !reflect.DeepEqual(dst, src) {
+		t.Fatalf("deepCopy = %+v, want %+v", dst, src)
+	}
+	if dst.Empty == nil || dst.Nil != nil {
+		t.Error("nil and empty slices not preserved")
+	}
+	if !dst.At.Equal(at) {
+		t.Errorf("At = %v, want %v", dst.At, at)
+	}
+
+	dst.Claims["marks"] = 99
+	dst.Claims["skills"].([]string)[0] = "rust"
+	dst.Claims["sub"].(map[string]interface{})["n"] = 2
+	dst.Nested.Values[0] = 9
+	dst.Fixed[0][0] = 9
+	if src.Claims["marks"] != 72 || src.Claims["skills"].([]string)[0] != "go" ||
+		src.Claims["sub"].(map[string]interface{})["n"] != 1 || src.Nested.Values[0] != 1 || src.Fixed[0][0] != 1 {
+		t.Errorf("source changed through the copy: %+v", src)
+	}
+}
@@ -0,0 +1,315 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cache-crew/cognify/internal/config"
+	"cache-crew/cognify/internal/models"
+)
+
+// ErrNotFound is returned by repositories when a document does not exist
+var ErrNotFound = errors.New("document not found")
+
+// UserStats counters that can be incremented atomically
+const (
+	StatForumPosts       = "forumPosts"
+	StatForumComments    = "forumComments"
+	StatCoursesCompleted = "coursesCompleted"
+	StatTotalXP          = "totalXp"
+)
+
+// UserProfileUpdate holds the optional profile fields a user can change.
+// Empty fields are left untouched.
+type UserProfileUpdate struct {
+	Name        string
+	Username    string
+	Bio         string
+	AvatarEmoji string
+	Institution string
+}
+
+// UserRepository stores platform users (collection: users)
+type UserRepository interface {
+	Get(ctx context.Context, id string) (*models.User, error)
+	GetByWallet(ctx context.Context, wallet string) (*models.User, error)
+	Save(ctx context.Context, user *models.User) error
+	UpdateProfile(ctx context.Context, id string, update UserProfileUpdate) error
+	SetPassword(ctx context.Context, id, passwordHash string) error
+	SetProgress(ctx context.Context, id string, xp, level int) error
+	SetWallet(ctx context.Context, id, wallet string) error
+	// SetAuthorization flags every user bound to the wallet as an (un)authorized issuer
+	SetAuthorization(ctx context.Context, wallet string, authorized bool) error
+	ListByRole(ctx context.Context, role string) ([]models.User, error)
+}
+
+// CourseRepository stores courses (collection: courses)
+type CourseRepository interface {
+	List(ctx context.Context) ([]models.Course, error)
+	ListByInstructor(ctx context.Context, instructorID string) ([]models.Course, error)
+	Get(ctx context.Context, id string) (*models.Course, error)
+	Save(ctx context.Context, course *models.Course) error
+}
+
+// QuestionRepository stores the global battle question pool (collection: questions)
+type QuestionRepository interface {
+	List(ctx context.Context, limit int) ([]models.Question, error)
+	Get(ctx context.Context, id string) (*models.Question, error)
+	Save(ctx context.Context, question *models.Question) error
+}
+
+// EnrollmentRepository stores course enrollments (collection: enrollments)
+type EnrollmentRepository interface {
+	Get(ctx context.Context, id string) (*models.Enrollment, error)
+	ListByUser(ctx context.Context, userID string) ([]models.Enrollment, error)
+	Save(ctx context.Context, enrollment *models.Enrollment) error
+}
+
+// CertificateRepository stores certificate metadata keyed by hash (collection: certificates)
+type CertificateRepository interface {
+	Get(ctx context.Context, hash string) (*models.Certificate, error)
+	Save(ctx context.Context, cert *models.Certificate) error
+	ListByWallet(ctx context.Context, wallet string) ([]models.Certificate, error)
+	// ListByInstructor returns certificates issued by a wallet, newest first
+	ListByInstructor(ctx context.Context, instructorWallet string) ([]models.Certificate, error)
+	Count(ctx context.Context) (int, error)
+	CountBelowTrustScore(ctx context.Context, score int) (int, error)
+	// MarkMinted records an on-chain mint on the given deployment, creating the document if needed
+	MarkMinted(ctx context.Context, hash, txHash string, block uint64, chain models.ChainRef) error
+	// MarkMintUnauthorized flags a recorded mint that failed its authorization check;
+	// MarkMinted and UnmarkMinted clear the flag
+	MarkMintUnauthorized(ctx context.Context, hash, reason string) error
+	// MarkRevoked records a revocation with its revoker and reason, creating the document if needed.
+	// It clears any pending revocation.
+	MarkRevoked(ctx context.Context, hash, revokedBy, reason string, revokedAt time.Time) error
+	// MarkRevocationPending records a revocation transaction that is not mined yet
+	MarkRevocationPending(ctx context.Context, hash string, pending models.PendingRevocation) error
+	// ClearRevocationPending drops a pending revocation whose transaction failed
+	ClearRevocationPending(ctx context.Context, hash string) error
+	// ListRevocationPending returns up to limit certificates with a pending revocation
+	ListRevocationPending(ctx context.Context, limit int) ([]models.Certificate, error)
+	// ListPending returns up to limit certificates that are not minted, anchored, revoked or expired
+	ListPending(ctx context.Context, limit int) ([]models.Certificate, error)
+	// MarkExpired flags a pending certificate that was never minted
+	MarkExpired(ctx context.Context, hash string, expiredAt time.Time) error
+	// MarkAnchored stores a certificate's Merkle proof; anchored certificates are no longer pending
+	MarkAnchored(ctx context.Context, hash, root string, proof []string, chain models.ChainRef) error
+	// UnmarkMinted undoes MarkMinted after the mint was reorged out of the chain
+	UnmarkMinted(ctx context.Context, hash string) error
+	// ClearRevocation undoes MarkRevoked after the revocation was reorged out of the chain
+	ClearRevocation(ctx context.Context, hash string) error
+}
+
+// PostRepository stores forum posts (collection: posts)
+type PostRepository interface {
+	// List returns posts oldest first, optionally filtered by course
+	List(ctx context.Context, courseID string) ([]models.Post, error)
+	Get(ctx context.Context, id string) (*models.Post, error)
+	Save(ctx context.Context, post *models.Post) error
+	IncrementCommentCount(ctx context.Context, id string) error
+	IncrementViewCount(ctx context.Context, id string) error
+}
+
+// CommentRepository stores forum comments (collection: comments)
+type CommentRepository interface {
+	// ListByPost returns comments for a post, oldest first
+	ListByPost(ctx context.Context, postID string) ([]models.Comment, error)
+	Get(ctx context.Context, id string) (*models.Comment, error)
+	Save(ctx context.Context, comment *models.Comment) error
+}
+
+// UserStatsRepository stores gamification stats (collection: user_stats)
+type UserStatsRepository interface {
+	Get(ctx context.Context, userID string) (*models.UserStats, error)
+	Save(ctx context.Context, stats *models.UserStats) error
+	// Increment atomically adds delta to one of the Stat* counters.
+	// Returns ErrNotFound if the user has no stats yet.
+	Increment(ctx context.Context, userID, counter string, delta int) error
+	// ListByXP returns stats ordered by total XP descending; limit <= 0 means all
+	ListByXP(ctx context.Context, limit int) ([]models.UserStats, error)
+	SetGlobalRank(ctx context.Context, userID string, rank int) error
+}
+
+// AchievementRepository stores unlocked achievements (collection: user_achievements)
+type AchievementRepository interface {
+	ListByUser(ctx context.Context, userID string) ([]models.UserAchievement, error)
+	Save(ctx context.Context, achievement *models.UserAchievement) error
+}
+
+// NotificationRepository stores user notifications (collection: notifications)
+type NotificationRepository interface {
+	ListByUser(ctx context.Context, userID string) ([]models.Notification, error)
+	Save(ctx context.Context, notification *models.Notification) error
+	MarkRead(ctx context.Context, id string) error
+}
+
+// VerificationLogRepository stores verification attempts (collection: verification_logs)
+type VerificationLogRepository interface {
+	Add(ctx context.Context, entry *models.VerificationLog) error
+	CountByResult(ctx context.Context, verified bool) (int, error)
+	CountByCertificate(ctx context.Context, certHash string) (int, error)
+}
+
+// VerificationMetricRepository stores per-certificate verification analytics (collection: verification_metrics)
+type VerificationMetricRepository interface {
+	Get(ctx context.Context, certHash string) (*models.VerificationMetric, error)
+	// Update applies fn atomically. fn receives nil if no metric exists yet.
+	Update(ctx context.Context, certHash string, fn func(*models.VerificationMetric) (*models.VerificationMetric, error)) error
+}
+
+// TrustRepository stores issuer reputation (issuer_reputation) and trust score history (trust_history)
+type TrustRepository interface {
+	GetIssuerReputation(ctx context.Context, wallet string) (*models.IssuerReputation, error)
+	SaveIssuerReputation(ctx context.Context, rep *models.IssuerReputation) error
+	AddHistory(ctx context.Context, event *models.TrustHistoryEvent) error
+}
+
+// InstructorRepository stores instructor dashboard documents
+// (instructor_stats, instructor_activities, instructor_analytics)
+type InstructorRepository interface {
+	GetStats(ctx context.Context, instructorID string) (*models.InstructorStats, error)
+	SaveStats(ctx context.Context, stats *models.InstructorStats) error
+	GetActivities(ctx context.Context, instructorID string) ([]models.ActivityItem, error)
+	SaveActivities(ctx context.Context, instructorID string, items []models.ActivityItem) error
+	GetAnalytics(ctx context.Context, instructorID string) (*models.InstructorAnalytics, error)
+	SaveAnalytics(ctx context.Context, analytics *models.InstructorAnalytics) error
+}
+
+// AuthNonceRepository stores wallet login nonces (collection: auth_nonces)
+type AuthNonceRepository interface {
+	Get(ctx context.Context, wallet string) (*models.AuthNonce, error)
+	Save(ctx context.Context, wallet string, nonce *models.AuthNonce) error
+	Delete(ctx context.Context, wallet string) error
+}
+
+// SystemStateRepository stores global system state (collection: system_state)
+type SystemStateRepository interface {
+	GetSyncState(ctx context.Context) (*models.SystemState, error)
+	SaveSyncState(ctx context.Context, state *models.SystemState) error
+	// GetReconciliationReport returns ErrNotFound before the reconciler's first run
+	GetReconciliationReport(ctx context.Context) (*models.ReconciliationReport, error)
+	SaveReconciliationReport(ctx context.Context, report *models.ReconciliationReport) error
+	// GetBackfillJob returns ErrNotFound before the first backfill
+	GetBackfillJob(ctx context.Context) (*models.BackfillJob, error)
+	SaveBackfillJob(ctx context.Context, job *models.BackfillJob) error
+}
+
+// TransactionRepository stores platform wallet transactions keyed by first hash (collection: chain_transactions)
+type TransactionRepository interface {
+	Get(ctx context.Context, id string) (*models.ChainTransaction, error)
+	Save(ctx context.Context, tx *models.ChainTransaction) error
+	// ListPending returns transactions not yet mined, lowest nonce first
+	ListPending(ctx context.Context) ([]models.ChainTransaction, error)
+}
+
+// AnchorRepository stores Merkle anchoring batches keyed by root (collection: anchor_batches)
+type AnchorRepository interface {
+	Get(ctx context.Context, root string) (*models.AnchorBatch, error)
+	Save(ctx context.Context, batch *models.AnchorBatch) error
+	// ListPending returns batches whose root is not yet confirmed on-chain, oldest first
+	ListPending(ctx context.Context) ([]models.AnchorBatch, error)
+}
+
+// IssuerApplicationRepository stores issuer applications keyed by lowercased wallet (collection: issuer_applications)
+type IssuerApplicationRepository interface {
+	Get(ctx context.Context, wallet string) (*models.IssuerApplication, error)
+	Save(ctx context.Context, app *models.IssuerApplication) error
+	// List returns applications with the given status (all when empty), oldest first
+	List(ctx context.Context, status string) ([]models.IssuerApplication, error)
+}
+
+// RelayedMintRepository stores gasless mints keyed by certificate hash (collection: relayed_mints)
+type RelayedMintRepository interface {
+	Get(ctx context.Context, certHash string) (*models.RelayedMint, error)
+	Save(ctx context.Context, relay *models.RelayedMint) error
+	// ListByInstructor returns an instructor's relayed mints, newest first
+	ListByInstructor(ctx context.Context, instructorWallet string) ([]models.RelayedMint, error)
+	// CountByInstructorSince counts an instructor's relayed mints created after since that did not fail
+	CountByInstructorSince(ctx context.Context, instructorWallet string, since time.Time) (int, error)
+}
+
+// ImportedBadgeRepository stores externally issued badges users imported (collection: imported_badges)
+type ImportedBadgeRepository interface {
+	Save(ctx context.Context, badge *models.ImportedBadge) error
+	// ListByUser returns a user's imported badges, newest first
+	ListByUser(ctx context.Context, userID string) ([]models.ImportedBadge, error)
+}
+
+// StatusListRepository stores bitstring status lists keyed by list number (collection: status_lists)
+type StatusListRepository interface {
+	Get(ctx context.Context, id string) (*models.StatusList, error)
+	// Update applies fn atomically. fn receives nil if the list does not exist yet.
+	Update(ctx context.Context, id string, fn func(*models.StatusList) (*models.StatusList, error)) error
+}
+
+// Repositories groups every storage repository used by the backend
+type Repositories struct {
+	Users               UserRepository
+	Courses             CourseRepository
+	Questions           QuestionRepository
+	Enrollments         EnrollmentRepository
+	Certificates        CertificateRepository
+	Posts               PostRepository
+	Comments            CommentRepository
+	UserStats           UserStatsRepository
+	Achievements        AchievementRepository
+	Notifications       NotificationRepository
+	VerificationLogs    VerificationLogRepository
+	VerificationMetrics VerificationMetricRepository
+	Trust               TrustRepository
+	Instructors         InstructorRepository
+	AuthNonces          AuthNonceRepository
+	SystemState         SystemStateRepository
+	Transactions        TransactionRepository
+	Anchors             AnchorRepository
+	IssuerApplications  IssuerApplicationRepository
+	RelayedMints        RelayedMintRepository
+	ImportedBadges      ImportedBadgeRepository
+	StatusLists         StatusListRepository
+}
+
+// Repos is the active storage backend. It is never nil after InitRepositories.
+var Repos *Repositories
+
+// InitRepositories selects the storage backend from config.StorageBackend.
+// "firestore" (the default) uses Firestore when it was initialized successfully
+// and otherwise falls back to an in-memory store so the server stays fully
+// functional for local development. "sqlite" and "postgres" open DatabaseURL
+// and apply pending schema migrations.
+func InitRepositories(ctx context.Context) error {
+	backend := config.AppConfig.StorageBackend
+
+	switch backend {
+	case "memory":
+		Repos = NewMemoryRepositories()
+		log.Println("Storage: using in-memory repositories (data is lost on restart)")
+		return nil
+
+	case DialectSQLite, DialectPostgres:
+		conn, err := OpenSQL(ctx, backend, config.AppConfig.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("open %s storage: %w", backend, err)
+		}
+		SQLClient = conn
+		Repos = NewSQLRepositories(conn, backend)
+		log.Printf("Storage: using %s repositories", backend)
+		return nil
+
+	case "", "firestore":
+		if FirestoreClient != nil {
+			Repos = NewFirestoreRepositories(FirestoreClient)
+			log.Println("Storage: using Firestore repositories")
+			return nil
+		}
+
+		Repos = NewMemoryRepositories()
+		log.Println("Storage: Firestore unavailable, using in-memory repositories (data is lost on restart)")
+		return nil
+
+	default:
+		return fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
@@ -0,0 +1,299 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// sqlMigrations is the ordered schema history for the SQL backend.
+// Migrations are append-only: never edit an entry that has shipped, add a new one.
+//
+// Every table keeps the full model as a JSON document in `data` and copies the
+// fields the handlers filter or sort on into real, indexed columns. Timestamps
+// used for ordering are stored as Unix milliseconds so SQLite and Postgres sort
+// them identically. {{serial}} expands to the dialect's auto-increment key,
+// {{json_bool:field}} to a dialect-specific read of a boolean field from `data`
+// (false when absent) and {{json_text:field}} to a read of a string field (empty
+// when absent), for backfilling new columns.
+var sqlMigrations = [][]string{
+	// 1: initial schema
+	{
+		`CREATE TABLE users (
+			id TEXT PRIMARY KEY,
+			wallet_address TEXT NOT NULL DEFAULT '',
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_users_wallet_address ON users (wallet_address)`,
+
+		`CREATE TABLE courses (
+			id TEXT PRIMARY KEY,
+			instructor_id TEXT NOT NULL DEFAULT '',
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_courses_instructor_id ON courses (instructor_id)`,
+
+		`CREATE TABLE questions (
+			id TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+
+		`CREATE TABLE enrollments (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL DEFAULT '',
+			course_id TEXT NOT NULL DEFAULT '',
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_enrollments_user_id ON enrollments (user_id)`,
+
+		`CREATE TABLE certificates (
+			hash TEXT PRIMARY KEY,
+			wallet_address TEXT NOT NULL DEFAULT '',
+			instructor_wallet TEXT NOT NULL DEFAULT '',
+			issued_at BIGINT NOT NULL DEFAULT 0,
+			trust_score INTEGER NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_certificates_wallet_address ON certificates (wallet_address)`,
+		`CREATE INDEX idx_certificates_instructor_issued ON certificates (instructor_wallet, issued_at DESC)`,
+		`CREATE INDEX idx_certificates_trust_score ON certificates (trust_score)`,
+
+		`CREATE TABLE posts (
+			id TEXT PRIMARY KEY,
+			course_id TEXT NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_posts_created_at ON posts (created_at)`,
+		`CREATE INDEX idx_posts_course_created ON posts (course_id, created_at)`,
+
+		`CREATE TABLE comments (
+			id TEXT PRIMARY KEY,
+			post_id TEXT NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_comments_post_created ON comments (post_id, created_at)`,
+
+		`CREATE TABLE user_stats (
+			user_id TEXT PRIMARY KEY,
+			total_xp INTEGER NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_user_stats_total_xp ON user_stats (total_xp DESC)`,
+
+		`CREATE TABLE user_achievements (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL DEFAULT '',
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_user_achievements_user_id ON user_achievements (user_id)`,
+
+		`CREATE TABLE notifications (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL DEFAULT '',
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_notifications_user_id ON notifications (user_id)`,
+
+		`CREATE TABLE verification_logs (
+			id {{serial}},
+			certificate_hash TEXT NOT NULL DEFAULT '',
+			verified BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at BIGINT NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_verification_logs_certificate_hash ON verification_logs (certificate_hash)`,
+		`CREATE INDEX idx_verification_logs_verified ON verification_logs (verified)`,
+
+		`CREATE TABLE verification_metrics (
+			certificate_hash TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+
+		`CREATE TABLE issuer_reputation (
+			instructor_id TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+
+		`CREATE TABLE trust_history (
+			id {{serial}},
+			certificate_hash TEXT NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_trust_history_certificate_hash ON trust_history (certificate_hash)`,
+
+		`CREATE TABLE instructor_stats (
+			instructor_id TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+		`CREATE TABLE instructor_activities (
+			instructor_id TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+		`CREATE TABLE instructor_analytics (
+			instructor_id TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+
+		`CREATE TABLE auth_nonces (
+			wallet TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+
+		`CREATE TABLE system_state (
+			id TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+	},
+	// 2: pending-mint flag for the reconciler
+	{
+		`ALTER TABLE certificates ADD COLUMN mint_pending BOOLEAN NOT NULL DEFAULT FALSE`,
+		`UPDATE certificates SET mint_pending = NOT ({{json_bool:isMinted}} OR {{json_bool:revoked}})`,
+		`CREATE INDEX idx_certificates_mint_pending ON certificates (mint_pending, issued_at)`,
+	},
+	// 3: platform wallet transactions tracked by the transaction manager
+	{
+		`CREATE TABLE chain_transactions (
+			id TEXT PRIMARY KEY,
+			status TEXT NOT NULL DEFAULT '',
+			nonce BIGINT NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_chain_transactions_status ON chain_transactions (status, nonce)`,
+	},
+	// 4: Merkle anchoring batches
+	{
+		`CREATE TABLE anchor_batches (
+			id TEXT PRIMARY KEY,
+			status TEXT NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_anchor_batches_status ON anchor_batches (status, created_at)`,
+	},
+	// 5: issuer authorization applications
+	{
+		`CREATE TABLE issuer_applications (
+			id TEXT PRIMARY KEY,
+			status TEXT NOT NULL DEFAULT '',
+			submitted_at BIGINT NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_issuer_applications_status ON issuer_applications (status, submitted_at)`,
+	},
+	// 6: gasless mints sent by the relayer
+	{
+		`CREATE TABLE relayed_mints (
+			id TEXT PRIMARY KEY,
+			instructor_wallet TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_relayed_mints_instructor ON relayed_mints (instructor_wallet, created_at)`,
+	},
+	// 7: user roles, to find the admins to alert
+	{
+		`ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT ''`,
+		`UPDATE users SET role = {{json_text:role}}`,
+		`CREATE INDEX idx_users_role ON users (role)`,
+	},
+	// 8: Open Badges imported from other issuers
+	{
+		`CREATE TABLE imported_badges (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL DEFAULT '',
+			imported_at BIGINT NOT NULL DEFAULT 0,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_imported_badges_user ON imported_badges (user_id, imported_at)`,
+	},
+	// 9: Bitstring status lists for credential revocation
+	{
+		`CREATE TABLE status_lists (
+			id TEXT PRIMARY KEY,
+			data TEXT NOT NULL
+		)`,
+	},
+	// 10: certificate wallet columns are stored lowercase, like the other tables'
+	{
+		`UPDATE certificates SET wallet_address = LOWER(wallet_address), instructor_wallet = LOWER(instructor_wallet)`,
+	},
+	// 11: revocations sent on-chain and not yet mined
+	{
+		`ALTER TABLE certificates ADD COLUMN revocation_pending BOOLEAN NOT NULL DEFAULT FALSE`,
+		`CREATE INDEX idx_certificates_revocation_pending ON certificates (revocation_pending, issued_at)`,
+	},
+}
+
+// migrate applies every migration newer than the recorded schema version.
+// Each migration runs in its own transaction.
+func (s *sqlStore) migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at BIGINT NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	for i := current; i < len(sqlMigrations); i++ {
+		version := i + 1
+		if err := s.applyMigration(ctx, version, sqlMigrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+		log.Printf("Storage: applied SQL migration %d", version)
+	}
+	return nil
+}
+
+func (s *sqlStore) applyMigration(ctx context.Context, version int, statements []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, s.expandSchema(stmt)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`), version, time.Now().UnixMilli()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// jsonBoolPlaceholder and jsonTextPlaceholder match {{json_bool:field}} and
+// {{json_text:field}} in migration statements
+var (
+	jsonBoolPlaceholder = regexp.MustCompile(`\{\{json_bool:(\w+)\}\}`)
+	jsonTextPlaceholder = regexp.MustCompile(`\{\{json_text:(\w+)\}\}`)
+)
+
+// expandSchema replaces dialect placeholders in a migration statement
+func (s *sqlStore) expandSchema(stmt string) string {
+	serial := "INTEGER PRIMARY KEY AUTOINCREMENT"
+	jsonBool := "COALESCE(json_extract(data, '$$.$1'), 0)"
+	jsonText := "COALESCE(json_extract(data, '$$.$1'), '')"
+	if s.dialect == DialectPostgres {
+		serial = "BIGSERIAL PRIMARY KEY"
+		jsonBool = "COALESCE((data::jsonb ->> '$1')::boolean, FALSE)"
+		jsonText = "COALESCE(data::jsonb ->> '$1', '')"
+	}
+	stmt = strings.ReplaceAll(stmt, "{{serial}}", serial)
+	stmt = jsonBoolPlaceholder.ReplaceAllString(stmt, jsonBool)
+	return jsonTextPlaceholder.ReplaceAllString(stmt, jsonText)
+}
@@ -0,0 +1,1092 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cache-crew/cognify/internal/models"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" driver
+	_ "github.com/mattn/go-sqlite3"    // registers the "sqlite3" driver
+)
+
+// Supported SQL dialects (values of config.StorageBackend)
+const (
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// defaultSQLitePath is used when DATABASE_URL is empty
+const defaultSQLitePath = "cognify.db"
+
+// SQLClient is the open SQL connection when a SQL backend is active
+var SQLClient *sql.DB
+
+// sqlStore wraps a connection together with its dialect
+type sqlStore struct {
+	db      *sql.DB
+	dialect string
+}
+
+// sqlQuerier is satisfied by both *sql.DB and *sql.Tx
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// rebind converts ? placeholders to $n for Postgres
+func (s *sqlStore) rebind(query string) string {
+	if s.dialect != DialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// inTx runs fn inside a transaction, committing only if fn succeeds
+func (s *sqlStore) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// OpenSQL connects to SQLite or Postgres and applies pending migrations
+func OpenSQL(ctx context.Context, dialect, dsn string) (*sql.DB, error) {
+	var (
+		conn *sql.DB
+		err  error
+	)
+
+	switch dialect {
+	case DialectSQLite:
+		if dsn == "" {
+			dsn = defaultSQLitePath
+		}
+		conn, err = sql.Open("sqlite3", sqliteDSN(dsn))
+		if err != nil {
+			return nil, err
+		}
+		// SQLite allows a single writer; one connection keeps
+		// read-modify-write transactions from failing with SQLITE_BUSY
+		conn.SetMaxOpenConns(1)
+	case DialectPostgres:
+		if dsn == "" {
+			return nil, errors.New("DATABASE_URL is required for the postgres backend")
+		}
+		conn, err = sql.Open("pgx", dsn)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported SQL dialect %q", dialect)
+	}
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	store := &sqlStore{db: conn, dialect: dialect}
+	if err := store.migrate(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// sqliteDSN enables foreign keys and WAL unless the caller already set options
+func sqliteDSN(path string) string {
+	if strings.Contains(path, "?") {
+		return path
+	}
+	return "file:" + path + "?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000"
+}
+
+// CloseSQL closes the SQL connection if one is open
+func CloseSQL() {
+	if SQLClient != nil {
+		SQLClient.Close()
+	}
+}
+
+// sqlColumn is an indexed column stored alongside a table's JSON document
+type sqlColumn struct {
+	name  string
+	value any
+}
+
+// sqlTable stores one model per row as JSON, keyed by a text primary key.
+// columns extracts the indexed columns that queries filter or sort on.
+type sqlTable[T any] struct {
+	store   *sqlStore
+	name    string
+	key     string
+	columns func(doc T) []sqlColumn
+}
+
+func newSQLTable[T any](store *sqlStore, name, key string, columns func(doc T) []sqlColumn) *sqlTable[T] {
+	return &sqlTable[T]{store: store, name: name, key: key, columns: columns}
+}
+
+func (t *sqlTable[T]) get(ctx context.Context, id string) (*T, error) {
+	return t.load(ctx, t.store.db, id, false)
+}
+
+// load reads a document; lock adds FOR UPDATE on dialects that support it
+func (t *sqlTable[T]) load(ctx context.Context, q sqlQuerier, id string, lock bool) (*T, error) {
+	query := "SELECT data FROM " + t.name + " WHERE " + t.key + " = ?"
+	if lock && t.store.dialect == DialectPostgres {
+		query += " FOR UPDATE"
+	}
+
+	var raw string
+	err := q.QueryRowContext(ctx, t.store.rebind(query), id).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc T
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, fmt.Errorf("decode %s/%s: %w", t.name, id, err)
+	}
+	return &doc, nil
+}
+
+func (t *sqlTable[T]) set(ctx context.Context, id string, doc T) error {
+	return t.write(ctx, t.store.db, id, doc)
+}
+
+// write upserts a document and its indexed columns
+func (t *sqlTable[T]) write(ctx context.Context, q sqlQuerier, id string, doc T) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	names := []string{t.key}
+	args := []any{id}
+	if t.columns != nil {
+		for _, c := range t.columns(doc) {
+			names = append(names, c.name)
+			args = append(args, c.value)
+		}
+	}
+	names = append(names, "data")
+	args = append(args, string(raw))
+
+	updates := make([]string, 0, len(names)-1)
+	for _, name := range names[1:] {
+		updates = append(updates, name+" = excluded."+name)
+	}
+
+	query := "INSERT INTO " + t.name + " (" + strings.Join(names, ", ") + ") VALUES (" +
+		strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ") + ") ON CONFLICT (" + t.key + ") DO UPDATE SET " +
+		strings.Join(updates, ", ")
+	_, err = q.ExecContext(ctx, t.store.rebind(query), args...)
+	return err
+}
+
+func (t *sqlTable[T]) delete(ctx context.Context, id string) error {
+	_, err := t.store.db.ExecContext(ctx, t.store.rebind("DELETE FROM "+t.name+" WHERE "+t.key+" = ?"), id)
+	return err
+}
+
+// update applies fn to a document inside a transaction. Returns ErrNotFound if missing.
+func (t *sqlTable[T]) update(ctx context.Context, id string, fn func(doc *T)) error {
+	return t.store.inTx(ctx, func(tx *sql.Tx) error {
+		doc, err := t.load(ctx, tx, id, true)
+		if err != nil {
+			return err
+		}
+		fn(doc)
+		return t.write(ctx, tx, id, *doc)
+	})
+}
+
+// upsert applies fn to a document inside a transaction, creating it if missing
+func (t *sqlTable[T]) upsert(ctx context.Context, id string, fn func(doc *T)) error {
+	return t.store.inTx(ctx, func(tx *sql.Tx) error {
+		doc, err := t.load(ctx, tx, id, true)
+		if errors.Is(err, ErrNotFound) {
+			doc, err = new(T), nil
+		}
+		if err != nil {
+			return err
+		}
+		fn(doc)
+		return t.write(ctx, tx, id, *doc)
+	})
+}
+
+// query returns every document matching clause (e.g. "WHERE user_id = ? ORDER BY id")
+func (t *sqlTable[T]) query(ctx context.Context, clause string, args ...any) ([]T, error) {
+	return queryDocs[T](ctx, t.store, "SELECT data FROM "+t.name+" "+clause, args...)
+}
+
+func (t *sqlTable[T]) count(ctx context.Context, clause string, args ...any) (int, error) {
+	return countRows(ctx, t.store, "SELECT COUNT(*) FROM "+t.name+" "+clause, args...)
+}
+
+// queryDocs runs a query whose only column is a JSON document
+func queryDocs[T any](ctx context.Context, store *sqlStore, query string, args ...any) ([]T, error) {
+	rows, err := store.db.QueryContext(ctx, store.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []T{}
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var doc T
+		if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+			return nil, err
+		}
+		results = append(results, doc)
+	}
+	return results, rows.Err()
+}
+
+func countRows(ctx context.Context, store *sqlStore, query string, args ...any) (int, error) {
+	var n int
+	err := store.db.QueryRowContext(ctx, store.rebind(query), args...).Scan(&n)
+	return n, err
+}
+
+// insertDoc appends a document to a table with an auto-increment key
+func insertDoc(ctx context.Context, store *sqlStore, table string, doc any, columns ...sqlColumn) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(columns)+1)
+	args := make([]any, 0, len(columns)+1)
+	for _, c := range columns {
+		names = append(names, c.name)
+		args = append(args, c.value)
+	}
+	names = append(names, "data")
+	args = append(args, string(raw))
+
+	query := "INSERT INTO " + table + " (" + strings.Join(names, ", ") + ") VALUES (" +
+		strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ") + ")"
+	_, err = store.db.ExecContext(ctx, store.rebind(query), args...)
+	return err
+}
+
+// sqlTime converts a timestamp to the Unix milliseconds stored in ordering columns
+func sqlTime(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+// NewSQLRepositories builds repositories on top of an open, migrated SQL connection
+func NewSQLRepositories(conn *sql.DB, dialect string) *Repositories {
+	store := &sqlStore{db: conn, dialect: dialect}
+
+	return &Repositories{
+		Users: &sqlUsers{newSQLTable(store, "users", "id", func(u sqlUserRow) []sqlColumn {
+			return []sqlColumn{{"wallet_address", strings.ToLower(u.WalletAddress)}, {"role", u.Role}}
+		})},
+		Courses: &sqlCourses{newSQLTable(store, "courses", "id", func(c models.Course) []sqlColumn {
+			return []sqlColumn{{"instructor_id", c.InstructorID}}
+		})},
+		Questions: &sqlQuestions{newSQLTable[models.Question](store, "questions", "id", nil)},
+		Enrollments: &sqlEnrollments{newSQLTable(store, "enrollments", "id", func(e models.Enrollment) []sqlColumn {
+			return []sqlColumn{{"user_id", e.UserID}, {"course_id", e.CourseID}}
+		})},
+		Certificates: &sqlCertificates{newSQLTable(store, "certificates", "hash", func(c models.Certificate) []sqlColumn {
+			return []sqlColumn{
+				{"wallet_address", strings.ToLower(c.WalletAddress)},
+				{"instructor_wallet", strings.ToLower(c.InstructorWallet)},
+				{"issued_at", sqlTime(c.IssuedAt)},
+				{"trust_score", c.TrustScore},
+				{"mint_pending", !c.IsMinted && !c.Revoked && !c.MintExpired && c.AnchorRoot == ""},
+				{"revocation_pending", c.PendingRevocation != nil},
+			}
+		})},
+		Posts: &sqlPosts{newSQLTable(store, "posts", "id", func(p models.Post) []sqlColumn {
+			return []sqlColumn{{"course_id", p.CourseID}, {"created_at", sqlTime(p.CreatedAt)}}
+		})},
+		Comments: &sqlComments{newSQLTable(store, "comments", "id", func(c models.Comment) []sqlColumn {
+			return []sqlColumn{{"post_id", c.PostID}, {"created_at", sqlTime(c.CreatedAt)}}
+		})},
+		UserStats: &sqlUserStats{newSQLTable(store, "user_stats", "user_id", func(s models.UserStats) []sqlColumn {
+			return []sqlColumn{{"total_xp", s.TotalXP}}
+		})},
+		Achievements: &sqlAchievements{newSQLTable(store, "user_achievements", "id", func(a models.UserAchievement) []sqlColumn {
+			return []sqlColumn{{"user_id", a.UserID}}
+		})},
+		Notifications: &sqlNotifications{newSQLTable(store, "notifications", "id", func(n models.Notification) []sqlColumn {
+			return []sqlColumn{{"user_id", n.UserID}}
+		})},
+		VerificationLogs:    &sqlVerificationLogs{store},
+		VerificationMetrics: &sqlVerificationMetrics{newSQLTable[models.VerificationMetric](store, "verification_metrics", "certificate_hash", nil)},
+		Trust: &sqlTrust{
+			store:       store,
+			reputations: newSQLTable[models.IssuerReputation](store, "issuer_reputation", "instructor_id", nil),
+		},
+		Instructors: &sqlInstructors{
+			stats:      newSQLTable[models.InstructorStats](store, "instructor_stats", "instructor_id", nil),
+			activities: newSQLTable[[]models.ActivityItem](store, "instructor_activities", "instructor_id", nil),
+			analytics:  newSQLTable[models.InstructorAnalytics](store, "instructor_analytics", "instructor_id", nil),
+		},
+		AuthNonces: &sqlAuthNonces{newSQLTable[models.AuthNonce](store, "auth_nonces", "wallet", nil)},
+		SystemState: &sqlSystemState{
+			table:   newSQLTable[models.SystemState](store, "system_state", "id", nil),
+			reports: newSQLTable[models.ReconciliationReport](store, "system_state", "id", nil),
+			jobs:    newSQLTable[models.BackfillJob](store, "system_state", "id", nil),
+		},
+		Transactions: &sqlTransactions{newSQLTable(store, "chain_transactions", "id", func(tx models.ChainTransaction) []sqlColumn {
+			return []sqlColumn{{"status", tx.Status}, {"nonce", int64(tx.Nonce)}}
+		})},
+		Anchors: &sqlAnchors{newSQLTable(store, "anchor_batches", "id", func(b models.AnchorBatch) []sqlColumn {
+			return []sqlColumn{{"status", b.Status}, {"created_at", sqlTime(b.CreatedAt)}}
+		})},
+		IssuerApplications: &sqlIssuerApplications{newSQLTable(store, "issuer_applications", "id", func(a models.IssuerApplication) []sqlColumn {
+			return []sqlColumn{{"status", a.Status}, {"submitted_at", sqlTime(a.SubmittedAt)}}
+		})},
+		RelayedMints: &sqlRelayedMints{newSQLTable(store, "relayed_mints", "id", func(m models.RelayedMint) []sqlColumn {
+			return []sqlColumn{
+				{"instructor_wallet", strings.ToLower(m.InstructorWallet)},
+				{"status", m.Status},
+				{"created_at", sqlTime(m.CreatedAt)},
+			}
+		})},
+		ImportedBadges: &sqlImportedBadges{newSQLTable(store, "imported_badges", "id", func(b models.ImportedBadge) []sqlColumn {
+			return []sqlColumn{{"user_id", b.UserID}, {"imported_at", sqlTime(b.ImportedAt)}}
+		})},
+		StatusLists: &sqlStatusLists{newSQLTable[models.StatusList](store, "status_lists", "id", nil)},
+	}
+}
+
+// -------------------------------------------------------------------
+// USERS
+// -------------------------------------------------------------------
+
+// sqlUserRow persists the password hash, which models.User hides from JSON
+type sqlUserRow struct {
+	models.User
+	PasswordHash string `json:"passwordHash,omitempty"`
+}
+
+func newSQLUserRow(u models.User) sqlUserRow {
+	return sqlUserRow{User: u, PasswordHash: u.Password}
+}
+
+func (row *sqlUserRow) user() *models.User {
+	u := row.User
+	u.Password = row.PasswordHash
+	return &u
+}
+
+// apply runs fn on the user view of the row and copies the result back
+func (row *sqlUserRow) apply(fn func(u *models.User)) {
+	u := row.user()
+	fn(u)
+	*row = newSQLUserRow(*u)
+}
+
+type sqlUsers struct {
+	table *sqlTable[sqlUserRow]
+}
+
+func (r *sqlUsers) Get(ctx context.Context, id string) (*models.User, error) {
+	row, err := r.table.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return row.user(), nil
+}
+
+func (r *sqlUsers) GetByWallet(ctx context.Context, wallet string) (*models.User, error) {
+	rows, err := r.table.query(ctx, "WHERE wallet_address = ? LIMIT 1", strings.ToLower(wallet))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+	return rows[0].user(), nil
+}
+
+func (r *sqlUsers) Save(ctx context.Context, user *models.User) error {
+	return r.table.set(ctx, user.ID, newSQLUserRow(*user))
+}
+
+func (r *sqlUsers) UpdateProfile(ctx context.Context, id string, update UserProfileUpdate) error {
+	return r.table.upsert(ctx, id, func(row *sqlUserRow) {
+		row.apply(func(u *models.User) {
+			if u.ID == "" {
+				u.ID = id
+			}
+			if update.Name != "" {
+				u.Name = update.Name
+			}
+			if update.Username != "" {
+				u.Username = update.Username
+			}
+			if update.Bio != "" {
+				u.Bio = update.Bio
+			}
+			if update.AvatarEmoji != "" {
+				u.AvatarEmoji = update.AvatarEmoji
+			}
+			if update.Institution != "" {
+				u.Institution = update.Institution
+			}
+			u.UpdatedAt = time.Now()
+		})
+	})
+}
+
+func (r *sqlUsers) SetPassword(ctx context.Context, id, passwordHash string) error {
+	return r.table.update(ctx, id, func(row *sqlUserRow) { row.PasswordHash = passwordHash })
+}
+
+func (r *sqlUsers) SetProgress(ctx context.Context, id string, xp, level int) error {
+	return r.table.update(ctx, id, func(row *sqlUserRow) {
+		row.XP = xp
+		row.Level = level
+	})
+}
+
+func (r *sqlUsers) SetWallet(ctx context.Context, id, wallet string) error {
+	return r.table.update(ctx, id, func(row *sqlUserRow) { row.WalletAddress = strings.ToLower(wallet) })
+}
+
+func (r *sqlUsers) ListByRole(ctx context.Context, role string) ([]models.User, error) {
+	rows, err := r.table.query(ctx, "WHERE role = ?", role)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]models.User, len(rows))
+	for i := range rows {
+		users[i] = *rows[i].user()
+	}
+	return users, nil
+}
+
+func (r *sqlUsers) SetAuthorization(ctx context.Context, wallet string, authorized bool) error {
+	rows, err := r.table.query(ctx, "WHERE wallet_address = ?", strings.ToLower(wallet))
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		err := r.table.update(ctx, row.ID, func(row *sqlUserRow) {
+			row.IsAuthorized = authorized
+			if authorized {
+				row.Role = "instructor"
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// -------------------------------------------------------------------
+// COURSES & QUESTIONS
+// -------------------------------------------------------------------
+
+type sqlCourses struct {
+	table *sqlTable[models.Course]
+}
+
+func (r *sqlCourses) List(ctx context.Context) ([]models.Course, error) {
+	return r.table.query(ctx, "ORDER BY id")
+}
+
+func (r *sqlCourses) ListByInstructor(ctx context.Context, instructorID string) ([]models.Course, error) {
+	return r.table.query(ctx, "WHERE instructor_id = ?", instructorID)
+}
+
+func (r *sqlCourses) Get(ctx context.Context, id string) (*models.Course, error) {
+	return r.table.get(ctx, id)
+}
+
+func (r *sqlCourses) Save(ctx context.Context, course *models.Course) error {
+	return r.table.set(ctx, course.ID, *course)
+}
+
+type sqlQuestions struct {
+	table *sqlTable[models.Question]
+}
+
+func (r *sqlQuestions) List(ctx context.Context, limit int) ([]models.Question, error) {
+	if limit > 0 {
+		return r.table.query(ctx, "ORDER BY id LIMIT ?", limit)
+	}
+	return r.table.query(ctx, "ORDER BY id")
+}
+
+func (r *sqlQuestions) Get(ctx context.Context, id string) (*models.Question, error) {
+	return r.table.get(ctx, id)
+}
+
+func (r *sqlQuestions) Save(ctx context.Context, question *models.Question) error {
+	return r.table.set(ctx, question.ID, *question)
+}
+
+// -------------------------------------------------------------------
+// ENROLLMENTS
+// -------------------------------------------------------------------
+
+type sqlEnrollments struct {
+	table *sqlTable[models.Enrollment]
+}
+
+func (r *sqlEnrollments) Get(ctx context.Context, id string) (*models.Enrollment, error) {
+	return r.table.get(ctx, id)
+}
+
+func (r *sqlEnrollments) ListByUser(ctx context.Context, userID string) ([]models.Enrollment, error) {
+	return r.table.query(ctx, "WHERE user_id = ?", userID)
+}
+
+func (r *sqlEnrollments) Save(ctx context.Context, enrollment *models.Enrollment) error {
+	return r.table.set(ctx, enrollment.ID, *enrollment)
+}
+
+// -------------------------------------------------------------------
+// CERTIFICATES
+// -------------------------------------------------------------------
+
+type sqlCertificates struct {
+	table *sqlTable[models.Certificate]
+}
+
+func (r *sqlCertificates) Get(ctx context.Context, hash string) (*models.Certificate, error) {
+	return r.table.get(ctx, hash)
+}
+
+func (r *sqlCertificates) Save(ctx context.Context, cert *models.Certificate) error {
+	return r.table.set(ctx, cert.Hash, *cert)
+}
+
+func (r *sqlCertificates) ListByWallet(ctx context.Context, wallet string) ([]models.Certificate, error) {
+	return r.table.query(ctx, "WHERE wallet_address = ?", strings.ToLower(wallet))
+}
+
+func (r *sqlCertificates) ListByInstructor(ctx context.Context, instructorWallet string) ([]models.Certificate, error) {
+	return r.table.query(ctx, "WHERE instructor_wallet = ? ORDER BY issued_at DESC", strings.ToLower(instructorWallet))
+}
+
+func (r *sqlCertificates) Count(ctx context.Context) (int, error) {
+	return r.table.count(ctx, "")
+}
+
+func (r *sqlCertificates) CountBelowTrustScore(ctx context.Context, score int) (int, error) {
+	return r.table.count(ctx, "WHERE trust_score < ?", score)
+}
+
+func (r *sqlCertificates) MarkMinted(ctx context.Context, hash, txHash string, block uint64, chain models.ChainRef) error {
+	return r.table.upsert(ctx, hash, func(c *models.Certificate) {
+		c.Hash = hash
+		c.IsMinted = true
+		c.BlockchainTx = txHash
+		c.BlockNumber = block
+		c.Chain = &chain
+		c.MintedAt = time.Now()
+		c.Revoked = false
+		c.MintExpired = false
+		c.MintUnauthorized = false
+		c.MintRejection = ""
+	})
+}
+
+func (r *sqlCertificates) MarkMintUnauthorized(ctx context.Context, hash, reason string) error {
+	return r.table.update(ctx, hash, func(c *models.Certificate) {
+		c.MintUnauthorized = true
+		c.MintRejection = reason
+	})
+}
+
+func (r *sqlCertificates) MarkRevoked(ctx context.Context, hash, revokedBy, reason string, revokedAt time.Time) error {
+	return r.table.upsert(ctx, hash, func(c *models.Certificate) {
+		c.Hash = hash
+		c.Revoked = true
+		c.RevokedAt = revokedAt
+		c.RevokedBy = revokedBy
+		c.RevocationReason = reason
+		c.PendingRevocation = nil
+	})
+}
+
+func (r *sqlCertificates) MarkRevocationPending(ctx context.Context, hash string, pending models.PendingRevocation) error {
+	return r.table.update(ctx, hash, func(c *models.Certificate) {
+		c.PendingRevocation = &pending
+	})
+}
+
+func (r *sqlCertificates) ClearRevocationPending(ctx context.Context, hash string) error {
+	return r.table.update(ctx, hash, func(c *models.Certificate) {
+		c.PendingRevocation = nil
+	})
+}
+
+func (r *sqlCertificates) ListRevocationPending(ctx context.Context, limit int) ([]models.Certificate, error) {
+	if limit > 0 {
+		return r.table.query(ctx, "WHERE revocation_pending = ? ORDER BY issued_at LIMIT ?", true, limit)
+	}
+	return r.table.query(ctx, "WHERE revocation_pending = ? ORDER BY issued_at", true)
+}
+
+func (r *sqlCertificates) UnmarkMinted(ctx context.Context, hash string) error {
+	return r.table.update(ctx, hash, func(c *models.Certificate) {
+		c.IsMinted = false
+		c.BlockchainTx = ""
+		c.BlockNumber = 0
+		c.Chain = nil
+		c.MintedAt = time.Time{}
+		c.MintUnauthorized = false
+		c.MintRejection = ""
+	})
+}
+
+func (r *sqlCertificates) ClearRevocation(ctx context.Context, hash string) error {
+	return r.table.update(ctx, hash, func(c *models.Certificate) {
+		c.Revoked = false
+		c.RevokedAt = time.Time{}
+		c.RevokedBy = ""
+		c.RevocationReason = ""
+	})
+}
+
+func (r *sqlCertificates) ListPending(ctx context.Context, limit int) ([]models.Certificate, error) {
+	if limit > 0 {
+		return r.table.query(ctx, "WHERE mint_pending = ? ORDER BY issued_at LIMIT ?", true, limit)
+	}
+	return r.table.query(ctx, "WHERE mint_pending = ? ORDER BY issued_at", true)
+}
+
+func (r *sqlCertificates) MarkExpired(ctx context.Context, hash string, expiredAt time.Time) error {
+	return r.table.update(ctx, hash, func(c *models.Certificate) {
+		c.MintExpired = true
+		c.MintExpiredAt = expiredAt
+	})
+}
+
+func (r *sqlCertificates) MarkAnchored(ctx context.Context, hash, root string, proof []string, chain models.ChainRef) error {
+	return r.table.update(ctx, hash, func(c *models.Certificate) {
+		c.AnchorRoot = root
+		c.MerkleProof = proof
+		c.Chain = &chain
+	})
+}
+
+// -------------------------------------------------------------------
+// FORUM
+// -------------------------------------------------------------------
+
+type sqlPosts struct {
+	table *sqlTable[models.Post]
+}
+
+func (r *sqlPosts) List(ctx context.Context, courseID string) ([]models.Post, error) {
+	if courseID != "" {
+		return r.table.query(ctx, "WHERE course_id = ? ORDER BY created_at", courseID)
+	}
+	return r.table.query(ctx, "ORDER BY created_at")
+}
+
+func (r *sqlPosts) Get(ctx context.Context, id string) (*models.Post, error) {
+	return r.table.get(ctx, id)
+}
+
+func (r *sqlPosts) Save(ctx context.Context, post *models.Post) error {
+	return r.table.set(ctx, post.ID, *post)
+}
+
+func (r *sqlPosts) IncrementCommentCount(ctx context.Context, id string) error {
+	return r.table.update(ctx, id, func(p *models.Post) { p.CommentCount++ })
+}
+
+func (r *sqlPosts) IncrementViewCount(ctx context.Context, id string) error {
+	return r.table.update(ctx, id, func(p *models.Post) { p.ViewCount++ })
+}
+
+type sqlComments struct {
+	table *sqlTable[models.Comment]
+}
+
+func (r *sqlComments) ListByPost(ctx context.Context, postID string) ([]models.Comment, error) {
+	return r.table.query(ctx, "WHERE post_id = ? ORDER BY created_at", postID)
+}
+
+func (r *sqlComments) Get(ctx context.Context, id string) (*models.Comment, error) {
+	return r.table.get(ctx, id)
+}
+
+func (r *sqlComments) Save(ctx context.Context, comment *models.Comment) error {
+	return r.table.set(ctx, comment.ID, *comment)
+}
+
+// -------------------------------------------------------------------
+// GAMIFICATION
+// -------------------------------------------------------------------
+
+type sqlUserStats struct {
+	table *sqlTable[models.UserStats]
+}
+
+func (r *sqlUserStats) Get(ctx context.Context, userID string) (*models.UserStats, error) {
+	return r.table.get(ctx, userID)
+}
+
+func (r *sqlUserStats) Save(ctx context.Context, stats *models.UserStats) error {
+	return r.table.set(ctx, stats.UserID, *stats)
+}
+
+func (r *sqlUserStats) Increment(ctx context.Context, userID, counter string, delta int) error {
+	return r.table.update(ctx, userID, func(s *models.UserStats) {
+		switch counter {
+		case StatForumPosts:
+			s.ForumPosts += delta
+		case StatForumComments:
+			s.ForumComments += delta
+		case StatCoursesCompleted:
+			s.CoursesCompleted += delta
+		case StatTotalXP:
+			s.TotalXP += delta
+		}
+	})
+}
+
+func (r *sqlUserStats) ListByXP(ctx context.Context, limit int) ([]models.UserStats, error) {
+	if limit > 0 {
+		return r.table.query(ctx, "ORDER BY total_xp DESC LIMIT ?", limit)
+	}
+	return r.table.query(ctx, "ORDER BY total_xp DESC")
+}
+
+func (r *sqlUserStats) SetGlobalRank(ctx context.Context, userID string, rank int) error {
+	return r.table.update(ctx, userID, func(s *models.UserStats) { s.GlobalRank = rank })
+}
+
+type sqlAchievements struct {
+	table *sqlTable[models.UserAchievement]
+}
+
+func (r *sqlAchievements) ListByUser(ctx context.Context, userID string) ([]models.UserAchievement, error) {
+	return r.table.query(ctx, "WHERE user_id = ?", userID)
+}
+
+func (r *sqlAchievements) Save(ctx context.Context, achievement *models.UserAchievement) error {
+	return r.table.set(ctx, achievement.ID, *achievement)
+}
+
+// -------------------------------------------------------------------
+// NOTIFICATIONS
+// -------------------------------------------------------------------
+
+type sqlNotifications struct {
+	table *sqlTable[models.Notification]
+}
+
+func (r *sqlNotifications) ListByUser(ctx context.Context, userID string) ([]models.Notification, error) {
+	return r.table.query(ctx, "WHERE user_id = ?", userID)
+}
+
+func (r *sqlNotifications) Save(ctx context.Context, notification *models.Notification) error {
+	return r.table.set(ctx, notification.ID, *notification)
+}
+
+func (r *sqlNotifications) MarkRead(ctx context.Context, id string) error {
+	return r.table.update(ctx, id, func(n *models.Notification) { n.IsRead = true })
+}
+
+// -------------------------------------------------------------------
+// VERIFICATION & TRUST
+// -------------------------------------------------------------------
+
+type sqlVerificationLogs struct {
+	store *sqlStore
+}
+
+func (r *sqlVerificationLogs) Add(ctx context.Context, entry *models.VerificationLog) error {
+	return insertDoc(ctx, r.store, "verification_logs", entry,
+		sqlColumn{"certificate_hash", entry.CertificateHash},
+		sqlColumn{"verified", entry.Verified},
+		sqlColumn{"created_at", sqlTime(entry.Timestamp)},
+	)
+}
+
+func (r *sqlVerificationLogs) CountByResult(ctx context.Context, verified bool) (int, error) {
+	return countRows(ctx, r.store, "SELECT COUNT(*) FROM verification_logs WHERE verified = ?", verified)
+}
+
+func (r *sqlVerificationLogs) CountByCertificate(ctx context.Context, certHash string) (int, error) {
+	return countRows(ctx, r.store, "SELECT COUNT(*) FROM verification_logs WHERE certificate_hash = ?", certHash)
+}
+
+type sqlVerificationMetrics struct {
+	table *sqlTable[models.VerificationMetric]
+}
+
+func (r *sqlVerificationMetrics) Get(ctx context.Context, certHash string) (*models.VerificationMetric, error) {
+	return r.table.get(ctx, certHash)
+}
+
+func (r *sqlVerificationMetrics) Update(ctx context.Context, certHash string, fn func(*models.VerificationMetric) (*models.VerificationMetric, error)) error {
+	return r.table.store.inTx(ctx, func(tx *sql.Tx) error {
+		current, err := r.table.load(ctx, tx, certHash, true)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		updated, err := fn(current)
+		if err != nil {
+			return err
+		}
+		return r.table.write(ctx, tx, certHash, *updated)
+	})
+}
+
+type sqlTrust struct {
+	store       *sqlStore
+	reputations *sqlTable[models.IssuerReputation]
+}
+
+func (r *sqlTrust) GetIssuerReputation(ctx context.Context, wallet string) (*models.IssuerReputation, error) {
+	return r.reputations.get(ctx, wallet)
+}
+
+func (r *sqlTrust) SaveIssuerReputation(ctx context.Context, rep *models.IssuerReputation) error {
+	return r.reputations.set(ctx, rep.InstructorID, *rep)
+}
+
+func (r *sqlTrust) AddHistory(ctx context.Context, event *models.TrustHistoryEvent) error {
+	return insertDoc(ctx, r.store, "trust_history", event,
+		sqlColumn{"certificate_hash", event.CertificateHash},
+		sqlColumn{"created_at", sqlTime(event.Timestamp)},
+	)
+}
+
+// -------------------------------------------------------------------
+// INSTRUCTOR DASHBOARD
+// -------------------------------------------------------------------
+
+type sqlInstructors struct {
+	stats      *sqlTable[models.InstructorStats]
+	activities *sqlTable[[]models.ActivityItem]
+	analytics  *sqlTable[models.InstructorAnalytics]
+}
+
+func (r *sqlInstructors) GetStats(ctx context.Context, instructorID string) (*models.InstructorStats, error) {
+	return r.stats.get(ctx, instructorID)
+}
+
+func (r *sqlInstructors) SaveStats(ctx context.Context, stats *models.InstructorStats) error {
+	return r.stats.set(ctx, stats.InstructorID, *stats)
+}
+
+func (r *sqlInstructors) GetActivities(ctx context.Context, instructorID string) ([]models.ActivityItem, error) {
+	items, err := r.activities.get(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	return *items, nil
+}
+
+func (r *sqlInstructors) SaveActivities(ctx context.Context, instructorID string, items []models.ActivityItem) error {
+	return r.activities.set(ctx, instructorID, items)
+}
+
+func (r *sqlInstructors) GetAnalytics(ctx context.Context, instructorID string) (*models.InstructorAnalytics, error) {
+	return r.analytics.get(ctx, instructorID)
+}
+
+func (r *sqlInstructors) SaveAnalytics(ctx context.Context, analytics *models.InstructorAnalytics) error {
+	return r.analytics.set(ctx, analytics.InstructorID, *analytics)
+}
+
+// -------------------------------------------------------------------
+// AUTH NONCES & SYSTEM STATE
+// -------------------------------------------------------------------
+
+type sqlAuthNonces struct {
+	table *sqlTable[models.AuthNonce]
+}
+
+func (r *sqlAuthNonces) Get(ctx context.Context, wallet string) (*models.AuthNonce, error) {
+	return r.table.get(ctx, strings.ToLower(wallet))
+}
+
+func (r *sqlAuthNonces) Save(ctx context.Context, wallet string, nonce *models.AuthNonce) error {
+	return r.table.set(ctx, strings.ToLower(wallet), *nonce)
+}
+
+func (r *sqlAuthNonces) Delete(ctx context.Context, wallet string) error {
+	return r.table.delete(ctx, strings.ToLower(wallet))
+}
+
+type sqlSystemState struct {
+	table   *sqlTable[models.SystemState]
+	reports *sqlTable[models.ReconciliationReport]
+	jobs    *sqlTable[models.BackfillJob]
+}
+
+func (r *sqlSystemState) GetSyncState(ctx context.Context) (*models.SystemState, error) {
+	return r.table.get(ctx, "sync_state")
+}
+
+func (r *sqlSystemState) SaveSyncState(ctx context.Context, state *models.SystemState) error {
+	state.ID = "sync_state"
+	state.UpdatedAt = time.Now()
+	return r.table.set(ctx, state.ID, *state)
+}
+
+func (r *sqlSystemState) GetReconciliationReport(ctx context.Context) (*models.ReconciliationReport, error) {
+	return r.reports.get(ctx, "reconciliation")
+}
+
+func (r *sqlSystemState) SaveReconciliationReport(ctx context.Context, report *models.ReconciliationReport) error {
+	report.ID = "reconciliation"
+	return r.reports.set(ctx, report.ID, *report)
+}
+
+func (r *sqlSystemState) GetBackfillJob(ctx context.Context) (*models.BackfillJob, error) {
+	return r.jobs.get(ctx, "backfill")
+}
+
+func (r *sqlSystemState) SaveBackfillJob(ctx context.Context, job *models.BackfillJob) error {
+	job.ID = "backfill"
+	return r.jobs.set(ctx, job.ID, *job)
+}
+
+type sqlTransactions struct {
+	table *sqlTable[models.ChainTransaction]
+}
+
+func (r *sqlTransactions) Get(ctx context.Context, id string) (*models.ChainTransaction, error) {
+	return r.table.get(ctx, id)
+}
+
+func (r *sqlTransactions) Save(ctx context.Context, tx *models.ChainTransaction) error {
+	return r.table.set(ctx, tx.ID, *tx)
+}
+
+func (r *sqlTransactions) ListPending(ctx context.Context) ([]models.ChainTransaction, error) {
+	return r.table.query(ctx, "WHERE status = ? ORDER BY nonce", models.TxStatusPending)
+}
+
+type sqlAnchors struct {
+	table *sqlTable[models.AnchorBatch]
+}
+
+func (r *sqlAnchors) Get(ctx context.Context, root string) (*models.AnchorBatch, error) {
+	return r.table.get(ctx, root)
+}
+
+func (r *sqlAnchors) Save(ctx context.Context, batch *models.AnchorBatch) error {
+	return r.table.set(ctx, batch.ID, *batch)
+}
+
+func (r *sqlAnchors) ListPending(ctx context.Context) ([]models.AnchorBatch, error) {
+	return r.table.query(ctx, "WHERE status = ? ORDER BY created_at", models.AnchorStatusPending)
+}
+
+type sqlIssuerApplications struct {
+	table *sqlTable[models.IssuerApplication]
+}
+
+func (r *sqlIssuerApplications) Get(ctx context.Context, wallet string) (*models.IssuerApplication, error) {
+	return r.table.get(ctx, strings.ToLower(wallet))
+}
+
+func (r *sqlIssuerApplications) Save(ctx context.Context, app *models.IssuerApplication) error {
+	app.ID = strings.ToLower(app.Wallet)
+	return r.table.set(ctx, app.ID, *app)
+}
+
+func (r *sqlIssuerApplications) List(ctx context.Context, status string) ([]models.IssuerApplication, error) {
+	if status == "" {
+		return r.table.query(ctx, "ORDER BY submitted_at")
+	}
+	return r.table.query(ctx, "WHERE status = ? ORDER BY submitted_at", status)
+}
+
+type sqlRelayedMints struct {
+	table *sqlTable[models.RelayedMint]
+}
+
+func (r *sqlRelayedMints) Get(ctx context.Context, certHash string) (*models.RelayedMint, error) {
+	return r.table.get(ctx, certHash)
+}
+
+func (r *sqlRelayedMints) Save(ctx context.Context, relay *models.RelayedMint) error {
+	return r.table.set(ctx, relay.ID, *relay)
+}
+
+func (r *sqlRelayedMints) ListByInstructor(ctx context.Context, instructorWallet string) ([]models.RelayedMint, error) {
+	return r.table.query(ctx, "WHERE instructor_wallet = ? ORDER BY created_at DESC", strings.ToLower(instructorWallet))
+}
+
+func (r *sqlRelayedMints) CountByInstructorSince(ctx context.Context, instructorWallet string, since time.Time) (int, error) {
+	return r.table.count(ctx, "WHERE instructor_wallet = ? AND created_at > ? AND status <> ?",
+		strings.ToLower(instructorWallet), sqlTime(since), models.RelayStatusFailed)
+}
+
+type sqlImportedBadges struct {
+	table *sqlTable[models.ImportedBadge]
+}
+
+func (r *sqlImportedBadges) Save(ctx context.Context, badge *models.ImportedBadge) error {
+	return r.table.set(ctx, badge.ID, *badge)
+}
+
+func (r *sqlImportedBadges) ListByUser(ctx context.Context, userID string) ([]models.ImportedBadge, error) {
+	return r.table.query(ctx, "WHERE user_id = ? ORDER BY imported_at DESC", userID)
+}
+
+type sqlStatusLists struct {
+	table *sqlTable[models.StatusList]
+}
+
+func (r *sqlStatusLists) Get(ctx context.Context, id string) (*models.StatusList, error) {
+	return r.table.get(ctx, id)
+}
+
+func (r *sqlStatusLists) Update(ctx context.Context, id string, fn func(*models.StatusList) (*models.StatusList, error)) error {
+	return r.table.store.inTx(ctx, func(tx *sql.Tx) error {
+		current, err := r.table.load(ctx, tx, id, true)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		updated, err := fn(current)
+		if err != nil {
+			return err
+		}
+		return r.table.write(ctx, tx, id, *updated)
+	})
+}
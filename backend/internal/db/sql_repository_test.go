@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/models"
+)
+
+// openTestSQLite opens a SQLite database in the test's temp dir, migrated to the latest schema
+func openTestSQLite(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	conn, err := OpenSQL(context.Background(), DialectSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenSQL: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestSQLRepositoriesRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	conn := openTestSQLite(t, filepath.Join(t.TempDir(), "cognify.db"))
+	repos := NewSQLRepositories(conn, DialectSQLite)
+
+	var version int
+	if err := conn.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil || version != len(sqlMigrations) {
+		t.Fatalf("schema version = %d, %v, want %d", version, err, len(sqlMigrations))
+	}
+
+	user := &models.User{
+		ID:            "u1",
+		Email:         "ada@example.com",
+		Name:          "Ada",
+		Role:          "instructor",
+		CreatedAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Password:      "bcrypt-hash",
+		WalletAddress: "0xAbCdEf0000000000000000000000000000000001",
+		IsAuthorized:  true,
+	}
+	if err := repos.Users.Save(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repos.Users.Get(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, user) {
+		t.Errorf("Users.Get = %+v, want %+v", got, user)
+	}
+	if byWallet, err := repos.Users.GetByWallet(ctx, "0xabcdef0000000000000000000000000000000001"); err != nil || byWallet.ID != user.ID {
+		t.Errorf("GetByWallet = %+v, %v", byWallet, err)
+	}
+
+	cert := &models.Certificate{
+		Hash:              "0xcert",
+		HashVersion:       models.CertificateHashVersion,
+		StudentID:         "s1",
+		StudentName:       "Zoë",
+		CourseName:        "Go",
+		Marks:             88.5,
+		WalletAddress:     "0xAbCdEf0000000000000000000000000000000002",
+		InstructorWallet:  user.WalletAddress,
+		IssuedAt:          time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC),
+		Skills:            []string{"concurrency"},
+		IsMinted:          true,
+		BlockchainTx:      "0xtx",
+		BlockNumber:       42,
+		Chain:             &models.ChainRef{ChainID: 137, Contract: "0xregistry"},
+		PendingRevocation: &models.PendingRevocation{TxHash: "0xrevoke", RequestedBy: "u1", Reason: "error", RequestedAt: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)},
+	}
+	if err := repos.Certificates.Save(ctx, cert); err != nil {
+		t.Fatal(err)
+	}
+	gotCert, err := repos.Certificates.Get(ctx, cert.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotCert, cert) {
+		t.Errorf("Certificates.Get = %+v, want %+v", gotCert, cert)
+	}
+	if pending, err := repos.Certificates.ListRevocationPending(ctx, 10); err != nil || len(pending) != 1 {
+		t.Errorf("ListRevocationPending = %d, %v, want the certificate", len(pending), err)
+	}
+	if err := repos.Certificates.MarkRevoked(ctx, cert.Hash, "u1", "error", time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := repos.Certificates.ListRevocationPending(ctx, 10); err != nil || len(pending) != 0 {
+		t.Errorf("ListRevocationPending after MarkRevoked = %d, %v, want none", len(pending), err)
+	}
+	if _, err := repos.Certificates.Get(ctx, "0xmissing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing certificate = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLMigrationLowercasesCertificateWallets(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cognify.db")
+
+	// A database at migration 9, holding rows written before wallets were lowercased
+	all := sqlMigrations
+	sqlMigrations = all[:9]
+	old := openTestSQLite(t, path)
+	sqlMigrations = all
+	if err := NewSQLRepositories(old, DialectSQLite).Users.Save(ctx, &models.User{ID: "u1", WalletAddress: "0xAbCdEf0000000000000000000000000000000001"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := old.Exec(`INSERT INTO certificates (hash, wallet_address, instructor_wallet, issued_at, data) VALUES (?, ?, ?, ?, ?)`,
+		"0xcert", "0xAbCdEf0000000000000000000000000000000002", "0xAbCdEf0000000000000000000000000000000001", 1,
+		`{"hash":"0xcert","walletAddress":"0xAbCdEf0000000000000000000000000000000002","instructorWallet":"0xAbCdEf0000000000000000000000000000000001","issuedAt":"2026-01-01T00:00:00Z"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old.Close()
+
+	repos := NewSQLRepositories(openTestSQLite(t, path), DialectSQLite)
+	for _, wallet := range []string{"0xAbCdEf0000000000000000000000000000000002", "0xABCDEF0000000000000000000000000000000002"} {
+		certs, err := repos.Certificates.ListByWallet(ctx, wallet)
+		if err != nil || len(certs) != 1 || certs[0].Hash != "0xcert" {
+			t.Errorf("ListByWallet(%s) = %+v, %v", wallet, certs, err)
+		}
+	}
+	if certs, err := repos.Certificates.ListByInstructor(ctx, "0xABCDEF0000000000000000000000000000000001"); err != nil || len(certs) != 1 {
+		t.Errorf("ListByInstructor = %+v, %v", certs, err)
+	}
+	if user, err := repos.Users.GetByWallet(ctx, "0xABCDEF0000000000000000000000000000000001"); err != nil || user.ID != "u1" {
+		t.Errorf("GetByWallet = %+v, %v", user, err)
+	}
+	// Migration 11 backfills existing rows as having no revocation pending
+	if pending, err := repos.Certificates.ListRevocationPending(ctx, 10); err != nil || len(pending) != 0 {
+		t.Errorf("ListRevocationPending = %+v, %v", pending, err)
+	}
+}
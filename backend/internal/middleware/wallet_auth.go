@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"cache-crew/cognify/internal/blockchain"
 	"cache-crew/cognify/internal/db"
 	"context"
 	"fmt"
@@ -91,39 +92,38 @@ func WalletAuthMiddleware(next http.Handler) http.Handler {
 		ctx := context.WithValue(r.Context(), "wallet", wallet)
 
 		// Fetch user from DB to get role
-		// Fetch user from DB to get role
-		if db.FirestoreClient != nil {
-			// QUERY by walletAddress field, do not assume Doc ID is wallet
-			// Ensure wallet is lowercase to match storage
-			walletLower := strings.ToLower(wallet)
-			iter := db.FirestoreClient.Collection("users").Where("walletAddress", "==", walletLower).Documents(r.Context())
-			snap, err := iter.Next() // Get first match
-
-			if err != nil {
-				// Log error but allow request to proceed (role check will fail downstream if needed)
-				log.Printf("WalletAuthMiddleware: Could not find user with wallet %s: %v", wallet, err)
-			} else {
-				data := snap.Data()
-				if role, ok := data["Role"].(string); ok {
-					ctx = context.WithValue(ctx, "role", role)
-
-					// If instructor, authorized check
-					if role == "instructor" {
-						ctx = context.WithValue(ctx, "is_authorized", true)
-					}
-				}
+		user, err := db.Repos.Users.GetByWallet(r.Context(), wallet)
+		if err != nil {
+			// Log error but allow request to proceed (role check will fail downstream if needed)
+			log.Printf("WalletAuthMiddleware: Could not find user with wallet %s: %v", wallet, err)
+		} else if user.Role != "" {
+			ctx = context.WithValue(ctx, "role", user.Role)
+
+			// Instructors may mint only while the registry lists their wallet as an issuer
+			if user.Role == "instructor" {
+				ctx = context.WithValue(ctx, "is_authorized", isAuthorizedIssuer(user.IsAuthorized, wallet))
 			}
-		} else {
-			// Mock Mode Fallback - For Dev/Audit
-			log.Println("WalletAuthMiddleware: DB client not available, ensuring role for audit")
-			ctx = context.WithValue(ctx, "role", "instructor")
-			ctx = context.WithValue(ctx, "is_authorized", true)
 		}
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// issuerAuthorization asks the registry whether a wallet may mint; tests replace it
+// to simulate an unreachable chain
+var issuerAuthorization = blockchain.IsAuthorizedIssuerCached
+
+// isAuthorizedIssuer checks the registry (cached). If the chain can't be reached it
+// falls back to the flag the event indexer last stored for the user.
+func isAuthorizedIssuer(stored bool, wallet string) bool {
+	authorized, err := issuerAuthorization(wallet)
+	if err != nil {
+		log.Printf("WalletAuthMiddleware: Could not check issuer authorization for %s, using stored flag: %v", wallet, err)
+		return stored
+	}
+	return authorized
+}
+
 // GetWalletFromContext retrieves the authenticated wallet address from context
 func GetWalletFromContext(ctx context.Context) (string, bool) {
 	wallet, ok := ctx.Value("wallet").(string)
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cache-crew/cognify/internal/blockchain"
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// signedInstructorRequest stores an instructor with the given stored flag and returns a
+// request carrying their wallet's personal_sign headers
+func signedInstructorRequest(t *testing.T, stored bool) (*http.Request, string) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	if err := db.Repos.Users.Save(context.Background(), &models.User{ID: wallet, WalletAddress: wallet, Role: "instructor", IsAuthorized: stored}); err != nil {
+		t.Fatal(err)
+	}
+
+	message := "Sign in to Cognify"
+	hash := crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+
+	req := httptest.NewRequest(http.MethodPost, "/api/certificates/mint", nil)
+	req.Header.Set("X-Wallet-Address", wallet)
+	req.Header.Set("X-Wallet-Signature", hexutil.Encode(sig))
+	req.Header.Set("X-Signed-Message", message)
+	return req, wallet
+}
+
+// authorizedInContext runs req through WalletAuthMiddleware and returns the
+// is_authorized value it put in the context
+func authorizedInContext(t *testing.T, req *http.Request) bool {
+	t.Helper()
+	var authorized, ok bool
+	handler := WalletAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorized, ok = r.Context().Value("is_authorized").(bool)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !ok {
+		t.Fatalf("middleware responded %d, is_authorized set %v: %s", rec.Code, ok, rec.Body)
+	}
+	return authorized
+}
+
+func TestWalletAuthIssuerAuthorization(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	client := blockchain.GetClient()
+
+	t.Run("chain overrides stored flag", func(t *testing.T) {
+		granted, grantedWallet := signedInstructorRequest(t, false)
+		if _, err := client.AuthorizeIssuer(grantedWallet); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { client.RevokeIssuer(grantedWallet) })
+		if !authorizedInContext(t, granted) {
+			t.Error("issuer authorized on chain but not stored: got unauthorized")
+		}
+
+		stale, _ := signedInstructorRequest(t, true)
+		if authorizedInContext(t, stale) {
+			t.Error("issuer stored as authorized but not on chain: got authorized")
+		}
+	})
+
+	t.Run("cached answer until forgotten", func(t *testing.T) {
+		req, wallet := signedInstructorRequest(t, false)
+		if authorizedInContext(t, req) {
+			t.Fatal("unauthorized issuer got authorized")
+		}
+		if _, err := client.AuthorizeIssuer(wallet); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { client.RevokeIssuer(wallet) })
+
+		if authorizedInContext(t, req) {
+			t.Error("cached answer not reused before ForgetIssuer")
+		}
+		blockchain.ForgetIssuer(wallet)
+		if !authorizedInContext(t, req) {
+			t.Error("grant not seen after ForgetIssuer")
+		}
+	})
+
+	t.Run("stored flag when chain unreachable", func(t *testing.T) {
+		previous := issuerAuthorization
+		issuerAuthorization = func(string) (bool, error) { return false, errors.New("dial tcp: connection refused") }
+		t.Cleanup(func() { issuerAuthorization = previous })
+
+		for _, stored := range []bool{true, false} {
+			req, _ := signedInstructorRequest(t, stored)
+			if got := authorizedInContext(t, req); got != stored {
+				t.Errorf("stored %v: got authorized %v", stored, got)
+			}
+		}
+	})
+}
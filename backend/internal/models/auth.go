@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // MetaMaskAuthRequest represents the authentication request from MetaMask
 type MetaMaskAuthRequest struct {
 	WalletAddress string `json:"walletAddress"`
@@ -19,3 +21,10 @@ type MetaMaskAuthResponse struct {
 	IsNewUser     bool   `json:"isNewUser"`
 	AcademicDNA   string `json:"academicDNA,omitempty"`
 }
+
+// AuthNonce is a one-time challenge a wallet must sign to log in
+type AuthNonce struct {
+	Nonce     string    `json:"nonce" firestore:"nonce"`
+	Message   string    `json:"message" firestore:"message"`
+	ExpiresAt time.Time `json:"expiresAt" firestore:"expiresAt"`
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type User struct {
 	ID              string    `json:"id,omitempty" firestore:"id,omitempty"`
@@ -19,6 +22,7 @@ type User struct {
 	UpdatedAt      time.Time `json:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
 	ProfilePicture string    `json:"profilePicture,omitempty" firestore:"profilePicture,omitempty"`
 	AvatarEmoji    string    `json:"avatarEmoji,omitempty" firestore:"avatarEmoji,omitempty"`
+	Bio            string    `json:"bio,omitempty" firestore:"bio,omitempty"`
 
 	// MetaMask Wallet Authentication
 	WalletAddress   string    `json:"walletAddress,omitempty" firestore:"wallet_address,omitempty"`
@@ -71,6 +75,7 @@ type Enrollment struct {
 	Completed   bool       `json:"completed" firestore:"completed"`
 	StartedAt   time.Time  `json:"startedAt" firestore:"startedAt"`
 	CompletedAt *time.Time `json:"completedAt,omitempty" firestore:"completedAt,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt,omitempty" firestore:"updatedAt,omitempty"`
 }
 
 type Post struct {
@@ -120,6 +125,7 @@ type Certificate struct {
 
 	// Blockchain Verification Fields (NEW)
 	Hash              string  `firestore:"hash" json:"hash,omitempty"`
+	HashVersion       int     `firestore:"hash_version,omitempty" json:"hashVersion,omitempty"` // CertificateHashVersion the hash was computed with; 0 for legacy hashes that cover no claims
 	StudentID         string  `firestore:"student_id" json:"studentId,omitempty"`
 	StudentName       string  `firestore:"student_name" json:"studentName,omitempty"`
 	CourseName        string  `firestore:"course_name" json:"courseName,omitempty"`
@@ -132,12 +138,86 @@ type Certificate struct {
 	Revoked           bool    `firestore:"revoked" json:"revoked,omitempty"`
 	IsMinted          bool    `firestore:"is_minted" json:"isMinted,omitempty"`
 
+	// On-chain sync state (written by the event listener / sync worker)
+	Chain       *ChainRef `firestore:"chain,omitempty" json:"chain,omitempty"` // Deployment holding the entry or root; nil before deployments were recorded
+	BlockNumber uint64    `firestore:"block_number,omitempty" json:"blockNumber,omitempty"`
+	MintedAt    time.Time `firestore:"minted_at,omitempty" json:"mintedAt,omitempty"`
+	RevokedAt   time.Time `firestore:"revoked_at,omitempty" json:"revokedAt,omitempty"`
+
+	// Revocation details (decoded from the CertificateRevoked event or set by the revoke API)
+	RevokedBy        string `firestore:"revoked_by,omitempty" json:"revokedBy,omitempty"`
+	RevocationReason string `firestore:"revocation_reason,omitempty" json:"revocationReason,omitempty"`
+
+	// Set by the revoke API while the platform wallet's revokeCertificate transaction is unmined.
+	// The CertificateRevoked event finalizes the revocation; the reconciler drops it if the transaction fails.
+	PendingRevocation *PendingRevocation `firestore:"pending_revocation,omitempty" json:"pendingRevocation,omitempty"`
+
+	// Set by the reconciler when a pending certificate is never minted within the TTL
+	MintExpired   bool      `firestore:"mint_expired,omitempty" json:"mintExpired,omitempty"`
+	MintExpiredAt time.Time `firestore:"mint_expired_at,omitempty" json:"mintExpiredAt,omitempty"`
+
+	// Set by the event indexer when a mint on chain fails its platform authorization check.
+	// The mint is still recorded, since the chain holds it; the reason says what failed.
+	MintUnauthorized bool   `firestore:"mint_unauthorized,omitempty" json:"mintUnauthorized,omitempty"`
+	MintRejection    string `firestore:"mint_rejection,omitempty" json:"mintRejection,omitempty"`
+
+	// Merkle anchoring: the certificate is proven by a batch root minted on-chain instead of its own entry
+	AnchorRoot  string   `firestore:"anchor_root,omitempty" json:"anchorRoot,omitempty"`
+	MerkleProof []string `firestore:"merkle_proof,omitempty" json:"merkleProof,omitempty"` // Sibling hashes, leaf to root
+
+	// Bitstring status list entry published for offline revocation checks
+	StatusListID    string `firestore:"status_list_id,omitempty" json:"statusListId,omitempty"`
+	StatusListIndex int    `firestore:"status_list_index,omitempty" json:"statusListIndex,omitempty"`
+
 	// Academic DNA Identity (NEW)
 	AcademicDNA string `firestore:"academic_dna" json:"academicDNA,omitempty"`
 
 	// Instructor Information (NEW)
 	InstructorWallet string `firestore:"instructor_wallet" json:"instructorWallet,omitempty"`
 	InstructorName   string `firestore:"instructor_name" json:"instructorName,omitempty"`
+
+	// Platform EIP-712 approval to mint; ConfirmMint rejects mints without a valid, unexpired one
+	// and the indexer flags them as MintUnauthorized
+	MintAuthorization *MintAuthorization `firestore:"mint_authorization,omitempty" json:"mintAuthorization,omitempty"`
+}
+
+// CertificateHashVersion is the current version of CertificateClaims. Bump it
+// whenever the claims or their canonical form change; stored hashes keep the
+// version they were computed with.
+const CertificateHashVersion = 1
+
+// CertificateClaims are the facts a certificate attests to. The certificate hash
+// is the SHA-256 of their canonical JSON (see utils.GenerateCanonicalHash), so
+// changing any claim changes the hash.
+type CertificateClaims struct {
+	Version          int     `json:"version"`
+	StudentID        string  `json:"studentId"`
+	StudentName      string  `json:"studentName"`
+	CourseID         string  `json:"courseId"`
+	CourseName       string  `json:"courseName"`
+	Marks            float64 `json:"marks"`
+	WalletAddress    string  `json:"walletAddress"`    // Lowercase
+	InstructorWallet string  `json:"instructorWallet"` // Lowercase; empty when issued by the platform
+	InstructorName   string  `json:"instructorName"`
+	IssuedAt         int64   `json:"issuedAt"` // Unix seconds
+	AcademicDNA      string  `json:"academicDNA"`
+}
+
+// Claims returns the certificate's claims in the form that is hashed
+func (c *Certificate) Claims() CertificateClaims {
+	return CertificateClaims{
+		Version:          c.HashVersion,
+		StudentID:        c.StudentID,
+		StudentName:      c.StudentName,
+		CourseID:         c.CourseID,
+		CourseName:       c.CourseName,
+		Marks:            c.Marks,
+		WalletAddress:    strings.ToLower(c.WalletAddress),
+		InstructorWallet: strings.ToLower(c.InstructorWallet),
+		InstructorName:   c.InstructorName,
+		IssuedAt:         c.IssuedAt.Unix(),
+		AcademicDNA:      c.AcademicDNA,
+	}
 }
 
 // Question represents a battle question
@@ -216,9 +296,271 @@ type ActivityItem struct {
 
 // SystemState tracks global system state like blockchain sync progress
 type SystemState struct {
-	ID              string    `json:"id" firestore:"id"` // "sync_state"
-	LastSyncedBlock uint64    `json:"lastSyncedBlock" firestore:"last_synced_block"`
-	UpdatedAt       time.Time `json:"updatedAt" firestore:"updated_at"`
+	ID              string        `json:"id" firestore:"id"` // "sync_state"
+	LastSyncedBlock uint64        `json:"lastSyncedBlock" firestore:"last_synced_block"`
+	LastSyncedHash  string        `json:"lastSyncedHash,omitempty" firestore:"last_synced_hash"` // Detects reorgs below the checkpoint
+	Journal         []SyncedBlock `json:"journal,omitempty" firestore:"journal"`                 // Recent blocks and the events applied from them
+	UpdatedAt       time.Time     `json:"updatedAt" firestore:"updated_at"`
+}
+
+// Types of SyncedEvent
+const (
+	SyncedEventMinted           = "minted"
+	SyncedEventRevoked          = "revoked"
+	SyncedEventIssuerAuthorized = "issuer_authorized"
+	SyncedEventIssuerRevoked    = "issuer_revoked"
+)
+
+// SyncedBlock is a block the indexer has applied, kept so a reorg can be undone
+type SyncedBlock struct {
+	Number uint64        `json:"number" firestore:"number"`
+	Hash   string        `json:"hash" firestore:"hash"`
+	Events []SyncedEvent `json:"events,omitempty" firestore:"events"`
+}
+
+// SyncedEvent is a registry event applied to storage
+type SyncedEvent struct {
+	Type   string `json:"type" firestore:"type"`
+	Key    string `json:"key" firestore:"key"` // Certificate hash or issuer address
+	TxHash string `json:"txHash" firestore:"tx_hash"`
+}
+
+// ReconciliationReport is the admin report written by the pending-mint reconciler
+type ReconciliationReport struct {
+	ID                string              `json:"id" firestore:"id"` // "reconciliation"
+	RunAt             time.Time           `json:"runAt" firestore:"run_at"`
+	PendingChecked    int                 `json:"pendingChecked" firestore:"pending_checked"`
+	Confirmed         []string            `json:"confirmed" firestore:"confirmed"`                  // Hashes found on-chain this run
+	Expired           []string            `json:"expired" firestore:"expired"`                      // Hashes expired this run
+	Orphans           []OrphanCertificate `json:"orphans" firestore:"orphans"`                      // Outstanding on-chain hashes with no metadata
+	Revoked           []string            `json:"revoked" firestore:"revoked"`                      // Pending revocations finalized this run
+	RevocationsFailed []string            `json:"revocationsFailed" firestore:"revocations_failed"` // Pending revocations whose transaction failed this run
+	LastScannedBlock  uint64              `json:"lastScannedBlock" firestore:"last_scanned_block"`
+}
+
+// ChainRef identifies a registry deployment by chain ID and contract address
+type ChainRef struct {
+	ChainID  int64  `json:"chainId" firestore:"chain_id"`
+	Contract string `json:"contract" firestore:"contract"`
+}
+
+// Deployment is one certificate registry contract on one chain. Minting always
+// targets the active deployment; retired ones stay listed so certificates minted
+// there can still be verified.
+type Deployment struct {
+	Name            string `json:"name"`
+	ChainID         int64  `json:"chainId"`
+	RPCURL          string `json:"rpcUrl,omitempty"`
+	ContractAddress string `json:"contractAddress"`
+	DeployBlock     uint64 `json:"deployBlock"`
+	Active          bool   `json:"active"`
+}
+
+// Ref returns the ChainRef of the deployment
+func (d Deployment) Ref() ChainRef {
+	return ChainRef{ChainID: d.ChainID, Contract: d.ContractAddress}
+}
+
+// MintAuthorization is the platform's EIP-712 signature approving one certificate
+// to be minted to a student wallet with a given Academic DNA before Expiry
+type MintAuthorization struct {
+	StudentWallet   string `json:"studentWallet" firestore:"student_wallet"`
+	CertificateHash string `json:"certificateHash" firestore:"certificate_hash"`
+	AcademicDNA     string `json:"academicDNA" firestore:"academic_dna"`
+	IssuedAt        int64  `json:"issuedAt" firestore:"issued_at"` // Unix seconds
+	Expiry          int64  `json:"expiry" firestore:"expiry"`      // Unix seconds
+	ChainID         int64  `json:"chainId" firestore:"chain_id"`
+	Contract        string `json:"contract" firestore:"contract"`
+	Signer          string `json:"signer" firestore:"signer"`
+	Signature       string `json:"signature" firestore:"signature"`
+}
+
+// PendingRevocation is a revocation sent on-chain from the platform wallet on an
+// instructor's or admin's behalf, not yet mined
+type PendingRevocation struct {
+	TxHash      string    `json:"txHash" firestore:"tx_hash"`
+	RequestedBy string    `json:"requestedBy" firestore:"requested_by"` // Wallet that asked for it; becomes RevokedBy
+	Reason      string    `json:"reason" firestore:"reason"`
+	RequestedAt time.Time `json:"requestedAt" firestore:"requested_at"`
+}
+
+// ImportedBadge is an Open Badges 3.0 credential issued outside Cognify that a
+// user added to their profile once its proof checked out
+type ImportedBadge struct {
+	ID                     string    `json:"id" firestore:"id"` // Hash of the user and the credential's id
+	UserID                 string    `json:"userId" firestore:"user_id"`
+	CredentialID           string    `json:"credentialId,omitempty" firestore:"credential_id,omitempty"`
+	IssuerID               string    `json:"issuerId" firestore:"issuer_id"`
+	IssuerName             string    `json:"issuerName,omitempty" firestore:"issuer_name,omitempty"`
+	AchievementName        string    `json:"achievementName" firestore:"achievement_name"`
+	AchievementDescription string    `json:"achievementDescription,omitempty" firestore:"achievement_description,omitempty"`
+	Criteria               string    `json:"criteria,omitempty" firestore:"criteria,omitempty"`
+	ImageURL               string    `json:"imageUrl,omitempty" firestore:"image_url,omitempty"`
+	Skills                 []string  `json:"skills,omitempty" firestore:"skills,omitempty"`
+	IssuedAt               time.Time `json:"issuedAt" firestore:"issued_at"`
+	ExpiresAt              time.Time `json:"expiresAt,omitempty" firestore:"expires_at,omitempty"`
+	ProofFormat            string    `json:"proofFormat" firestore:"proof_format"`                         // "jwt" or a data integrity proof type
+	VerificationMethod     string    `json:"verificationMethod,omitempty" firestore:"verification_method"` // Key or DID the proof was checked against
+	Credential             string    `json:"credential" firestore:"credential"`                            // As presented: JSON or a compact JWT
+	ImportedAt             time.Time `json:"importedAt" firestore:"imported_at"`
+}
+
+// StatusListSize is the number of entries in a status list: 16KB uncompressed,
+// the minimum the Bitstring Status List spec allows for herd privacy
+const StatusListSize = 131072
+
+// StatusList is a W3C Bitstring Status List of certificate revocations. Bit i,
+// counting from the most significant bit of the first byte, is set when the
+// certificate assigned index i is revoked.
+type StatusList struct {
+	ID        string    `json:"id" firestore:"id"`
+	Purpose   string    `json:"purpose" firestore:"purpose"` // "revocation"
+	Bits      []byte    `json:"bits" firestore:"bits"`
+	NextIndex int       `json:"nextIndex" firestore:"next_index"` // Next unassigned index; the list is full at StatusListSize
+	Version   int64     `json:"version" firestore:"version"`      // Incremented on every change, for cache validation
+	UpdatedAt time.Time `json:"updatedAt" firestore:"updated_at"`
+}
+
+// Statuses of a RelayedMint
+const (
+	RelayStatusSubmitted = "submitted" // Sent from the platform wallet, not yet mined
+	RelayStatusMined     = "mined"     // CertificateMinted event seen in the receipt
+	RelayStatusFailed    = "failed"    // Rejected by the node, reverted or dropped; may be retried
+)
+
+// RelayedMint is a mint an instructor signed off-chain and the platform wallet sent
+// and paid for, keyed by certificate hash
+type RelayedMint struct {
+	ID               string    `json:"id" firestore:"id"` // Certificate hash
+	InstructorWallet string    `json:"instructorWallet" firestore:"instructor_wallet"`
+	StudentWallet    string    `json:"studentWallet" firestore:"student_wallet"`
+	Deadline         int64     `json:"deadline" firestore:"deadline"`   // Unix seconds, signed by the instructor
+	Signature        string    `json:"signature" firestore:"signature"` // Instructor's EIP-712 MintRequest signature
+	Status           string    `json:"status" firestore:"status"`
+	TxHash           string    `json:"txHash,omitempty" firestore:"tx_hash,omitempty"` // ChainTransaction ID
+	BlockNumber      uint64    `json:"blockNumber,omitempty" firestore:"block_number,omitempty"`
+	Error            string    `json:"error,omitempty" firestore:"error,omitempty"`
+	CreatedAt        time.Time `json:"createdAt" firestore:"created_at"`
+	UpdatedAt        time.Time `json:"updatedAt" firestore:"updated_at"`
+}
+
+// Statuses of an IssuerApplication
+const (
+	IssuerApplicationPending  = "pending"  // Waiting for an admin
+	IssuerApplicationApproved = "approved" // authorizeIssuer submitted on-chain
+	IssuerApplicationRejected = "rejected" // Declined without touching the chain
+	IssuerApplicationRevoked  = "revoked"  // revokeIssuer submitted on-chain
+)
+
+// IssuerApplication is an instructor's request to mint as an authorized issuer,
+// keyed by lowercased wallet. It also records admin decisions for wallets that never applied.
+type IssuerApplication struct {
+	ID          string    `json:"id" firestore:"id"` // Lowercased wallet
+	Wallet      string    `json:"wallet" firestore:"wallet"`
+	UserID      string    `json:"userId,omitempty" firestore:"user_id,omitempty"`
+	Name        string    `json:"name,omitempty" firestore:"name,omitempty"`
+	Institution string    `json:"institution,omitempty" firestore:"institution,omitempty"`
+	Statement   string    `json:"statement,omitempty" firestore:"statement,omitempty"`
+	Status      string    `json:"status" firestore:"status"`
+	TxHash      string    `json:"txHash,omitempty" firestore:"tx_hash,omitempty"` // Latest authorize/revoke transaction
+	ReviewedBy  string    `json:"reviewedBy,omitempty" firestore:"reviewed_by,omitempty"`
+	ReviewNote  string    `json:"reviewNote,omitempty" firestore:"review_note,omitempty"`
+	SubmittedAt time.Time `json:"submittedAt" firestore:"submitted_at"`
+	ReviewedAt  time.Time `json:"reviewedAt,omitempty" firestore:"reviewed_at,omitempty"`
+}
+
+// Statuses of a BackfillJob
+const (
+	BackfillStatusRunning   = "running"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusFailed    = "failed"
+)
+
+// BackfillJob is the progress of the latest historical re-sync of registry events
+type BackfillJob struct {
+	ID           string    `json:"id" firestore:"id"` // "backfill"
+	Status       string    `json:"status" firestore:"status"`
+	FromBlock    uint64    `json:"fromBlock" firestore:"from_block"`
+	CurrentBlock uint64    `json:"currentBlock" firestore:"current_block"` // Last block applied
+	TargetBlock  uint64    `json:"targetBlock" firestore:"target_block"`   // Newest confirmed block, moves with the chain
+	Progress     float64   `json:"progress" firestore:"progress"`          // Percent of FromBlock..TargetBlock applied
+	Events       int       `json:"events" firestore:"events"`
+	Error        string    `json:"error,omitempty" firestore:"error,omitempty"`
+	StartedAt    time.Time `json:"startedAt" firestore:"started_at"`
+	UpdatedAt    time.Time `json:"updatedAt" firestore:"updated_at"`
+	FinishedAt   time.Time `json:"finishedAt,omitempty" firestore:"finished_at,omitempty"`
+}
+
+// OrphanCertificate is an on-chain certificate with no metadata in storage
+type OrphanCertificate struct {
+	Hash        string    `json:"hash" firestore:"hash"`
+	Owner       string    `json:"owner" firestore:"owner"`
+	Issuer      string    `json:"issuer" firestore:"issuer"`
+	TxHash      string    `json:"txHash" firestore:"tx_hash"`
+	BlockNumber uint64    `json:"blockNumber" firestore:"block_number"`
+	DetectedAt  time.Time `json:"detectedAt" firestore:"detected_at"`
+}
+
+// Statuses of an AnchorBatch
+const (
+	AnchorStatusPending  = "pending"  // Root submitted (or about to be), not yet on-chain
+	AnchorStatusAnchored = "anchored" // Root found in the registry
+)
+
+// AnchorBatch is a Merkle tree of certificate hashes whose root is anchored on-chain
+type AnchorBatch struct {
+	ID          string    `json:"id" firestore:"id"` // Merkle root, no 0x prefix
+	Leaves      []string  `json:"leaves" firestore:"leaves"`
+	Status      string    `json:"status" firestore:"status"`
+	TxHash      string    `json:"txHash,omitempty" firestore:"tx_hash"`
+	CreatedAt   time.Time `json:"createdAt" firestore:"created_at"`
+	SubmittedAt time.Time `json:"submittedAt,omitempty" firestore:"submitted_at"`
+	AnchoredAt  time.Time `json:"anchoredAt,omitempty" firestore:"anchored_at"`
+}
+
+// Statuses of a ChainTransaction
+const (
+	TxStatusPending = "pending" // Broadcast, not yet mined
+	TxStatusMined   = "mined"   // Mined successfully
+	TxStatusFailed  = "failed"  // Mined but reverted
+	TxStatusDropped = "dropped" // Nonce used by a transaction the platform did not send
+)
+
+// ChainTransaction is a transaction sent from the platform wallet, tracked until it is mined.
+// Fee bumps re-send the same nonce, so one ChainTransaction may cover several hashes.
+type ChainTransaction struct {
+	ID          string    `json:"id" firestore:"id"` // Hash of the first broadcast
+	Action      string    `json:"action" firestore:"action"`
+	Reference   string    `json:"reference,omitempty" firestore:"reference"` // Certificate hash or address acted on
+	Status      string    `json:"status" firestore:"status"`
+	Nonce       uint64    `json:"nonce" firestore:"nonce"`
+	To          string    `json:"to" firestore:"to"`
+	Data        string    `json:"data" firestore:"data"` // Hex calldata, kept for fee bumps
+	GasLimit    uint64    `json:"gasLimit" firestore:"gas_limit"`
+	Legacy      bool      `json:"legacy,omitempty" firestore:"legacy"`         // Pre-EIP-1559 chain: GasFeeCap is the gas price
+	GasTipCap   string    `json:"gasTipCap,omitempty" firestore:"gas_tip_cap"` // Wei, decimal
+	GasFeeCap   string    `json:"gasFeeCap" firestore:"gas_fee_cap"`           // Wei, decimal
+	Hash        string    `json:"hash" firestore:"hash"`                       // Latest broadcast, or the hash that was mined
+	Hashes      []string  `json:"hashes" firestore:"hashes"`                   // Every broadcast, oldest first
+	BlockNumber uint64    `json:"blockNumber,omitempty" firestore:"block_number"`
+	GasUsed     uint64    `json:"gasUsed,omitempty" firestore:"gas_used"`
+	SubmittedAt time.Time `json:"submittedAt" firestore:"submitted_at"`
+	BroadcastAt time.Time `json:"broadcastAt" firestore:"broadcast_at"` // Time of the latest broadcast
+	UpdatedAt   time.Time `json:"updatedAt" firestore:"updated_at"`
+}
+
+// TreasuryStatus is the treasury monitor's latest sample of the platform wallet.
+// It is held in memory and recomputed on every sample.
+type TreasuryStatus struct {
+	Wallet         string    `json:"wallet"`
+	BalanceWei     string    `json:"balanceWei"`   // Decimal
+	FeePerGasWei   string    `json:"feePerGasWei"` // Max fee per gas a new transaction would commit to
+	GasPerMint     uint64    `json:"gasPerMint"`
+	MintsRemaining int64     `json:"mintsRemaining"` // Mints the balance covers at the current fee
+	LowFundsMints  int64     `json:"lowFundsMints"`  // Admins are alerted below this many mints
+	LowFunds       bool      `json:"lowFunds"`
+	Error          string    `json:"error,omitempty"` // Why the latest sample failed, if it did
+	SampledAt      time.Time `json:"sampledAt"`
 }
 
 // -------------------------------------------------------------------
@@ -14,8 +14,12 @@ type VerificationLog struct {
 // VerificationResponse is the API response for certificate verification
 type VerificationResponse struct {
 	Verified          bool           `json:"verified"`
+	ClaimsVerified    bool           `json:"claimsVerified,omitempty"` // The claims were rehashed and match the certificate hash
+	Tampered          bool           `json:"tampered,omitempty"`       // An uploaded PDF's seal or printed claims do not match
+	TamperedFields    []string       `json:"tamperedFields,omitempty"` // Printed claims that differ from the issued certificate
 	StudentName       string         `json:"studentName,omitempty"`
 	CourseName        string         `json:"courseName,omitempty"`
+	Marks             float64        `json:"marks,omitempty"`
 	Issuer            string         `json:"issuer,omitempty"`
 	WalletAddress     string         `json:"walletAddress,omitempty"`
 	IssuedAt          time.Time      `json:"issuedAt,omitempty"`
@@ -26,6 +30,9 @@ type VerificationResponse struct {
 	IPFSPdfLink       string         `json:"ipfsPdfLink,omitempty"`
 	VerificationCount int            `json:"verificationCount,omitempty"`
 	Revoked           bool           `json:"revoked,omitempty"`
+	ProofType         string         `json:"proofType,omitempty"`  // "direct" or "merkle"
+	MerkleRoot        string         `json:"merkleRoot,omitempty"` // Anchored root, for merkle proofs
+	Chain             *ChainRef      `json:"chain,omitempty"`      // Deployment the proof was checked against
 	Message           string         `json:"message,omitempty"`
 }
 
@@ -35,3 +42,36 @@ type VerificationStats struct {
 	TotalVerified int `json:"totalVerified"`
 	FraudAttempts int `json:"fraudAttempts"`
 }
+
+// VerifiableCredential is a W3C Verifiable Credential as a JSON-LD document. It is
+// kept as generic JSON so its proof can be derived from whatever fields it holds.
+type VerifiableCredential map[string]interface{}
+
+// CredentialVerification is the API response for verifiable credential verification
+type CredentialVerification struct {
+	Verified        bool      `json:"verified"`
+	SignatureValid  bool      `json:"signatureValid"`           // Proof is by the platform DID named as issuer
+	Anchored        bool      `json:"anchored"`                 // credentialStatus names a registry entry or anchored root holding the certificate
+	ClaimsVerified  bool      `json:"claimsVerified,omitempty"` // The subject's claims hash to the anchored certificate hash
+	Revoked         bool      `json:"revoked,omitempty"`
+	Issuer          string    `json:"issuer,omitempty"`
+	CertificateHash string    `json:"certificateHash,omitempty"`
+	ProofType       string    `json:"proofType,omitempty"` // "direct" or "merkle"
+	Chain           *ChainRef `json:"chain,omitempty"`
+	Message         string    `json:"message,omitempty"`
+}
+
+// SDJWTVerification is the API response for verifying an SD-JWT certificate
+// presentation. Only the claims the holder disclosed are returned.
+type SDJWTVerification struct {
+	Verified        bool                   `json:"verified"`
+	SignatureValid  bool                   `json:"signatureValid"`     // Issuer JWT is signed by the platform DID
+	Anchored        bool                   `json:"anchored,omitempty"` // The disclosed certificate hash is in the registry
+	Revoked         bool                   `json:"revoked,omitempty"`
+	Issuer          string                 `json:"issuer,omitempty"`
+	CertificateHash string                 `json:"certificateHash,omitempty"` // Only when disclosed
+	Disclosed       map[string]interface{} `json:"disclosed,omitempty"`
+	HiddenClaims    int                    `json:"hiddenClaims"` // Signed claims the holder did not disclose
+	Chain           *ChainRef              `json:"chain,omitempty"`
+	Message         string                 `json:"message,omitempty"`
+}
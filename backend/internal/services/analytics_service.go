@@ -11,8 +11,6 @@ import (
 	"cache-crew/cognify/internal/db"
 	"cache-crew/cognify/internal/models"
 	"cache-crew/cognify/internal/utils"
-
-	"cloud.google.com/go/firestore"
 )
 
 type AnalyticsService struct{}
@@ -75,10 +73,6 @@ func (s *AnalyticsService) CheckFraud(ctx context.Context, ip string) bool {
 
 // TrackVerification updates verification metrics for a certificate
 func (s *AnalyticsService) TrackVerification(ctx context.Context, certHash string, ipAddress string, countryCode string) error {
-	if db.FirestoreClient == nil {
-		return fmt.Errorf("firestore client not initialized")
-	}
-
 	// Check Fraud
 	isFraud := s.CheckFraud(ctx, ipAddress)
 	if isFraud {
@@ -91,26 +85,18 @@ func (s *AnalyticsService) TrackVerification(ctx context.Context, certHash strin
 		countryCode = s.resolveCountry(ipAddress)
 	}
 
-	docRef := db.FirestoreClient.Collection("verification_metrics").Doc(certHash)
-
-	// Transaction to update metrics atomically
-	err := db.FirestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
-		doc, err := tx.Get(docRef)
-		var metric models.VerificationMetric
-
-		if err != nil {
+	// Update metrics atomically
+	err := db.Repos.VerificationMetrics.Update(ctx, certHash, func(current *models.VerificationMetric) (*models.VerificationMetric, error) {
+		metric := current
+		if metric == nil {
 			// Create new if not exists
-			metric = models.VerificationMetric{
+			metric = &models.VerificationMetric{
 				CertificateHash:    certHash,
 				TotalVerifications: 0,
 				UniqueVerifiers:    0,
 				GeoDistribution:    make(map[string]int),
 				VerificationTrend:  make(map[string]int),
 			}
-		} else {
-			if err := doc.DataTo(&metric); err != nil {
-				return err
-			}
 		}
 
 		// Update fields
@@ -135,7 +121,7 @@ func (s *AnalyticsService) TrackVerification(ctx context.Context, certHash strin
 		}
 		metric.VerificationTrend[monthKey]++
 
-		return tx.Set(docRef, metric)
+		return metric, nil
 	})
 
 	if err != nil {
@@ -148,15 +134,8 @@ func (s *AnalyticsService) TrackVerification(ctx context.Context, certHash strin
 
 // UpdateIssuerReputation recalculates reputation for an instructor
 func (s *AnalyticsService) UpdateIssuerReputation(ctx context.Context, instructorID string) error {
-	if db.FirestoreClient == nil {
-		return nil
-	}
-
 	// 1. Get all certificates by this instructor
-	certs, err := db.FirestoreClient.Collection("certificates").
-		Where("instructor_wallet", "==", instructorID). // assuming ID is wallet for now, or match field
-		Documents(ctx).GetAll()
-
+	certs, err := db.Repos.Certificates.ListByInstructor(ctx, instructorID) // assuming ID is wallet for now
 	if err != nil {
 		return err
 	}
@@ -169,9 +148,7 @@ func (s *AnalyticsService) UpdateIssuerReputation(ctx context.Context, instructo
 	revokedCount := 0
 	totalTrustScore := 0
 
-	for _, doc := range certs {
-		var cert models.Certificate
-		doc.DataTo(&cert) // Ignore error
+	for _, cert := range certs {
 		if cert.Revoked {
 			revokedCount++
 		}
@@ -203,36 +180,17 @@ func (s *AnalyticsService) UpdateIssuerReputation(ctx context.Context, instructo
 		UpdatedAt:       time.Now(),
 	}
 
-	_, err = db.FirestoreClient.Collection("issuer_reputation").Doc(instructorID).Set(ctx, rep)
-	return err
+	return db.Repos.Trust.SaveIssuerReputation(ctx, &rep)
 }
 
 // GetMetrics retrieves metrics for trust engine
 func (s *AnalyticsService) GetMetrics(ctx context.Context, certHash string) (*models.VerificationMetric, error) {
-	if db.FirestoreClient == nil {
-		return nil, fmt.Errorf("db not connected")
-	}
-
-	doc, err := db.FirestoreClient.Collection("verification_metrics").Doc(certHash).Get(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	var metric models.VerificationMetric
-	if err := doc.DataTo(&metric); err != nil {
-		return nil, err
-	}
-
-	return &metric, nil
+	return db.Repos.VerificationMetrics.Get(ctx, certHash)
 }
 
 // SnapshotTrustScore records the current trust score for trending analysis
 // Should be called periodically or on significant events to build history
 func (s *AnalyticsService) SnapshotTrustScore(ctx context.Context, certHash string, score int, reason string) error {
-	if db.FirestoreClient == nil {
-		return nil
-	}
-
 	event := models.TrustHistoryEvent{
 		CertificateHash: certHash,
 		Score:           score,
@@ -240,63 +198,32 @@ func (s *AnalyticsService) SnapshotTrustScore(ctx context.Context, certHash stri
 		Timestamp:       time.Now(),
 	}
 
-	// Store in a subcollection for better organization: certificates/{hash}/trust_history
-	// Or a top-level collection if we want to query across all certs easily
-	// Let's use top-level for now for simpler "global trend" queries if needed later,
-	// but partitioned by hash in ID or field.
-	_, _, err := db.FirestoreClient.Collection("trust_history").Add(ctx, event)
-	return err
+	return db.Repos.Trust.AddHistory(ctx, &event)
 }
 
 // GetCertificateRank calculates the percentile of this certificate's score compared to others
 // Returns a float between 0.0 and 100.0 (e.g. 95.5 means top 4.5%)
 func (s *AnalyticsService) GetCertificateRank(ctx context.Context, score int) (float64, error) {
-	if db.FirestoreClient == nil {
-		return 0, nil
-	}
-
 	// In a real production system with millions of records, DO NOT do this count query every time.
 	// You would maintain a histogram or use a counter sharding approach.
 	// For this scale, counting is fine.
 
-	col := db.FirestoreClient.Collection("certificates")
-
 	// Count total certificates (cache this in production)
-	// Using Select() allows us to get a valid Query object to chain NewAggregationQuery
-	q := col.Select() // Select all (empty arg)
-	aggQuery := q.NewAggregationQuery().WithCount("total")
-	results, err := aggQuery.Get(ctx)
+	totalCount, err := db.Repos.Certificates.Count(ctx)
 	if err != nil {
 		return 0, err
 	}
 
-	totalCount := int64(0)
-	if val, ok := results["total"]; ok {
-		// safe type assertion for firestore count result
-		if v, ok := val.(interface{ Integer() int64 }); ok {
-			totalCount = v.Integer()
-		}
-	}
-
 	if totalCount == 0 {
 		return 100.0, nil
 	}
 
 	// Count certificates with lower score
-	qLower := col.Where("trust_score", "<", score)
-	aggLower := qLower.NewAggregationQuery().WithCount("lower")
-	lowerResults, err := aggLower.Get(ctx)
+	lowerCount, err := db.Repos.Certificates.CountBelowTrustScore(ctx, score)
 	if err != nil {
 		return 0, err
 	}
 
-	lowerCount := int64(0)
-	if val, ok := lowerResults["lower"]; ok {
-		if v, ok := val.(interface{ Integer() int64 }); ok {
-			lowerCount = v.Integer()
-		}
-	}
-
 	percentile := (float64(lowerCount) / float64(totalCount)) * 100.0
 	return percentile, nil
 }
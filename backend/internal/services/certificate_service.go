@@ -2,17 +2,96 @@ package services
 
 import (
 	"bytes"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"cache-crew/cognify/internal/config"
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/utils"
+
+	"github.com/boombuler/barcode/qr"
 	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Certificate PDFs carry their seal twice: in the XMP metadata and in a QR code.
+// Each printed claim is wrapped in a marked-content sequence tagged
+// certificateClaimTag, so ReadCertificatePDF can recompute the hash from what
+// the certificate actually shows.
+const (
+	certificateClaimTag       = "CognifyClaim"
+	certificateXMPNamespace   = "urn:cognify:ns:certificate:1.0#"
+	certificateIssuedAtLayout = "January 2, 2006 15:04:05 UTC" // To the second, as the hash covers it
+)
+
+// ErrNotCertificatePDF is returned for PDFs without a readable certificate seal or claims
+var ErrNotCertificatePDF = errors.New("not a Cognify certificate PDF")
+
+// CertificateSeal is the platform's proof of origin embedded in a certificate PDF
+type CertificateSeal struct {
+	Hash        string // Certificate hash, hex without 0x
+	HashVersion int    // CertificateHashVersion the hash was computed with
+	Issuer      string // did:ethr DID of the platform wallet
+	Signature   string // EIP-712 signature over Hash (see blockchain.SignCertificateSeal)
+}
+
+// Core PDF fonts are Windows-1252. Claims must print exactly so the hash can be
+// recomputed; other text just loses the characters it cannot show.
+var (
+	claimEncoder     = charmap.Windows1252.NewEncoder()
+	printableEncoder = encoding.ReplaceUnsupported(charmap.Windows1252.NewEncoder())
 )
 
-// GenerateCertificatePDF creates a PDF certificate and returns the bytes
-func GenerateCertificatePDF(studentName, courseName, achievement string, skills []string, congratMessage string, issuedAt time.Time) ([]byte, error) {
+// GenerateCertificatePDF creates a PDF certificate showing claims and sealed with
+// seal, and returns the bytes. It fails if a claim cannot be printed exactly.
+func GenerateCertificatePDF(claims models.CertificateClaims, achievement string, skills []string, congratMessage string, seal CertificateSeal) ([]byte, error) {
+	printed := map[string]string{
+		"studentName":      claims.StudentName,
+		"studentId":        claims.StudentID,
+		"courseName":       claims.CourseName,
+		"courseId":         claims.CourseID,
+		"marks":            strconv.FormatFloat(claims.Marks, 'f', -1, 64),
+		"walletAddress":    claims.WalletAddress,
+		"instructorName":   claims.InstructorName,
+		"instructorWallet": claims.InstructorWallet,
+		"issuedAt":         time.Unix(claims.IssuedAt, 0).UTC().Format(certificateIssuedAtLayout),
+		"academicDNA":      claims.AcademicDNA,
+	}
+	for name, value := range printed {
+		encoded, err := claimEncoder.String(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s %q cannot be printed: %w", name, value, err)
+		}
+		printed[name] = encoded
+	}
+	text := func(s string) string {
+		encoded, _ := printableEncoder.String(s)
+		return encoded
+	}
+
 	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle(text("Certificate: "+claims.CourseName), false)
+	pdf.SetAuthor("Cognify Platform", false)
+	pdf.SetXmpMetadata(certificateXMP(seal))
 	pdf.AddPage()
 
+	// claim prints a claim's value in a tagged marked-content sequence
+	claim := func(name string, w, h float64, align string) {
+		pdf.RawWriteStr(fmt.Sprintf("/%s <</Name (%s)>> BDC", certificateClaimTag, name))
+		pdf.CellFormat(w, h, printed[name], "", 0, align, false, 0, "")
+		pdf.RawWriteStr("EMC")
+	}
+	// labelled prints "label value" from the current position
+	labelled := func(label, name string, h float64) {
+		pdf.CellFormat(pdf.GetStringWidth(label)+1, h, label, "", 0, "L", false, 0, "")
+		claim(name, pdf.GetStringWidth(printed[name])+1, h, "L")
+	}
+
 	// Page dimensions
 	pageWidth, pageHeight := 297.0, 210.0
 
@@ -53,7 +132,7 @@ func GenerateCertificatePDF(studentName, courseName, achievement string, skills
 		pdf.SetTextColor(191, 0, 255)
 		pdf.SetFont("Helvetica", "I", 14)
 		pdf.SetXY(0, 75)
-		pdf.CellFormat(pageWidth, 10, achievement, "", 0, "C", false, 0, "")
+		pdf.CellFormat(pageWidth, 10, text(achievement), "", 0, "C", false, 0, "")
 	}
 
 	// This certifies text
@@ -66,7 +145,7 @@ func GenerateCertificatePDF(studentName, courseName, achievement string, skills
 	pdf.SetTextColor(0, 245, 255)
 	pdf.SetFont("Helvetica", "B", 32)
 	pdf.SetXY(0, 100)
-	pdf.CellFormat(pageWidth, 18, studentName, "", 0, "C", false, 0, "")
+	claim("studentName", pageWidth, 18, "C")
 
 	// Has successfully completed
 	pdf.SetTextColor(200, 200, 200)
@@ -78,32 +157,57 @@ func GenerateCertificatePDF(studentName, courseName, achievement string, skills
 	pdf.SetTextColor(255, 255, 255)
 	pdf.SetFont("Helvetica", "B", 20)
 	pdf.SetXY(0, 132)
-	pdf.CellFormat(pageWidth, 12, courseName, "", 0, "C", false, 0, "")
+	claim("courseName", pageWidth, 12, "C")
 
 	// Skills section
 	if len(skills) > 0 {
 		pdf.SetTextColor(150, 150, 150)
 		pdf.SetFont("Helvetica", "", 10)
-		pdf.SetXY(0, 150)
-		skillsText := "Skills Acquired: " + skills[0]
-		for i := 1; i < len(skills); i++ {
-			skillsText += " • " + skills[i]
-		}
-		pdf.CellFormat(pageWidth, 8, skillsText, "", 0, "C", false, 0, "")
+		pdf.SetXY(0, 146)
+		pdf.CellFormat(pageWidth, 8, text("Skills Acquired: "+strings.Join(skills, " • ")), "", 0, "C", false, 0, "")
 	}
 
-	// Date
+	// Date and marks
 	pdf.SetTextColor(100, 100, 100)
 	pdf.SetFont("Helvetica", "", 10)
-	pdf.SetXY(20, 175)
-	pdf.CellFormat(100, 8, fmt.Sprintf("Issued: %s", issuedAt.Format("January 2, 2006")), "", 0, "L", false, 0, "")
+	pdf.SetXY(25, 156)
+	labelled("Issued:", "issuedAt", 6)
+	pdf.SetXY(25, 162)
+	labelled("Marks:", "marks", 6)
 
-	// Signature placeholder
-	pdf.SetXY(pageWidth-120, 175)
+	// Signature
+	if printed["instructorName"] != "" {
+		pdf.SetXY(pageWidth-170, 152)
+		claim("instructorName", 100, 8, "R")
+	}
+	pdf.SetXY(pageWidth-170, 158)
 	pdf.CellFormat(100, 8, "________________________", "", 0, "R", false, 0, "")
-	pdf.SetXY(pageWidth-120, 182)
+	pdf.SetXY(pageWidth-170, 165)
 	pdf.CellFormat(100, 8, "Cognify Platform", "", 0, "R", false, 0, "")
 
+	// Details the hash also covers, and the hash itself
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.SetXY(25, 173)
+	labelled("Student ID:", "studentId", 3.5)
+	pdf.CellFormat(6, 3.5, "", "", 0, "L", false, 0, "")
+	labelled("Course ID:", "courseId", 3.5)
+	pdf.SetXY(25, 176.5)
+	labelled("Wallet:", "walletAddress", 3.5)
+	pdf.SetXY(25, 180)
+	labelled("Instructor wallet:", "instructorWallet", 3.5)
+	pdf.SetXY(25, 183.5)
+	labelled("Academic DNA:", "academicDNA", 3.5)
+	pdf.SetXY(25, 187)
+	pdf.CellFormat(160, 3.5, "Certificate hash: 0x"+seal.Hash, "", 0, "L", false, 0, "")
+
+	// QR code with the verification link and the seal
+	if err := drawQRCode(pdf, certificateQRContent(seal), pageWidth-55, 150, 30); err != nil {
+		return nil, err
+	}
+	pdf.SetTextColor(150, 150, 150)
+	pdf.SetXY(pageWidth-55, 181)
+	pdf.CellFormat(30, 4, "Scan to verify", "", 0, "C", false, 0, "")
+
 	// Output to bytes
 	var buf bytes.Buffer
 	err := pdf.Output(&buf)
@@ -113,3 +217,152 @@ func GenerateCertificatePDF(studentName, courseName, achievement string, skills
 
 	return buf.Bytes(), nil
 }
+
+// certificateQRContent links to the verification page for the sealed hash, with
+// the issuer and signature along so the seal can be checked from a scan alone
+func certificateQRContent(seal CertificateSeal) string {
+	return fmt.Sprintf("%s%s?issuer=%s&sig=%s", config.AppConfig.VerifyURL, seal.Hash, seal.Issuer, seal.Signature)
+}
+
+// drawQRCode draws content as a QR code of the given size with a light quiet zone,
+// one filled square per module
+func drawQRCode(pdf *gofpdf.Fpdf, content string, x, y, size float64) error {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	modules := code.Bounds().Dx()
+	module := size / float64(modules+8) // 4-module quiet zone each side
+
+	pdf.SetFillColor(255, 255, 255)
+	pdf.Rect(x, y, size, size, "F")
+	pdf.SetFillColor(0, 0, 0)
+	for row := 0; row < modules; row++ {
+		for col := 0; col < modules; col++ {
+			if r, _, _, _ := code.At(col, row).RGBA(); r == 0 {
+				pdf.Rect(x+float64(col+4)*module, y+float64(row+4)*module, module, module, "F")
+			}
+		}
+	}
+	return nil
+}
+
+// certificateXMP is the XMP packet holding seal
+func certificateXMP(seal CertificateSeal) []byte {
+	escape := func(s string) string {
+		var b bytes.Buffer
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+	return []byte(fmt.Sprintf(`<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about="" xmlns:cognify="%s">
+   <cognify:certificateHash>%s</cognify:certificateHash>
+   <cognify:hashVersion>%d</cognify:hashVersion>
+   <cognify:issuer>%s</cognify:issuer>
+   <cognify:signature>%s</cognify:signature>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="r"?>`, certificateXMPNamespace, escape(seal.Hash), seal.HashVersion, escape(seal.Issuer), escape(seal.Signature)))
+}
+
+// ReadCertificatePDF extracts the seal from a certificate PDF's XMP metadata and
+// the claims it prints. The claims are what the page shows, so a PDF whose text
+// was edited yields claims that no longer hash to the sealed hash.
+func ReadCertificatePDF(data []byte) (*CertificateSeal, models.CertificateClaims, error) {
+	streams := utils.PDFStreams(data)
+
+	var seal *CertificateSeal
+	for _, candidate := range append([][]byte{data}, streams...) {
+		if seal = parseCertificateXMP(candidate); seal != nil {
+			break
+		}
+	}
+	if seal == nil {
+		return nil, models.CertificateClaims{}, fmt.Errorf("%w: no certificate seal in its metadata", ErrNotCertificatePDF)
+	}
+
+	printed := make(map[string]string)
+	for _, stream := range streams {
+		for name, value := range utils.PDFMarkedText(stream, certificateClaimTag) {
+			decoded, err := charmap.Windows1252.NewDecoder().String(value)
+			if err != nil {
+				return nil, models.CertificateClaims{}, fmt.Errorf("%w: unreadable %s", ErrNotCertificatePDF, name)
+			}
+			printed[name] += decoded
+		}
+	}
+	if printed["studentName"] == "" || printed["issuedAt"] == "" {
+		return nil, models.CertificateClaims{}, fmt.Errorf("%w: no printed certificate details", ErrNotCertificatePDF)
+	}
+
+	// Printed values that no longer parse can't be the issued ones; leaving them
+	// zero makes the recomputed hash differ
+	claims := models.CertificateClaims{
+		Version:          seal.HashVersion,
+		StudentID:        printed["studentId"],
+		StudentName:      printed["studentName"],
+		CourseID:         printed["courseId"],
+		CourseName:       printed["courseName"],
+		WalletAddress:    printed["walletAddress"],
+		InstructorWallet: printed["instructorWallet"],
+		InstructorName:   printed["instructorName"],
+		AcademicDNA:      printed["academicDNA"],
+	}
+	claims.Marks, _ = strconv.ParseFloat(printed["marks"], 64)
+	if issuedAt, err := time.Parse(certificateIssuedAtLayout, printed["issuedAt"]); err == nil {
+		claims.IssuedAt = issuedAt.Unix()
+	}
+	return seal, claims, nil
+}
+
+// parseCertificateXMP returns the seal in the first XMP packet in data, accepting
+// its properties as elements or, as some editors rewrite them, as attributes
+func parseCertificateXMP(data []byte) *CertificateSeal {
+	start := bytes.Index(data, []byte("<x:xmpmeta"))
+	end := bytes.Index(data, []byte("</x:xmpmeta>"))
+	if start < 0 || end < start {
+		return nil
+	}
+
+	values := make(map[string]string)
+	decoder := xml.NewDecoder(bytes.NewReader(data[start : end+len("</x:xmpmeta>")]))
+	current := ""
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			current = ""
+			if t.Name.Space == certificateXMPNamespace {
+				current = t.Name.Local
+			}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == certificateXMPNamespace {
+					values[attr.Name.Local] = attr.Value
+				}
+			}
+		case xml.CharData:
+			if current != "" {
+				values[current] += strings.TrimSpace(string(t))
+			}
+		case xml.EndElement:
+			current = ""
+		}
+	}
+
+	if values["certificateHash"] == "" {
+		return nil
+	}
+	version, _ := strconv.Atoi(values["hashVersion"])
+	return &CertificateSeal{
+		Hash:        strings.ToLower(strings.TrimPrefix(values["certificateHash"], "0x")),
+		HashVersion: version,
+		Issuer:      values["issuer"],
+		Signature:   values["signature"],
+	}
+}
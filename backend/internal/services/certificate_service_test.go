@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"cache-crew/cognify/internal/models"
+	"cache-crew/cognify/internal/utils"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+func testCertificateClaims() models.CertificateClaims {
+	return models.CertificateClaims{
+		Version:          models.CertificateHashVersion,
+		StudentID:        "student-1",
+		StudentName:      `Zoë O'Brien (Jr.) \ Smith`,
+		CourseID:         "course-1",
+		CourseName:       "Café Économie: Prices (€) & Ratios",
+		Marks:            91.5,
+		WalletAddress:    "0xabcabcabcabcabcabcabcabcabcabcabcabcabca",
+		InstructorWallet: "0x1111111111111111111111111111111111111111",
+		InstructorName:   "Åsa Ångström",
+		IssuedAt:         time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC).Unix(),
+		AcademicDNA:      "dna-v1",
+	}
+}
+
+// sealedCertificatePDF renders claims with a seal over their hash
+func sealedCertificatePDF(t *testing.T, claims models.CertificateClaims) ([]byte, CertificateSeal) {
+	t.Helper()
+	hash, err := utils.GenerateCanonicalHash(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seal := CertificateSeal{Hash: hash, HashVersion: claims.Version, Issuer: "did:ethr:0x1:0x2222222222222222222222222222222222222222", Signature: "0xsig"}
+	pdf, err := GenerateCertificatePDF(claims, "With Distinction", []string{"Go"}, "", seal)
+	if err != nil {
+		t.Fatalf("GenerateCertificatePDF: %v", err)
+	}
+	return pdf, seal
+}
+
+var streamStart = regexp.MustCompile(`>>\s*stream\r?\n`)
+
+// rewritePDFText replaces old with new in every compressed stream of a PDF, the way
+// an editor changing the printed text would
+func rewritePDFText(t *testing.T, data []byte, old, new string) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	last, replaced := 0, false
+	for _, loc := range streamStart.FindAllIndex(data, -1) {
+		start := loc[1]
+		end := start + bytes.Index(data[start:], []byte("endstream"))
+		zr, err := zlib.NewReader(bytes.NewReader(data[start:end]))
+		if err != nil {
+			continue
+		}
+		content, _ := io.ReadAll(zr)
+		if !bytes.Contains(content, []byte(old)) {
+			continue
+		}
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(bytes.ReplaceAll(content, []byte(old), []byte(new)))
+		zw.Close()
+
+		out.Write(data[last:start])
+		out.Write(compressed.Bytes())
+		out.WriteString("\n")
+		last, replaced = end, true
+	}
+	if !replaced {
+		t.Fatalf("%q not found in any stream", old)
+	}
+	out.Write(data[last:])
+	return out.Bytes()
+}
+
+func TestReadCertificatePDFRoundTrip(t *testing.T) {
+	claims := testCertificateClaims()
+	pdf, seal := sealedCertificatePDF(t, claims)
+
+	gotSeal, gotClaims, err := ReadCertificatePDF(pdf)
+	if err != nil {
+		t.Fatalf("ReadCertificatePDF: %v", err)
+	}
+	if *gotSeal != seal {
+		t.Errorf("seal = %+v, want %+v", *gotSeal, seal)
+	}
+	if gotClaims != claims {
+		t.Errorf("claims = %+v, want %+v", gotClaims, claims)
+	}
+	if hash, _ := utils.GenerateCanonicalHash(gotClaims); hash != seal.Hash {
+		t.Errorf("printed claims hash to %s, want the sealed %s", hash, seal.Hash)
+	}
+}
+
+func TestReadCertificatePDFTamperedText(t *testing.T) {
+	claims := testCertificateClaims()
+	pdf, seal := sealedCertificatePDF(t, claims)
+
+	tampered := rewritePDFText(t, pdf, "Smith", "Jones")
+	gotSeal, gotClaims, err := ReadCertificatePDF(tampered)
+	if err != nil {
+		t.Fatalf("ReadCertificatePDF: %v", err)
+	}
+	if *gotSeal != seal {
+		t.Errorf("seal = %+v, want the original %+v", *gotSeal, seal)
+	}
+	if want := `Zoë O'Brien (Jr.) \ Jones`; gotClaims.StudentName != want {
+		t.Errorf("StudentName = %q, want the edited %q", gotClaims.StudentName, want)
+	}
+	// The edited claims no longer hash to what the platform sealed
+	if hash, _ := utils.GenerateCanonicalHash(gotClaims); hash == seal.Hash {
+		t.Error("tampered claims still hash to the sealed hash")
+	}
+}
+
+func TestReadCertificatePDFWithoutSeal(t *testing.T) {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.CellFormat(100, 10, "Certificate of Completion", "", 0, "L", false, 0, "")
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := ReadCertificatePDF(buf.Bytes()); !errors.Is(err, ErrNotCertificatePDF) {
+		t.Errorf("ReadCertificatePDF error = %v, want ErrNotCertificatePDF", err)
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"cache-crew/cognify/internal/models"
 
 	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/iterator"
 )
 
 // RecommendationRequest contains the context for generating recommendations
@@ -116,24 +115,9 @@ Respond in this JSON format ONLY (no markdown):
 }
 
 func fetchAvailableCourses(ctx context.Context) ([]models.Course, error) {
-	if db.FirestoreClient == nil {
-		// Return mock data if DB not ready
-		return getMockCourses(), nil
-	}
-
-	var courses []models.Course
-	iter := db.FirestoreClient.Collection("courses").Documents(ctx)
-	for {
-		doc, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-		var c models.Course
-		doc.DataTo(&c)
-		courses = append(courses, c)
+	courses, err := db.Repos.Courses.List(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	// If DB is empty, use mock
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+)
+
+// RevocationService applies certificate revocations to storage
+type RevocationService struct{}
+
+func NewRevocationService() *RevocationService {
+	return &RevocationService{}
+}
+
+// Record persists a revocation, sets the certificate's status list bit, notifies the student
+// and recomputes the issuer's reputation.
+// It is idempotent: a certificate that is already revoked only has its status list bit set again,
+// so the revoke API, the event listener and the sync worker can all report the same revocation safely.
+// A pending revocation sent by the revoke API is finalized here, crediting the wallet that asked for
+// it rather than the platform wallet that sent the transaction.
+func (s *RevocationService) Record(ctx context.Context, hash, revokedBy, reason string, revokedAt time.Time) error {
+	cert, err := db.Repos.Certificates.Get(ctx, hash)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return err
+	}
+	// The status list bit is set even on repeats, so a retry publishes a
+	// revocation whose list update failed before
+	if cert != nil {
+		if err := NewStatusListService().SetRevoked(ctx, cert, true); err != nil {
+			return fmt.Errorf("failed to publish revocation in status list: %w", err)
+		}
+	}
+	if cert != nil && cert.Revoked {
+		return nil
+	}
+	if cert != nil && cert.PendingRevocation != nil {
+		revokedBy = cert.PendingRevocation.RequestedBy
+	}
+
+	if err := db.Repos.Certificates.MarkRevoked(ctx, hash, revokedBy, reason, revokedAt); err != nil {
+		return err
+	}
+	log.Printf("🔴 Certificate %s revoked by %s (reason: %q)", hash, revokedBy, reason)
+
+	// Unknown to storage (e.g. minted outside the platform): nobody to notify
+	if cert == nil {
+		return nil
+	}
+
+	s.notifyStudent(ctx, cert, reason, revokedAt)
+
+	if cert.InstructorWallet != "" {
+		if err := NewAnalyticsService().UpdateIssuerReputation(ctx, cert.InstructorWallet); err != nil {
+			log.Printf("Failed to update issuer reputation for %s: %v", cert.InstructorWallet, err)
+		}
+	}
+	return nil
+}
+
+func (s *RevocationService) notifyStudent(ctx context.Context, cert *models.Certificate, reason string, revokedAt time.Time) {
+	studentID := cert.StudentID
+	if studentID == "" {
+		studentID = cert.UserID
+	}
+	if studentID == "" {
+		return
+	}
+
+	courseName := cert.CourseName
+	if courseName == "" {
+		courseName = cert.CourseTitle
+	}
+
+	body := fmt.Sprintf("Your certificate for %s has been revoked.", courseName)
+	if reason != "" {
+		body = fmt.Sprintf("Your certificate for %s has been revoked. Reason: %s", courseName, reason)
+	}
+
+	notification := &models.Notification{
+		ID:        "cert_revoked_" + cert.Hash, // One per certificate, so repeats overwrite
+		UserID:    studentID,
+		Title:     "Certificate Revoked",
+		Body:      body,
+		Type:      "system",
+		IsRead:    false,
+		CreatedAt: revokedAt,
+	}
+	if err := db.Repos.Notifications.Save(ctx, notification); err != nil {
+		log.Printf("Failed to notify student %s of revocation: %v", studentID, err)
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+)
+
+// StatusListPurpose is the statusPurpose of every list the platform publishes
+const StatusListPurpose = "revocation"
+
+// errStatusListFull is returned from a status list update when every index is taken
+var errStatusListFull = errors.New("status list is full")
+
+// StatusListService maintains the bitstring status lists credentials point at.
+// Lists are numbered from 1 and filled in order; a certificate keeps its list
+// and index for life.
+type StatusListService struct{}
+
+func NewStatusListService() *StatusListService {
+	return &StatusListService{}
+}
+
+// Assign gives cert the next free index in the first list with room, setting its
+// bit straight away if cert is already revoked. Certificates that have an index
+// keep it. The caller saves cert.
+func (s *StatusListService) Assign(ctx context.Context, cert *models.Certificate) error {
+	if cert.StatusListID != "" {
+		return nil
+	}
+
+	for n := 1; ; n++ {
+		id := strconv.Itoa(n)
+		if list, err := db.Repos.StatusLists.Get(ctx, id); err == nil && list.NextIndex >= models.StatusListSize {
+			continue
+		} else if err != nil && !errors.Is(err, db.ErrNotFound) {
+			return err
+		}
+
+		index := 0
+		err := db.Repos.StatusLists.Update(ctx, id, func(list *models.StatusList) (*models.StatusList, error) {
+			if list == nil {
+				list = newStatusList(id)
+			}
+			if list.NextIndex >= models.StatusListSize {
+				return nil, errStatusListFull
+			}
+			index = list.NextIndex
+			list.NextIndex++
+			if cert.Revoked {
+				setStatusBit(list, index, true)
+			}
+			return list, nil
+		})
+		if errors.Is(err, errStatusListFull) {
+			continue // Filled up by a concurrent assignment
+		}
+		if err != nil {
+			return err
+		}
+
+		cert.StatusListID = id
+		cert.StatusListIndex = index
+		return nil
+	}
+}
+
+// SetRevoked sets or clears cert's bit in its status list. Certificates without
+// an index are left alone.
+func (s *StatusListService) SetRevoked(ctx context.Context, cert *models.Certificate, revoked bool) error {
+	if cert.StatusListID == "" {
+		return nil
+	}
+
+	err := db.Repos.StatusLists.Update(ctx, cert.StatusListID, func(list *models.StatusList) (*models.StatusList, error) {
+		if list == nil || cert.StatusListIndex >= list.NextIndex {
+			return nil, fmt.Errorf("status list %s has no index %d", cert.StatusListID, cert.StatusListIndex)
+		}
+		setStatusBit(list, cert.StatusListIndex, revoked)
+		return list, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	state := "cleared"
+	if revoked {
+		state = "set"
+	}
+	log.Printf("📋 Status list %s bit %d %s for certificate %s", cert.StatusListID, cert.StatusListIndex, state, cert.Hash)
+	return nil
+}
+
+// StatusBit reports whether index is set in list
+func StatusBit(list *models.StatusList, index int) bool {
+	if index < 0 || index/8 >= len(list.Bits) {
+		return false
+	}
+	return list.Bits[index/8]&(0x80>>(index%8)) != 0
+}
+
+// EncodeStatusList returns list's bitstring as the spec's encodedList: GZIP
+// compressed, base64url encoded without padding and prefixed "u" (multibase)
+func EncodeStatusList(list *models.StatusList) (string, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(list.Bits); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return "u" + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// EncodeTokenStatusList returns list's bitstring as a Token Status List's lst, the
+// form SD-JWT VCs reference: one bit per status with index 0 the least significant
+// bit of the first byte, ZLIB compressed and base64url encoded without padding
+func EncodeTokenStatusList(list *models.StatusList) (string, error) {
+	bits := make([]byte, len(list.Bits))
+	for i, b := range list.Bits {
+		bits[i] = reverseBits(b)
+	}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(bits); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// reverseBits converts a byte between the bit orders of the two list formats
+func reverseBits(b byte) byte {
+	var r byte
+	for i := 0; i < 8; i++ {
+		r = r<<1 | b&1
+		b >>= 1
+	}
+	return r
+}
+
+func newStatusList(id string) *models.StatusList {
+	return &models.StatusList{
+		ID:        id,
+		Purpose:   StatusListPurpose,
+		Bits:      make([]byte, models.StatusListSize/8),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// setStatusBit sets or clears index in list, bumping its version if that changed
+// it. The bits are copied first since the stored list may share them.
+func setStatusBit(list *models.StatusList, index int, value bool) {
+	if StatusBit(list, index) == value {
+		return
+	}
+	bits := append([]byte(nil), list.Bits...)
+	if value {
+		bits[index/8] |= 0x80 >> (index % 8)
+	} else {
+		bits[index/8] &^= 0x80 >> (index % 8)
+	}
+	list.Bits = bits
+	list.Version++
+	list.UpdatedAt = time.Now()
+}
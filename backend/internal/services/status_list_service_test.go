@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"cache-crew/cognify/internal/db"
+	"cache-crew/cognify/internal/models"
+)
+
+func TestStatusBits(t *testing.T) {
+	tests := []struct {
+		index int
+		byte  int
+		mask  byte
+	}{
+		{0, 0, 0x80},
+		{1, 0, 0x40},
+		{7, 0, 0x01},
+		{8, 1, 0x80},
+		{13, 1, 0x04},
+		{models.StatusListSize - 1, models.StatusListSize/8 - 1, 0x01},
+	}
+	for _, tt := range tests {
+		list := newStatusList("1")
+		setStatusBit(list, tt.index, true)
+		if !StatusBit(list, tt.index) {
+			t.Errorf("bit %d not set", tt.index)
+		}
+		// Index 0 is the most significant bit of the first byte
+		if list.Bits[tt.byte] != tt.mask {
+			t.Errorf("bit %d set byte %d to %#02x, want %#02x", tt.index, tt.byte, list.Bits[tt.byte], tt.mask)
+		}
+		if StatusBit(list, tt.index+1) || (tt.index > 0 && StatusBit(list, tt.index-1)) {
+			t.Errorf("setting bit %d set a neighbour", tt.index)
+		}
+
+		setStatusBit(list, tt.index, false)
+		if StatusBit(list, tt.index) || list.Bits[tt.byte] != 0 {
+			t.Errorf("bit %d not cleared", tt.index)
+		}
+	}
+
+	list := newStatusList("1")
+	for _, index := range []int{-1, models.StatusListSize} {
+		if StatusBit(list, index) {
+			t.Errorf("out of range index %d reported set", index)
+		}
+	}
+}
+
+func TestSetStatusBitVersion(t *testing.T) {
+	list := newStatusList("1")
+	shared := list.Bits
+
+	setStatusBit(list, 5, true)
+	if list.Version != 1 {
+		t.Errorf("Version = %d after a change, want 1", list.Version)
+	}
+	// The stored list may share its bits, so they are copied rather than modified
+	if shared[0] != 0 {
+		t.Error("setStatusBit modified the original bits")
+	}
+
+	setStatusBit(list, 5, true)
+	setStatusBit(list, 6, false)
+	if list.Version != 1 {
+		t.Errorf("Version = %d after no-op updates, want 1", list.Version)
+	}
+}
+
+func TestEncodeStatusList(t *testing.T) {
+	list := newStatusList("1")
+	for _, index := range []int{0, 3, 9, 42, models.StatusListSize - 1} {
+		setStatusBit(list, index, true)
+	}
+
+	encoded, err := EncodeStatusList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Multibase base64url: a "u" prefix and no padding
+	if !strings.HasPrefix(encoded, "u") || strings.ContainsAny(encoded, "=+/") {
+		t.Fatalf("encodedList %q is not multibase base64url", encoded)
+	}
+	compressed, err := base64.RawURLEncoding.DecodeString(encoded[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("encodedList is not GZIP: %v", err)
+	}
+	bits, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bits) != models.StatusListSize/8 || !bytes.Equal(bits, list.Bits) {
+		t.Fatalf("decoded %d bytes, want the list's %d", len(bits), len(list.Bits))
+	}
+	if bits[0] != 0x90 || bits[1] != 0x40 {
+		t.Errorf("first bytes = %#02x %#02x, want 0x90 0x40", bits[0], bits[1])
+	}
+}
+
+// decodeTokenStatusList inflates a Token Status List lst
+func decodeTokenStatusList(t *testing.T, lst string) []byte {
+	t.Helper()
+	compressed, err := base64.RawURLEncoding.DecodeString(lst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("lst is not ZLIB: %v", err)
+	}
+	bits, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bits
+}
+
+func TestEncodeTokenStatusList(t *testing.T) {
+	// The example list of the Token Status List draft, section 4.1: statuses
+	// 1 0 0 1 1 1 0 1 1 1 0 0 0 1 0 1 are the bytes 0xB9 0xA3
+	if got := decodeTokenStatusList(t, "eNrbuRgAAhcBXQ"); !bytes.Equal(got, []byte{0xb9, 0xa3}) {
+		t.Fatalf("example lst decoded to %x", got)
+	}
+
+	list := newStatusList("1")
+	for i, status := range "1001110111000101" {
+		setStatusBit(list, i, status == '1')
+	}
+	lst, err := EncodeTokenStatusList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := decodeTokenStatusList(t, lst)
+	if len(bits) != models.StatusListSize/8 {
+		t.Fatalf("decoded %d bytes, want %d", len(bits), models.StatusListSize/8)
+	}
+	if bits[0] != 0xb9 || bits[1] != 0xa3 {
+		t.Errorf("first bytes = %#02x %#02x, want 0xb9 0xa3", bits[0], bits[1])
+	}
+	if list.Bits[0] == 0xb9 {
+		t.Error("encoding reordered the stored bits")
+	}
+}
+
+func TestStatusListAssignAndRevoke(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+	service := NewStatusListService()
+
+	first := &models.Certificate{Hash: "a"}
+	revoked := &models.Certificate{Hash: "b", Revoked: true}
+	for _, cert := range []*models.Certificate{first, revoked} {
+		if err := service.Assign(ctx, cert); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if first.StatusListID != "1" || first.StatusListIndex != 0 || revoked.StatusListIndex != 1 {
+		t.Errorf("assigned %s/%d and %s/%d, want 1/0 and 1/1", first.StatusListID, first.StatusListIndex, revoked.StatusListID, revoked.StatusListIndex)
+	}
+
+	// A certificate keeps its index
+	if err := service.Assign(ctx, first); err != nil || first.StatusListIndex != 0 {
+		t.Errorf("reassigned to %d (%v)", first.StatusListIndex, err)
+	}
+
+	bit := func(cert *models.Certificate) bool {
+		list, err := db.Repos.StatusLists.Get(ctx, cert.StatusListID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return StatusBit(list, cert.StatusListIndex)
+	}
+	if bit(first) || !bit(revoked) {
+		t.Errorf("bits = %v, %v after assignment, want false, true", bit(first), bit(revoked))
+	}
+
+	if err := service.SetRevoked(ctx, first, true); err != nil || !bit(first) {
+		t.Errorf("SetRevoked(true): bit %v (%v)", bit(first), err)
+	}
+	if err := service.SetRevoked(ctx, first, false); err != nil || bit(first) {
+		t.Errorf("SetRevoked(false): bit %v (%v)", bit(first), err)
+	}
+
+	unassigned := &models.Certificate{Hash: "c", StatusListID: "1", StatusListIndex: 2}
+	if err := service.SetRevoked(ctx, unassigned, true); err == nil {
+		t.Error("SetRevoked set an unassigned index")
+	}
+	if err := service.SetRevoked(ctx, &models.Certificate{Hash: "d"}, true); err != nil {
+		t.Errorf("SetRevoked without an index: %v", err)
+	}
+}
+
+func TestStatusListAssignFull(t *testing.T) {
+	db.Repos = db.NewMemoryRepositories()
+	ctx := context.Background()
+	err := db.Repos.StatusLists.Update(ctx, "1", func(*models.StatusList) (*models.StatusList, error) {
+		list := newStatusList("1")
+		list.NextIndex = models.StatusListSize
+		return list, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := &models.Certificate{Hash: "a"}
+	if err := NewStatusListService().Assign(ctx, cert); err != nil {
+		t.Fatal(err)
+	}
+	if cert.StatusListID != "2" || cert.StatusListIndex != 0 {
+		t.Errorf("assigned %s/%d, want 2/0", cert.StatusListID, cert.StatusListIndex)
+	}
+}
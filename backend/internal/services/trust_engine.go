@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"math"
 	"time"
@@ -107,19 +106,11 @@ func (te *TrustEngine) DetectFraud(ctx context.Context, certHash string, metadat
 	// For now, basic heuristics:
 
 	// Check for suspicious patterns in verification logs
-	if db.FirestoreClient != nil {
-		// Query verification logs for this certificate
-		docs, err := db.FirestoreClient.Collection("verification_logs").
-			Where("certificate_hash", "==", certHash).
-			Limit(100).
-			Documents(ctx).
-			GetAll()
-
-		if err == nil && len(docs) > 50 {
-			// Too many verifications in short time could be suspicious
-			log.Printf("⚠️ High verification count for certificate: %s", certHash[:16]+"...")
-			return true
-		}
+	count, err := db.Repos.VerificationLogs.CountByCertificate(ctx, certHash)
+	if err == nil && count > 50 {
+		// Too many verifications in short time could be suspicious
+		log.Printf("⚠️ High verification count for certificate: %s", certHash[:16]+"...")
+		return true
 	}
 
 	return false
@@ -135,16 +126,10 @@ func (te *TrustEngine) GetReputationMetrics(ctx context.Context, certHash string
 	}
 
 	// Fetch verification count
-	if db.FirestoreClient != nil {
-		doc, err := db.FirestoreClient.Collection("certificates").Doc(certHash).Get(ctx)
-		if err == nil {
-			var cert models.Certificate
-			if err := doc.DataTo(&cert); err == nil {
-				metrics["verificationCount"] = cert.VerificationCount
-				metrics["trustScore"] = cert.TrustScore
-				metrics["issuedAt"] = cert.IssuedAt
-			}
-		}
+	if cert, err := db.Repos.Certificates.Get(ctx, certHash); err == nil {
+		metrics["verificationCount"] = cert.VerificationCount
+		metrics["trustScore"] = cert.TrustScore
+		metrics["issuedAt"] = cert.IssuedAt
 	}
 
 	return metrics
@@ -152,30 +137,17 @@ func (te *TrustEngine) GetReputationMetrics(ctx context.Context, certHash string
 
 // IncrementVerificationCount updates the verification count for a certificate
 func (te *TrustEngine) IncrementVerificationCount(ctx context.Context, certHash string) error {
-	if db.FirestoreClient == nil {
-		return fmt.Errorf("firestore client not initialized")
-	}
-
-	docRef := db.FirestoreClient.Collection("certificates").Doc(certHash)
-
 	// Get current certificate
-	doc, err := docRef.Get(ctx)
+	cert, err := db.Repos.Certificates.Get(ctx, certHash)
 	if err != nil {
 		return err
 	}
 
-	var cert models.Certificate
-	if err := doc.DataTo(&cert); err != nil {
-		return err
-	}
-
 	// Increment count
 	cert.VerificationCount++
 
 	// Recalculate trust score
-	cert.TrustScore = te.CalculateTrustScore(ctx, &cert)
+	cert.TrustScore = te.CalculateTrustScore(ctx, cert)
 
-	// Update in Firestore
-	_, err = docRef.Set(ctx, cert)
-	return err
+	return db.Repos.Certificates.Save(ctx, cert)
 }
@@ -1,23 +1,49 @@
 package utils
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
-// GenerateCertificateHash creates a unique SHA256 hash for a certificate
-// Hash is based on student ID, course ID, and timestamp
-func GenerateCertificateHash(studentID, courseID string, timestamp time.Time) string {
-	// Create a unique string combining all identifiers
-	data := fmt.Sprintf("%s:%s:%d", studentID, courseID, timestamp.Unix())
+// CanonicalJSON serializes v as JSON with object keys sorted, no insignificant
+// whitespace and no HTML escaping, so equal values always give identical bytes
+func CanonicalJSON(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
 
-	// Generate SHA256 hash
-	hash := sha256.Sum256([]byte(data))
+	// Round-trip through generic values: maps encode with sorted keys, and
+	// json.Number keeps numbers exactly as first encoded
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
 
-	// Convert to hex string
-	return hex.EncodeToString(hash[:])
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(generic); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// GenerateCanonicalHash returns the hex SHA256 of v's canonical JSON.
+// Certificate hashes are computed this way over models.CertificateClaims.
+func GenerateCanonicalHash(v interface{}) (string, error) {
+	canonical, err := CanonicalJSON(v)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(canonical)
+	return hex.EncodeToString(hash[:]), nil
 }
 
 // GenerateBlockchainTxHash creates a mock blockchain transaction hash
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"cache-crew/cognify/internal/models"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"sorted keys", map[string]interface{}{"b": 1, "a": 2, "c": 3}, `{"a":2,"b":1,"c":3}`},
+		{"struct fields sorted", struct {
+			Z string `json:"z"`
+			A string `json:"a"`
+		}{"last", "first"}, `{"a":"first","z":"last"}`},
+		{"nested", map[string]interface{}{"y": map[string]interface{}{"d": []interface{}{map[string]int{"q": 1, "p": 2}}, "c": nil}, "x": true}, `{"x":true,"y":{"c":null,"d":[{"p":2,"q":1}]}}`},
+		{"whole float", 72.0, `72`},
+		{"fraction", 91.5, `91.5`},
+		{"shortest float", 0.1, `0.1`},
+		{"negative zero", -0.0, `0`},
+		{"small float", 1e-7, `1e-7`},
+		{"large float", 1e21, `1e+21`},
+		{"large integer", int64(9007199254740993), `9007199254740993`},
+		{"json.Number kept", json.Number("1.50"), `1.50`},
+		{"no HTML escaping", "<b>A&B</b>", `"<b>A&B</b>"`},
+		{"unicode", "Zoë Ångström", `"Zoë Ångström"`},
+		{"raw message reformatted", json.RawMessage("{ \"b\" : [1, 2],\n \"a\" : {} }"), `{"a":{},"b":[1,2]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalJSON(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("CanonicalJSON = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalJSONRejects(t *testing.T) {
+	if _, err := CanonicalJSON(map[string]interface{}{"f": func() {}}); err == nil {
+		t.Error("encoded a func")
+	}
+}
+
+// testClaims are the claims of the golden certificate hash below
+var testClaims = models.CertificateClaims{
+	Version:          2,
+	StudentID:        "student-1",
+	StudentName:      "Ada Lovelace",
+	CourseID:         "course-1",
+	CourseName:       "Analytical Engines",
+	Marks:            72,
+	WalletAddress:    "0xabcabcabcabcabcabcabcabcabcabcabcabcabca",
+	InstructorWallet: "0x1111111111111111111111111111111111111111",
+	InstructorName:   "Charles Babbage",
+	IssuedAt:         1700000000,
+	AcademicDNA:      "dna-v1",
+}
+
+func TestGenerateCanonicalHashStable(t *testing.T) {
+	// Certificates already anchored on chain were hashed this way; any change to the
+	// encoding breaks their verification
+	const golden = "60f07f2530da745644920a5e36dbd05cd6f8036de236338708839d263ad962ff"
+	got, err := GenerateCanonicalHash(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != golden {
+		t.Errorf("GenerateCanonicalHash = %s, want %s", got, golden)
+	}
+
+	// The same claims in another key order, and marks written as a float, hash the same
+	shuffled := map[string]interface{}{
+		"academicDNA":      "dna-v1",
+		"issuedAt":         1700000000,
+		"instructorName":   "Charles Babbage",
+		"marks":            72.0,
+		"walletAddress":    "0xabcabcabcabcabcabcabcabcabcabcabcabcabca",
+		"courseName":       "Analytical Engines",
+		"instructorWallet": "0x1111111111111111111111111111111111111111",
+		"studentName":      "Ada Lovelace",
+		"courseId":         "course-1",
+		"version":          2,
+		"studentId":        "student-1",
+	}
+	if got, err := GenerateCanonicalHash(shuffled); err != nil || got != golden {
+		t.Errorf("shuffled claims hash = %s (%v), want %s", got, err, golden)
+	}
+	decoded := json.RawMessage(`{"version":2,"studentId":"student-1","studentName":"Ada Lovelace","courseId":"course-1",` +
+		`"courseName":"Analytical Engines","marks":72,"walletAddress":"0xabcabcabcabcabcabcabcabcabcabcabcabcabca",` +
+		`"instructorWallet":"0x1111111111111111111111111111111111111111","instructorName":"Charles Babbage",` +
+		`"issuedAt":1700000000,"academicDNA":"dna-v1"}`)
+	if got, err := GenerateCanonicalHash(decoded); err != nil || got != golden {
+		t.Errorf("claims as sent by a client hash = %s (%v), want %s", got, err, golden)
+	}
+}
+
+func TestGenerateCanonicalHashSensitive(t *testing.T) {
+	base, err := GenerateCanonicalHash(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		change func(*models.CertificateClaims)
+	}{
+		{"marks", func(c *models.CertificateClaims) { c.Marks = 72.5 }},
+		{"student name", func(c *models.CertificateClaims) { c.StudentName = "Ada King" }},
+		{"instructor wallet", func(c *models.CertificateClaims) { c.InstructorWallet = "" }},
+		{"wallet case", func(c *models.CertificateClaims) { c.WalletAddress = "0xABCABCABCABCABCABCABCABCABCABCABCABCABCA" }},
+		{"issued at", func(c *models.CertificateClaims) { c.IssuedAt++ }},
+		{"version", func(c *models.CertificateClaims) { c.Version = 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := testClaims
+			tt.change(&claims)
+			got, err := GenerateCanonicalHash(claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got == base {
+				t.Error("changed claims kept the hash")
+			}
+			if !ValidateHash(got) {
+				t.Errorf("hash %s is not 64 hex characters", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package utils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// These helpers read back what the platform writes into PDFs. They cover the
+// subset of PDF that gofpdf produces and common editors keep: Flate-compressed
+// streams and text shown inside marked content. They are not a general parser.
+
+// maxPDFStreamSize bounds a decompressed stream, so a crafted upload cannot
+// inflate without limit
+const maxPDFStreamSize = 16 << 20
+
+var pdfStreamStart = regexp.MustCompile(`>>\s*stream\r?\n`)
+
+// PDFStreams returns the data of every stream in a PDF, inflated when the stream
+// is FlateDecode compressed. Streams that fail to inflate are skipped.
+func PDFStreams(data []byte) [][]byte {
+	var streams [][]byte
+	for _, loc := range pdfStreamStart.FindAllIndex(data, -1) {
+		start := loc[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			continue
+		}
+		body := bytes.TrimRight(data[start:start+end], "\r\n")
+
+		// The stream dictionary runs from its object header to the stream keyword
+		dictStart := bytes.LastIndex(data[:loc[0]], []byte("obj"))
+		if dictStart < 0 || !bytes.Contains(data[dictStart:loc[0]], []byte("/FlateDecode")) {
+			streams = append(streams, body)
+			continue
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		inflated, err := io.ReadAll(io.LimitReader(zr, maxPDFStreamSize))
+		zr.Close()
+		if err != nil && len(inflated) == 0 {
+			continue
+		}
+		streams = append(streams, inflated)
+	}
+	return streams
+}
+
+// PDFMarkedText returns the text shown inside each marked-content sequence with
+// the given tag whose property list has a /Name, keyed by that name. Nested
+// sequences contribute to every enclosing one.
+func PDFMarkedText(content []byte, tag string) map[string]string {
+	type frame struct {
+		name   string
+		text   bytes.Buffer
+		tagged bool
+	}
+	texts := make(map[string]string)
+	var frames []*frame
+	var operands []interface{}
+
+	show := func(s []byte) {
+		for _, f := range frames {
+			if f.tagged {
+				f.text.Write(s)
+			}
+		}
+	}
+
+	lex := &pdfLexer{data: content}
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			break
+		}
+		op, isOp := tok.(pdfOperator)
+		if !isOp {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch op {
+		case "BMC":
+			frames = append(frames, &frame{})
+		case "BDC":
+			f := &frame{}
+			if len(operands) == 2 {
+				name, _ := operands[0].(pdfName)
+				props, _ := operands[1].(map[string]interface{})
+				if string(name) == tag && props != nil {
+					switch n := props["Name"].(type) {
+					case []byte:
+						f.name, f.tagged = string(n), true
+					case pdfName:
+						f.name, f.tagged = string(n), true
+					}
+				}
+			}
+			frames = append(frames, f)
+		case "EMC":
+			if len(frames) > 0 {
+				f := frames[len(frames)-1]
+				frames = frames[:len(frames)-1]
+				if f.tagged {
+					texts[f.name] += f.text.String()
+				}
+			}
+		case "Tj", "'", "\"":
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].([]byte); ok {
+					show(s)
+				}
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				items, _ := operands[len(operands)-1].([]interface{})
+				for _, item := range items {
+					if s, ok := item.([]byte); ok {
+						show(s)
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	return texts
+}
+
+// pdfName and pdfOperator are lexed PDF names (without the slash) and bare
+// keywords. Strings lex to []byte, arrays to []interface{}, dictionaries to
+// map[string]interface{} and numbers to float64.
+type (
+	pdfName     string
+	pdfOperator string
+)
+
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isPDFDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+// next returns the next object or operator, or false at the end of the data
+func (l *pdfLexer) next() (interface{}, bool) {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFWhitespace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case c == '(':
+			return l.literalString(), true
+		case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+			l.pos += 2
+			return l.dictionary(), true
+		case c == '<':
+			return l.hexString(), true
+		case c == '[':
+			l.pos++
+			var items []interface{}
+			for {
+				item, ok := l.next()
+				if !ok || item == pdfOperator("]") {
+					return items, true
+				}
+				items = append(items, item)
+			}
+		case c == ']' || (c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>'):
+			if c == ']' {
+				l.pos++
+				return pdfOperator("]"), true
+			}
+			l.pos += 2
+			return pdfOperator(">>"), true
+		case c == '/':
+			l.pos++
+			return pdfName(l.regular()), true
+		default:
+			word := l.regular()
+			if word == "" {
+				l.pos++ // Stray delimiter such as { or >
+				continue
+			}
+			if n, err := strconv.ParseFloat(word, 64); err == nil {
+				return n, true
+			}
+			return pdfOperator(word), true
+		}
+	}
+	return nil, false
+}
+
+// regular reads a run of regular characters
+func (l *pdfLexer) regular() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+// dictionary reads key/value pairs up to >>, the opening << already consumed
+func (l *pdfLexer) dictionary() map[string]interface{} {
+	dict := make(map[string]interface{})
+	for {
+		key, ok := l.next()
+		if !ok || key == pdfOperator(">>") {
+			return dict
+		}
+		name, isName := key.(pdfName)
+		value, ok := l.next()
+		if !ok || value == pdfOperator(">>") {
+			return dict
+		}
+		if isName {
+			dict[string(name)] = value
+		}
+	}
+}
+
+// literalString reads a (string), resolving escapes and nested parentheses
+func (l *pdfLexer) literalString() []byte {
+	l.pos++ // (
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\r':
+			// An unescaped end of line is a newline, whichever form it takes
+			if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+				l.pos++
+			}
+			out = append(out, '\n')
+			continue
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					n := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						n = n*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(n))
+				} else {
+					out = append(out, e)
+				}
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// hexString reads a <hex string>; an odd final digit is followed by an implied 0
+func (l *pdfLexer) hexString() []byte {
+	l.pos++ // <
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isPDFWhitespace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // >
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	hex.Decode(out, digits)
+	return out
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"reflect"
+	"testing"
+)
+
+func TestPDFStreams(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write([]byte("BT (inflated) Tj ET"))
+	zw.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n1 0 obj\n<</Length 12>>\nstream\nBT (plain) Tj ET\nendstream\nendobj\n")
+	pdf.WriteString("2 0 obj\n<</Filter /FlateDecode /Length 1>>\nstream\n")
+	pdf.Write(compressed.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n")
+	// Claims to be compressed but isn't: skipped rather than returned garbled
+	pdf.WriteString("3 0 obj\n<</Filter /FlateDecode>>\nstream\nnot zlib\nendstream\nendobj\n")
+
+	got := PDFStreams(pdf.Bytes())
+	want := [][]byte{[]byte("BT (plain) Tj ET"), []byte("BT (inflated) Tj ET")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PDFStreams = %q, want %q", got, want)
+	}
+}
+
+func TestPDFMarkedText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+	}{
+		{"literal", `/Claim <</Name (studentName)>> BDC BT (Ada Lovelace) Tj ET EMC`, map[string]string{"studentName": "Ada Lovelace"}},
+		{"balanced parentheses", `/Claim <</Name (n)>> BDC (O'Brien (Jr.)) Tj EMC`, map[string]string{"n": "O'Brien (Jr.)"}},
+		{"escaped parentheses and backslash", `/Claim <</Name (n)>> BDC (a\) \( b \\ c) Tj EMC`, map[string]string{"n": `a) ( b \ c`}},
+		{"octal escapes", `/Claim <</Name (n)>> BDC (Zo\353 \200) Tj EMC`, map[string]string{"n": "Zo\xeb \x80"}},
+		{"line continuation", "/Claim <</Name (n)>> BDC (split \\\nline) Tj EMC", map[string]string{"n": "split line"}},
+		{"hex string", `/Claim <</Name (n)>> BDC <4164 61> Tj EMC`, map[string]string{"n": "Ada"}},
+		{"TJ array", `/Claim <</Name (n)>> BDC [(Ad) -20 (a)] TJ EMC`, map[string]string{"n": "Ada"}},
+		{"name as a name object", `/Claim <</Name /n>> BDC (x) Tj EMC`, map[string]string{"n": "x"}},
+		{"other tags ignored", `/Span <</Name (n)>> BDC (x) Tj EMC /Artifact BMC (y) Tj EMC`, map[string]string{}},
+		{"text outside ignored", `(before) Tj /Claim <</Name (n)>> BDC (in) Tj EMC (after) Tj`, map[string]string{"n": "in"}},
+		{"nested", `/Claim <</Name (outer)>> BDC (a) Tj /Claim <</Name (inner)>> BDC (b) Tj EMC EMC`, map[string]string{"outer": "ab", "inner": "b"}},
+		{"repeated name concatenated", `/Claim <</Name (n)>> BDC (a) Tj EMC /Claim <</Name (n)>> BDC (b) Tj EMC`, map[string]string{"n": "ab"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PDFMarkedText([]byte(tt.content), "Claim")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PDFMarkedText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SDJWTHashAlg is the _sd_alg of SD-JWTs built with these helpers
+const SDJWTHashAlg = "sha-256"
+
+// SDJWTDisclosure is one selectively disclosable object property of an SD-JWT
+// (RFC 9901): the issuer signs only its digest, and the holder reveals the
+// property by presenting Encoded alongside the JWT
+type SDJWTDisclosure struct {
+	Salt    string
+	Name    string
+	Value   interface{}
+	Encoded string // base64url of the JSON array [salt, name, value]
+}
+
+// NewSDJWTDisclosure discloses name with a fresh 128-bit salt
+func NewSDJWTDisclosure(name string, value interface{}) (SDJWTDisclosure, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return SDJWTDisclosure{}, err
+	}
+	d := SDJWTDisclosure{Salt: base64.RawURLEncoding.EncodeToString(salt), Name: name, Value: value}
+
+	raw, err := json.Marshal([]interface{}{d.Salt, d.Name, d.Value})
+	if err != nil {
+		return SDJWTDisclosure{}, err
+	}
+	d.Encoded = base64.RawURLEncoding.EncodeToString(raw)
+	return d, nil
+}
+
+// ParseSDJWTDisclosure decodes a disclosure as presented. Array element
+// disclosures are not supported.
+func ParseSDJWTDisclosure(encoded string) (SDJWTDisclosure, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return SDJWTDisclosure{}, fmt.Errorf("disclosure is not base64url: %w", err)
+	}
+	var parts []interface{}
+	if err := json.Unmarshal(raw, &parts); err != nil || len(parts) != 3 {
+		return SDJWTDisclosure{}, errors.New("disclosure is not a [salt, name, value] array")
+	}
+	salt, okSalt := parts[0].(string)
+	name, okName := parts[1].(string)
+	if !okSalt || !okName || name == "" || name == "_sd" || name == "..." {
+		return SDJWTDisclosure{}, errors.New("disclosure has an invalid salt or name")
+	}
+	return SDJWTDisclosure{Salt: salt, Name: name, Value: parts[2], Encoded: encoded}, nil
+}
+
+// Digest is the value the issuer lists in _sd for this disclosure
+func (d SDJWTDisclosure) Digest() string {
+	hash := sha256.Sum256([]byte(d.Encoded))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// JoinSDJWT assembles an SD-JWT from the issuer-signed JWT and the disclosures
+// being presented, without key binding: <jwt>~<disclosure>~...~
+func JoinSDJWT(jwt string, disclosures []string) string {
+	var b strings.Builder
+	b.WriteString(jwt)
+	b.WriteString("~")
+	for _, d := range disclosures {
+		b.WriteString(d)
+		b.WriteString("~")
+	}
+	return b.String()
+}
+
+// SplitSDJWT separates an SD-JWT into its issuer-signed JWT, its disclosures and
+// its key binding JWT, which is empty when the presentation has none
+func SplitSDJWT(sdJWT string) (jwt string, disclosures []string, keyBinding string, err error) {
+	parts := strings.Split(strings.TrimSpace(sdJWT), "~")
+	if len(parts) < 2 || parts[0] == "" {
+		return "", nil, "", errors.New("not an SD-JWT: expected <jwt>~[<disclosure>~]*")
+	}
+	for _, d := range parts[1 : len(parts)-1] {
+		if d == "" {
+			return "", nil, "", errors.New("SD-JWT has an empty disclosure")
+		}
+		disclosures = append(disclosures, d)
+	}
+	return parts[0], disclosures, parts[len(parts)-1], nil
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSDJWTDisclosureRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{} // As JSON decodes it
+	}{
+		{"studentName", "Zoë Ångström", "Zoë Ångström"},
+		{"marks", 91.5, 91.5},
+		{"issuedAt", "2023-11-14T22:13:20Z", "2023-11-14T22:13:20Z"},
+		{"skills", []string{"Go", "Solidity"}, []interface{}{"Go", "Solidity"}},
+		{"verified", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewSDJWTDisclosure(tt.name, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := ParseSDJWTDisclosure(d.Encoded)
+			if err != nil {
+				t.Fatalf("ParseSDJWTDisclosure: %v", err)
+			}
+			if parsed.Salt != d.Salt || parsed.Name != tt.name || !reflect.DeepEqual(parsed.Value, tt.want) {
+				t.Errorf("parsed = %+v, want salt %s, name %s, value %v", parsed, d.Salt, tt.name, tt.want)
+			}
+			if parsed.Digest() != d.Digest() {
+				t.Errorf("digest changed in the round trip: %s, want %s", parsed.Digest(), d.Digest())
+			}
+		})
+	}
+}
+
+func TestSDJWTDisclosureSalted(t *testing.T) {
+	a, _ := NewSDJWTDisclosure("marks", 90)
+	b, _ := NewSDJWTDisclosure("marks", 90)
+	if a.Salt == b.Salt || a.Digest() == b.Digest() {
+		t.Error("equal claims got the same salt or digest")
+	}
+}
+
+func TestSDJWTDisclosureDigest(t *testing.T) {
+	// Example disclosure and digest from RFC 9901, section 5.2.2
+	d, err := ParseSDJWTDisclosure("WyI2cU1RdlJMNWhhaiIsICJmYW1pbHlfbmFtZSIsICJNw7ZiaXVzIl0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "family_name" || d.Value != "Möbius" {
+		t.Errorf("parsed = %+v", d)
+	}
+	if got, want := d.Digest(), "uutlBuYeMDyjLLTpf6Jxi7yNkEF35jdyWMn9U7b_RYY"; got != want {
+		t.Errorf("Digest() = %s, want %s", got, want)
+	}
+}
+
+func TestSDJWTDisclosureTampered(t *testing.T) {
+	d, err := NewSDJWTDisclosure("marks", 72.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-encoding the same salt and name with another value yields another digest
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`["` + d.Salt + `","marks",99]`))
+	parsed, err := ParseSDJWTDisclosure(forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Digest() == d.Digest() {
+		t.Error("a forged value kept the signed digest")
+	}
+
+	// Changing a single character also changes the digest
+	flipped := d.Encoded[:len(d.Encoded)-2] + "A" + d.Encoded[len(d.Encoded)-1:]
+	if flipped == d.Encoded {
+		flipped = d.Encoded[:len(d.Encoded)-2] + "B" + d.Encoded[len(d.Encoded)-1:]
+	}
+	if p, err := ParseSDJWTDisclosure(flipped); err == nil && p.Digest() == d.Digest() {
+		t.Error("an altered disclosure kept the signed digest")
+	}
+}
+
+func TestParseSDJWTDisclosureRejects(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"not base64url", "not base64!"},
+		{"not JSON", encode("salt,name,value")},
+		{"array element", encode(`["salt","value"]`)},
+		{"too long", encode(`["salt","name","value","extra"]`)},
+		{"numeric salt", encode(`[1,"name","value"]`)},
+		{"numeric name", encode(`["salt",2,"value"]`)},
+		{"empty name", encode(`["salt","","value"]`)},
+		{"_sd name", encode(`["salt","_sd",[]]`)},
+		{"ellipsis name", encode(`["salt","...","value"]`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d, err := ParseSDJWTDisclosure(tt.encoded); err == nil {
+				t.Errorf("ParseSDJWTDisclosure accepted %+v", d)
+			}
+		})
+	}
+}
+
+func TestSplitSDJWT(t *testing.T) {
+	tests := []struct {
+		name        string
+		sdJWT       string
+		jwt         string
+		disclosures []string
+		keyBinding  string
+		wantErr     bool
+	}{
+		{"no disclosures", "h.p.s~", "h.p.s", nil, "", false},
+		{"disclosures", "h.p.s~d1~d2~", "h.p.s", []string{"d1", "d2"}, "", false},
+		{"key binding", "h.p.s~d1~kh.kp.ks", "h.p.s", []string{"d1"}, "kh.kp.ks", false},
+		{"bare JWT", "h.p.s", "", nil, "", true},
+		{"no JWT", "~d1~", "", nil, "", true},
+		{"empty disclosure", "h.p.s~d1~~", "", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwt, disclosures, keyBinding, err := SplitSDJWT(tt.sdJWT)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if jwt != tt.jwt || !reflect.DeepEqual(disclosures, tt.disclosures) || keyBinding != tt.keyBinding {
+				t.Errorf("SplitSDJWT = %q, %q, %q", jwt, disclosures, keyBinding)
+			}
+			if tt.keyBinding == "" && JoinSDJWT(jwt, disclosures) != tt.sdJWT {
+				t.Errorf("JoinSDJWT did not rebuild %q", tt.sdJWT)
+			}
+		})
+	}
+
+	if !strings.HasSuffix(JoinSDJWT("h.p.s", nil), "~") {
+		t.Error("JoinSDJWT without disclosures must end in ~")
+	}
+}
@@ -0,0 +1,50 @@
+const fs = require("fs");
+const path = require("path");
+const { solidity } = require("../hardhat.config");
+
+// solcVersion reads the compiler version solc appends to the bytecode's CBOR metadata
+// ("solc" followed by a 3-byte major.minor.patch)
+function solcVersion(bytecode) {
+    const match = bytecode.match(/64736f6c6343([0-9a-f]{6})0033$/i);
+    if (!match) {
+        return "";
+    }
+    return [0, 2, 4].map((i) => parseInt(match[1].slice(i, i + 2), 16)).join(".");
+}
+
+// Copies the compiled ABI and bytecode into the backend so Go bindings can be
+// regenerated with `go generate ./internal/blockchain/...`
+function main() {
+    const artifactPath = path.join(
+        __dirname,
+        "../artifacts/contracts/CertificateRegistry.sol/SoulboundCertificateRegistry.json"
+    );
+    if (!fs.existsSync(artifactPath)) {
+        console.error("❌ Artifact not found. Run `npm run compile` first.");
+        process.exit(1);
+    }
+
+    const artifact = JSON.parse(fs.readFileSync(artifactPath, "utf8"));
+    // The checked-in bytecode must come from the compiler the contract is pinned to
+    const compiledWith = solcVersion(artifact.bytecode);
+    if (compiledWith !== solidity.version) {
+        console.error(`❌ Artifact was built with solc ${compiledWith || "(unknown)"}, expected ${solidity.version}. Run \`npx hardhat clean && npm run compile\`.`);
+        process.exit(1);
+    }
+    const outDir = path.join(__dirname, "../../backend/internal/blockchain/contracts");
+    fs.mkdirSync(outDir, { recursive: true });
+
+    fs.writeFileSync(
+        path.join(outDir, "SoulboundCertificateRegistry.abi"),
+        JSON.stringify(artifact.abi, null, 2) + "\n"
+    );
+    fs.writeFileSync(
+        path.join(outDir, "SoulboundCertificateRegistry.bin"),
+        artifact.bytecode.replace(/^0x/, "")
+    );
+
+    console.log("✅ ABI and bytecode exported to", outDir);
+    console.log("   Next: cd ../backend && go generate ./internal/blockchain/...");
+}
+
+main();